@@ -0,0 +1,291 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ApplyManifest maps tokens to destination files and environment variables, for the
+// "secrets-init" pattern where a sidecar or entrypoint materializes configuration
+// before the main process starts.
+//
+// Example (YAML):
+//
+//	files:
+//	  - path: /run/secrets/db-password
+//	    token: keyring:myapp/db
+//	    mode: "0400"
+//	  - path: /etc/app/config.yaml
+//	    template: /etc/app/config.yaml.tmpl
+//	  - path: /etc/app/dsn
+//	    content: postgres://${env:DB_USER}@${json:/etc/app/db.json//host}/app
+//	env:
+//	  API_TOKEN: infisical:<workspace-id>/prod/API_TOKEN
+//	envFile: /run/app/env
+type ApplyManifest struct {
+	Files   []FileTarget      `yaml:"files" json:"files"`
+	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty"`         // NAME: token
+	EnvFile string            `yaml:"envFile,omitempty" json:"envFile,omitempty"` // optional dotenv file receiving Env
+}
+
+// FileTarget is one file written by Apply. Exactly one of Token, Template and
+// Content must be set.
+type FileTarget struct {
+	Path     string `yaml:"path" json:"path"`
+	Token    string `yaml:"token,omitempty" json:"token,omitempty"`       // resolved with ResolveVariable, written verbatim
+	Template string `yaml:"template,omitempty" json:"template,omitempty"` // template file rendered with ResolveFile
+	Content  string `yaml:"content,omitempty" json:"content,omitempty"`   // inline template resolved with ResolveString
+	Mode     string `yaml:"mode,omitempty" json:"mode,omitempty"`         // octal permissions; defaults to "0600"
+}
+
+// ApplyResult reports what Apply wrote. Env holds the resolved environment values
+// for the caller to export (e.g. before exec'ing the main process).
+type ApplyResult struct {
+	Files []string
+	Env   map[string]string
+}
+
+// LoadApplyManifest reads a YAML (or JSON) apply manifest from path.
+func LoadApplyManifest(path string) (*ApplyManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %q: %w", path, err)
+	}
+	var m ApplyManifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %q: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Apply resolves every target in m, validates rendered .json/.yaml/.toml/.ini files
+// and then writes all files at once: nothing is written unless everything resolved
+// and validated, and if replacing a file fails, files already replaced are restored.
+func (r *Registry) Apply(ctx context.Context, m *ApplyManifest) (*ApplyResult, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	ctx = r.ensureResolutionID(ctx)
+
+	res := &ApplyResult{Env: make(map[string]string, len(m.Env))}
+	var staged []stagedFile
+
+	for _, f := range m.Files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		content, err := r.resolveTarget(ctx, f)
+		if err != nil {
+			return nil, fmt.Errorf("apply %q: %w", f.Path, err)
+		}
+		mode, _ := parseFileMode(f.Mode) // validated above
+		staged = append(staged, stagedFile{path: f.Path, content: content, mode: mode})
+	}
+
+	names := make([]string, 0, len(m.Env))
+	for name := range m.Env {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		val, err := r.ResolveVariableContext(ctx, m.Env[name])
+		if err != nil {
+			return nil, fmt.Errorf("apply env %s: %w", name, err)
+		}
+		res.Env[name] = val
+	}
+	if m.EnvFile != "" {
+		var b strings.Builder
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s=%s\n", name, quoteShell(res.Env[name]))
+		}
+		staged = append(staged, stagedFile{path: m.EnvFile, content: b.String(), mode: 0o600})
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := commitFiles(staged); err != nil {
+		return nil, err
+	}
+	for _, s := range staged {
+		res.Files = append(res.Files, s.path)
+	}
+	return res, nil
+}
+
+// validate checks the manifest before anything is resolved.
+func (m *ApplyManifest) validate() error {
+	seen := make(map[string]bool)
+	for i, f := range m.Files {
+		if strings.TrimSpace(f.Path) == "" {
+			return fmt.Errorf("%w: file %d has no path", ErrBadPath, i)
+		}
+		sources := 0
+		for _, s := range []string{f.Token, f.Template, f.Content} {
+			if s != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("%w: file %q needs exactly one of token, template or content", ErrBadPath, f.Path)
+		}
+		if _, err := parseFileMode(f.Mode); err != nil {
+			return fmt.Errorf("%w: file %q: %v", ErrBadPath, f.Path, err)
+		}
+		clean := filepath.Clean(f.Path)
+		if seen[clean] {
+			return fmt.Errorf("%w: file %q listed twice", ErrBadPath, f.Path)
+		}
+		seen[clean] = true
+	}
+	if m.EnvFile != "" && seen[filepath.Clean(m.EnvFile)] {
+		return fmt.Errorf("%w: envFile %q is also listed in files", ErrBadPath, m.EnvFile)
+	}
+	for name, token := range m.Env {
+		if name == "" || strings.ContainsAny(name, "= \t\n") {
+			return fmt.Errorf("%w: invalid env name %q", ErrBadPath, name)
+		}
+		if strings.TrimSpace(token) == "" {
+			return fmt.Errorf("%w: env %s has no token", ErrBadPath, name)
+		}
+	}
+	return nil
+}
+
+// resolveTarget produces the content of f and validates it by extension.
+func (r *Registry) resolveTarget(ctx context.Context, f FileTarget) (string, error) {
+	var (
+		out string
+		err error
+	)
+	switch {
+	case f.Token != "":
+		return r.ResolveVariableContext(ctx, f.Token)
+	case f.Template != "":
+		out, _, err = r.renderFile(ctx, f.Template, RenderOptions{})
+	default:
+		out, err = r.ResolveStringContext(ctx, f.Content)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := validateOutput(filepath.Ext(f.Path), out); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+	}
+	return out, nil
+}
+
+// parseFileMode parses an octal mode string; "" means 0600.
+func parseFileMode(s string) (fs.FileMode, error) {
+	if s == "" {
+		return 0o600, nil
+	}
+	n, err := strconv.ParseUint(strings.TrimPrefix(s, "0o"), 8, 32)
+	if err != nil || n > 0o777 {
+		return 0, fmt.Errorf("invalid mode %q", s)
+	}
+	return fs.FileMode(n), nil
+}
+
+// stagedFile is a file waiting to be committed.
+type stagedFile struct {
+	path    string
+	content string
+	mode    fs.FileMode
+	tmp     string      // temporary file next to path
+	old     []byte      // previous content, for rollback
+	oldMode fs.FileMode // previous permissions, for rollback
+	existed bool
+}
+
+// commitFiles writes every file to a temporary sibling first and then renames
+// them into place. If a rename fails, files already replaced are restored.
+func commitFiles(files []stagedFile) (err error) {
+	cleanup := func() {
+		for _, f := range files {
+			if f.tmp != "" {
+				_ = os.Remove(f.tmp)
+			}
+		}
+	}
+
+	for i := range files {
+		f := &files[i]
+		if f.old, err = os.ReadFile(f.path); err == nil {
+			f.existed = true
+			if info, err := os.Stat(f.path); err == nil {
+				f.oldMode = info.Mode().Perm()
+			}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			cleanup()
+			return fmt.Errorf("read %q: %w", f.path, err)
+		}
+		if f.tmp, err = writeTemp(f.path, []byte(f.content), f.mode); err != nil {
+			cleanup()
+			return err
+		}
+	}
+
+	for i := range files {
+		if err := os.Rename(files[i].tmp, files[i].path); err != nil {
+			cleanup()
+			rollback(files[:i])
+			return fmt.Errorf("replace %q: %w", files[i].path, err)
+		}
+		files[i].tmp = ""
+	}
+	return nil
+}
+
+// rollback restores the previous state of files that were already replaced.
+func rollback(files []stagedFile) {
+	for _, f := range files {
+		if !f.existed {
+			_ = os.Remove(f.path)
+			continue
+		}
+		if tmp, err := writeTemp(f.path, f.old, f.oldMode); err == nil {
+			_ = os.Rename(tmp, f.path)
+		}
+	}
+}
+
+// writeTemp writes data to a new temporary file in the directory of path
+// (creating it if needed) and returns its name.
+func writeTemp(path string, data []byte, mode fs.FileMode) (string, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create directory for %q: %w", path, err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("stage %q: %w", path, err)
+	}
+	name := tmp.Name()
+	if _, err = tmp.Write(data); err == nil {
+		if err = tmp.Chmod(mode); err == nil {
+			err = tmp.Sync()
+		}
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(name)
+		return "", fmt.Errorf("stage %q: %w", path, err)
+	}
+	return name, nil
+}
@@ -0,0 +1,154 @@
+package resolver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newApplyRegistry() *Registry {
+	values := map[string]string{"pw": `p"w`, "host": "db.local", "user": "app"}
+	r := NewRegistry()
+	r.Register("v:", ResolverFunc(func(k string) (string, error) {
+		if v, ok := values[k]; ok {
+			return v, nil
+		}
+		return "", ErrNotFound
+	}))
+	return r
+}
+
+func TestRegistry_Apply(t *testing.T) {
+	r := newApplyRegistry()
+
+	t.Run("Writes files and env", func(t *testing.T) {
+		dir := t.TempDir()
+		tmpl := writeTemplate(t, dir, "config.yaml.tmpl", "host: ${v:host}\n")
+		m := &ApplyManifest{
+			Files: []FileTarget{
+				{Path: filepath.Join(dir, "secrets", "pw"), Token: "v:pw", Mode: "0400"},
+				{Path: filepath.Join(dir, "config.yaml"), Template: tmpl},
+				{Path: filepath.Join(dir, "dsn"), Content: "postgres://${v:user}@${v:host}/app"},
+			},
+			Env:     map[string]string{"DB_USER": "v:user", "DB_PASSWORD": "v:pw"},
+			EnvFile: filepath.Join(dir, "app.env"),
+		}
+
+		res, err := r.Apply(context.Background(), m)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"DB_USER": "app", "DB_PASSWORD": `p"w`}, res.Env)
+		assert.Len(t, res.Files, 4)
+
+		assertFile(t, filepath.Join(dir, "secrets", "pw"), `p"w`, 0o400)
+		assertFile(t, filepath.Join(dir, "config.yaml"), "host: db.local\n", 0o600)
+		assertFile(t, filepath.Join(dir, "dsn"), "postgres://app@db.local/app", 0o600)
+		assertFile(t, filepath.Join(dir, "app.env"), "DB_PASSWORD='p\"w'\nDB_USER='app'\n", 0o600)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		for _, e := range entries {
+			assert.NotContains(t, e.Name(), ".tmp-", "no temporary files left behind")
+		}
+	})
+
+	t.Run("Nothing is written when a token fails", func(t *testing.T) {
+		dir := t.TempDir()
+		existing := writeTemplate(t, dir, "keep", "old")
+		m := &ApplyManifest{Files: []FileTarget{
+			{Path: existing, Token: "v:host"},
+			{Path: filepath.Join(dir, "new"), Token: "v:missing"},
+		}}
+
+		_, err := r.Apply(context.Background(), m)
+		require.ErrorIs(t, err, ErrNotFound)
+		assertFile(t, existing, "old", 0o640)
+		assert.NoFileExists(t, filepath.Join(dir, "new"))
+	})
+
+	t.Run("Invalid rendered output aborts", func(t *testing.T) {
+		dir := t.TempDir()
+		m := &ApplyManifest{Files: []FileTarget{
+			{Path: filepath.Join(dir, "a.txt"), Token: "v:host"},
+			{Path: filepath.Join(dir, "config.json"), Content: `{"pw": "${v:pw}"}`},
+		}}
+
+		_, err := r.Apply(context.Background(), m)
+		require.ErrorIs(t, err, ErrInvalidOutput)
+		assert.NoFileExists(t, filepath.Join(dir, "a.txt"))
+	})
+
+	t.Run("Failed replace rolls back", func(t *testing.T) {
+		dir := t.TempDir()
+		first := writeTemplate(t, dir, "first", "old")
+		blocker := filepath.Join(dir, "blocker")
+		require.NoError(t, os.MkdirAll(filepath.Join(blocker, "child"), 0o755)) // renaming a file over a non-empty dir fails
+
+		_, err := r.Apply(context.Background(), &ApplyManifest{Files: []FileTarget{
+			{Path: first, Token: "v:host"},
+			{Path: filepath.Join(dir, "created"), Token: "v:user"},
+			{Path: blocker, Token: "v:user"},
+		}})
+		require.Error(t, err)
+		assertFile(t, first, "old", 0o640)
+		assert.NoFileExists(t, filepath.Join(dir, "created"))
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := r.Apply(ctx, &ApplyManifest{Files: []FileTarget{{Path: filepath.Join(t.TempDir(), "x"), Token: "v:host"}}})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestApplyManifest_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		m    ApplyManifest
+	}{
+		{"No path", ApplyManifest{Files: []FileTarget{{Token: "v:x"}}}},
+		{"No source", ApplyManifest{Files: []FileTarget{{Path: "a"}}}},
+		{"Two sources", ApplyManifest{Files: []FileTarget{{Path: "a", Token: "v:x", Content: "y"}}}},
+		{"Bad mode", ApplyManifest{Files: []FileTarget{{Path: "a", Token: "v:x", Mode: "0999"}}}},
+		{"Duplicate path", ApplyManifest{Files: []FileTarget{{Path: "a", Token: "v:x"}, {Path: "./a", Token: "v:y"}}}},
+		{"Env file collides", ApplyManifest{Files: []FileTarget{{Path: "a", Token: "v:x"}}, EnvFile: "a"}},
+		{"Bad env name", ApplyManifest{Env: map[string]string{"A=B": "v:x"}}},
+		{"Empty env token", ApplyManifest{Env: map[string]string{"A": " "}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.ErrorIs(t, tc.m.validate(), ErrBadPath)
+		})
+	}
+}
+
+func TestLoadApplyManifest(t *testing.T) {
+	p := writeTemplate(t, t.TempDir(), "apply.yaml", `files:
+  - path: /run/secrets/pw
+    token: env:PW
+    mode: "0400"
+env:
+  HOST: env:HOST
+envFile: /run/app/env
+`)
+	m, err := LoadApplyManifest(p)
+	require.NoError(t, err)
+	assert.Equal(t, []FileTarget{{Path: "/run/secrets/pw", Token: "env:PW", Mode: "0400"}}, m.Files)
+	assert.Equal(t, map[string]string{"HOST": "env:HOST"}, m.Env)
+	assert.Equal(t, "/run/app/env", m.EnvFile)
+}
+
+// assertFile checks the content and permissions of path.
+func assertFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(got))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, mode, info.Mode().Perm())
+}
@@ -0,0 +1,134 @@
+package resolver
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// ArchiveResolver resolves a value from a file inside a .zip, .tar or .tar.gz/.tgz
+// archive, without extracting it. Format: "archive:/path/archive!/member//key", e.g.
+// "archive:/opt/bundle.tgz!/config/app.yaml//server.host".
+//
+// The archive type is detected from its content. The member is read up to
+// DefaultMaxOutputSize bytes, and the key is selected with the format of the member's
+// extension (.json, .yaml, .toml, .ini, ..., otherwise key=value lines). If no key is
+// provided, returns the whole member as a string.
+type ArchiveResolver struct{}
+
+func (r *ArchiveResolver) Resolve(value string) (string, error) {
+	ref, keyPath := splitFileAndKey(value)
+	archivePath, member, ok := strings.Cut(ref, "!/")
+	archivePath = os.ExpandEnv(archivePath)
+	member = path.Clean("/" + member)[1:]
+
+	if strings.TrimSpace(archivePath) == "" {
+		return "", fmt.Errorf("%w: empty archive path", ErrBadPath)
+	}
+	if !ok || member == "" {
+		return "", fmt.Errorf("%w: missing member in %q (want archive!/member)", ErrBadPath, ref)
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, archivePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, archivePath)
+		}
+		return "", fmt.Errorf("failed to open archive %q: %w", archivePath, err)
+	}
+	defer f.Close() // nolint:errcheck
+
+	data, err := readArchiveMember(f, member)
+	if err != nil {
+		return "", fmt.Errorf("archive %q: %w", archivePath, err)
+	}
+	return selectByExtension(member, data, keyPath)
+}
+
+// Describe reports the resolver metadata.
+func (r *ArchiveResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// readArchiveMember returns the content of member in the zip or (optionally gzipped)
+// tar archive f.
+func readArchiveMember(f *os.File, member string) ([]byte, error) {
+	br := bufio.NewReader(f)
+	magic, _ := br.Peek(4)
+
+	switch {
+	case bytes.Equal(magic, []byte("PK\x03\x04")), bytes.Equal(magic, []byte("PK\x05\x06")):
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		zr, err := zip.NewReader(f, info.Size())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read zip: %w", err)
+		}
+		for _, zf := range zr.File {
+			if archiveName(zf.Name) != member || zf.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := zf.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to open %q: %w", member, err)
+			}
+			defer rc.Close() // nolint:errcheck
+			return readMember(rc, member)
+		}
+		return nil, fmt.Errorf("%w: member %q", ErrNotFound, member)
+	}
+
+	var rd io.Reader = br
+	if len(magic) >= 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip: %w", err)
+		}
+		defer gz.Close() // nolint:errcheck
+		rd = gz
+	}
+	tr := tar.NewReader(rd)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: member %q", ErrNotFound, member)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && archiveName(hdr.Name) == member {
+			return readMember(tr, member)
+		}
+	}
+}
+
+// archiveName normalizes a member name ("./config/app.yaml" -> "config/app.yaml").
+func archiveName(name string) string {
+	return path.Clean("/" + name)[1:]
+}
+
+// readMember reads r, up to DefaultMaxOutputSize bytes.
+func readMember(r io.Reader, member string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, DefaultMaxOutputSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", member, err)
+	}
+	if len(data) > DefaultMaxOutputSize {
+		return nil, fmt.Errorf("%w: member %q is larger than %d bytes", ErrTooLarge, member, DefaultMaxOutputSize)
+	}
+	return data, nil
+}
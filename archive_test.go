@@ -0,0 +1,109 @@
+package resolver
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testArchiveFiles = map[string]string{
+	"./config/app.yaml": "server:\n  host: db.internal\n  port: 5432\n",
+	"config/app.json":   `{"name":"billing"}`,
+	"README":            "hello\n",
+}
+
+func writeTestTar(t *testing.T, gz bool) string {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(&buf)
+	if gz {
+		tw = tar.NewWriter(gw)
+	}
+	for name, content := range testArchiveFiles {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	name := "bundle.tar"
+	if gz {
+		require.NoError(t, gw.Close())
+		name = "bundle.tgz"
+	}
+	p := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(p, buf.Bytes(), 0o666))
+	return p
+}
+
+func writeTestZip(t *testing.T) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range testArchiveFiles {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	p := filepath.Join(t.TempDir(), "bundle.zip")
+	require.NoError(t, os.WriteFile(p, buf.Bytes(), 0o666))
+	return p
+}
+
+func TestArchiveResolver_Resolve(t *testing.T) {
+	r := &ArchiveResolver{}
+	archives := map[string]string{
+		"tar":    writeTestTar(t, false),
+		"tar.gz": writeTestTar(t, true),
+		"zip":    writeTestZip(t),
+	}
+
+	for kind, p := range archives {
+		t.Run(kind, func(t *testing.T) {
+			cases := []struct {
+				name, ref, want string
+			}{
+				{"YAML member", "!/config/app.yaml//server.host", "db.internal"},
+				{"JSON member", "!/config/app.json//name", "billing"},
+				{"Whole member", "!/README", "hello"},
+				{"Member without leading slash", "!/./config/app.yaml//server.port", "5432"},
+			}
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					val, err := r.Resolve(p + tc.ref)
+					require.NoError(t, err)
+					assert.Equal(t, tc.want, val)
+				})
+			}
+
+			t.Run("Missing member", func(t *testing.T) {
+				_, err := r.Resolve(p + "!/config/nope.yaml//a")
+				assert.ErrorIs(t, err, ErrNotFound)
+			})
+
+			t.Run("Missing key", func(t *testing.T) {
+				_, err := r.Resolve(p + "!/config/app.yaml//server.nope")
+				assert.ErrorIs(t, err, ErrNotFound)
+			})
+		})
+	}
+
+	t.Run("No member", func(t *testing.T) {
+		_, err := r.Resolve(archives["zip"] + "//a")
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Missing archive", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "nope.zip") + "!/a.json//a")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,504 @@
+package resolver
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// AvroResolver resolves a value from a record of an Avro object container file.
+// Format: "avro:/path/file.avro//key1.key2.keyN", e.g. "avro:/data/events.avro//user.id".
+//
+// The records are decoded with the schema embedded in the file (null or deflate codec).
+// A key path navigates the first record, unless it starts with an index or a filter,
+// which select among all records ("3.user.id", "[type=login].user.id"). Unions are
+// decoded to the value of their branch, bytes and fixed values to base64, enums to
+// their symbol, and timestamp-millis/-micros and date values to RFC 3339 (UTC).
+// Strings are returned as-is, other values as JSON. If no key is provided, returns
+// all records as a JSON list.
+type AvroResolver struct{}
+
+func (r *AvroResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read Avro file %q: %w", filePath, err)
+	}
+
+	// Only the first record is needed unless the path selects among records.
+	all := keyPath == "" || keyPath[0] == '[' || (keyPath[0] >= '0' && keyPath[0] <= '9')
+	records, err := decodeAvroFile(data, all)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse Avro in %q: %w", filePath, err)
+	}
+
+	var val any = records
+	switch {
+	case keyPath == "":
+	case all:
+		val, err = selectPath(records, keyPath)
+	case len(records) == 0:
+		err = errors.New("file has no records")
+	default:
+		val, err = selectPath(records[0], keyPath)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: key path %q in Avro %q: %v", ErrNotFound, keyPath, filePath, err)
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	out, err := json.Marshal(val)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode Avro value: %w", err)
+	}
+	return string(out), nil
+}
+
+// Describe reports the resolver metadata.
+func (r *AvroResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector}}
+}
+
+// avroMagic starts every Avro object container file.
+var avroMagic = []byte("Obj\x01")
+
+// maxAvroDepth bounds the nesting of schemas and values.
+const maxAvroDepth = 512
+
+// decodeAvroFile returns the records of an object container file, or only the first
+// one unless all is set.
+func decodeAvroFile(data []byte, all bool) ([]any, error) {
+	if !bytes.HasPrefix(data, avroMagic) {
+		return nil, errors.New("not an Avro object container file")
+	}
+	d := &avroDecoder{data: data, pos: len(avroMagic)}
+
+	meta := make(map[string]string)
+	for {
+		n, err := d.blockCount()
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			break
+		}
+		for ; n > 0; n-- {
+			k, err := d.bytes()
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.bytes()
+			if err != nil {
+				return nil, err
+			}
+			meta[string(k)] = string(v)
+		}
+	}
+	sync, err := d.next(16)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw any
+	if err := json.Unmarshal([]byte(meta["avro.schema"]), &raw); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	schema, err := parseAvroSchema(raw, "", make(map[string]*avroSchema), 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	codec := firstNonEmpty(meta["avro.codec"], "null")
+	if codec != "null" && codec != "deflate" {
+		return nil, fmt.Errorf("unsupported codec %q", codec)
+	}
+
+	records := []any{}
+	for d.pos < len(d.data) {
+		count, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		size, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 || size > int64(len(d.data)) {
+			return nil, errors.New("invalid block size")
+		}
+		block, err := d.next(int(size))
+		if err != nil {
+			return nil, err
+		}
+		if codec == "deflate" {
+			fr := flate.NewReader(bytes.NewReader(block))
+			block, err = io.ReadAll(io.LimitReader(fr, DefaultMaxOutputSize+1))
+			fr.Close() // nolint:errcheck
+			if err != nil {
+				return nil, fmt.Errorf("failed to inflate block: %w", err)
+			}
+			if len(block) > DefaultMaxOutputSize {
+				return nil, fmt.Errorf("%w: block is larger than %d bytes", ErrTooLarge, DefaultMaxOutputSize)
+			}
+		}
+		// Records of an empty schema take no bytes; bound them anyway.
+		if count < 0 || count > int64(len(block))+1024 {
+			return nil, fmt.Errorf("invalid block record count %d", count)
+		}
+
+		bd := &avroDecoder{data: block}
+		for ; count > 0; count-- {
+			rec, err := bd.value(schema, 0)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, rec)
+			if !all {
+				return records, nil
+			}
+		}
+		if bd.pos != len(block) {
+			return nil, fmt.Errorf("%d trailing bytes in block", len(block)-bd.pos)
+		}
+		marker, err := d.next(16)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(marker, sync) {
+			return nil, errors.New("sync marker mismatch")
+		}
+	}
+	return records, nil
+}
+
+// avroSchema is a parsed Avro schema.
+type avroSchema struct {
+	typ      string // primitive name, record, enum, array, map, fixed or union
+	logical  string
+	fields   []avroField   // record
+	symbols  []string      // enum
+	items    *avroSchema   // array items, map values
+	size     int           // fixed
+	branches []*avroSchema // union
+}
+
+// avroField is a field of a record schema.
+type avroField struct {
+	name   string
+	schema *avroSchema
+}
+
+// parseAvroSchema parses the JSON form of a schema. Named types are registered in
+// names under their full name and are resolved relative to namespace.
+func parseAvroSchema(raw any, namespace string, names map[string]*avroSchema, depth int) (*avroSchema, error) {
+	if depth > maxAvroDepth {
+		return nil, errors.New("schema nested too deeply")
+	}
+	switch v := raw.(type) {
+	case string:
+		switch v {
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+			return &avroSchema{typ: v}, nil
+		}
+		if s, ok := names[avroFullName(v, namespace)]; ok {
+			return s, nil
+		}
+		if s, ok := names[v]; ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("unknown type %q", v)
+	case []any:
+		s := &avroSchema{typ: "union"}
+		for _, b := range v {
+			bs, err := parseAvroSchema(b, namespace, names, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			s.branches = append(s.branches, bs)
+		}
+		return s, nil
+	}
+
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid schema %v", raw)
+	}
+	typ, _ := obj["type"].(string)
+	logical, _ := obj["logicalType"].(string)
+	switch typ {
+	case "record", "error", "enum", "fixed":
+	case "array", "map":
+		key := map[string]string{"array": "items", "map": "values"}[typ]
+		items, err := parseAvroSchema(obj[key], namespace, names, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return &avroSchema{typ: typ, items: items}, nil
+	default:
+		if _, isObj := obj["type"].(map[string]any); isObj || typ == "" {
+			return parseAvroSchema(obj["type"], namespace, names, depth+1)
+		}
+		s, err := parseAvroSchema(typ, namespace, names, depth+1)
+		if err != nil || logical == "" {
+			return s, err
+		}
+		return &avroSchema{typ: s.typ, logical: logical}, nil
+	}
+
+	// Named types.
+	name, _ := obj["name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("%s schema without a name", typ)
+	}
+	if ns, ok := obj["namespace"].(string); ok && !strings.Contains(name, ".") {
+		namespace = ns
+	}
+	full := avroFullName(name, namespace)
+	if i := strings.LastIndexByte(full, '.'); i >= 0 {
+		namespace = full[:i]
+	}
+	s := &avroSchema{typ: typ, logical: logical}
+	names[full] = s // registered first, for recursive records
+
+	switch typ {
+	case "enum":
+		syms, _ := obj["symbols"].([]any)
+		for _, sym := range syms {
+			str, _ := sym.(string)
+			s.symbols = append(s.symbols, str)
+		}
+	case "fixed":
+		size, ok := obj["size"].(float64)
+		if !ok || size < 0 {
+			return nil, fmt.Errorf("fixed %q without a size", name)
+		}
+		s.size = int(size)
+	default:
+		s.typ = "record"
+		fields, _ := obj["fields"].([]any)
+		for _, f := range fields {
+			fo, _ := f.(map[string]any)
+			fname, _ := fo["name"].(string)
+			fschema, err := parseAvroSchema(fo["type"], namespace, names, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("field %q of %q: %w", fname, name, err)
+			}
+			s.fields = append(s.fields, avroField{name: fname, schema: fschema})
+		}
+	}
+	return s, nil
+}
+
+// avroFullName qualifies name with namespace unless it is already qualified.
+func avroFullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// avroDecoder decodes Avro binary values from data.
+type avroDecoder struct {
+	data []byte
+	pos  int
+}
+
+// next returns the next n bytes.
+func (d *avroDecoder) next(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data)-d.pos {
+		return nil, errors.New("unexpected end of data")
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// long reads a zig-zag encoded variable-length integer.
+func (d *avroDecoder) long() (int64, error) {
+	u, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, errors.New("invalid varint")
+	}
+	d.pos += n
+	return int64(u>>1) ^ -int64(u&1), nil
+}
+
+// bytes reads length-prefixed bytes.
+func (d *avroDecoder) bytes() ([]byte, error) {
+	n, err := d.long()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > math.MaxInt32 {
+		return nil, errors.New("invalid length")
+	}
+	return d.next(int(n))
+}
+
+// blockCount reads the item count of an array or map block; negative counts are
+// followed by the block size, which is skipped. Items of an empty schema take no
+// bytes, so counts are bounded by the remaining data plus a margin.
+func (d *avroDecoder) blockCount() (int64, error) {
+	n, err := d.long()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		if _, err := d.long(); err != nil {
+			return 0, err
+		}
+		n = -n
+	}
+	if n < 0 || n > int64(len(d.data)-d.pos)+1024 {
+		return 0, fmt.Errorf("invalid block count %d", n)
+	}
+	return n, nil
+}
+
+// value decodes the next value of schema s.
+func (d *avroDecoder) value(s *avroSchema, depth int) (any, error) {
+	if depth > maxAvroDepth {
+		return nil, errors.New("nested too deeply")
+	}
+	switch s.typ {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "int", "long":
+		v, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		switch s.logical {
+		case "timestamp-millis":
+			return time.UnixMilli(v).UTC().Format(time.RFC3339Nano), nil
+		case "timestamp-micros":
+			return time.UnixMicro(v).UTC().Format(time.RFC3339Nano), nil
+		case "date":
+			return time.Unix(v*86400, 0).UTC().Format(time.DateOnly), nil
+		}
+		return v, nil
+	case "float":
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case "double":
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case "bytes":
+		b, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	case "fixed":
+		b, err := d.next(s.size)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	case "string":
+		b, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(b) {
+			return nil, errors.New("invalid UTF-8 in string")
+		}
+		return string(b), nil
+	case "enum":
+		i, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(s.symbols)) {
+			return nil, fmt.Errorf("enum index %d out of range", i)
+		}
+		return s.symbols[i], nil
+	case "union":
+		i, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(s.branches)) {
+			return nil, fmt.Errorf("union index %d out of range", i)
+		}
+		return d.value(s.branches[i], depth+1)
+	case "record":
+		rec := make(map[string]any, len(s.fields))
+		for _, f := range s.fields {
+			v, err := d.value(f.schema, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			rec[f.name] = v
+		}
+		return rec, nil
+	case "array":
+		arr := []any{}
+		for {
+			n, err := d.blockCount()
+			if err != nil || n == 0 {
+				return arr, err
+			}
+			for ; n > 0; n-- {
+				v, err := d.value(s.items, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+		}
+	case "map":
+		m := make(map[string]any)
+		for {
+			n, err := d.blockCount()
+			if err != nil || n == 0 {
+				return m, err
+			}
+			for ; n > 0; n-- {
+				k, err := d.bytes()
+				if err != nil {
+					return nil, err
+				}
+				if m[string(k)], err = d.value(s.items, depth+1); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("unsupported type %q", s.typ)
+}
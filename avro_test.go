@@ -0,0 +1,181 @@
+package resolver
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// avroLong appends the zig-zag varint encoding of v.
+func avroLong(b []byte, v int64) []byte {
+	return binary.AppendUvarint(b, uint64((v<<1)^(v>>63)))
+}
+
+// avroString appends a length-prefixed string.
+func avroString(b []byte, s string) []byte {
+	return append(avroLong(b, int64(len(s))), s...)
+}
+
+// createAvroTestFile writes an object container file with one block of records.
+func createAvroTestFile(t *testing.T, schema, codec string, count int, records []byte) string {
+	t.Helper()
+	sync := []byte("0123456789abcdef")
+
+	data := append([]byte(nil), avroMagic...)
+	data = avroLong(data, 2)
+	data = avroString(avroString(data, "avro.schema"), schema)
+	data = avroString(avroString(data, "avro.codec"), codec)
+	data = avroLong(data, 0)
+	data = append(data, sync...)
+
+	if codec == "deflate" {
+		var buf bytes.Buffer
+		fw, err := flate.NewWriter(&buf, flate.BestCompression)
+		require.NoError(t, err)
+		_, err = fw.Write(records)
+		require.NoError(t, err)
+		require.NoError(t, fw.Close())
+		records = buf.Bytes()
+	}
+	data = avroLong(data, int64(count))
+	data = avroLong(data, int64(len(records)))
+	data = append(data, records...)
+	data = append(data, sync...)
+
+	p := filepath.Join(t.TempDir(), "events.avro")
+	require.NoError(t, os.WriteFile(p, data, 0o600))
+	return p
+}
+
+const testAvroSchema = `{
+  "type": "record", "name": "Event", "namespace": "com.example",
+  "fields": [
+    {"name": "type", "type": {"type": "enum", "name": "Kind", "symbols": ["LOGIN", "LOGOUT"]}},
+    {"name": "user", "type": {"type": "record", "name": "User", "fields": [
+      {"name": "id", "type": "long"},
+      {"name": "email", "type": ["null", "string"]}
+    ]}},
+    {"name": "at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+    {"name": "score", "type": "double"},
+    {"name": "tags", "type": {"type": "array", "items": "string"}},
+    {"name": "labels", "type": {"type": "map", "values": "int"}},
+    {"name": "hash", "type": {"type": "fixed", "name": "Hash", "size": 2}},
+    {"name": "previous", "type": ["null", "com.example.User"]}
+  ]
+}`
+
+// appendTestAvroEvent appends an Event record.
+func appendTestAvroEvent(b []byte, kind, id int64, email string) []byte {
+	b = avroLong(b, kind)
+	b = avroLong(b, id)
+	if email == "" {
+		b = avroLong(b, 0)
+	} else {
+		b = avroString(avroLong(b, 1), email)
+	}
+	b = avroLong(b, 1700000000000)
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(0.5))
+	b = avroString(avroLong(b, 2), "a") // tags, one block
+	b = avroString(b, "b")
+	b = avroLong(b, 0)
+	b = avroLong(b, -1) // labels, one block with a size
+	b = avroLong(b, 3)
+	b = avroLong(avroString(b, "x"), 7)
+	b = avroLong(b, 0)
+	b = append(b, 0xca, 0xfe)
+	b = avroLong(avroLong(b, 1), 9) // previous: User{id: 9, email: null}
+	return avroLong(b, 0)
+}
+
+func TestAvroResolver_Resolve(t *testing.T) {
+	r := &AvroResolver{}
+
+	records := appendTestAvroEvent(nil, 0, 1, "alice@example.com")
+	records = appendTestAvroEvent(records, 1, 2, "")
+
+	for _, codec := range []string{"null", "deflate"} {
+		p := createAvroTestFile(t, testAvroSchema, codec, 2, records)
+
+		cases := []struct {
+			name     string
+			key      string
+			expected string
+		}{
+			{"Enum", "type", "LOGIN"},
+			{"Nested field", "user.id", "1"},
+			{"Union branch", "user.email", "alice@example.com"},
+			{"Timestamp", "at", "2023-11-14T22:13:20Z"},
+			{"Double", "score", "0.5"},
+			{"Array element", "tags.1", "b"},
+			{"Map", "labels", `{"x":7}`},
+			{"Fixed", "hash", "yv4="},
+			{"Named type reference", "previous.id", "9"},
+			{"Null union", "previous.email", "null"},
+			{"Record index", "1.user.id", "2"},
+			{"Record filter", "[type=LOGOUT].user.email", "null"},
+		}
+		for _, tc := range cases {
+			t.Run(codec+"/"+tc.name, func(t *testing.T) {
+				val, err := r.Resolve(p + "//" + tc.key)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, val)
+			})
+		}
+
+		t.Run(codec+"/All records", func(t *testing.T) {
+			val, err := r.Resolve(p)
+			require.NoError(t, err)
+			assert.Contains(t, val, `"email":"alice@example.com"`)
+			assert.Contains(t, val, `"type":"LOGOUT"`)
+		})
+	}
+
+	p := createAvroTestFile(t, testAvroSchema, "null", 2, records)
+
+	t.Run("Missing key", func(t *testing.T) {
+		_, err := r.Resolve(p + "//user.name")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Record index out of range", func(t *testing.T) {
+		_, err := r.Resolve(p + "//2.user.id")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Unsupported codec", func(t *testing.T) {
+		bad := createAvroTestFile(t, testAvroSchema, "snappy", 2, records)
+		_, err := r.Resolve(bad + "//type")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		data, err := os.ReadFile(p)
+		require.NoError(t, err)
+		bad := filepath.Join(t.TempDir(), "bad.avro")
+		require.NoError(t, os.WriteFile(bad, data[:len(data)-30], 0o600))
+		_, err = r.Resolve(bad + "//1.type")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Not Avro", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.avro")
+		require.NoError(t, os.WriteFile(bad, []byte(`{"type":"LOGIN"}`), 0o600))
+		_, err := r.Resolve(bad + "//type")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "missing.avro") + "//type")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,120 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// defaultAzureIdentityEndpoint is the Azure Instance Metadata Service token endpoint.
+const defaultAzureIdentityEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// AzureBlobResolver resolves the content of an Azure Blob Storage object.
+// Format: "azblob:<account>/<container>/<blob path>" or with a selector,
+// "azblob:<account>/<container>/<blob path>//key.path", which is applied according
+// to the blob's extension (.json, .yaml, .toml, .ini, .xml, otherwise key=value lines).
+//
+// Authentication uses a SAS token if one is configured (SASToken or
+// $AZURE_STORAGE_SAS_TOKEN), otherwise a managed identity token from the instance
+// metadata service ($AZURE_CLIENT_ID selects a user-assigned identity).
+type AzureBlobResolver struct {
+	SASToken string
+	// Endpoint overrides "https://<account>.blob.core.windows.net", e.g. for Azurite
+	// ("http://127.0.0.1:10000/devstoreaccount1").
+	Endpoint string
+	// IdentityEndpoint overrides the managed identity token endpoint.
+	IdentityEndpoint string
+	Client           *http.Client
+}
+
+func (r *AzureBlobResolver) Resolve(value string) (string, error) {
+	return r.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context bounding the token and blob requests.
+func (r *AzureBlobResolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	location, keyPath := splitFileAndKey(value)
+	parts := strings.SplitN(strings.Trim(strings.TrimSpace(location), "/"), "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("%w: azblob reference %q must be account/container/blob", ErrBadPath, location)
+	}
+	account, container, blob := parts[0], parts[1], parts[2]
+
+	endpoint := r.Endpoint
+	if endpoint == "" {
+		endpoint = "https://" + account + ".blob.core.windows.net"
+	}
+	u, err := url.Parse(strings.TrimRight(endpoint, "/") + "/" + url.PathEscape(container) + "/" + escapeBlobPath(blob))
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid azblob endpoint: %v", ErrBadPath, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("build azblob request: %w", err)
+	}
+	req.Header.Set("x-ms-version", "2021-08-06")
+
+	if sas := strings.TrimPrefix(firstNonEmpty(r.SASToken, os.Getenv("AZURE_STORAGE_SAS_TOKEN")), "?"); sas != "" {
+		req.URL.RawQuery = sas
+	} else {
+		token, err := r.managedIdentityToken(ctx)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	data, err := doHTTP(r.Client, req)
+	if err != nil {
+		return "", err
+	}
+	return selectByExtension(blob, data, keyPath)
+}
+
+// Describe reports the resolver metadata.
+func (r *AzureBlobResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile, CapabilityRemote}}
+}
+
+// managedIdentityToken fetches an access token for Azure Storage from the metadata service.
+func (r *AzureBlobResolver) managedIdentityToken(ctx context.Context) (string, error) {
+	q := url.Values{}
+	q.Set("api-version", "2018-02-01")
+	q.Set("resource", "https://storage.azure.com/")
+	if id := os.Getenv("AZURE_CLIENT_ID"); id != "" {
+		q.Set("client_id", id)
+	}
+	endpoint := firstNonEmpty(r.IdentityEndpoint, defaultAzureIdentityEndpoint)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("build managed identity request: %w", err)
+	}
+	req.Header.Set("Metadata", "true")
+
+	body, err := doHTTP(r.Client, req)
+	if err != nil {
+		return "", fmt.Errorf("managed identity token: %w", err)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &tok); err != nil || tok.AccessToken == "" {
+		return "", fmt.Errorf("%w: managed identity returned no access token", ErrForbidden)
+	}
+	return tok.AccessToken, nil
+}
+
+// escapeBlobPath escapes each segment of a blob name, keeping '/' separators.
+func escapeBlobPath(p string) string {
+	segs := strings.Split(p, "/")
+	for i, s := range segs {
+		segs[i] = url.PathEscape(s)
+	}
+	return strings.Join(segs, "/")
+}
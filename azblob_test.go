@@ -0,0 +1,90 @@
+package resolver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureBlobResolver_Resolve(t *testing.T) {
+	blobs := map[string]string{
+		"/configs/app/config.json": `{"server":{"host":"blob.local"}}`,
+		"/configs/app.env":         "TOKEN=abc\n",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/identity":
+			if req.Header.Get("Metadata") != "true" || req.URL.Query().Get("resource") != "https://storage.azure.com/" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"mi-token"}`))
+			return
+		}
+		authorized := req.URL.Query().Get("sig") == "good" || req.Header.Get("Authorization") == "Bearer mi-token"
+		if !authorized {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, ok := blobs[req.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	t.Run("SAS token with JSON selector", func(t *testing.T) {
+		r := &AzureBlobResolver{Endpoint: srv.URL, SASToken: "?sv=2021&sig=good"}
+		val, err := r.Resolve("acct/configs/app/config.json//server.host")
+		require.NoError(t, err)
+		assert.Equal(t, "blob.local", val)
+	})
+
+	t.Run("Whole blob", func(t *testing.T) {
+		r := &AzureBlobResolver{Endpoint: srv.URL, SASToken: "sig=good"}
+		val, err := r.Resolve("acct/configs/app.env")
+		require.NoError(t, err)
+		assert.Equal(t, "TOKEN=abc", val)
+	})
+
+	t.Run("Managed identity", func(t *testing.T) {
+		t.Setenv("AZURE_STORAGE_SAS_TOKEN", "")
+		r := &AzureBlobResolver{Endpoint: srv.URL, IdentityEndpoint: srv.URL + "/identity"}
+		val, err := r.Resolve("acct/configs/app.env//TOKEN")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", val)
+	})
+
+	t.Run("SAS token from environment", func(t *testing.T) {
+		t.Setenv("AZURE_STORAGE_SAS_TOKEN", "sig=good")
+		r := &AzureBlobResolver{Endpoint: srv.URL}
+		val, err := r.Resolve("acct/configs/app.env//TOKEN")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", val)
+	})
+
+	t.Run("Missing blob", func(t *testing.T) {
+		r := &AzureBlobResolver{Endpoint: srv.URL, SASToken: "sig=good"}
+		_, err := r.Resolve("acct/configs/nope.json")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Bad SAS token", func(t *testing.T) {
+		r := &AzureBlobResolver{Endpoint: srv.URL, SASToken: "sig=bad"}
+		_, err := r.Resolve("acct/configs/app.env")
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Malformed reference", func(t *testing.T) {
+		r := &AzureBlobResolver{Endpoint: srv.URL}
+		for _, in := range []string{"acct", "acct/configs", "acct//blob"} {
+			_, err := r.Resolve(in)
+			require.ErrorIs(t, err, ErrBadPath, in)
+		}
+	})
+}
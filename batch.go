@@ -0,0 +1,60 @@
+package resolver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// BatchOption configures ResolveSlice, ResolveSliceBestEffort and ResolveMap.
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	templates bool
+}
+
+// Templates makes batch resolution treat each value containing "${" as an
+// interpolation template (see ResolveString), so mixed values like
+// "host=${env:HOST}:5432" resolve. Values without "${" are still resolved as bare tokens.
+func Templates() BatchOption {
+	return func(o *batchOptions) { o.templates = true }
+}
+
+// newBatchOptions applies opts to the defaults.
+func newBatchOptions(opts []BatchOption) batchOptions {
+	var o batchOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// resolveElement resolves one value of a batch.
+func (r *Registry) resolveElement(v string, o batchOptions) (string, error) {
+	if o.templates && strings.Contains(v, "${") {
+		return r.ResolveString(v)
+	}
+	return r.ResolveVariable(v)
+}
+
+// ResolveMap resolves each value of values with the same rules as ResolveSlice
+// (strict) and returns a new map with the same keys. Values are resolved in key
+// order, so the reported error is deterministic.
+func (r *Registry) ResolveMap(values map[string]string, opts ...BatchOption) (map[string]string, error) {
+	o := newBatchOptions(opts)
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	out := make(map[string]string, len(values))
+	for _, k := range keys {
+		s, err := r.resolveElement(values[k], o)
+		if err != nil {
+			return nil, fmt.Errorf("resolve map key %q (%q): %w", k, values[k], err)
+		}
+		out[k] = s
+	}
+	return out, nil
+}
@@ -0,0 +1,49 @@
+package resolver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_BatchTemplates(t *testing.T) {
+	r := NewRegistry()
+	r.Register("x:", ResolverFunc(func(v string) (string, error) { return "X" + v, nil }))
+	r.Register("fail:", ResolverFunc(func(string) (string, error) { return "", errors.New("boom") }))
+
+	t.Run("Slice without option keeps templates literal", func(t *testing.T) {
+		got, err := r.ResolveSlice([]string{"host=${x:a}", "x:b"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"host=${x:a}", "Xb"}, got)
+	})
+
+	t.Run("Slice with templates", func(t *testing.T) {
+		got, err := r.ResolveSlice([]string{"host=${x:a}:5432", "x:b", "plain"}, Templates())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"host=Xa:5432", "Xb", "plain"}, got)
+	})
+
+	t.Run("Best effort with templates", func(t *testing.T) {
+		got, errs := r.ResolveSliceBestEffort([]string{"${x:a}-${x:b}", "v=${fail:c}"}, Templates())
+		assert.Equal(t, []string{"Xa-Xb", ""}, got)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "index 1")
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		got, err := r.ResolveMap(map[string]string{
+			"DSN":  "postgres://${x:user}@db:5432",
+			"HOST": "x:host",
+		}, Templates())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"DSN": "postgres://Xuser@db:5432", "HOST": "Xhost"}, got)
+	})
+
+	t.Run("Map reports first failing key", func(t *testing.T) {
+		_, err := r.ResolveMap(map[string]string{"B": "fail:b", "A": "fail:a", "C": "x:c"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `key "A"`)
+	})
+}
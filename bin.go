@@ -0,0 +1,68 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// BinaryResolver resolves the content of a binary file, encoded as text. Unlike the
+// other file schemes, the content is not trimmed or otherwise interpreted.
+// Format: "bin:/path/file", e.g. "bin+base64:/etc/tls/key.der" or
+// "bin+hex:/var/lib/app/seed".
+//
+// Encoding is "base64" (standard, padded; the default), "base64url" (URL-safe, padded)
+// or "hex". Files are read up to DefaultMaxOutputSize bytes.
+type BinaryResolver struct {
+	Encoding string
+}
+
+func (r *BinaryResolver) Resolve(value string) (string, error) {
+	filePath := os.ExpandEnv(value)
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	var encode func([]byte) string
+	switch r.Encoding {
+	case "", "base64":
+		encode = base64.StdEncoding.EncodeToString
+	case "base64url":
+		encode = base64.URLEncoding.EncodeToString
+	case "hex":
+		encode = hex.EncodeToString
+	default:
+		return "", fmt.Errorf("%w: unknown binary encoding %q", ErrBadPath, r.Encoding)
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to open file %q: %w", filePath, err)
+	}
+	defer f.Close() // nolint:errcheck
+
+	data, err := io.ReadAll(io.LimitReader(f, DefaultMaxOutputSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %q: %w", filePath, err)
+	}
+	if len(data) > DefaultMaxOutputSize {
+		return "", fmt.Errorf("%w: file %q is larger than %d bytes", ErrTooLarge, filePath, DefaultMaxOutputSize)
+	}
+	return encode(data), nil
+}
+
+// Describe reports the resolver metadata.
+func (r *BinaryResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilityWholeFile}}
+}
@@ -0,0 +1,64 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryResolver_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "key.der")
+	// Leading and trailing whitespace bytes must survive.
+	require.NoError(t, os.WriteFile(p, []byte{'\n', 0x00, 0xfb, 0xff, ' '}, 0o600))
+
+	cases := []struct {
+		name     string
+		encoding string
+		expected string
+	}{
+		{"Default", "", "CgD7/yA="},
+		{"Base64", "base64", "CgD7/yA="},
+		{"Base64 URL", "base64url", "CgD7_yA="},
+		{"Hex", "hex", "0a00fbff20"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := (&BinaryResolver{Encoding: tc.encoding}).Resolve(p)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, val)
+		})
+	}
+
+	t.Run("Registered schemes", func(t *testing.T) {
+		r := NewExtendedRegistry()
+		val, err := r.ResolveVariable("bin+hex:" + p)
+		require.NoError(t, err)
+		assert.Equal(t, "0a00fbff20", val)
+
+		val, err = r.ResolveVariable("bin:" + p)
+		require.NoError(t, err)
+		assert.Equal(t, "CgD7/yA=", val)
+	})
+
+	t.Run("Empty file", func(t *testing.T) {
+		empty := filepath.Join(dir, "empty")
+		require.NoError(t, os.WriteFile(empty, nil, 0o600))
+		val, err := (&BinaryResolver{}).Resolve(empty)
+		require.NoError(t, err)
+		assert.Equal(t, "", val)
+	})
+
+	t.Run("Unknown encoding", func(t *testing.T) {
+		_, err := (&BinaryResolver{Encoding: "base32"}).Resolve(p)
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := (&BinaryResolver{}).Resolve(filepath.Join(dir, "missing"))
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
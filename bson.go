@@ -0,0 +1,320 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// BSONResolver resolves a value by decoding a BSON file and extracting a nested key.
+// Format: "bson:/path/file.bson//key1.key2.keyN", e.g. "bson:/backup/settings.bson//smtp.host".
+//
+// A file holding several documents (mongodump output) is a list, so select with an
+// index or a filter ("bson:/dump/users.bson//[name=admin].email"); a single document is
+// navigated directly. ObjectIds are returned as hex, dates in RFC 3339 (UTC), binary
+// data as base64 and Decimal128 as its decimal string. Strings are returned as-is,
+// other values as JSON. If no key is provided, returns the whole file as JSON.
+type BSONResolver struct{}
+
+func (r *BSONResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read BSON file %q: %w", filePath, err)
+	}
+
+	return selectBSON(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *BSONResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectBSON returns the value at keyPath in BSON data, or all documents if keyPath
+// is empty. source names the document in error messages.
+func selectBSON(data []byte, keyPath, source string) (string, error) {
+	var docs []any
+	for rest := data; len(rest) > 0; {
+		doc, n, err := decodeBSONDocument(rest, 0)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse BSON in %q: %w", source, err)
+		}
+		docs = append(docs, doc)
+		rest = rest[n:]
+	}
+	if len(docs) == 0 {
+		return "", fmt.Errorf("failed to parse BSON in %q: no documents", source)
+	}
+
+	var content any = docs
+	if len(docs) == 1 {
+		content = docs[0]
+	}
+	val := content
+	if keyPath != "" {
+		var err error
+		val, err = selectPath(content, keyPath)
+		if err != nil {
+			return "", fmt.Errorf("%w: key path %q in BSON %q: %v", ErrNotFound, keyPath, source, err)
+		}
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	out, _ := json.Marshal(val)
+	return string(out), nil
+}
+
+// maxBSONDepth bounds the nesting of documents and arrays.
+const maxBSONDepth = 512
+
+// errBSONShort reports data ending inside a document.
+var errBSONShort = errors.New("unexpected end of data")
+
+// decodeBSONDocument decodes the document at the start of data and returns it with
+// its length in bytes.
+func decodeBSONDocument(data []byte, depth int) (map[string]any, int, error) {
+	if depth > maxBSONDepth {
+		return nil, 0, errors.New("nested too deeply")
+	}
+	if len(data) < 5 {
+		return nil, 0, errBSONShort
+	}
+	size := int(binary.LittleEndian.Uint32(data))
+	if size < 5 || size > len(data) {
+		return nil, 0, errBSONShort
+	}
+	if data[size-1] != 0 {
+		return nil, 0, errors.New("document not terminated")
+	}
+
+	doc := make(map[string]any)
+	body := data[4 : size-1]
+	for len(body) > 0 {
+		typ := body[0]
+		name, rest, err := bsonCString(body[1:])
+		if err != nil {
+			return nil, 0, err
+		}
+		v, n, err := decodeBSONValue(typ, rest, depth)
+		if err != nil {
+			return nil, 0, fmt.Errorf("field %q: %w", name, err)
+		}
+		doc[name] = v
+		body = rest[n:]
+	}
+	return doc, size, nil
+}
+
+// decodeBSONValue decodes an element value of type typ at the start of data and
+// returns it with its length in bytes.
+func decodeBSONValue(typ byte, data []byte, depth int) (any, int, error) {
+	fixed := func(n int) ([]byte, error) {
+		if len(data) < n {
+			return nil, errBSONShort
+		}
+		return data[:n], nil
+	}
+
+	switch typ {
+	case 0x01: // double
+		b, err := fixed(8)
+		if err != nil {
+			return nil, 0, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), 8, nil
+	case 0x02, 0x0d, 0x0e: // string, JavaScript code, symbol
+		return bsonString(data)
+	case 0x03, 0x04: // document, array
+		doc, n, err := decodeBSONDocument(data, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		if typ == 0x03 {
+			return doc, n, nil
+		}
+		arr := make([]any, len(doc))
+		for i := range arr {
+			v, ok := doc[fmt.Sprint(i)]
+			if !ok {
+				return nil, 0, errors.New("array keys are not 0..n-1")
+			}
+			arr[i] = v
+		}
+		return arr, n, nil
+	case 0x05: // binary
+		b, err := fixed(5)
+		if err != nil {
+			return nil, 0, err
+		}
+		n := int(binary.LittleEndian.Uint32(b))
+		if n < 0 || n > len(data)-5 {
+			return nil, 0, errBSONShort
+		}
+		return base64.StdEncoding.EncodeToString(data[5 : 5+n]), 5 + n, nil
+	case 0x06, 0x0a: // undefined, null
+		return nil, 0, nil
+	case 0x07: // ObjectId
+		b, err := fixed(12)
+		if err != nil {
+			return nil, 0, err
+		}
+		return hex.EncodeToString(b), 12, nil
+	case 0x08: // boolean
+		b, err := fixed(1)
+		if err != nil {
+			return nil, 0, err
+		}
+		return b[0] != 0, 1, nil
+	case 0x09: // UTC datetime, milliseconds since the epoch
+		b, err := fixed(8)
+		if err != nil {
+			return nil, 0, err
+		}
+		ms := int64(binary.LittleEndian.Uint64(b))
+		return time.UnixMilli(ms).UTC().Format(time.RFC3339Nano), 8, nil
+	case 0x0b: // regular expression
+		pattern, rest, err := bsonCString(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		options, rest2, err := bsonCString(rest)
+		if err != nil {
+			return nil, 0, err
+		}
+		return "/" + pattern + "/" + options, len(data) - len(rest2), nil
+	case 0x0c: // DBPointer: namespace and ObjectId
+		ns, n, err := bsonString(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(data) < n+12 {
+			return nil, 0, errBSONShort
+		}
+		return map[string]any{"$ref": ns, "$id": hex.EncodeToString(data[n : n+12])}, n + 12, nil
+	case 0x0f: // JavaScript code with scope
+		b, err := fixed(4)
+		if err != nil {
+			return nil, 0, err
+		}
+		total := int(binary.LittleEndian.Uint32(b))
+		if total < 4 || total > len(data) {
+			return nil, 0, errBSONShort
+		}
+		code, _, err := bsonString(data[4:total])
+		return code, total, err
+	case 0x10: // int32
+		b, err := fixed(4)
+		if err != nil {
+			return nil, 0, err
+		}
+		return int64(int32(binary.LittleEndian.Uint32(b))), 4, nil
+	case 0x11: // timestamp
+		b, err := fixed(8)
+		if err != nil {
+			return nil, 0, err
+		}
+		return binary.LittleEndian.Uint64(b), 8, nil
+	case 0x12: // int64
+		b, err := fixed(8)
+		if err != nil {
+			return nil, 0, err
+		}
+		return int64(binary.LittleEndian.Uint64(b)), 8, nil
+	case 0x13: // Decimal128
+		b, err := fixed(16)
+		if err != nil {
+			return nil, 0, err
+		}
+		return decimal128String(binary.LittleEndian.Uint64(b[8:]), binary.LittleEndian.Uint64(b)), 16, nil
+	case 0xff:
+		return map[string]any{"$minKey": int64(1)}, 0, nil
+	case 0x7f:
+		return map[string]any{"$maxKey": int64(1)}, 0, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported element type 0x%02x", typ)
+}
+
+// bsonCString reads a NUL-terminated string and returns it with the remaining data.
+func bsonCString(data []byte) (string, []byte, error) {
+	for i, c := range data {
+		if c == 0 {
+			return string(data[:i]), data[i+1:], nil
+		}
+	}
+	return "", nil, errBSONShort
+}
+
+// bsonString reads a length-prefixed, NUL-terminated string and returns it with its
+// length in bytes.
+func bsonString(data []byte) (any, int, error) {
+	if len(data) < 4 {
+		return nil, 0, errBSONShort
+	}
+	n := int(binary.LittleEndian.Uint32(data))
+	if n < 1 || n > len(data)-4 || data[3+n] != 0 {
+		return nil, 0, errBSONShort
+	}
+	return string(data[4 : 3+n]), 4 + n, nil
+}
+
+// decimal128String formats an IEEE 754-2008 decimal128 (BID encoding) value.
+func decimal128String(high, low uint64) string {
+	sign := ""
+	if high>>63 != 0 {
+		sign = "-"
+	}
+	var (
+		exp  int
+		coef = new(big.Int)
+	)
+	switch {
+	case (high>>58)&0x1f == 0x1f:
+		return "NaN"
+	case (high>>58)&0x1f == 0x1e:
+		return sign + "Infinity"
+	case (high>>61)&3 == 3:
+		// Coefficients in this form exceed the maximum and are treated as zero.
+		exp = int((high>>47)&0x3fff) - 6176
+	default:
+		exp = int((high>>49)&0x3fff) - 6176
+		coef.SetUint64(high & (1<<49 - 1))
+		coef.Lsh(coef, 64)
+		coef.Or(coef, new(big.Int).SetUint64(low))
+	}
+
+	digits := coef.String()
+	switch {
+	case exp == 0:
+		return sign + digits
+	case exp > 0:
+		return sign + digits + "E+" + fmt.Sprint(exp)
+	}
+	point := len(digits) + exp
+	if point <= 0 {
+		return sign + "0." + strings.Repeat("0", -point) + digits
+	}
+	return sign + digits[:point] + "." + digits[point:]
+}
@@ -0,0 +1,145 @@
+package resolver
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bsonDoc builds a BSON document from encoded elements.
+func bsonDoc(elems ...[]byte) []byte {
+	var body []byte
+	for _, e := range elems {
+		body = append(body, e...)
+	}
+	out := binary.LittleEndian.AppendUint32(nil, uint32(len(body)+5))
+	out = append(out, body...)
+	return append(out, 0)
+}
+
+// bsonElem encodes an element of type typ.
+func bsonElem(typ byte, name string, payload []byte) []byte {
+	out := append([]byte{typ}, name...)
+	out = append(out, 0)
+	return append(out, payload...)
+}
+
+// bsonStr encodes a string payload.
+func bsonStr(s string) []byte {
+	out := binary.LittleEndian.AppendUint32(nil, uint32(len(s)+1))
+	out = append(out, s...)
+	return append(out, 0)
+}
+
+func TestBSONResolver_Resolve(t *testing.T) {
+	oid := []byte{0x65, 0x53, 0xf1, 0x00, 1, 2, 3, 4, 5, 6, 7, 8}
+	settings := bsonDoc(
+		bsonElem(0x07, "_id", oid),
+		bsonElem(0x03, "smtp", bsonDoc(
+			bsonElem(0x02, "host", bsonStr("mail.internal")),
+			bsonElem(0x10, "port", binary.LittleEndian.AppendUint32(nil, 587)),
+		)),
+		bsonElem(0x04, "tags", bsonDoc(
+			bsonElem(0x02, "0", bsonStr("a")),
+			bsonElem(0x02, "1", bsonStr("b")),
+		)),
+		bsonElem(0x01, "ratio", binary.LittleEndian.AppendUint64(nil, math.Float64bits(0.5))),
+		bsonElem(0x08, "enabled", []byte{1}),
+		bsonElem(0x09, "updated", binary.LittleEndian.AppendUint64(nil, 1700000000000)),
+		bsonElem(0x12, "big", binary.LittleEndian.AppendUint64(nil, 1<<40)),
+		bsonElem(0x05, "blob", append(binary.LittleEndian.AppendUint32(nil, 2), 0, 'h', 'i')),
+		bsonElem(0x0a, "none", nil),
+		// 12.345 = coefficient 12345, exponent -3
+		bsonElem(0x13, "price", append(
+			binary.LittleEndian.AppendUint64(nil, 12345),
+			binary.LittleEndian.AppendUint64(nil, uint64(6176-3)<<49)...)),
+	)
+
+	dir := t.TempDir()
+	single := filepath.Join(dir, "settings.bson")
+	require.NoError(t, os.WriteFile(single, settings, 0o666))
+	r := &BSONResolver{}
+
+	cases := []struct {
+		name, key, want string
+	}{
+		{"ObjectId", "_id", "6553f1000102030405060708"},
+		{"Nested string", "smtp.host", "mail.internal"},
+		{"Int32", "smtp.port", "587"},
+		{"Array index", "tags.1", "b"},
+		{"Double", "ratio", "0.5"},
+		{"Bool", "enabled", "true"},
+		{"Date", "updated", "2023-11-14T22:13:20Z"},
+		{"Int64", "big", "1099511627776"},
+		{"Binary", "blob", "aGk="},
+		{"Null", "none", "null"},
+		{"Decimal128", "price", "12.345"},
+		{"Document as JSON", "smtp", `{"host":"mail.internal","port":587}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(single + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("Several documents", func(t *testing.T) {
+		dump := filepath.Join(dir, "users.bson")
+		data := append(
+			bsonDoc(bsonElem(0x02, "name", bsonStr("admin")), bsonElem(0x02, "email", bsonStr("admin@example.com"))),
+			bsonDoc(bsonElem(0x02, "name", bsonStr("bob")), bsonElem(0x02, "email", bsonStr("bob@example.com")))...)
+		require.NoError(t, os.WriteFile(dump, data, 0o666))
+
+		val, err := r.Resolve(dump + "//[name=bob].email")
+		require.NoError(t, err)
+		assert.Equal(t, "bob@example.com", val)
+
+		val, err = r.Resolve(dump + "//0.name")
+		require.NoError(t, err)
+		assert.Equal(t, "admin", val)
+
+		val, err = r.Resolve(dump)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"name":"admin","email":"admin@example.com"},{"name":"bob","email":"bob@example.com"}]`, val)
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		_, err := r.Resolve(single + "//smtp.nope")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		bad := filepath.Join(dir, "bad.bson")
+		require.NoError(t, os.WriteFile(bad, settings[:30], 0o666))
+		_, err := r.Resolve(bad + "//smtp")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(dir, "nope.bson") + "//smtp")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestDecimal128String(t *testing.T) {
+	cases := []struct {
+		high, low uint64
+		want      string
+	}{
+		{uint64(6176) << 49, 42, "42"},
+		{1<<63 | uint64(6176-2)<<49, 5, "-0.05"},
+		{uint64(6176+3) << 49, 7, "7E+3"},
+		{0x1f << 58, 0, "NaN"},
+		{1<<63 | 0x1e<<58, 0, "-Infinity"},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, decimal128String(tc.high, tc.low))
+	}
+}
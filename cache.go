@@ -0,0 +1,200 @@
+package resolver
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CacheOptions configures a CachedResolver.
+type CacheOptions struct {
+	// TTL is how long a resolved value is fresh. Zero or negative disables caching.
+	TTL time.Duration
+	// StaleWhileRevalidate serves a stale value immediately and refreshes it in the
+	// background, instead of blocking the caller on the backend.
+	StaleWhileRevalidate bool
+	// MaxStale bounds how long past TTL a stale value may be served in
+	// stale-while-revalidate mode. Older values are resolved synchronously.
+	MaxStale time.Duration
+	// Store holds cached values. Nil uses a private in-memory store; use
+	// NewFileCacheStore to persist values across process runs.
+	Store CacheStore
+	// Scheme and Version are part of every cache key, so a shared or persistent
+	// store keeps entries of different schemes and source versions (e.g. backend
+	// address or config revision) apart. Changing Version invalidates old entries.
+	Scheme  string
+	Version string
+	// Clock decides freshness; defaults to SystemClock.
+	Clock Clock
+}
+
+// CacheEntry is a cached value and the time it was resolved.
+type CacheEntry struct {
+	Value    string    `json:"value"`
+	StoredAt time.Time `json:"storedAt"`
+}
+
+// CacheStore is a storage backend for CachedResolver. Implementations must be safe
+// for concurrent use. Keys are opaque strings built by CachedResolver.
+type CacheStore interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, e CacheEntry) error
+	Delete(key string)
+	Purge()
+}
+
+// CachedResolver wraps a Resolver and caches successful results per input value.
+// Errors are never cached. It is safe for concurrent use.
+//
+//	vault := resolver.NewCachedResolver(myVaultResolver, resolver.CacheOptions{
+//		TTL:                  time.Minute,
+//		StaleWhileRevalidate: true,
+//		MaxStale:             10 * time.Minute,
+//	})
+//	resolver.RegisterResolver("vault:", vault)
+type CachedResolver struct {
+	inner Resolver
+	opts  CacheOptions
+	now   func() time.Time
+
+	store CacheStore
+
+	mu         sync.Mutex
+	refreshing map[string]struct{} // keys with a background refresh in flight
+}
+
+// NewCachedResolver returns a caching wrapper around inner.
+func NewCachedResolver(inner Resolver, opts CacheOptions) *CachedResolver {
+	store := opts.Store
+	if store == nil {
+		store = newMemoryCacheStore()
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &CachedResolver{
+		inner:      inner,
+		opts:       opts,
+		now:        clock.Now,
+		store:      store,
+		refreshing: make(map[string]struct{}),
+	}
+}
+
+// Resolve returns a cached value if it is fresh (or stale but servable), otherwise
+// it resolves value through the wrapped resolver and caches the result.
+func (c *CachedResolver) Resolve(value string) (string, error) {
+	return c.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context, forwarded to the wrapped resolver if it
+// implements ContextResolver. Background refreshes keep ctx's values but not its deadline.
+func (c *CachedResolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	if c.opts.TTL <= 0 {
+		return resolveWith(ctx, c.inner, value)
+	}
+
+	if e, ok := c.store.Get(c.cacheKey(value)); ok {
+		age := c.now().Sub(e.StoredAt)
+		if age < c.opts.TTL {
+			return e.Value, nil
+		}
+		if c.opts.StaleWhileRevalidate && age < c.opts.TTL+c.opts.MaxStale {
+			c.mu.Lock()
+			if _, busy := c.refreshing[value]; !busy {
+				c.refreshing[value] = struct{}{}
+				go c.refresh(context.WithoutCancel(ctx), value)
+			}
+			c.mu.Unlock()
+			return e.Value, nil
+		}
+	}
+
+	res, err := resolveWith(ctx, c.inner, value)
+	if err != nil {
+		return "", err
+	}
+	c.put(value, res)
+	return res, nil
+}
+
+// Invalidate drops the cached value for value, if any.
+func (c *CachedResolver) Invalidate(value string) {
+	c.store.Delete(c.cacheKey(value))
+}
+
+// Purge drops all cached values from the store.
+func (c *CachedResolver) Purge() {
+	c.store.Purge()
+}
+
+// Describe reports the wrapped resolver's metadata.
+func (c *CachedResolver) Describe() ResolverMeta {
+	if d, ok := c.inner.(Describer); ok {
+		return d.Describe()
+	}
+	return ResolverMeta{}
+}
+
+// refresh re-resolves value in the background. On failure the stale value is kept
+// until it exceeds MaxStale.
+func (c *CachedResolver) refresh(ctx context.Context, value string) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.refreshing, value)
+		c.mu.Unlock()
+	}()
+
+	res, err := resolveWith(ctx, c.inner, value)
+	if err != nil {
+		return
+	}
+	c.put(value, res)
+}
+
+// put caches res for value as of now. Store failures only cost a cache miss later.
+func (c *CachedResolver) put(value, res string) {
+	_ = c.store.Set(c.cacheKey(value), CacheEntry{Value: res, StoredAt: c.now()})
+}
+
+// cacheKey combines scheme, source version and payload into a store key.
+func (c *CachedResolver) cacheKey(value string) string {
+	return c.opts.Scheme + "\x00" + c.opts.Version + "\x00" + value
+}
+
+// memoryCacheStore is the default in-process CacheStore.
+type memoryCacheStore struct {
+	mu      sync.RWMutex
+	entries map[string]CacheEntry
+}
+
+func newMemoryCacheStore() *memoryCacheStore {
+	return &memoryCacheStore{entries: make(map[string]CacheEntry)}
+}
+
+func (m *memoryCacheStore) Get(key string) (CacheEntry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.entries[key]
+	return e, ok
+}
+
+func (m *memoryCacheStore) Set(key string, e CacheEntry) error {
+	m.mu.Lock()
+	m.entries[key] = e
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *memoryCacheStore) Delete(key string) {
+	m.mu.Lock()
+	delete(m.entries, key)
+	m.mu.Unlock()
+}
+
+func (m *memoryCacheStore) Purge() {
+	m.mu.Lock()
+	m.entries = make(map[string]CacheEntry)
+	m.mu.Unlock()
+}
@@ -0,0 +1,128 @@
+package resolver
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cacheFileExt is the extension of entries written by FileCacheStore.
+const cacheFileExt = ".cache"
+
+// FileCacheStore is a persistent CacheStore keeping one AES-GCM encrypted file per entry
+// in a directory, so short-lived processes can reuse values resolved by earlier runs.
+// File names are HMACs of the cache keys under a key derived from the encryption key,
+// so a guessed token cannot be checked against the directory listing without it;
+// neither keys nor values are stored in clear.
+// Entries that fail to decrypt (wrong key, corruption) are treated as misses.
+type FileCacheStore struct {
+	dir     string
+	aead    cipher.AEAD
+	nameKey []byte // HMAC key for file names
+}
+
+// NewFileCacheStore creates dir (mode 0700) if needed and returns a store encrypting
+// entries with key, which must be 16, 24 or 32 bytes (AES-128/192/256).
+func NewFileCacheStore(dir string, key []byte) (*FileCacheStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cache encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cache encryption key: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create cache dir %q: %w", dir, err)
+	}
+	// Derive the file name key rather than reuse the encryption key for a second purpose.
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("resolver file cache names"))
+	return &FileCacheStore{dir: dir, aead: aead, nameKey: mac.Sum(nil)}, nil
+}
+
+// Get returns the entry for key, or false if it is missing or unreadable.
+func (s *FileCacheStore) Get(key string) (CacheEntry, bool) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	n := s.aead.NonceSize()
+	if len(data) < n {
+		return CacheEntry{}, false
+	}
+	plain, err := s.aead.Open(nil, data[:n], data[n:], []byte(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	var e CacheEntry
+	if err := json.Unmarshal(plain, &e); err != nil {
+		return CacheEntry{}, false
+	}
+	return e, true
+}
+
+// Set encrypts e and atomically replaces the file for key.
+func (s *FileCacheStore) Set(key string, e CacheEntry) error {
+	plain, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode cache entry: %w", err)
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generate cache nonce: %w", err)
+	}
+	// The key is authenticated as additional data, so entries cannot be swapped between files.
+	data := s.aead.Seal(nonce, nonce, plain, []byte(key))
+
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() // nolint:errcheck
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the entry for key.
+func (s *FileCacheStore) Delete(key string) {
+	_ = os.Remove(s.path(key))
+}
+
+// Purge removes all entries written by the store.
+func (s *FileCacheStore) Purge() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, de := range entries {
+		if !de.IsDir() && strings.HasSuffix(de.Name(), cacheFileExt) {
+			_ = os.Remove(filepath.Join(s.dir, de.Name()))
+		}
+	}
+}
+
+// path returns the file holding key.
+func (s *FileCacheStore) path(key string) string {
+	mac := hmac.New(sha256.New, s.nameKey)
+	mac.Write([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(mac.Sum(nil))+cacheFileExt)
+}
@@ -0,0 +1,143 @@
+package resolver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCacheStore(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	entry := CacheEntry{Value: "s3cr3t", StoredAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	t.Run("Round trip", func(t *testing.T) {
+		s, err := NewFileCacheStore(t.TempDir(), key)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Set("vault:\x00v1\x00secret/app", entry))
+		got, ok := s.Get("vault:\x00v1\x00secret/app")
+		require.True(t, ok)
+		assert.Equal(t, entry.Value, got.Value)
+		assert.True(t, entry.StoredAt.Equal(got.StoredAt))
+	})
+
+	t.Run("Values and keys are not stored in clear", func(t *testing.T) {
+		dir := t.TempDir()
+		s, err := NewFileCacheStore(dir, key)
+		require.NoError(t, err)
+		require.NoError(t, s.Set("secret/app", entry))
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.NotContains(t, files[0].Name(), "secret")
+		data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "s3cr3t")
+	})
+
+	t.Run("File names depend on the encryption key", func(t *testing.T) {
+		dirA, dirB := t.TempDir(), t.TempDir()
+		a, err := NewFileCacheStore(dirA, key)
+		require.NoError(t, err)
+		b, err := NewFileCacheStore(dirB, bytes.Repeat([]byte{8}, 32))
+		require.NoError(t, err)
+		require.NoError(t, a.Set("secret/app", entry))
+		require.NoError(t, b.Set("secret/app", entry))
+
+		nameA, nameB := cacheFileName(t, dirA), cacheFileName(t, dirB)
+		assert.NotEqual(t, nameA, nameB)
+		sum := sha256.Sum256([]byte("secret/app"))
+		assert.NotEqual(t, hex.EncodeToString(sum[:])+cacheFileExt, nameA, "a plain hash lets anyone test a guessed key")
+	})
+
+	t.Run("Persists across instances", func(t *testing.T) {
+		dir := t.TempDir()
+		s1, err := NewFileCacheStore(dir, key)
+		require.NoError(t, err)
+		require.NoError(t, s1.Set("k", entry))
+
+		s2, err := NewFileCacheStore(dir, key)
+		require.NoError(t, err)
+		got, ok := s2.Get("k")
+		require.True(t, ok)
+		assert.Equal(t, "s3cr3t", got.Value)
+	})
+
+	t.Run("Wrong key is a miss", func(t *testing.T) {
+		dir := t.TempDir()
+		s1, err := NewFileCacheStore(dir, key)
+		require.NoError(t, err)
+		require.NoError(t, s1.Set("k", entry))
+
+		s2, err := NewFileCacheStore(dir, bytes.Repeat([]byte{8}, 32))
+		require.NoError(t, err)
+		_, ok := s2.Get("k")
+		assert.False(t, ok)
+	})
+
+	t.Run("Delete and purge", func(t *testing.T) {
+		dir := t.TempDir()
+		s, err := NewFileCacheStore(dir, key)
+		require.NoError(t, err)
+		require.NoError(t, s.Set("a", entry))
+		require.NoError(t, s.Set("b", entry))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "unrelated.txt"), []byte("x"), 0o600))
+
+		s.Delete("a")
+		_, ok := s.Get("a")
+		assert.False(t, ok)
+
+		s.Purge()
+		_, ok = s.Get("b")
+		assert.False(t, ok)
+		assert.FileExists(t, filepath.Join(dir, "unrelated.txt"))
+	})
+
+	t.Run("Invalid key length", func(t *testing.T) {
+		_, err := NewFileCacheStore(t.TempDir(), []byte("short"))
+		require.Error(t, err)
+	})
+
+	t.Run("Backs a CachedResolver across runs", func(t *testing.T) {
+		dir := t.TempDir()
+		opts := func() CacheOptions {
+			s, err := NewFileCacheStore(dir, key)
+			require.NoError(t, err)
+			return CacheOptions{TTL: time.Hour, Store: s, Scheme: "v:", Version: "1"}
+		}
+
+		inner := &versionedResolver{}
+		v, err := NewCachedResolver(inner, opts()).Resolve("k")
+		require.NoError(t, err)
+		assert.Equal(t, "k@1", v)
+
+		// A new process with the same store reuses the value.
+		v, err = NewCachedResolver(inner, opts()).Resolve("k")
+		require.NoError(t, err)
+		assert.Equal(t, "k@1", v)
+
+		// A different source version does not.
+		o := opts()
+		o.Version = "2"
+		v, err = NewCachedResolver(inner, o).Resolve("k")
+		require.NoError(t, err)
+		assert.Equal(t, "k@2", v)
+	})
+}
+
+// cacheFileName returns the name of the only file in dir.
+func cacheFileName(t *testing.T, dir string) string {
+	t.Helper()
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	return files[0].Name()
+}
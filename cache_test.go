@@ -0,0 +1,190 @@
+package resolver
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock for cache tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+}
+
+// versionedResolver returns "<value>@<n>" where n counts calls; it fails while fail is set.
+type versionedResolver struct {
+	calls atomic.Int32
+	fail  atomic.Bool
+	gate  chan struct{} // if non-nil, each call waits for a receive
+}
+
+func (v *versionedResolver) Resolve(value string) (string, error) {
+	if v.gate != nil {
+		<-v.gate
+	}
+	n := v.calls.Add(1)
+	if v.fail.Load() {
+		return "", errors.New("backend down")
+	}
+	return value + "@" + string(rune('0'+n)), nil
+}
+
+func newTestCache(inner Resolver, opts CacheOptions) (*CachedResolver, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewCachedResolver(inner, opts)
+	c.now = clock.Now
+	return c, clock
+}
+
+func TestCachedResolver_Resolve(t *testing.T) {
+	t.Run("Fresh values are cached", func(t *testing.T) {
+		inner := &versionedResolver{}
+		c, clock := newTestCache(inner, CacheOptions{TTL: time.Minute})
+
+		v1, err := c.Resolve("k")
+		require.NoError(t, err)
+		clock.Advance(30 * time.Second)
+		v2, err := c.Resolve("k")
+		require.NoError(t, err)
+
+		assert.Equal(t, "k@1", v1)
+		assert.Equal(t, v1, v2)
+		assert.Equal(t, int32(1), inner.calls.Load())
+	})
+
+	t.Run("Expired values are resolved synchronously", func(t *testing.T) {
+		inner := &versionedResolver{}
+		c, clock := newTestCache(inner, CacheOptions{TTL: time.Minute})
+
+		_, _ = c.Resolve("k")
+		clock.Advance(time.Minute)
+		v, err := c.Resolve("k")
+		require.NoError(t, err)
+		assert.Equal(t, "k@2", v)
+	})
+
+	t.Run("Errors are not cached", func(t *testing.T) {
+		inner := &versionedResolver{}
+		inner.fail.Store(true)
+		c, _ := newTestCache(inner, CacheOptions{TTL: time.Minute})
+
+		_, err := c.Resolve("k")
+		require.Error(t, err)
+		inner.fail.Store(false)
+		v, err := c.Resolve("k")
+		require.NoError(t, err)
+		assert.Equal(t, "k@2", v)
+	})
+
+	t.Run("Zero TTL disables caching", func(t *testing.T) {
+		inner := &versionedResolver{}
+		c, _ := newTestCache(inner, CacheOptions{})
+
+		_, _ = c.Resolve("k")
+		_, _ = c.Resolve("k")
+		assert.Equal(t, int32(2), inner.calls.Load())
+	})
+
+	t.Run("Invalidate and purge", func(t *testing.T) {
+		inner := &versionedResolver{}
+		c, _ := newTestCache(inner, CacheOptions{TTL: time.Minute})
+
+		_, _ = c.Resolve("a")
+		_, _ = c.Resolve("b")
+		c.Invalidate("a")
+		v, _ := c.Resolve("a")
+		assert.Equal(t, "a@3", v)
+
+		c.Purge()
+		v, _ = c.Resolve("b")
+		assert.Equal(t, "b@4", v)
+	})
+
+	t.Run("Describe forwards metadata", func(t *testing.T) {
+		c := NewCachedResolver(&InfisicalResolver{}, CacheOptions{TTL: time.Minute})
+		assert.True(t, c.Describe().Sensitive)
+		assert.Equal(t, ResolverMeta{}, NewCachedResolver(&stubResolver{}, CacheOptions{}).Describe())
+	})
+}
+
+func TestCachedResolver_StaleWhileRevalidate(t *testing.T) {
+	opts := CacheOptions{TTL: time.Minute, StaleWhileRevalidate: true, MaxStale: 10 * time.Minute}
+
+	t.Run("Serves stale value and refreshes in background", func(t *testing.T) {
+		inner := &versionedResolver{gate: make(chan struct{}, 1)}
+		c, clock := newTestCache(inner, opts)
+
+		inner.gate <- struct{}{}
+		v, err := c.Resolve("k")
+		require.NoError(t, err)
+		assert.Equal(t, "k@1", v)
+
+		clock.Advance(2 * time.Minute)
+		v, err = c.Resolve("k") // stale → served immediately, refresh blocked on gate
+		require.NoError(t, err)
+		assert.Equal(t, "k@1", v)
+
+		v, err = c.Resolve("k") // refresh still in flight → no second refresh
+		require.NoError(t, err)
+		assert.Equal(t, "k@1", v)
+
+		inner.gate <- struct{}{}
+		require.Eventually(t, func() bool {
+			v, _ := c.Resolve("k")
+			return v == "k@2"
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, int32(2), inner.calls.Load())
+	})
+
+	t.Run("Failed refresh keeps stale value", func(t *testing.T) {
+		inner := &versionedResolver{}
+		c, clock := newTestCache(inner, opts)
+
+		_, _ = c.Resolve("k")
+		inner.fail.Store(true)
+		clock.Advance(2 * time.Minute)
+
+		v, err := c.Resolve("k")
+		require.NoError(t, err)
+		assert.Equal(t, "k@1", v)
+		require.Eventually(t, func() bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return len(c.refreshing) == 0
+		}, time.Second, time.Millisecond)
+
+		v, err = c.Resolve("k")
+		require.NoError(t, err)
+		assert.Equal(t, "k@1", v)
+	})
+
+	t.Run("Max staleness is a hard bound", func(t *testing.T) {
+		inner := &versionedResolver{}
+		c, clock := newTestCache(inner, opts)
+
+		_, _ = c.Resolve("k")
+		clock.Advance(11 * time.Minute)
+		inner.fail.Store(true)
+
+		_, err := c.Resolve("k")
+		require.Error(t, err, "values older than TTL+MaxStale must not be served")
+	})
+}
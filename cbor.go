@@ -0,0 +1,279 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// CBORResolver resolves a value by decoding a CBOR file and extracting a nested key.
+// Format: "cbor:/path/file.cbor//key1.key2.keyN", e.g. "cbor:/etc/device.cbor//wifi.ssid".
+//
+// Maps and arrays are navigated like JSON; non-string map keys are formatted as text.
+// Byte strings are returned as base64 and epoch timestamps (tag 1) in RFC 3339 (UTC);
+// other tags are dropped in favour of their content. Strings are returned as-is, other
+// values as JSON. If no key is provided, returns the whole document as JSON.
+type CBORResolver struct{}
+
+func (r *CBORResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read CBOR file %q: %w", filePath, err)
+	}
+
+	return selectCBOR(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *CBORResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectCBOR returns the value at keyPath in CBOR data, or the whole document if
+// keyPath is empty. source names the document in error messages.
+func selectCBOR(data []byte, keyPath, source string) (string, error) {
+	d := &cborDecoder{data: data}
+	content, err := d.value(0)
+	if err == nil && d.pos != len(data) {
+		err = fmt.Errorf("%d trailing bytes", len(data)-d.pos)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to parse CBOR in %q: %w", source, err)
+	}
+
+	val := content
+	if keyPath != "" {
+		val, err = selectPath(content, keyPath)
+		if err != nil {
+			return "", fmt.Errorf("%w: key path %q in CBOR %q: %v", ErrNotFound, keyPath, source, err)
+		}
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	out, _ := json.Marshal(val)
+	return string(out), nil
+}
+
+// maxCBORDepth bounds the nesting of maps, arrays and tags.
+const maxCBORDepth = 512
+
+// errCBORBreak is returned by value for the "break" stop code of indefinite-length items.
+var errCBORBreak = errors.New("unexpected break")
+
+// cborDecoder decodes CBOR data items from data.
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+// next returns the next n bytes.
+func (d *cborDecoder) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errors.New("unexpected end of data")
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// head reads an initial byte and its argument. indefinite is set for additional
+// information 31.
+func (d *cborDecoder) head() (major byte, arg uint64, indefinite bool, err error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	major, info := b[0]>>5, b[0]&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), false, nil
+	case info <= 27:
+		raw, err := d.next(1 << (info - 24))
+		if err != nil {
+			return 0, 0, false, err
+		}
+		return major, readBigEndian(raw), false, nil
+	case info == 31:
+		return major, 0, true, nil
+	}
+	return 0, 0, false, fmt.Errorf("invalid additional information %d", info)
+}
+
+// value decodes the next data item.
+func (d *cborDecoder) value(depth int) (any, error) {
+	if depth > maxCBORDepth {
+		return nil, errors.New("nested too deeply")
+	}
+	start := d.pos
+	major, arg, indefinite, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	if indefinite && (major < 2 || major == 6) {
+		return nil, fmt.Errorf("invalid indefinite length for major type %d", major)
+	}
+
+	switch major {
+	case 0:
+		if arg > math.MaxInt64 {
+			return arg, nil
+		}
+		return int64(arg), nil
+	case 1:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("negative integer out of range")
+		}
+		return -1 - int64(arg), nil
+	case 2, 3:
+		raw, err := d.bytes(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == 3 {
+			return string(raw), nil
+		}
+		return base64.StdEncoding.EncodeToString(raw), nil
+	case 4:
+		if !indefinite && arg > uint64(len(d.data)-d.pos) {
+			return nil, errors.New("unexpected end of data")
+		}
+		arr := []any{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			v, err := d.value(depth + 1)
+			if indefinite && errors.Is(err, errCBORBreak) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	case 5:
+		if !indefinite && arg > uint64(len(d.data)-d.pos)/2 {
+			return nil, errors.New("unexpected end of data")
+		}
+		m := map[string]any{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			k, err := d.value(depth + 1)
+			if indefinite && errors.Is(err, errCBORBreak) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			ks, ok := k.(string)
+			if !ok {
+				ks = fmt.Sprint(k)
+			}
+			m[ks] = v
+		}
+		return m, nil
+	case 6:
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if arg == 1 { // epoch-based date/time
+			switch t := v.(type) {
+			case int64:
+				return time.Unix(t, 0).UTC().Format(time.RFC3339), nil
+			case float64:
+				sec, frac := math.Modf(t)
+				return time.Unix(int64(sec), int64(frac*1e9)).UTC().Format(time.RFC3339Nano), nil
+			}
+		}
+		return v, nil
+	}
+
+	// major type 7: simple values and floats
+	info := d.data[start] & 0x1f
+	switch {
+	case indefinite:
+		return nil, errCBORBreak
+	case info == 20:
+		return false, nil
+	case info == 21:
+		return true, nil
+	case info == 22, info == 23:
+		return nil, nil
+	case info == 25:
+		return halfToFloat(uint16(arg)), nil
+	case info == 26:
+		return float64(math.Float32frombits(uint32(arg))), nil
+	case info == 27:
+		return math.Float64frombits(arg), nil
+	}
+	return nil, fmt.Errorf("unsupported simple value %d", arg)
+}
+
+// bytes reads the content of a byte or text string, joining indefinite-length chunks.
+func (d *cborDecoder) bytes(major byte, n uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		return d.next(n)
+	}
+	var out []byte
+	for {
+		cm, cn, cind, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if cm == 7 && cind {
+			return out, nil
+		}
+		if cm != major || cind {
+			return nil, errors.New("invalid chunk in indefinite-length string")
+		}
+		chunk, err := d.next(cn)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk...)
+	}
+}
+
+// halfToFloat converts an IEEE 754 half-precision value.
+func halfToFloat(h uint16) float64 {
+	exp, mant := int(h>>10)&0x1f, float64(h&0x3ff)
+	var v float64
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			v = math.Inf(1)
+		} else {
+			v = math.NaN()
+		}
+	default:
+		v = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,89 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCBOR encodes {"wifi": {"ssid": "home", "channel": 11}, "ratio": 1.5 (half),
+// "pi": 3.25 (double), "on": true, "neg": -500, "tags": ["a", "b"] (indefinite),
+// "key": h'0102', "at": 1(1700000000), "name": "ab" (chunked), 7: null}.
+var testCBOR = []byte{
+	0xaa,
+	0x64, 'w', 'i', 'f', 'i',
+	0xa2, 0x64, 's', 's', 'i', 'd', 0x64, 'h', 'o', 'm', 'e', 0x67, 'c', 'h', 'a', 'n', 'n', 'e', 'l', 0x0b,
+	0x65, 'r', 'a', 't', 'i', 'o', 0xf9, 0x3e, 0x00,
+	0x62, 'p', 'i', 0xfb, 0x40, 0x0a, 0, 0, 0, 0, 0, 0,
+	0x62, 'o', 'n', 0xf5,
+	0x63, 'n', 'e', 'g', 0x39, 0x01, 0xf3,
+	0x64, 't', 'a', 'g', 's', 0x9f, 0x61, 'a', 0x61, 'b', 0xff,
+	0x63, 'k', 'e', 'y', 0x42, 0x01, 0x02,
+	0x62, 'a', 't', 0xc1, 0x1a, 0x65, 0x53, 0xf1, 0x00,
+	0x64, 'n', 'a', 'm', 'e', 0x7f, 0x61, 'a', 0x61, 'b', 0xff,
+	0x07, 0xf6,
+}
+
+func TestCBORResolver_Resolve(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "device.cbor")
+	require.NoError(t, os.WriteFile(p, testCBOR, 0o666))
+	r := &CBORResolver{}
+
+	cases := []struct {
+		name, key, want string
+	}{
+		{"Nested string", "wifi.ssid", "home"},
+		{"Integer", "wifi.channel", "11"},
+		{"Half float", "ratio", "1.5"},
+		{"Double", "pi", "3.25"},
+		{"Bool", "on", "true"},
+		{"Negative integer", "neg", "-500"},
+		{"Indefinite array", "tags.1", "b"},
+		{"Byte string", "key", "AQI="},
+		{"Epoch timestamp", "at", "2023-11-14T22:13:20Z"},
+		{"Chunked text", "name", "ab"},
+		{"Integer key", "7", "null"},
+		{"Map as JSON", "wifi", `{"channel":11,"ssid":"home"}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("Whole file as JSON", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.Contains(t, val, `"wifi":{"channel":11,"ssid":"home"}`)
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		_, err := r.Resolve(p + "//wifi.nope")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.cbor")
+		require.NoError(t, os.WriteFile(bad, testCBOR[:20], 0o666))
+		_, err := r.Resolve(bad + "//wifi")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Huge length", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "huge.cbor")
+		require.NoError(t, os.WriteFile(bad, []byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 0o666))
+		_, err := r.Resolve(bad)
+		require.Error(t, err)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "nope.cbor") + "//wifi")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
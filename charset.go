@@ -0,0 +1,55 @@
+package resolver
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// charsetOption selects the encoding of a text file: "/path/file?charset=latin1".
+const charsetOption = "?charset="
+
+// readTextFile reads the text file at p and transcodes it to UTF-8 for the file-based
+// resolvers. A "?charset=<name>" suffix on p names the file's encoding (an IANA name or
+// alias such as "latin1", "windows-1252", "utf-16le" or "shift_jis"); without one,
+// UTF-16 files are detected by their byte order mark. A leading UTF-8 byte order mark
+// is removed. Errors from reading the file are returned as is.
+func readTextFile(p string) ([]byte, error) {
+	p, charset, _ := strings.Cut(p, charsetOption)
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	return decodeText(data, charset)
+}
+
+// decodeText transcodes data from charset, or from the encoding given by its byte
+// order mark if charset is empty, to UTF-8 without a byte order mark.
+func decodeText(data []byte, charset string) ([]byte, error) {
+	var enc encoding.Encoding
+	switch {
+	case charset != "":
+		e, err := ianaindex.IANA.Encoding(charset)
+		if err != nil || e == nil {
+			return nil, fmt.Errorf("%w: unknown charset %q", ErrBadPath, charset)
+		}
+		enc = e
+	case bytes.HasPrefix(data, []byte{0xff, 0xfe}):
+		enc = unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+	case bytes.HasPrefix(data, []byte{0xfe, 0xff}):
+		enc = unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM)
+	default:
+		return bytes.TrimPrefix(data, []byte("\uFEFF")), nil
+	}
+
+	out, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s text: %w", enc, err)
+	}
+	return bytes.TrimPrefix(out, []byte("\uFEFF")), nil
+}
@@ -0,0 +1,96 @@
+package resolver
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// utf16Bytes encodes s as UTF-16 with a byte order mark.
+func utf16Bytes(s string, order binary.AppendByteOrder) []byte {
+	var b []byte
+	for _, c := range utf16.Encode([]rune("\uFEFF" + s)) {
+		b = order.AppendUint16(b, c)
+	}
+	return b
+}
+
+func TestDecodeText(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     []byte
+		charset  string
+		expected string
+	}{
+		{"UTF-8", []byte("grüezi"), "", "grüezi"},
+		{"UTF-8 BOM", []byte("\uFEFFgrüezi"), "", "grüezi"},
+		{"UTF-16LE BOM", utf16Bytes("grüezi", binary.LittleEndian), "", "grüezi"},
+		{"UTF-16BE BOM", utf16Bytes("grüezi", binary.BigEndian), "", "grüezi"},
+		{"Latin-1", []byte("gr\xfcezi"), "latin1", "grüezi"},
+		{"Windows-1252", []byte("\x80 5"), "windows-1252", "€ 5"},
+		{"Explicit UTF-16LE", utf16Bytes("grüezi", binary.LittleEndian), "utf-16le", "grüezi"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := decodeText(tc.data, tc.charset)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(out))
+		})
+	}
+
+	t.Run("Unknown charset", func(t *testing.T) {
+		_, err := decodeText([]byte("x"), "klingon")
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+}
+
+func TestFileResolvers_Charset(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, data, 0o600))
+		return p
+	}
+
+	jsonUTF16 := write("app.json", utf16Bytes(`{"user": "jürg"}`, binary.LittleEndian))
+	iniLatin1 := write("app.ini", []byte("[Database]\nUser = j\xfcrg\n"))
+	kvUTF16 := write("app.txt", utf16Bytes("USER=jürg\r\n", binary.BigEndian))
+	envDir := filepath.Join(dir, "env")
+	require.NoError(t, os.Mkdir(envDir, 0o700))
+	write("env/.env", []byte("USER=j\xfcrg\n"))
+
+	cases := []struct {
+		name     string
+		r        Resolver
+		value    string
+		expected string
+	}{
+		{"JSON UTF-16 BOM", &JSONResolver{}, jsonUTF16 + "//user", "jürg"},
+		{"INI latin1", &INIResolver{}, iniLatin1 + "?charset=latin1//Database.User", "jürg"},
+		{"Key-value UTF-16 BOM", &KeyValueFileResolver{}, kvUTF16 + "//USER", "jürg"},
+		{"Whole file UTF-16 BOM", &KeyValueFileResolver{}, kvUTF16, "USER=jürg"},
+		{"Dotenv directory latin1", &DotenvResolver{}, envDir + "?charset=iso-8859-1//USER", "jürg"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := tc.r.Resolve(tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, val)
+		})
+	}
+
+	t.Run("Unknown charset", func(t *testing.T) {
+		_, err := (&INIResolver{}).Resolve(iniLatin1 + "?charset=klingon//Database.User")
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Missing file with charset", func(t *testing.T) {
+		_, err := (&JSONResolver{}).Resolve(filepath.Join(dir, "missing.json") + "?charset=latin1//user")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
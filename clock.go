@@ -0,0 +1,148 @@
+package resolver
+
+import (
+	"crypto/rand"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time for registries, caches and in-memory stores. Replace
+// the default SystemClock with a ManualClock to make dependent tests deterministic.
+type Clock interface {
+	Now() time.Time
+	// After returns a channel that receives the current time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the real wall clock.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// ManualClock is a Clock that only moves when told to. It is safe for concurrent use.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []manualWaiter
+}
+
+// manualWaiter is a pending After call.
+type manualWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewManualClock returns a ManualClock set to now.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the clock's current time.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that fires once Advance or Set moves the clock by d.
+// A non-positive d fires immediately.
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, manualWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d and fires the After channels that are due.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	now := c.now.Add(d)
+	c.mu.Unlock()
+	c.Set(now)
+}
+
+// Set moves the clock to t and fires the After channels that are due.
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+
+	sort.SliceStable(c.waiters, func(i, j int) bool { return c.waiters[i].at.Before(c.waiters[j].at) })
+	n := 0
+	for _, w := range c.waiters {
+		if w.at.After(t) {
+			break
+		}
+		w.ch <- t
+		n++
+	}
+	c.waiters = c.waiters[n:]
+}
+
+// Waiters returns the number of pending After calls. Tests use it to know that code
+// under test is blocked on the clock before advancing it.
+func (c *ManualClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// SetClock sets the clock used for resolution timings, WaitFor backoff and render
+// timestamps. Child registries use their parent's clock unless they set their own.
+func (r *Registry) SetClock(c Clock) {
+	r.mu.Lock()
+	r.clock = c
+	r.mu.Unlock()
+}
+
+// SetRandom sets the source of randomness for generated values such as resolution IDs.
+// Child registries use their parent's source unless they set their own. Encryption
+// keys and nonces always come from crypto/rand.
+func (r *Registry) SetRandom(src io.Reader) {
+	r.mu.Lock()
+	r.random = src
+	r.mu.Unlock()
+}
+
+// effectiveClock returns the clock of r or its nearest ancestor with one, else SystemClock.
+func (r *Registry) effectiveClock() Clock {
+	r.mu.RLock()
+	c, parent := r.clock, r.parent
+	r.mu.RUnlock()
+
+	switch {
+	case c != nil:
+		return c
+	case parent != nil:
+		return parent.effectiveClock()
+	default:
+		return SystemClock
+	}
+}
+
+// effectiveRandom returns the randomness source of r or its nearest ancestor with one,
+// else crypto/rand.
+func (r *Registry) effectiveRandom() io.Reader {
+	r.mu.RLock()
+	src, parent := r.random, r.parent
+	r.mu.RUnlock()
+
+	switch {
+	case src != nil:
+		return src
+	case parent != nil:
+		return parent.effectiveRandom()
+	default:
+		return rand.Reader
+	}
+}
@@ -0,0 +1,128 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("After fires when advanced past deadline", func(t *testing.T) {
+		c := NewManualClock(start)
+		ch := c.After(time.Minute)
+		assert.Equal(t, 1, c.Waiters())
+
+		c.Advance(30 * time.Second)
+		select {
+		case <-ch:
+			t.Fatal("fired early")
+		default:
+		}
+
+		c.Advance(30 * time.Second)
+		assert.Equal(t, start.Add(time.Minute), <-ch)
+		assert.Equal(t, 0, c.Waiters())
+	})
+
+	t.Run("Non-positive duration fires immediately", func(t *testing.T) {
+		c := NewManualClock(start)
+		assert.Equal(t, start, <-c.After(0))
+	})
+}
+
+func TestRegistry_Clock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Deterministic resolution IDs and durations", func(t *testing.T) {
+		clock := NewManualClock(start)
+		r := NewRegistry()
+		r.SetClock(clock)
+		r.SetRandom(bytes.NewReader(bytes.Repeat([]byte{0xab}, 8)))
+		r.Register("slow:", ResolverFunc(func(v string) (string, error) {
+			clock.Advance(2 * time.Second)
+			return v, nil
+		}))
+
+		var ev ResolveEvent
+		r.SetHooks(Hooks{OnResolve: func(_ context.Context, e ResolveEvent) { ev = e }})
+
+		_, err := r.ResolveVariable("slow:x")
+		require.NoError(t, err)
+		assert.Equal(t, "abababababababab", ev.ResolutionID)
+		assert.Equal(t, 2*time.Second, ev.Duration)
+	})
+
+	t.Run("Child inherits clock", func(t *testing.T) {
+		clock := NewManualClock(start)
+		parent := NewRegistry()
+		parent.SetClock(clock)
+		assert.Same(t, clock, parent.Child().effectiveClock())
+		assert.Equal(t, SystemClock, NewRegistry().effectiveClock())
+	})
+
+	t.Run("WaitFor backs off on the registry clock", func(t *testing.T) {
+		clock := NewManualClock(start)
+		r := NewRegistry()
+		r.SetClock(clock)
+		calls := 0
+		r.Register("slow:", ResolverFunc(func(v string) (string, error) {
+			if calls++; calls < 3 {
+				return "", ErrNotFound
+			}
+			return v, nil
+		}))
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := r.WaitFor(context.Background(), []string{"slow:x"}, WaitOptions{InitialBackoff: time.Hour})
+			done <- err
+		}()
+
+		for _, d := range []time.Duration{time.Hour, 2 * time.Hour} {
+			require.Eventually(t, func() bool { return clock.Waiters() == 1 }, time.Second, time.Millisecond)
+			clock.Advance(d)
+		}
+		require.NoError(t, <-done)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("Cache expiry follows clock option", func(t *testing.T) {
+		clock := NewManualClock(start)
+		calls := 0
+		c := NewCachedResolver(ResolverFunc(func(v string) (string, error) {
+			calls++
+			return v, nil
+		}), CacheOptions{TTL: time.Minute, Clock: clock})
+
+		for range 2 {
+			_, err := c.Resolve("a")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, calls)
+
+		clock.Advance(2 * time.Minute)
+		_, err := c.Resolve("a")
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Mem expiry follows clock", func(t *testing.T) {
+		clock := NewManualClock(start)
+		m := NewMemResolver()
+		m.SetClock(clock)
+		m.SetWithTTL("k", "v", time.Minute)
+
+		_, err := m.Resolve("k")
+		require.NoError(t, err)
+
+		clock.Advance(time.Minute)
+		_, err = m.Resolve("k")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,111 @@
+// Command resolver is a configuration preflight tool built on the resolver package.
+//
+// Usage:
+//
+//	resolver verify manifest.yaml
+//	resolver apply manifest.yaml
+//
+// verify resolves every token listed in the manifest, checks it against its
+// expectations and exits with status 1 if any check fails. Resolved values are
+// never printed.
+//
+// apply resolves the files and env file of an apply manifest and writes them all
+// at once, or nothing if any token fails; it exits with status 1 on failure.
+//
+// Manifests may use every built-in scheme except exec:, fd:, mem: and plugin:, and
+// JSONPath and JMESPath key paths; jq needs -tags resolver_jq.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/containeroo/resolver"
+	_ "github.com/containeroo/resolver/jmespath"
+	_ "github.com/containeroo/resolver/jsonpath"
+)
+
+const usage = `usage: resolver verify <manifest.yaml>
+       resolver apply <manifest.yaml>`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	reg := resolver.NewExtendedRegistry()
+	resolver.RegisterBackends(reg)
+	resolver.SetDefaultRegistry(reg)
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the CLI and returns the process exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) != 2 {
+		fmt.Fprintln(stderr, usage) // nolint:errcheck
+		return 2
+	}
+	switch args[0] {
+	case "verify":
+		return verify(ctx, args[1], stdout, stderr)
+	case "apply":
+		return apply(ctx, args[1], stdout, stderr)
+	}
+	fmt.Fprintln(stderr, usage) // nolint:errcheck
+	return 2
+}
+
+// verify runs the verify subcommand.
+func verify(ctx context.Context, path string, stdout, stderr io.Writer) int {
+	m, err := resolver.LoadManifest(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err) // nolint:errcheck
+		return 2
+	}
+	rep, err := resolver.Verify(ctx, m)
+	if err != nil {
+		fmt.Fprintln(stderr, err) // nolint:errcheck
+		return 2
+	}
+
+	printReport(stdout, rep)
+	if !rep.Passed() {
+		return 1
+	}
+	return 0
+}
+
+// apply runs the apply subcommand.
+func apply(ctx context.Context, path string, stdout, stderr io.Writer) int {
+	m, err := resolver.LoadApplyManifest(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err) // nolint:errcheck
+		return 2
+	}
+	res, err := resolver.Apply(ctx, m)
+	if err != nil {
+		fmt.Fprintln(stderr, err) // nolint:errcheck
+		return 1
+	}
+	for _, f := range res.Files {
+		fmt.Fprintf(stdout, "WROTE %s\n", f) // nolint:errcheck
+	}
+	return 0
+}
+
+// printReport writes one line per check followed by a summary.
+func printReport(w io.Writer, rep *resolver.Report) {
+	for _, res := range rep.Results {
+		switch {
+		case res.Skipped:
+			fmt.Fprintf(w, "SKIP %s (optional, not set)\n", res.Check.Name) // nolint:errcheck
+		case res.Passed:
+			fmt.Fprintf(w, "PASS %s\n", res.Check.Name) // nolint:errcheck
+		default:
+			fmt.Fprintf(w, "FAIL %s: %s\n", res.Check.Name, strings.Join(res.Problems, "; ")) // nolint:errcheck
+		}
+	}
+	fmt.Fprintf(w, "%d checks, %d failed\n", len(rep.Results), len(rep.Failed())) // nolint:errcheck
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	return p
+}
+
+func TestRun(t *testing.T) {
+	t.Run("All checks pass", func(t *testing.T) {
+		t.Setenv("VERIFY_URL", "https://example.com")
+		p := writeManifest(t, `
+checks:
+  - token: env:VERIFY_URL
+    type: url
+  - token: env:VERIFY_MISSING
+    optional: true
+`)
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), []string{"verify", p}, &stdout, &stderr)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "PASS env:VERIFY_URL\nSKIP env:VERIFY_MISSING (optional, not set)\n2 checks, 0 failed\n", stdout.String())
+	})
+
+	t.Run("Failing check", func(t *testing.T) {
+		t.Setenv("VERIFY_PORT", "http")
+		p := writeManifest(t, `
+checks:
+  - name: port
+    token: env:VERIFY_PORT
+    type: int
+`)
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), []string{"verify", p}, &stdout, &stderr)
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stdout.String(), "FAIL port: is not an integer")
+		assert.NotContains(t, stdout.String(), "http", "values must not be printed")
+	})
+
+	t.Run("Apply writes files", func(t *testing.T) {
+		t.Setenv("APPLY_PW", "s3cr3t")
+		out := filepath.Join(t.TempDir(), "pw")
+		p := writeManifest(t, "files:\n  - path: "+out+"\n    token: env:APPLY_PW\n")
+
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), []string{"apply", p}, &stdout, &stderr)
+		assert.Equal(t, 0, code, stderr.String())
+		assert.Equal(t, "WROTE "+out+"\n", stdout.String())
+
+		got, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", string(got))
+	})
+
+	t.Run("Apply fails without writing", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "pw")
+		p := writeManifest(t, "files:\n  - path: "+out+"\n    token: env:APPLY_MISSING_VAR\n")
+
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), []string{"apply", p}, &stdout, &stderr)
+		assert.Equal(t, 1, code)
+		assert.NoFileExists(t, out)
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), []string{"check"}, &stdout, &stderr)
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr.String(), "usage:")
+	})
+
+	t.Run("Missing manifest", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), []string{"verify", filepath.Join(t.TempDir(), "nope.yaml")}, &stdout, &stderr)
+		assert.Equal(t, 2, code)
+	})
+}
@@ -0,0 +1,102 @@
+package resolver
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"time"
+)
+
+// ContextResolver is implemented by resolvers that honour cancellation and deadlines
+// and want the request-scoped values of the calling context, such as the resolution ID.
+// The registry calls ResolveContext instead of Resolve when it is available.
+type ContextResolver interface {
+	Resolver
+	ResolveContext(ctx context.Context, value string) (string, error)
+}
+
+// Hooks observe resolutions performed by a registry.
+type Hooks struct {
+	// OnResolve is called after every token resolved through a registered scheme.
+	// ctx carries the resolution ID of the top-level call.
+	OnResolve func(ctx context.Context, ev ResolveEvent)
+
+	// OnWarning is called when ResolveString replaces a failed "?optional" token
+	// with its fallback instead of failing.
+	OnWarning func(ctx context.Context, ev WarningEvent)
+}
+
+// ResolveEvent describes one token resolution. The resolved value is deliberately absent.
+type ResolveEvent struct {
+	ResolutionID string        // shared by all tokens of one top-level call
+	Scheme       string        // matched scheme, e.g. "env:"
+	Token        string        // full token including the scheme
+	Duration     time.Duration // time spent in the resolver
+	Err          error         // nil on success
+}
+
+// WarningEvent describes a failure that did not fail the call.
+type WarningEvent struct {
+	ResolutionID string // shared by all tokens of one top-level call
+	Token        string // token without the "?optional" suffix
+	Err          error  // the error that was replaced by the fallback
+}
+
+type resolutionIDKey struct{}
+
+// WithResolutionID returns a context carrying id as the resolution ID. Calls that
+// receive it reuse the ID instead of generating one, so callers can correlate
+// resolver logs with their own request IDs.
+func WithResolutionID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, resolutionIDKey{}, id)
+}
+
+// ResolutionID returns the resolution ID carried by ctx, or "" if there is none.
+// Resolvers implementing ContextResolver use it to tag logs, metrics and audit events.
+func ResolutionID(ctx context.Context) string {
+	id, _ := ctx.Value(resolutionIDKey{}).(string)
+	return id
+}
+
+// ensureResolutionID returns ctx unchanged if it carries a resolution ID, or a child
+// context with a new random one drawn from the registry's randomness source.
+func (r *Registry) ensureResolutionID(ctx context.Context) context.Context {
+	if ResolutionID(ctx) != "" {
+		return ctx
+	}
+	var b [8]byte
+	_, _ = io.ReadFull(r.effectiveRandom(), b[:])
+	return WithResolutionID(ctx, hex.EncodeToString(b[:]))
+}
+
+// resolveWith calls res with ctx if it supports contexts, otherwise without.
+func resolveWith(ctx context.Context, res Resolver, value string) (string, error) {
+	if cr, ok := res.(ContextResolver); ok {
+		return cr.ResolveContext(ctx, value)
+	}
+	return res.Resolve(value)
+}
+
+// SetHooks installs hooks on r. Child registries use their parent's hooks unless
+// they set their own.
+func (r *Registry) SetHooks(h Hooks) {
+	r.mu.Lock()
+	r.hooks = &h
+	r.mu.Unlock()
+}
+
+// effectiveHooks returns the hooks of r or its nearest ancestor with hooks.
+func (r *Registry) effectiveHooks() Hooks {
+	r.mu.RLock()
+	h, parent := r.hooks, r.parent
+	r.mu.RUnlock()
+
+	switch {
+	case h != nil:
+		return *h
+	case parent != nil:
+		return parent.effectiveHooks()
+	default:
+		return Hooks{}
+	}
+}
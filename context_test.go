@@ -0,0 +1,135 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ctxStub records the resolution ID it is called with.
+type ctxStub struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (s *ctxStub) Resolve(value string) (string, error) {
+	return s.ResolveContext(context.Background(), value)
+}
+
+func (s *ctxStub) ResolveContext(ctx context.Context, value string) (string, error) {
+	s.mu.Lock()
+	s.ids = append(s.ids, ResolutionID(ctx))
+	s.mu.Unlock()
+	return value, nil
+}
+
+func TestRegistry_ResolutionID(t *testing.T) {
+	t.Run("Shared by all tokens of one call", func(t *testing.T) {
+		stub := &ctxStub{}
+		r := NewRegistry()
+		r.Register("c:", stub)
+
+		var events []ResolveEvent
+		r.SetHooks(Hooks{OnResolve: func(_ context.Context, ev ResolveEvent) { events = append(events, ev) }})
+
+		got, err := r.ResolveStringContext(context.Background(), "${c:a}-${c:b}")
+		require.NoError(t, err)
+		assert.Equal(t, "a-b", got)
+
+		require.Len(t, stub.ids, 2)
+		require.NotEmpty(t, stub.ids[0])
+		assert.Equal(t, stub.ids[0], stub.ids[1])
+
+		require.Len(t, events, 2)
+		for _, ev := range events {
+			assert.Equal(t, stub.ids[0], ev.ResolutionID)
+			assert.Equal(t, "c:", ev.Scheme)
+			assert.NoError(t, ev.Err)
+		}
+		assert.Equal(t, "c:a", events[0].Token)
+		assert.Equal(t, "c:b", events[1].Token)
+	})
+
+	t.Run("Separate calls get separate IDs", func(t *testing.T) {
+		stub := &ctxStub{}
+		r := NewRegistry()
+		r.Register("c:", stub)
+
+		_, err := r.ResolveVariable("c:a")
+		require.NoError(t, err)
+		_, err = r.ResolveVariable("c:b")
+		require.NoError(t, err)
+
+		require.Len(t, stub.ids, 2)
+		assert.NotEqual(t, stub.ids[0], stub.ids[1])
+	})
+
+	t.Run("Caller supplied ID", func(t *testing.T) {
+		stub := &ctxStub{}
+		r := NewRegistry()
+		r.Register("c:", stub)
+
+		ctx := WithResolutionID(context.Background(), "req-42")
+		_, err := r.ResolveStringContext(ctx, "${c:a}")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"req-42"}, stub.ids)
+	})
+
+	t.Run("Cached resolver forwards context", func(t *testing.T) {
+		stub := &ctxStub{}
+		r := NewRegistry()
+		r.Register("c:", NewCachedResolver(stub, CacheOptions{TTL: time.Minute}))
+
+		ctx := WithResolutionID(context.Background(), "req-7")
+		_, err := r.ResolveVariableContext(ctx, "c:a")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"req-7"}, stub.ids)
+	})
+}
+
+func TestRegistry_Hooks(t *testing.T) {
+	t.Run("Error reported", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register("fail:", ResolverFunc(func(string) (string, error) { return "", errors.New("boom") }))
+
+		var got ResolveEvent
+		r.SetHooks(Hooks{OnResolve: func(_ context.Context, ev ResolveEvent) { got = ev }})
+
+		_, err := r.ResolveVariable("fail:x")
+		require.Error(t, err)
+		assert.EqualError(t, got.Err, "boom")
+		assert.Equal(t, "fail:x", got.Token)
+	})
+
+	t.Run("Child inherits hooks", func(t *testing.T) {
+		parent := NewRegistry()
+		calls := 0
+		parent.SetHooks(Hooks{OnResolve: func(context.Context, ResolveEvent) { calls++ }})
+
+		child := parent.Child()
+		child.Register("x:", ResolverFunc(func(v string) (string, error) { return v, nil }))
+		_, err := child.ResolveVariable("x:a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+
+		child.SetHooks(Hooks{})
+		_, err = child.ResolveVariable("x:a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls, "child hooks replace the parent's")
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register("x:", ResolverFunc(func(v string) (string, error) { return v, nil }))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := r.ResolveStringContext(ctx, "${x:a}")
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
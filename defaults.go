@@ -0,0 +1,71 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+)
+
+// SetDefaultsFile points the registry at a defaults document (.json, .yaml/.yml, .toml,
+// .ini or .xml, chosen by extension). When a file-based resolver (one reporting
+// CapabilityWholeFile) fails with ErrNotFound for a token with a key path, the same key
+// path is looked up in the defaults document before the error is returned, so default
+// values live in one place instead of in every token:
+//
+//	reg.SetDefaultsFile("/etc/app/defaults.yaml")
+//	reg.ResolveVariable("yaml:/etc/app/config.yaml//db.port") // defaults.yaml db.port if config.yaml has none
+//
+// The document is read on each fallback; if it is missing or lacks the key, the
+// original error is returned. An empty path disables the fallback. Child registries
+// inherit the setting unless they set it themselves.
+func (r *Registry) SetDefaultsFile(path string) {
+	r.mu.Lock()
+	r.defaults = path
+	r.defaultsSet = true
+	r.mu.Unlock()
+}
+
+// defaultsFile returns the effective defaults document, inheriting from the parent unless set.
+func (r *Registry) defaultsFile() string {
+	r.mu.RLock()
+	path, set, parent := r.defaults, r.defaultsSet, r.parent
+	r.mu.RUnlock()
+
+	if set || parent == nil {
+		return path
+	}
+	return parent.defaultsFile()
+}
+
+// fromDefaults looks up the key path of value (a token without its scheme) in the
+// defaults document, if res is file-based and a defaults document is set.
+func (r *Registry) fromDefaults(res Resolver, value string) (string, bool) {
+	path := r.defaultsFile()
+	if path == "" {
+		return "", false
+	}
+	d, ok := res.(Describer)
+	if !ok || !slices.Contains(d.Describe().Capabilities, CapabilityWholeFile) {
+		return "", false
+	}
+	_, keyPath := splitFileAndKey(value)
+	if keyPath == "" {
+		return "", false
+	}
+	v, err := selectDefaults(path, keyPath)
+	return v, err == nil
+}
+
+// selectDefaults returns keyPath from the defaults document at path.
+func selectDefaults(path, keyPath string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: defaults file %s", ErrNotFound, path)
+		}
+		return "", fmt.Errorf("failed to read defaults file %q: %w", path, err)
+	}
+	return selectByExtension(path, data, keyPath)
+}
@@ -0,0 +1,74 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_SetDefaultsFile(t *testing.T) {
+	dir := t.TempDir()
+	defaults := filepath.Join(dir, "defaults.yaml")
+	require.NoError(t, os.WriteFile(defaults, []byte("db:\n  host: localhost\n  port: 5432\nTOKEN: fallback\n"), 0o600))
+	config := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(config, []byte(`{"db":{"host":"db.prod"}}`), 0o600))
+
+	reg := NewDefaultRegistry()
+	reg.SetDefaultsFile(defaults)
+
+	t.Run("Value in document wins", func(t *testing.T) {
+		val, err := reg.ResolveVariable("json:" + config + "//db.host")
+		require.NoError(t, err)
+		assert.Equal(t, "db.prod", val)
+	})
+
+	t.Run("Missing key falls back", func(t *testing.T) {
+		val, err := reg.ResolveVariable("json:" + config + "//db.port")
+		require.NoError(t, err)
+		assert.Equal(t, "5432", val)
+	})
+
+	t.Run("Missing file falls back", func(t *testing.T) {
+		val, err := reg.ResolveVariable("yaml:" + filepath.Join(dir, "nope.yaml") + "//db.host")
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", val)
+	})
+
+	t.Run("Missing in defaults too", func(t *testing.T) {
+		_, err := reg.ResolveVariable("json:" + config + "//db.user")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Whole-file token does not fall back", func(t *testing.T) {
+		_, err := reg.ResolveVariable("json:" + filepath.Join(dir, "nope.json"))
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Non-file schemes do not fall back", func(t *testing.T) {
+		t.Setenv("TOKEN", "")
+		require.NoError(t, os.Unsetenv("TOKEN"))
+		_, err := reg.ResolveVariable("env:TOKEN")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Inherited by children", func(t *testing.T) {
+		val, err := reg.Child().ResolveVariable("json:" + config + "//db.port")
+		require.NoError(t, err)
+		assert.Equal(t, "5432", val)
+
+		child := reg.Child()
+		child.SetDefaultsFile("")
+		_, err = child.ResolveVariable("json:" + config + "//db.port")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing defaults file keeps the error", func(t *testing.T) {
+		other := NewDefaultRegistry()
+		other.SetDefaultsFile(filepath.Join(dir, "nope.yaml"))
+		_, err := other.ResolveVariable("json:" + config + "//db.port")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,54 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultDockerSecretsDir is where Docker and Swarm mount secrets inside containers.
+const DefaultDockerSecretsDir = "/run/secrets"
+
+// DockerSecretResolver resolves Docker/Swarm secrets mounted as files.
+// Format: "docker-secret:<name>" reads <BaseDir>/<name> with trailing newlines trimmed.
+// BaseDir defaults to DefaultDockerSecretsDir.
+type DockerSecretResolver struct {
+	BaseDir string
+}
+
+func (r *DockerSecretResolver) Resolve(value string) (string, error) {
+	name := strings.TrimSpace(value)
+	if name == "" {
+		return "", fmt.Errorf("%w: empty docker secret name", ErrBadPath)
+	}
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("%w: invalid docker secret name %q", ErrBadPath, name)
+	}
+
+	dir := r.BaseDir
+	if dir == "" {
+		dir = DefaultDockerSecretsDir
+	}
+	path := filepath.Join(dir, name)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: docker secret %q", ErrNotFound, name)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: docker secret %q", ErrForbidden, name)
+		}
+		return "", fmt.Errorf("failed to read docker secret %q: %w", path, err)
+	}
+	// Only trailing newlines are trimmed; other whitespace may be part of the secret.
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// Describe reports the resolver metadata.
+func (r *DockerSecretResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true}
+}
@@ -0,0 +1,50 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerSecretResolver_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db_password"), []byte(" s3cr3t \n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "crlf"), []byte("line\r\n"), 0o600))
+	r := &DockerSecretResolver{BaseDir: dir}
+
+	t.Run("Trailing newline trimmed", func(t *testing.T) {
+		val, err := r.Resolve("db_password")
+		require.NoError(t, err)
+		assert.Equal(t, " s3cr3t ", val)
+	})
+
+	t.Run("CRLF trimmed", func(t *testing.T) {
+		val, err := r.Resolve("crlf")
+		require.NoError(t, err)
+		assert.Equal(t, "line", val)
+	})
+
+	t.Run("Missing secret", func(t *testing.T) {
+		_, err := r.Resolve("nope")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid names", func(t *testing.T) {
+		for _, in := range []string{"", "..", "../etc/passwd", "a/b"} {
+			_, err := r.Resolve(in)
+			require.Error(t, err, in)
+			assert.ErrorIs(t, err, ErrBadPath, in)
+		}
+	})
+
+	t.Run("Default base dir", func(t *testing.T) {
+		r := &DockerSecretResolver{}
+		_, err := r.Resolve("resolver-test-missing-secret")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "resolver-test-missing-secret")
+	})
+}
@@ -0,0 +1,350 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DotenvResolver resolves a value from a .env file with the full dotenv syntax, unlike
+// the line-based "file:" parser. Format: "dotenv:/path/.env//KEY".
+//
+// Supported syntax:
+//   - KEY=value and export KEY=value, with '#' comment lines and inline " # comments"
+//   - single-quoted values: literal, may span lines
+//   - double-quoted values: may span lines; \n, \r, \t, \", \\ and \$ escapes
+//   - backtick-quoted values: literal, may span lines
+//   - expansion of ${VAR}, $VAR, ${VAR:-default} (unset or empty) and ${VAR-default}
+//     (unset) in unquoted and double-quoted values; VAR is looked up in the entries
+//     above, then in the process environment
+//
+// Malformed lines are errors rather than being skipped. If no key is provided, returns
+// the whole file as a string.
+//
+// If the path is a directory, ".env", ".env.<stage>", ".env.local" and
+// ".env.<stage>.local" are loaded from it in that order, later files overriding earlier ones (and able to reference their
+// entries); missing files are skipped. Without a key, the merged entries are returned
+// as JSON.
+type DotenvResolver struct {
+	// Stage selects ".env.<stage>" and ".env.<stage>.local" in directory mode. Defaults to DOTENV_STAGE, then
+	// NODE_ENV; with neither set, only ".env" and ".env.local" are loaded.
+	Stage string
+}
+
+func (r *DotenvResolver) Resolve(value string) (string, error) {
+	filePath, key := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	dir, _, _ := strings.Cut(filePath, charsetOption)
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return r.resolveDir(dir, filePath[len(dir):], key)
+	}
+
+	data, err := readTextFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read dotenv file %q: %w", filePath, err)
+	}
+
+	if key == "" {
+		return strings.TrimSpace(stripBOM(string(data))), nil
+	}
+	vars, err := parseDotenv(string(data), filePath, nil)
+	if err != nil {
+		return "", err
+	}
+	v, ok := vars[key]
+	if !ok {
+		return "", fmt.Errorf("%w: key %q in %q", ErrNotFound, key, filePath)
+	}
+	return v, nil
+}
+
+// resolveDir resolves key against the layered dotenv files of dir. opts is the charset
+// option of the path, if any, and applies to every file.
+func (r *DotenvResolver) resolveDir(dir, opts, key string) (string, error) {
+	stage := firstNonEmpty(r.Stage, os.Getenv("DOTENV_STAGE"), os.Getenv("NODE_ENV"))
+	names := []string{".env"}
+	if stage != "" {
+		names = append(names, ".env."+stage)
+	}
+	names = append(names, ".env.local")
+	if stage != "" {
+		names = append(names, ".env."+stage+".local")
+	}
+
+	var (
+		vars  map[string]string
+		found bool
+	)
+	for _, name := range names {
+		p := filepath.Join(dir, name)
+		data, err := readTextFile(p + opts)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if errors.Is(err, fs.ErrPermission) {
+				return "", fmt.Errorf("%w: %s", ErrForbidden, p)
+			}
+			return "", fmt.Errorf("failed to read dotenv file %q: %w", p, err)
+		}
+		if vars, err = parseDotenv(string(data), p, vars); err != nil {
+			return "", err
+		}
+		found = true
+	}
+	if !found {
+		return "", fmt.Errorf("%w: no dotenv files in %s", ErrNotFound, dir)
+	}
+
+	if key == "" {
+		out, _ := json.Marshal(vars)
+		return string(out), nil
+	}
+	v, ok := vars[key]
+	if !ok {
+		return "", fmt.Errorf("%w: key %q in dotenv files of %q", ErrNotFound, key, dir)
+	}
+	return v, nil
+}
+
+// Describe reports the resolver metadata.
+func (r *DotenvResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// parseDotenv parses dotenv content into a map. Expansions see the entries parsed so
+// far (starting from base, which is not modified), then the process environment.
+// source names the document in error messages.
+func parseDotenv(src, source string, base map[string]string) (map[string]string, error) {
+	vars := make(map[string]string, len(base))
+	for k, v := range base {
+		vars[k] = v
+	}
+	p := &dotenvParser{src: stripBOM(src), source: source, vars: vars}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.src) {
+			return vars, nil
+		}
+		if p.src[p.pos] == '#' {
+			p.skipLine()
+			continue
+		}
+		if err := p.entry(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// dotenvParser is the state of parseDotenv.
+type dotenvParser struct {
+	src    string
+	source string
+	pos    int
+	vars   map[string]string
+}
+
+func (p *dotenvParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.src[:p.pos], "\n") + 1
+	return fmt.Errorf("invalid dotenv file %q, line %d: %s", p.source, line, fmt.Sprintf(format, args...))
+}
+
+// skipBlank skips whitespace, including newlines.
+func (p *dotenvParser) skipBlank() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// skipSpace skips spaces and tabs.
+func (p *dotenvParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// skipLine moves past the next newline.
+func (p *dotenvParser) skipLine() {
+	if i := strings.IndexByte(p.src[p.pos:], '\n'); i >= 0 {
+		p.pos += i + 1
+		return
+	}
+	p.pos = len(p.src)
+}
+
+// entry parses one KEY=value entry.
+func (p *dotenvParser) entry() error {
+	if rest, ok := strings.CutPrefix(p.src[p.pos:], "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+		p.pos += len("export")
+		p.skipSpace()
+	}
+
+	start := p.pos
+	for p.pos < len(p.src) && isDotenvKeyByte(p.src[p.pos], p.pos == start) {
+		p.pos++
+	}
+	key := p.src[start:p.pos]
+	if key == "" {
+		return p.errorf("expected a variable name")
+	}
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != '=' {
+		return p.errorf("expected '=' after %s", key)
+	}
+	p.pos++
+	p.skipSpace()
+
+	var (
+		val string
+		err error
+	)
+	if p.pos < len(p.src) && strings.IndexByte(`'"`+"`", p.src[p.pos]) >= 0 {
+		val, err = p.quoted(p.src[p.pos])
+		if err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] != '\n' && p.src[p.pos] != '\r' && p.src[p.pos] != '#' {
+			return p.errorf("unexpected text after quoted value of %s", key)
+		}
+	} else {
+		end := strings.IndexByte(p.src[p.pos:], '\n')
+		if end < 0 {
+			end = len(p.src) - p.pos
+		}
+		raw := strings.TrimRight(p.src[p.pos:p.pos+end], "\r")
+		p.pos += end
+		raw = strings.TrimSpace(cutInlineCommentUnquoted(raw))
+		if val, err = p.expand(raw, false); err != nil {
+			return err
+		}
+	}
+	p.skipLine()
+	p.vars[key] = val
+	return nil
+}
+
+// quoted parses a value quoted with q, starting at the opening quote.
+func (p *dotenvParser) quoted(q byte) (string, error) {
+	open := p.pos
+	p.pos++
+	for i := p.pos; i < len(p.src); i++ {
+		switch {
+		case p.src[i] == '\\' && q == '"':
+			i++ // the escaped byte cannot close the value
+		case p.src[i] == q:
+			raw := p.src[p.pos:i]
+			p.pos = i + 1
+			if q != '"' {
+				return raw, nil
+			}
+			return p.expand(raw, true)
+		}
+	}
+	p.pos = open
+	return "", p.errorf("unterminated %c-quoted value", q)
+}
+
+// dotenvEscapes maps the escapes of double-quoted values to their replacement.
+var dotenvEscapes = map[byte]string{'n': "\n", 'r': "\r", 't': "\t", '"': `"`, '\\': `\`, '$': "$"}
+
+// expand processes escapes and variable references in raw. In double-quoted values all
+// escapes apply; elsewhere only \$.
+func (p *dotenvParser) expand(raw string, double bool) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == '\\' && i+1 < len(raw):
+			next := raw[i+1]
+			if double {
+				if r, ok := dotenvEscapes[next]; ok {
+					b.WriteString(r)
+					i++
+					continue
+				}
+			} else if next == '$' {
+				b.WriteByte('$')
+				i++
+				continue
+			}
+			b.WriteByte(c)
+		case c == '$' && i+1 < len(raw) && raw[i+1] == '{':
+			end := strings.IndexByte(raw[i+2:], '}')
+			if end < 0 {
+				return "", p.errorf("unterminated ${ in %q", raw)
+			}
+			v, err := p.reference(raw[i+2 : i+2+end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			i += 2 + end
+		case c == '$' && i+1 < len(raw) && isDotenvKeyByte(raw[i+1], true):
+			j := i + 1
+			for j < len(raw) && isDotenvKeyByte(raw[j], false) && raw[j] != '.' && raw[j] != '-' {
+				j++
+			}
+			b.WriteString(p.lookup(raw[i+1 : j]))
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// reference evaluates the inside of ${...}: NAME, NAME:-default or NAME-default.
+func (p *dotenvParser) reference(ref string) (string, error) {
+	name, def, op := ref, "", ""
+	if i := strings.Index(ref, ":-"); i >= 0 {
+		name, def, op = ref[:i], ref[i+2:], ":-"
+	} else if i := strings.IndexByte(ref, '-'); i >= 0 {
+		name, def, op = ref[:i], ref[i+1:], "-"
+	}
+	if name == "" {
+		return "", p.errorf("empty variable name in ${%s}", ref)
+	}
+	v, ok := p.vars[name]
+	if !ok {
+		v, ok = os.LookupEnv(name)
+	}
+	if (op == ":-" && v == "") || (op == "-" && !ok) {
+		return p.expand(def, false)
+	}
+	return v, nil
+}
+
+// lookup returns the value of name from the entries so far or the environment.
+func (p *dotenvParser) lookup(name string) string {
+	if v, ok := p.vars[name]; ok {
+		return v
+	}
+	return os.Getenv(name)
+}
+
+// isDotenvKeyByte reports whether c may appear in a variable name (first: at its start).
+func isDotenvKeyByte(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		return true
+	case c >= '0' && c <= '9', c == '.', c == '-':
+		return !first
+	}
+	return false
+}
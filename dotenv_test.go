@@ -0,0 +1,173 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDotenv = `# app settings
+export APP_NAME=billing
+HOST = db.internal # inline comment
+PORT=5432
+URL=postgres://${HOST}:$PORT/app
+QUOTED="a \"quoted\" value\twith tab"
+SINGLE='no ${HOST} expansion \n here'
+BACKTICK=` + "`raw $HOST`" + `
+MULTILINE="-----BEGIN KEY-----
+abc
+-----END KEY-----"
+SINGLE_MULTI='line1
+line2'
+HASH="value # not a comment"
+ESCAPED=\$HOST
+DEFAULT=${MISSING_DOTENV_VAR:-fallback}
+UNSET_ONLY=${EMPTY-x}|${EMPTY:-y}
+EMPTY=
+AFTER_EMPTY=${EMPTY-x}|${EMPTY:-y}
+FROM_ENV=${DOTENV_TEST_FROM_ENV}
+`
+
+func TestDotenvResolver_Resolve(t *testing.T) {
+	t.Setenv("DOTENV_TEST_FROM_ENV", "env-value")
+	p := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(p, []byte(testDotenv), 0o600))
+	r := &DotenvResolver{}
+
+	cases := []struct {
+		key, want string
+	}{
+		{"APP_NAME", "billing"},
+		{"HOST", "db.internal"},
+		{"URL", "postgres://db.internal:5432/app"},
+		{"QUOTED", "a \"quoted\" value\twith tab"},
+		{"SINGLE", `no ${HOST} expansion \n here`},
+		{"BACKTICK", "raw $HOST"},
+		{"MULTILINE", "-----BEGIN KEY-----\nabc\n-----END KEY-----"},
+		{"SINGLE_MULTI", "line1\nline2"},
+		{"HASH", "value # not a comment"},
+		{"ESCAPED", "$HOST"},
+		{"DEFAULT", "fallback"},
+		{"UNSET_ONLY", "x|y"},
+		{"EMPTY", ""},
+		{"AFTER_EMPTY", "|y"},
+		{"FROM_ENV", "env-value"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.key, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("Missing key", func(t *testing.T) {
+		_, err := r.Resolve(p + "//NOPE")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), ".env") + "//HOST")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestParseDotenv_Errors(t *testing.T) {
+	cases := []struct {
+		name, src, msg string
+	}{
+		{"Missing equals", "A=1\nJUST_A_WORD\n", "line 2: expected '=' after JUST_A_WORD"},
+		{"Bad name", "1A=x\n", "line 1: expected a variable name"},
+		{"Unterminated quote", "A=1\nB=\"open\nC=2\n", `line 2: unterminated "-quoted value`},
+		{"Text after quote", "A='x' y\n", "line 1: unexpected text after quoted value of A"},
+		{"Unterminated expansion", "A=${B\n", "line 1: unterminated ${"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseDotenv(tc.src, "test.env", nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.msg)
+		})
+	}
+}
+
+func TestDotenvResolver_Directory(t *testing.T) {
+	t.Setenv("DOTENV_STAGE", "")
+	t.Setenv("NODE_ENV", "")
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	write(".env", "HOST=db\nPORT=5432\nMODE=base\nSTAGE_ONLY=no\n")
+	write(".env.production", "MODE=production\nSTAGE_ONLY=yes\nURL=${HOST}:${PORT}\n")
+	write(".env.local", "MODE=local\n")
+
+	t.Run("Precedence", func(t *testing.T) {
+		r := &DotenvResolver{Stage: "production"}
+		cases := []struct{ key, want string }{
+			{"HOST", "db"},
+			{"STAGE_ONLY", "yes"},
+			{"URL", "db:5432"},
+			{"MODE", "local"},
+		}
+		for _, tc := range cases {
+			val, err := r.Resolve(dir + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val, tc.key)
+		}
+	})
+
+	t.Run("Layer order", func(t *testing.T) {
+		dir := t.TempDir()
+		layers := map[string]string{
+			".env":                  "A=env\nB=env\nC=env\nD=env\n",
+			".env.production":       "B=stage\nC=stage\nD=stage\n",
+			".env.local":            "C=local\nD=local\n",
+			".env.production.local": "D=stage-local\n",
+			".env.staging.local":    "D=other-stage\n",
+		}
+		for name, content := range layers {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+		}
+
+		val, err := (&DotenvResolver{Stage: "production"}).Resolve(dir)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"A":"env","B":"stage","C":"local","D":"stage-local"}`, val)
+
+		val, err = (&DotenvResolver{}).Resolve(dir)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"A":"env","B":"env","C":"local","D":"local"}`, val, "no stage, no stage files")
+	})
+
+	t.Run("Stage from environment", func(t *testing.T) {
+		t.Setenv("NODE_ENV", "production")
+		val, err := (&DotenvResolver{}).Resolve(dir + "//STAGE_ONLY")
+		require.NoError(t, err)
+		assert.Equal(t, "yes", val)
+	})
+
+	t.Run("No stage", func(t *testing.T) {
+		val, err := (&DotenvResolver{}).Resolve(dir + "//STAGE_ONLY")
+		require.NoError(t, err)
+		assert.Equal(t, "no", val)
+	})
+
+	t.Run("Merged as JSON", func(t *testing.T) {
+		val, err := (&DotenvResolver{}).Resolve(dir)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"HOST":"db","PORT":"5432","MODE":"local","STAGE_ONLY":"no"}`, val)
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		_, err := (&DotenvResolver{}).Resolve(dir + "//NOPE")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Empty directory", func(t *testing.T) {
+		_, err := (&DotenvResolver{}).Resolve(t.TempDir() + "//HOST")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
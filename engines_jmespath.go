@@ -0,0 +1,7 @@
+//go:build resolver_jmespath
+
+package resolver
+
+// JMESPath is opt-in like jq: build with -tags resolver_jmespath, or import
+// github.com/containeroo/resolver/jmespath.
+import _ "github.com/containeroo/resolver/jmespath"
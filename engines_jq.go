@@ -0,0 +1,7 @@
+//go:build resolver_jq
+
+package resolver
+
+// jq pulls in github.com/itchyny/gojq, so it is opt-in: build with -tags resolver_jq,
+// or import github.com/containeroo/resolver/jq.
+import _ "github.com/containeroo/resolver/jq"
@@ -0,0 +1,7 @@
+//go:build resolver_jsonpath
+
+package resolver
+
+// JSONPath is opt-in like jq: build with -tags resolver_jsonpath, or import
+// github.com/containeroo/resolver/jsonpath.
+import _ "github.com/containeroo/resolver/jsonpath"
@@ -1,14 +1,24 @@
 package resolver
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"strings"
 )
 
 // EnvResolver resolves values from environment variables.
 // Format: "env:MY_ENV_VAR".
-type EnvResolver struct{}
+//
+// With FileFallback, an unset MY_ENV_VAR falls back to the contents of the file named
+// by MY_ENV_VAR_FILE (trimmed), the convention of Docker secrets and many official
+// images. The default registry does not enable it; register an EnvResolver with it:
+//
+//	resolver.RegisterResolver("env:", &resolver.EnvResolver{FileFallback: true})
+type EnvResolver struct {
+	FileFallback bool
+}
 
 // Resolve returns the environment variable value or a typed error (ErrBadPath / ErrNotFound).
 func (r *EnvResolver) Resolve(value string) (string, error) {
@@ -17,8 +27,28 @@ func (r *EnvResolver) Resolve(value string) (string, error) {
 		return "", fmt.Errorf("%w: empty environment variable name", ErrBadPath)
 	}
 	res, found := os.LookupEnv(v)
-	if !found {
-		return "", fmt.Errorf("%w: env %q", ErrNotFound, v)
+	if found {
+		return res, nil
+	}
+	if r.FileFallback {
+		if path, ok := os.LookupEnv(v + "_FILE"); ok {
+			return readEnvFile(v+"_FILE", path)
+		}
+	}
+	return "", fmt.Errorf("%w: env %q", ErrNotFound, v)
+}
+
+// readEnvFile returns the trimmed contents of path, named by the variable name.
+func readEnvFile(name, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s (from %s)", ErrNotFound, path, name)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s (from %s)", ErrForbidden, path, name)
+		}
+		return "", fmt.Errorf("failed to read %s file %q: %w", name, path, err)
 	}
-	return res, nil
+	return strings.TrimSpace(string(data)), nil
 }
@@ -1,6 +1,9 @@
 package resolver
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -61,3 +64,45 @@ func TestDefaultRegistry_NoMatchingSchemePassThrough(t *testing.T) {
 		t.Fatalf("ResolveVariable(%q) = %q, want %q", in, got, in)
 	}
 }
+
+func TestEnvResolver_FileFallback(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "db_password")
+	if err := os.WriteFile(secret, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r := &EnvResolver{FileFallback: true}
+
+	t.Run("unset variable reads _FILE", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD_FILE", secret)
+		got, err := r.Resolve("DB_PASSWORD")
+		if err != nil {
+			t.Fatalf("Resolve(DB_PASSWORD) unexpected error: %v", err)
+		}
+		if got != "s3cr3t" {
+			t.Fatalf("Resolve(DB_PASSWORD) = %q, want %q", got, "s3cr3t")
+		}
+	})
+
+	t.Run("set variable wins", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "direct")
+		t.Setenv("DB_PASSWORD_FILE", secret)
+		got, err := r.Resolve("DB_PASSWORD")
+		if err != nil || got != "direct" {
+			t.Fatalf("Resolve(DB_PASSWORD) = %q, %v, want %q", got, err, "direct")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD_FILE", filepath.Join(t.TempDir(), "nope"))
+		if _, err := r.Resolve("DB_PASSWORD"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Resolve(DB_PASSWORD) error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD_FILE", secret)
+		if _, err := (&EnvResolver{}).Resolve("DB_PASSWORD"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Resolve(DB_PASSWORD) error = %v, want ErrNotFound", err)
+		}
+	})
+}
@@ -6,4 +6,9 @@ var (
 	ErrNotFound  = errors.New("resolver: not found")
 	ErrBadPath   = errors.New("resolver: bad path")
 	ErrForbidden = errors.New("resolver: forbidden")
+	ErrTooLarge  = errors.New("resolver: output too large")
+
+	// ErrInvalidOutput is returned by the renderer when RenderOptions.Validate is set
+	// and the rendered file no longer parses in its format.
+	ErrInvalidOutput = errors.New("resolver: invalid rendered output")
 )
@@ -0,0 +1,56 @@
+// Command config loads application settings from tokens: each setting names where
+// its value comes from (a config file, an environment variable, ...), and the
+// resolver package fetches them all at once.
+//
+//	APP_CONFIG=config/app.yaml DB_PASSWORD=s3cr3t go run ./examples/config
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containeroo/resolver"
+)
+
+// Config is the application configuration.
+type Config struct {
+	DBHost     string
+	DBPassword string
+	LogLevel   string
+}
+
+// Settings returns the token of every setting, reading non-secret settings from the
+// YAML file at configFile.
+func Settings(configFile string) map[string]string {
+	return map[string]string{
+		"db.host":     "yaml:" + configFile + "//db.host",
+		"db.password": "env:DB_PASSWORD",
+		"log.level":   "yaml:" + configFile + "//log.level",
+	}
+}
+
+// Load resolves settings with reg.
+func Load(reg *resolver.Registry, settings map[string]string) (Config, error) {
+	vals, err := reg.ResolveMap(settings)
+	if err != nil {
+		return Config{}, err
+	}
+	return Config{
+		DBHost:     vals["db.host"],
+		DBPassword: vals["db.password"],
+		LogLevel:   vals["log.level"],
+	}, nil
+}
+
+func main() {
+	configFile := os.Getenv("APP_CONFIG")
+	if configFile == "" {
+		configFile = "config/app.yaml"
+	}
+	cfg, err := Load(resolver.DefaultRegistry(), Settings(configFile))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err) // nolint:errcheck
+		os.Exit(1)
+	}
+	fmt.Printf("connecting to %s (log level %s)\n", cfg.DBHost, cfg.LogLevel)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/containeroo/resolver"
+	"github.com/containeroo/resolver/resolverharness"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	h := resolverharness.New(t)
+	file := h.WriteYAML("app.yaml", map[string]any{
+		"db":  map[string]any{"host": "db.test"},
+		"log": map[string]any{"level": "debug"},
+	})
+	h.Setenv("DB_PASSWORD", "s3cr3t")
+
+	cfg, err := Load(h.Registry, Settings(file))
+	require.NoError(t, err)
+	assert.Equal(t, Config{DBHost: "db.test", DBPassword: "s3cr3t", LogLevel: "debug"}, cfg)
+}
+
+func TestLoad_MissingSetting(t *testing.T) {
+	h := resolverharness.New(t)
+	file := h.WriteYAML("app.yaml", map[string]any{"db": map[string]any{"host": "db.test"}})
+	h.Setenv("DB_PASSWORD", "s3cr3t")
+
+	_, err := Load(h.Registry, Settings(file))
+	assert.ErrorIs(t, err, resolver.ErrNotFound)
+}
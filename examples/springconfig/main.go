@@ -0,0 +1,31 @@
+// Command springconfig reads a connection string from a Spring Cloud Config server,
+// filling in a password from the environment.
+//
+//	SPRING_CLOUD_CONFIG_URI=http://config:8888 DB_PASSWORD=s3cr3t go run ./examples/springconfig
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containeroo/resolver"
+)
+
+// dsnTemplate is the connection string with its parts as tokens.
+const dsnTemplate = "postgres://${spring-config:billing/prod//db.user}:${env:DB_PASSWORD}@${spring-config:billing/prod//db.host}/billing"
+
+// DSN resolves the connection string with reg.
+func DSN(reg *resolver.Registry) (string, error) {
+	return reg.ResolveString(dsnTemplate)
+}
+
+func main() {
+	reg := resolver.NewDefaultRegistry()
+	reg.Register("spring-config:", &resolver.SpringConfigResolver{})
+	dsn, err := DSN(reg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err) // nolint:errcheck
+		os.Exit(1)
+	}
+	fmt.Println(len(dsn), "byte connection string resolved")
+}
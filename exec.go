@@ -0,0 +1,268 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultExecTimeout bounds a command run by ExecResolver when ExecOptions.Timeout is unset.
+const DefaultExecTimeout = 10 * time.Second
+
+// ExecOptions configures an ExecResolver.
+type ExecOptions struct {
+	Timeout time.Duration // per command; defaults to DefaultExecTimeout
+
+	// AllowedCommands restricts the programs that may run; the first word of the value
+	// must equal one of them ("op" does not allow "/tmp/op"). Empty allows any command.
+	AllowedCommands []string
+
+	// AllowedArgs restricts arguments: every argument must fully match one of these
+	// regular expressions. Empty allows any argument.
+	AllowedArgs []string
+
+	// Registry resolves ${...} tokens inside words after the command line is split, so
+	// a resolved value is always exactly one argument, whatever it contains. The
+	// allowlists apply to the resolved words. Nil leaves words as they are.
+	Registry *Registry
+
+	// Env is the command's environment: "NAME" copies NAME from the current process
+	// (if set), "NAME=value" sets it. Nil inherits the whole environment; an empty,
+	// non-nil slice runs the command with an empty environment.
+	Env []string
+
+	// Dir is the working directory of the command; empty uses the current one.
+	Dir string
+
+	// Path lists the directories searched for commands without a '/' instead of
+	// $PATH; a leading "~" is expanded to the home directory.
+	Path []string
+}
+
+// maxExecStderr bounds the stderr excerpt included in errors.
+const maxExecStderr = 1024
+
+// ExecResolver runs a command and returns its trimmed stdout.
+// Format: "exec:<command> [args...]", e.g. "exec:op read op://vault/item/field".
+// Arguments are split on whitespace; single and double quotes group words and a
+// backslash escapes the next character. No shell is involved. With a Registry, words
+// may contain tokens: "exec:vault kv get -field=token ${env:VAULT_PATH}". Failing
+// commands report the end of their stderr in the error.
+//
+// It is not registered by default; register an instance explicitly:
+//
+//	ex, err := NewExecResolver(ExecOptions{AllowedCommands: []string{"op"}})
+//	RegisterResolver("exec:", ex)
+type ExecResolver struct {
+	timeout  time.Duration
+	commands []string
+	args     []*regexp.Regexp
+	registry *Registry
+	env      []string
+	dir      string
+	path     []string
+}
+
+// NewExecResolver creates an ExecResolver. It fails if an AllowedArgs pattern is invalid.
+func NewExecResolver(opts ExecOptions) (*ExecResolver, error) {
+	r := &ExecResolver{
+		timeout:  opts.Timeout,
+		commands: slices.Clone(opts.AllowedCommands),
+		registry: opts.Registry,
+		env:      slices.Clone(opts.Env),
+		dir:      opts.Dir,
+	}
+	if opts.Env != nil && r.env == nil {
+		r.env = []string{}
+	}
+	for _, dir := range opts.Path {
+		if rest, ok := strings.CutPrefix(dir, "~"); ok && (rest == "" || rest[0] == '/') {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("expand Path entry %q: %w", dir, err)
+			}
+			dir = home + rest
+		}
+		r.path = append(r.path, dir)
+	}
+	if r.timeout <= 0 {
+		r.timeout = DefaultExecTimeout
+	}
+	for _, p := range opts.AllowedArgs {
+		re, err := regexp.Compile(`^(?:` + p + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AllowedArgs pattern %q: %w", p, err)
+		}
+		r.args = append(r.args, re)
+	}
+	return r, nil
+}
+
+func (r *ExecResolver) Resolve(value string) (string, error) {
+	return r.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context; cancelling ctx kills the command.
+func (r *ExecResolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	argv, err := splitArgs(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBadPath, err)
+	}
+	if len(argv) == 0 {
+		return "", fmt.Errorf("%w: empty command", ErrBadPath)
+	}
+	if r.registry != nil {
+		for i, word := range argv {
+			if argv[i], err = r.registry.ResolveStringContext(ctx, word); err != nil {
+				return "", fmt.Errorf("exec argument %d: %w", i, err)
+			}
+		}
+	}
+	if err := r.check(argv); err != nil {
+		return "", err
+	}
+	name, err := r.lookPath(argv[0])
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, argv[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Dir = r.dir
+	if r.env != nil {
+		cmd.Env = r.environ()
+	}
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("%w: command %q", ErrNotFound, argv[0])
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("exec %q: timed out after %s", argv[0], r.timeout)
+		}
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("exec %q: %w", argv[0], ctx.Err())
+		}
+		if msg := stderrExcerpt(stderr.Bytes()); msg != "" {
+			return "", fmt.Errorf("exec %q: %w: %s", argv[0], err, msg)
+		}
+		return "", fmt.Errorf("exec %q: %w", argv[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// lookPath finds name in the configured Path, or leaves it to exec on $PATH.
+func (r *ExecResolver) lookPath(name string) (string, error) {
+	if len(r.path) == 0 || strings.ContainsRune(name, '/') {
+		return name, nil
+	}
+	for _, dir := range r.path {
+		p := filepath.Join(dir, name)
+		if fi, err := os.Stat(p); err == nil && fi.Mode().IsRegular() && fi.Mode()&0o111 != 0 {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("%w: command %q in %s", ErrNotFound, name, strings.Join(r.path, string(os.PathListSeparator)))
+}
+
+// environ builds the scrubbed environment from r.env.
+func (r *ExecResolver) environ() []string {
+	env := make([]string, 0, len(r.env))
+	for _, e := range r.env {
+		if strings.Contains(e, "=") {
+			env = append(env, e)
+		} else if v, ok := os.LookupEnv(e); ok {
+			env = append(env, e+"="+v)
+		}
+	}
+	return env
+}
+
+// stderrExcerpt returns the trimmed end of stderr, at most maxExecStderr bytes.
+func stderrExcerpt(stderr []byte) string {
+	msg := strings.TrimSpace(string(stderr))
+	if len(msg) > maxExecStderr {
+		msg = "..." + strings.ToValidUTF8(msg[len(msg)-maxExecStderr:], "")
+	}
+	return msg
+}
+
+// Describe reports the resolver metadata.
+func (r *ExecResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true}
+}
+
+// check enforces the command and argument allowlists.
+func (r *ExecResolver) check(argv []string) error {
+	if len(r.commands) > 0 && !slices.Contains(r.commands, argv[0]) {
+		return fmt.Errorf("%w: command %q is not allowed", ErrForbidden, argv[0])
+	}
+	if len(r.args) == 0 {
+		return nil
+	}
+	for _, a := range argv[1:] {
+		if !slices.ContainsFunc(r.args, func(re *regexp.Regexp) bool { return re.MatchString(a) }) {
+			return fmt.Errorf("%w: argument %q is not allowed", ErrForbidden, a)
+		}
+	}
+	return nil
+}
+
+// splitArgs splits s into words like a POSIX shell would, without expansions.
+func splitArgs(s string) ([]string, error) {
+	var (
+		args    []string
+		cur     strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, c := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote, inWord = c, true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				args = append(args, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(c)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inWord {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
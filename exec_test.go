@@ -0,0 +1,203 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecResolver_Resolve(t *testing.T) {
+	t.Run("Trimmed stdout", func(t *testing.T) {
+		r, err := NewExecResolver(ExecOptions{})
+		require.NoError(t, err)
+
+		val, err := r.Resolve("echo '  hello world  '")
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", val)
+	})
+
+	t.Run("Double slashes are passed through", func(t *testing.T) {
+		r, err := NewExecResolver(ExecOptions{})
+		require.NoError(t, err)
+
+		val, err := r.Resolve("echo op://vault/item/field")
+		require.NoError(t, err)
+		assert.Equal(t, "op://vault/item/field", val)
+	})
+
+	t.Run("Command not allowed", func(t *testing.T) {
+		r, err := NewExecResolver(ExecOptions{AllowedCommands: []string{"echo"}})
+		require.NoError(t, err)
+
+		_, err = r.Resolve("printf x")
+		require.ErrorIs(t, err, ErrForbidden)
+		_, err = r.Resolve("/bin/echo x")
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Argument allowlist", func(t *testing.T) {
+		r, err := NewExecResolver(ExecOptions{AllowedArgs: []string{`read`, `op://[a-z/]+`}})
+		require.NoError(t, err)
+
+		val, err := r.Resolve("echo read op://vault/item")
+		require.NoError(t, err)
+		assert.Equal(t, "read op://vault/item", val)
+
+		_, err = r.Resolve("echo read op://vault/item; rm")
+		require.ErrorIs(t, err, ErrForbidden)
+		_, err = r.Resolve("echo reader")
+		require.ErrorIs(t, err, ErrForbidden, "patterns are anchored")
+	})
+
+	t.Run("Invalid argument pattern", func(t *testing.T) {
+		_, err := NewExecResolver(ExecOptions{AllowedArgs: []string{"("}})
+		require.Error(t, err)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		r, err := NewExecResolver(ExecOptions{Timeout: 50 * time.Millisecond})
+		require.NoError(t, err)
+
+		_, err = r.Resolve("sleep 5")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "timed out")
+	})
+
+	t.Run("Non-zero exit", func(t *testing.T) {
+		r, err := NewExecResolver(ExecOptions{})
+		require.NoError(t, err)
+
+		_, err = r.Resolve("false")
+		require.Error(t, err)
+	})
+
+	t.Run("Unknown command", func(t *testing.T) {
+		r, err := NewExecResolver(ExecOptions{})
+		require.NoError(t, err)
+
+		_, err = r.Resolve("definitely-not-a-command-xyz")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Empty command", func(t *testing.T) {
+		r, err := NewExecResolver(ExecOptions{})
+		require.NoError(t, err)
+
+		_, err = r.Resolve("   ")
+		require.ErrorIs(t, err, ErrBadPath)
+	})
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"Plain words", "op read  item", []string{"op", "read", "item"}},
+		{"Single quotes", `echo 'a b' c`, []string{"echo", "a b", "c"}},
+		{"Double quotes with escape", `echo "say \"hi\""`, []string{"echo", `say "hi"`}},
+		{"Backslash in single quotes is literal", `echo 'a\b'`, []string{"echo", `a\b`}},
+		{"Escaped space", `echo a\ b`, []string{"echo", "a b"}},
+		{"Empty quoted argument", `echo ""`, []string{"echo", ""}},
+		{"Empty input", "", nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := splitArgs(tc.in)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("Unterminated quote", func(t *testing.T) {
+		_, err := splitArgs(`echo "oops`)
+		require.Error(t, err)
+	})
+}
+
+func TestExecResolver_Subprocess(t *testing.T) {
+	t.Run("Templated arguments stay one word", func(t *testing.T) {
+		reg := NewRegistry()
+		reg.Register("x:", ResolverFunc(func(v string) (string, error) { return "a b; " + v, nil }))
+		r, err := NewExecResolver(ExecOptions{Registry: reg})
+		require.NoError(t, err)
+
+		val, err := r.Resolve(`sh -c 'printf "%s|" "$@"' sh ${x:1} -field=${x:2}`)
+		require.NoError(t, err)
+		assert.Equal(t, "a b; 1|-field=a b; 2|", val)
+	})
+
+	t.Run("Allowlist applies to resolved words", func(t *testing.T) {
+		reg := NewRegistry()
+		reg.Register("x:", ResolverFunc(func(string) (string, error) { return "rm", nil }))
+		r, err := NewExecResolver(ExecOptions{Registry: reg, AllowedCommands: []string{"echo"}})
+		require.NoError(t, err)
+
+		_, err = r.Resolve("${x:cmd} -rf /")
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Scrubbed environment", func(t *testing.T) {
+		t.Setenv("EXEC_KEEP", "kept")
+		t.Setenv("EXEC_DROP", "dropped")
+		r, err := NewExecResolver(ExecOptions{Env: []string{"PATH", "EXEC_KEEP", "EXTRA=1"}})
+		require.NoError(t, err)
+
+		val, err := r.Resolve(`sh -c 'echo "$EXEC_KEEP/$EXEC_DROP/$EXTRA"'`)
+		require.NoError(t, err)
+		assert.Equal(t, "kept//1", val)
+	})
+
+	t.Run("Working directory", func(t *testing.T) {
+		dir := t.TempDir()
+		r, err := NewExecResolver(ExecOptions{Dir: dir})
+		require.NoError(t, err)
+
+		val, err := r.Resolve("pwd")
+		require.NoError(t, err)
+		want, err := filepath.EvalSymlinks(dir)
+		require.NoError(t, err)
+		assert.Equal(t, want, val)
+	})
+
+	t.Run("Search path", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "greet"), []byte("#!/bin/sh\necho hi\n"), 0o755))
+		r, err := NewExecResolver(ExecOptions{Path: []string{dir}})
+		require.NoError(t, err)
+
+		val, err := r.Resolve("greet")
+		require.NoError(t, err)
+		assert.Equal(t, "hi", val)
+
+		_, err = r.Resolve("echo x")
+		require.ErrorIs(t, err, ErrNotFound, "$PATH is not searched")
+	})
+
+	t.Run("Tilde in search path", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		require.NoError(t, os.MkdirAll(filepath.Join(home, "bin"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(home, "bin", "greet"), []byte("#!/bin/sh\necho home\n"), 0o755))
+		r, err := NewExecResolver(ExecOptions{Path: []string{"~/bin"}})
+		require.NoError(t, err)
+
+		val, err := r.Resolve("greet")
+		require.NoError(t, err)
+		assert.Equal(t, "home", val)
+	})
+
+	t.Run("Stderr in error", func(t *testing.T) {
+		r, err := NewExecResolver(ExecOptions{})
+		require.NoError(t, err)
+
+		_, err = r.Resolve(`sh -c 'echo "permission denied: token expired" >&2; exit 3'`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exit status 3: permission denied: token expired")
+	})
+}
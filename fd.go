@@ -0,0 +1,106 @@
+package resolver
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FDResolver resolves values from a document passed on an inherited file descriptor,
+// as launchers and systemd socket activation do to keep secrets off the filesystem.
+// Format: "fd:<n>[//key]" or "fd:<name>[//key]", e.g. "fd:3//TOKEN". A name is looked
+// up in $LISTEN_FDNAMES (systemd's FileDescriptorName=), whose descriptors start at 3.
+// Keys are selected as for stdin: (JSON, YAML or dotenv, detected from the content).
+//
+// Only descriptors listed in Allowed are read, so a config value cannot drain a file,
+// socket or pipe the process uses for something else. The resolver reads a duplicate
+// of the descriptor, leaving the original open; each is read to EOF on first use and
+// its content kept for the lifetime of the resolver, since pipes and sockets cannot be
+// read twice.
+//
+// It is not registered by default; register an instance explicitly:
+//
+//	resolver.RegisterResolver("fd:", &resolver.FDResolver{Allowed: []string{"secrets"}})
+type FDResolver struct {
+	// Allowed lists the descriptors that may be read, as numbers ("3") or
+	// $LISTEN_FDNAMES names ("secrets"). Empty allows none.
+	Allowed []string
+
+	mu   sync.Mutex
+	data map[int][]byte
+}
+
+func (r *FDResolver) Resolve(value string) (string, error) {
+	ref, key := splitFileAndKey(strings.TrimSpace(value))
+	ref = strings.TrimSpace(ref)
+	fd, err := parseFD(ref)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(r.Allowed, ref) && !slices.Contains(r.Allowed, strconv.Itoa(fd)) {
+		return "", fmt.Errorf("%w: file descriptor %q is not allowed", ErrForbidden, ref)
+	}
+	data, err := r.read(fd)
+	if err != nil {
+		return "", err
+	}
+	return selectSniffed(data, key, "fd "+strconv.Itoa(fd))
+}
+
+// Describe reports the resolver metadata.
+func (r *FDResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// read returns the content of fd, reading a duplicate of it on first use.
+func (r *FDResolver) read(fd int) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if data, ok := r.data[fd]; ok {
+		return data, nil
+	}
+
+	dup, err := dupFD(fd)
+	if err != nil {
+		return nil, fmt.Errorf("%w: file descriptor %d: %v", ErrNotFound, fd, err)
+	}
+	f := os.NewFile(uintptr(dup), "fd"+strconv.Itoa(fd))
+	defer f.Close() // nolint:errcheck // closes the duplicate only
+	data, err := io.ReadAll(io.LimitReader(f, DefaultMaxOutputSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: file descriptor %d: %v", ErrNotFound, fd, err)
+	}
+	if len(data) > DefaultMaxOutputSize {
+		return nil, fmt.Errorf("%w: file descriptor %d is larger than %d bytes", ErrTooLarge, fd, DefaultMaxOutputSize)
+	}
+	if r.data == nil {
+		r.data = make(map[int][]byte)
+	}
+	r.data[fd] = data
+	return data, nil
+}
+
+// parseFD returns the descriptor number of ref, a number or a $LISTEN_FDNAMES name.
+func parseFD(ref string) (int, error) {
+	if ref == "" {
+		return 0, fmt.Errorf("%w: empty file descriptor", ErrBadPath)
+	}
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 3 {
+			return 0, fmt.Errorf("%w: file descriptor %d is a standard stream (use stdin: for 0)", ErrBadPath, n)
+		}
+		return n, nil
+	}
+	if names := os.Getenv("LISTEN_FDNAMES"); names != "" {
+		for i, name := range strings.Split(names, ":") {
+			if name == ref {
+				return 3 + i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w: no file descriptor named %q in LISTEN_FDNAMES", ErrNotFound, ref)
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package resolver
+
+import "errors"
+
+// dupFD is not supported on this platform.
+func dupFD(int) (int, error) {
+	return 0, errors.New("file descriptors are not supported on this platform")
+}
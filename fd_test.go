@@ -0,0 +1,131 @@
+//go:build unix
+
+package resolver
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pipeFD returns a descriptor that reads content.
+func pipeFD(t *testing.T, content string) int {
+	t.Helper()
+	rd, w, err := os.Pipe()
+	require.NoError(t, err)
+	_, err = w.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	// A plain descriptor, as a launcher would pass it; rd's finalizer cannot close it.
+	fd, err := syscall.Dup(int(rd.Fd()))
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+	t.Cleanup(func() { syscall.Close(fd) }) // nolint:errcheck
+	return fd
+}
+
+func TestFDResolver_Resolve(t *testing.T) {
+	t.Run("Key from dotenv", func(t *testing.T) {
+		fd := pipeFD(t, "TOKEN=abc\nREGION=eu\n")
+		r := &FDResolver{Allowed: []string{strconv.Itoa(fd)}}
+
+		val, err := r.Resolve(strconv.Itoa(fd) + "//TOKEN")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", val)
+
+		// The pipe is drained; later lookups use the kept content.
+		val, err = r.Resolve(strconv.Itoa(fd) + "//REGION")
+		require.NoError(t, err)
+		assert.Equal(t, "eu", val)
+	})
+
+	t.Run("JSON and whole content", func(t *testing.T) {
+		fd := pipeFD(t, `{"db":{"password":"s3cr3t"}}`)
+		r := &FDResolver{Allowed: []string{strconv.Itoa(fd)}}
+
+		val, err := r.Resolve(strconv.Itoa(fd) + "//db.password")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", val)
+
+		val, err = r.Resolve(strconv.Itoa(fd))
+		require.NoError(t, err)
+		assert.Equal(t, `{"db":{"password":"s3cr3t"}}`, val)
+	})
+
+	t.Run("Standard streams are rejected", func(t *testing.T) {
+		_, err := (&FDResolver{Allowed: []string{"1"}}).Resolve("1")
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Descriptors must be allowed", func(t *testing.T) {
+		fd := pipeFD(t, "TOKEN=abc\n")
+
+		_, err := (&FDResolver{}).Resolve(strconv.Itoa(fd) + "//TOKEN")
+		assert.ErrorIs(t, err, ErrForbidden)
+
+		_, err = (&FDResolver{Allowed: []string{"99"}}).Resolve(strconv.Itoa(fd) + "//TOKEN")
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Allowed by name", func(t *testing.T) {
+		fd := pipeFD(t, "TOKEN=abc\n")
+		names := make([]string, fd-3+1)
+		names[fd-3] = "secrets"
+		t.Setenv("LISTEN_FDNAMES", strings.Join(names, ":"))
+
+		val, err := (&FDResolver{Allowed: []string{"secrets"}}).Resolve("secrets//TOKEN")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", val)
+	})
+
+	t.Run("Caller's descriptor stays open", func(t *testing.T) {
+		f, err := os.CreateTemp(t.TempDir(), "fd")
+		require.NoError(t, err)
+		defer f.Close() // nolint:errcheck
+		_, err = f.WriteString("TOKEN=abc\n")
+		require.NoError(t, err)
+		_, err = f.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+
+		fd := strconv.Itoa(int(f.Fd()))
+		val, err := (&FDResolver{Allowed: []string{fd}}).Resolve(fd + "//TOKEN")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", val)
+
+		_, err = f.Stat()
+		require.NoError(t, err, "the resolver closed the caller's descriptor")
+		_, err = f.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+	})
+
+	t.Run("Not registered by default", func(t *testing.T) {
+		fd := pipeFD(t, "TOKEN=abc\n")
+		val, err := NewDefaultRegistry().ResolveVariable("fd:" + strconv.Itoa(fd) + "//TOKEN")
+		require.NoError(t, err)
+		assert.Equal(t, "fd:"+strconv.Itoa(fd)+"//TOKEN", val)
+	})
+}
+
+func TestParseFD(t *testing.T) {
+	t.Setenv("LISTEN_FDNAMES", "http:secrets")
+
+	fd, err := parseFD("secrets")
+	require.NoError(t, err)
+	assert.Equal(t, 4, fd)
+
+	fd, err = parseFD("7")
+	require.NoError(t, err)
+	assert.Equal(t, 7, fd)
+
+	_, err = parseFD("nope")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = parseFD("")
+	assert.ErrorIs(t, err, ErrBadPath)
+}
@@ -0,0 +1,10 @@
+//go:build unix
+
+package resolver
+
+import "syscall"
+
+// dupFD returns a new descriptor for the same file as fd, which the caller owns.
+func dupFD(fd int) (int, error) {
+	return syscall.Dup(fd)
+}
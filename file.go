@@ -2,8 +2,11 @@ package resolver
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"strings"
 	"unicode"
@@ -24,27 +27,36 @@ func (f *KeyValueFileResolver) Resolve(value string) (string, error) {
 		return "", fmt.Errorf("%w: empty key after // in %q", ErrBadPath, value)
 	}
 
-	file, err := os.Open(filePath)
+	data, err := readTextFile(filePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to open file %q: %w", filePath, err)
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read file %q: %w", filePath, err)
 	}
-	defer file.Close() // nolint:errcheck
+	return selectKeyValue(data, keyPath, filePath)
+}
 
-	if keyPath != "" {
-		return searchKeyInFile(file, keyPath)
-	}
+// Describe reports the resolver metadata.
+func (f *KeyValueFileResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
 
-	// No key specified, read the whole file
-	data, err := io.ReadAll(file)
-	if err != nil {
-		return "", fmt.Errorf("failed to read file %q: %w", filePath, err)
+// selectKeyValue returns the value of key in key=value data, or the whole content if key is empty.
+// source names the document in error messages.
+func selectKeyValue(data []byte, key, source string) (string, error) {
+	if key == "" {
+		return strings.TrimSpace(stripBOM(string(data))), nil
 	}
-	return strings.TrimSpace(stripBOM(string(data))), nil
+	return searchKey(bytes.NewReader(data), key, source)
 }
 
-// searchKeyInFile searches for a specified key in a file and returns its associated value.
-func searchKeyInFile(file *os.File, key string) (string, error) {
-	scanner := bufio.NewScanner(file)
+// searchKey searches for a specified key in key=value content and returns its associated value.
+func searchKey(rd io.Reader, key, source string) (string, error) {
+	scanner := bufio.NewScanner(rd)
 	// Bump max token size to handle unusually long lines.
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
@@ -59,9 +71,9 @@ func searchKeyInFile(file *os.File, key string) (string, error) {
 		}
 	}
 	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("failed scanning file %q: %w", file.Name(), err)
+		return "", fmt.Errorf("failed scanning file %q: %w", source, err)
 	}
-	return "", fmt.Errorf("%w: key %q in %q", ErrNotFound, key, file.Name())
+	return "", fmt.Errorf("%w: key %q in %q", ErrNotFound, key, source)
 }
 
 // parseKV parses a single line of the form:
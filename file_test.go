@@ -158,7 +158,7 @@ T="a\tb\rc"
 	t.Run("File not found", func(t *testing.T) {
 		r := &KeyValueFileResolver{}
 		_, err := r.Resolve(filepath.Join(t.TempDir(), "nope.txt"))
-		require.Error(t, err)
+		require.ErrorIs(t, err, ErrNotFound)
 	})
 
 	t.Run("Expands env vars in path", func(t *testing.T) {
@@ -230,3 +230,25 @@ T="a\tb\rc"
 		require.Error(t, err, "expected scanner to report ErrTooLong for oversized token")
 	})
 }
+
+func FuzzParseKV(f *testing.F) {
+	for _, seed := range []string{
+		"", "#", "=", "=v", "k=", "k=v", "export k=v", "export  k = v # c", "k=\"v # not\" # c",
+		"k='a\\'b'", "k=\"a\\n\\\"b\\\"\"", "k=\"unterminated", "k=\"trailing\\", "k=#v", "k=v#v",
+		"export", "export =v", " k \t=\t v ", "k=\"\"", "k='", "\ufeffk=v",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, line string) {
+		k, v, ok := parseKV(line)
+		if !ok {
+			assert.Empty(t, k)
+			assert.Empty(t, v)
+			return
+		}
+		assert.NotEmpty(t, k)
+		assert.NotContains(t, k, "=")
+		assert.Equal(t, strings.TrimSpace(k), k)
+		assert.Equal(t, strings.TrimSpace(v), v)
+	})
+}
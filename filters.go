@@ -0,0 +1,81 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// escapeFilters are the "#name" suffixes accepted at the end of a ${...} token.
+// Each one turns the resolved value into a complete, quoted literal of its format,
+// so a secret containing quotes or newlines cannot break the surrounding document.
+var escapeFilters = map[string]func(string) string{
+	"jsonstr": quoteJSON,
+	"yamlstr": quoteYAML,
+	"shq":     quoteShell,
+}
+
+// splitFilters separates trailing escape filters from a token, e.g.
+// "env:PW#jsonstr" -> ("env:PW", [quoteJSON]). A '#' followed by anything
+// other than a known filter name stays part of the token.
+func splitFilters(token string) (string, []func(string) string) {
+	var filters []func(string) string
+	for {
+		i := strings.LastIndexByte(token, '#')
+		if i < 0 {
+			break
+		}
+		f, ok := escapeFilters[token[i+1:]]
+		if !ok {
+			break
+		}
+		filters = append([]func(string) string{f}, filters...)
+		token = token[:i]
+	}
+	return token, filters
+}
+
+// quoteJSON returns s as a JSON string literal, including the quotes.
+func quoteJSON(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s) // encoding a string cannot fail
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// quoteYAML returns s as a YAML double-quoted scalar, including the quotes.
+func quoteYAML(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range s {
+		switch {
+		case c == '"':
+			b.WriteString(`\"`)
+		case c == '\\':
+			b.WriteString(`\\`)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case !unicode.IsPrint(c) || c == unicode.ReplacementChar:
+			if c <= 0xff {
+				fmt.Fprintf(&b, `\x%02X`, c)
+			} else {
+				fmt.Fprintf(&b, `\u%04X`, c)
+			}
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// quoteShell returns s as a single-quoted POSIX shell word.
+func quoteShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,76 @@
+package resolver
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestEscapeFilters(t *testing.T) {
+	secret := "p\"a's\\s\nw\td</>\x7f"
+
+	t.Run("jsonstr", func(t *testing.T) {
+		assert.Equal(t, `"p\"a's\\s\nw\td</>`+"\x7f"+`"`, quoteJSON(secret))
+	})
+
+	t.Run("yamlstr round-trips", func(t *testing.T) {
+		var v map[string]string
+		require.NoError(t, yaml.Unmarshal([]byte("k: "+quoteYAML(secret)), &v))
+		assert.Equal(t, secret, v["k"])
+		assert.Equal(t, `"x\x7F\u2028"`, quoteYAML("x\x7f\u2028"))
+	})
+
+	t.Run("shq round-trips", func(t *testing.T) {
+		if _, err := exec.LookPath("sh"); err != nil {
+			t.Skip("sh not available")
+		}
+		out, err := exec.Command("sh", "-c", "printf %s "+quoteShell(secret)).Output()
+		require.NoError(t, err)
+		assert.Equal(t, secret, string(out))
+	})
+}
+
+func TestSplitFilters(t *testing.T) {
+	t.Run("No filter", func(t *testing.T) {
+		expr, filters := splitFilters("env:PW")
+		assert.Equal(t, "env:PW", expr)
+		assert.Empty(t, filters)
+	})
+
+	t.Run("Chained filters", func(t *testing.T) {
+		expr, filters := splitFilters("env:PW#jsonstr#shq")
+		assert.Equal(t, "env:PW", expr)
+		require.Len(t, filters, 2)
+	})
+
+	t.Run("Unknown suffix stays in the token", func(t *testing.T) {
+		expr, filters := splitFilters("file:/etc/app#1.env//KEY")
+		assert.Equal(t, "file:/etc/app#1.env//KEY", expr)
+		assert.Empty(t, filters)
+	})
+}
+
+func TestResolveString_Filters(t *testing.T) {
+	t.Setenv("RESOLVER_TEST_PW", `it's "x"`)
+	r := NewDefaultRegistry()
+
+	got, err := r.ResolveString(`{"pw": ${env:RESOLVER_TEST_PW#jsonstr}}`)
+	require.NoError(t, err)
+	assert.Equal(t, `{"pw": "it's \"x\""}`, got)
+
+	got, err = r.ResolveString(`pw: ${env:RESOLVER_TEST_PW#yamlstr}`)
+	require.NoError(t, err)
+	assert.Equal(t, `pw: "it's \"x\""`, got)
+
+	got, err = r.ResolveString(`PW=${env:RESOLVER_TEST_PW#shq}`)
+	require.NoError(t, err)
+	assert.Equal(t, `PW='it'\''s "x"'`, got)
+
+	t.Run("Filters run after the resolver", func(t *testing.T) {
+		_, err := r.ResolveString(`${env:RESOLVER_TEST_MISSING#jsonstr}`)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,75 @@
+package resolver
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// selectByExtension applies keyPath to data using the format implied by name's extension:
+// .json, .jsonc, .jsonl/.ndjson, .yaml/.yml, .toml, .ini, .xml and .hcl/.tf use the
+// matching parser; anything else is treated as key=value lines. An empty keyPath returns
+// the whole content. UTF-16 content with a byte order mark is transcoded first.
+func selectByExtension(name string, data []byte, keyPath string) (string, error) {
+	data, err := decodeText(data, "")
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return selectJSON(data, keyPath, name)
+	case ".jsonc":
+		return selectJSONC(data, keyPath, name)
+	case ".jsonl", ".ndjson":
+		return selectNDJSON(data, keyPath, name)
+	case ".yaml", ".yml":
+		return selectYAML(data, keyPath, name)
+	case ".toml":
+		return selectTOML(data, keyPath, name)
+	case ".ini":
+		return selectINI(data, keyPath, name)
+	case ".xml":
+		return selectXML(data, keyPath, name)
+	case ".hcl", ".tf":
+		return selectHCL(data, keyPath, name)
+	default:
+		return selectKeyValue(data, keyPath, name)
+	}
+}
+
+// selectSniffed applies keyPath to data whose format is guessed from its content, for
+// sources without a file name: a leading '{' or '[' means JSON, content made only of
+// key=value lines (and comments) is dotenv, anything else is parsed as YAML.
+// An empty keyPath returns the whole content.
+func selectSniffed(data []byte, keyPath, source string) (string, error) {
+	if keyPath == "" {
+		return strings.TrimSpace(stripBOM(string(data))), nil
+	}
+	text := strings.TrimSpace(stripBOM(string(data)))
+	if strings.HasPrefix(text, "{") || strings.HasPrefix(text, "[") {
+		return selectJSON([]byte(text), keyPath, source)
+	}
+	if isKeyValueText(text) {
+		return selectKeyValue([]byte(text), keyPath, source)
+	}
+	return selectYAML([]byte(text), keyPath, source)
+}
+
+// isKeyValueText reports whether every non-blank, non-comment line of text is a
+// key=value pair whose key looks like a variable name (no blanks or ':', which
+// YAML mappings have).
+func isKeyValueText(text string) bool {
+	found := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, _, ok := parseKV(line)
+		if !ok || strings.ContainsAny(k, " \t:") {
+			return false
+		}
+		found = true
+	}
+	return found
+}
@@ -0,0 +1,108 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectByExtension(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name, file, data, key, want string
+	}{
+		{"JSON", "app.json", `{"server":{"host":"h"}}`, "server.host", "h"},
+		{"YAML", "app.yaml", "server:\n  host: h\n", "server.host", "h"},
+		{"YML upper case", "APP.YML", "server:\n  host: h\n", "server.host", "h"},
+		{"TOML", "app.toml", "[server]\nhost = \"h\"\n", "server.host", "h"},
+		{"INI", "app.ini", "[server]\nhost = h\n", "server.host", "h"},
+		{"Key-value fallback", "app.env", "export HOST=h\n", "HOST", "h"},
+		{"Whole content", "app.txt", "  body\n", "", "body"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := selectByExtension(tc.file, []byte(tc.data), tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("Missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := selectByExtension("app.json", []byte(`{}`), "nope")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestSelectSniffed(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name, data, key, want string
+	}{
+		{"JSON object", `{"db":{"host":"h"}}`, "db.host", "h"},
+		{"YAML", "db:\n  host: h\n", "db.host", "h"},
+		{"YAML with equals sign", "db: host=h\n", "db", "host=h"},
+		{"Dotenv", "# bundle\nexport DB_HOST=h\nDB_PORT=5432\n", "DB_HOST", "h"},
+		{"Whole content", "  body\n", "", "body"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := selectSniffed([]byte(tc.data), tc.key, "test")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestSelect_DeterministicEncoding pins that re-encoded objects do not depend on map
+// iteration order: the same document must always produce the same bytes.
+func TestSelect_DeterministicEncoding(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"zeta", "alpha", "mike", "bravo", "yankee", "charlie", "xray", "delta"}
+	var jsonDoc, yamlDoc, tomlDoc, hclDoc strings.Builder
+	jsonDoc.WriteString(`{"obj":{`)
+	yamlDoc.WriteString("obj:\n")
+	tomlDoc.WriteString("[obj]\n")
+	hclDoc.WriteString("obj {\n")
+	for i, k := range keys {
+		if i > 0 {
+			jsonDoc.WriteString(",")
+		}
+		fmt.Fprintf(&jsonDoc, `%q:{"b":%d,"a":[{"y":1,"x":2}]}`, k, i)
+		fmt.Fprintf(&yamlDoc, "  %s:\n    b: %d\n    a:\n      - y: 1\n        x: 2\n", k, i)
+		fmt.Fprintf(&tomlDoc, "%s = { b = %d, a = [{ y = 1, x = 2 }] }\n", k, i)
+		fmt.Fprintf(&hclDoc, "  %s = { b = %d, a = [{ y = 1, x = 2 }] }\n", k, i)
+	}
+	jsonDoc.WriteString("}}")
+	hclDoc.WriteString("}\n")
+
+	docs := map[string]string{
+		"app.json": jsonDoc.String(),
+		"app.yaml": yamlDoc.String(),
+		"app.toml": tomlDoc.String(),
+		"app.hcl":  hclDoc.String(),
+	}
+	for name, doc := range docs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			first, err := selectByExtension(name, []byte(doc), "obj")
+			require.NoError(t, err)
+			for range 50 {
+				got, err := selectByExtension(name, []byte(doc), "obj")
+				require.NoError(t, err)
+				require.Equal(t, first, got)
+			}
+			// Keys come out sorted, whatever their order in the source.
+			assert.Less(t, strings.Index(first, "alpha"), strings.Index(first, "zeta"))
+			assert.Less(t, strings.Index(first, "bravo"), strings.Index(first, "charlie"))
+		})
+	}
+}
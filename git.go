@@ -0,0 +1,190 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitResolver resolves a file at a ref of a git repository.
+// Format: "git:<repo URL>@<ref>:<path>" or "git:<repo URL>@<ref>:<path>//key.path",
+// where the selector is applied according to the file's extension
+// (.json, .yaml, .toml, .ini, .xml, otherwise key=value lines).
+//
+// Example: "git:https://github.com/org/config.git@main:apps/api.yaml//server.host".
+//
+// Refs are fetched shallowly (--depth=1) with the git CLI into a cache directory and
+// reused for CacheTTL before fetching again. Credentials come from the usual git
+// configuration (credential helpers, SSH agent).
+type GitResolver struct {
+	// CacheDir holds the fetched repositories; defaults to <user cache dir>/resolver-git.
+	CacheDir string
+	// CacheTTL is how long a fetched ref is reused; defaults to one minute, < 0 always fetches.
+	CacheTTL time.Duration
+	// Git is the git binary; defaults to "git" on $PATH.
+	Git string
+	// Clock decides when CacheTTL has passed; defaults to SystemClock.
+	Clock Clock
+
+	mu      sync.Mutex
+	fetched map[string]gitFetch // "<url>@<ref>" -> last fetch
+	now     func() time.Time
+}
+
+// gitFetch records the commit a ref pointed to when it was last fetched.
+type gitFetch struct {
+	commit string
+	at     time.Time
+}
+
+func (r *GitResolver) Resolve(value string) (string, error) {
+	return r.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context; cancelling ctx kills the running git command.
+func (r *GitResolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	repo, ref, file, keyPath, err := parseGitRef(value)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	commit, dir, err := r.fetch(ctx, repo, ref)
+	if err != nil {
+		return "", err
+	}
+	data, err := r.git(ctx, dir, "show", commit+":"+file)
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "exists on disk, but not in") {
+			return "", fmt.Errorf("%w: %s in %s@%s", ErrNotFound, file, repo, ref)
+		}
+		return "", fmt.Errorf("read %s from %s@%s: %w", file, repo, ref, err)
+	}
+	return selectByExtension(file, data, keyPath)
+}
+
+// Describe reports the resolver metadata.
+func (r *GitResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile, CapabilityRemote}}
+}
+
+// parseGitRef splits "<url>@<ref>:<path>[//key]". The key is split off first, at the
+// first "//" that does not follow the URL's "scheme:", so keys may contain '@'
+// ("items.@sortdesc(date)", "[email=a@b.c]"). Git ref names cannot contain ':', so
+// the last '@' followed by "<ref>:" separates the URL even for "git@host:" URLs.
+func parseGitRef(value string) (repo, ref, file, keyPath string, err error) {
+	head, keyPath := cutGitKey(value)
+	at := strings.LastIndexByte(head, '@')
+	if at <= 0 {
+		return "", "", "", "", fmt.Errorf("%w: git reference %q must be <url>@<ref>:<path>", ErrBadPath, value)
+	}
+	repo = strings.TrimSpace(head[:at])
+	ref, file, ok := strings.Cut(head[at+1:], ":")
+	if !ok || strings.TrimSpace(ref) == "" {
+		return "", "", "", "", fmt.Errorf("%w: git reference %q must be <url>@<ref>:<path>", ErrBadPath, value)
+	}
+	file = strings.TrimPrefix(strings.TrimSpace(file), "/")
+	if file == "" {
+		return "", "", "", "", fmt.Errorf("%w: empty file path in git reference %q", ErrBadPath, value)
+	}
+	if strings.HasPrefix(ref, "-") {
+		return "", "", "", "", fmt.Errorf("%w: invalid git ref %q", ErrBadPath, ref)
+	}
+	return repo, strings.TrimSpace(ref), file, keyPath, nil
+}
+
+// cutGitKey splits value at the "//" that starts its key path, skipping the "//" of a
+// URL scheme ("https://", "file:///").
+func cutGitKey(value string) (string, string) {
+	for i := 0; ; {
+		j := strings.Index(value[i:], "//")
+		if j < 0 {
+			return value, ""
+		}
+		j += i
+		if j > 0 && value[j-1] == ':' {
+			i = j + 2
+			continue
+		}
+		return value[:j], value[j+2:]
+	}
+}
+
+// fetch returns the commit of repo@ref, fetching it unless a recent fetch is cached.
+// Callers must hold r.mu.
+func (r *GitResolver) fetch(ctx context.Context, repo, ref string) (commit, dir string, err error) {
+	if r.fetched == nil {
+		r.fetched = make(map[string]gitFetch)
+	}
+	if r.now == nil {
+		r.now = SystemClock.Now
+		if r.Clock != nil {
+			r.now = r.Clock.Now
+		}
+	}
+
+	base := r.CacheDir
+	if base == "" {
+		uc, err := os.UserCacheDir()
+		if err != nil {
+			return "", "", fmt.Errorf("locate git cache dir: %w", err)
+		}
+		base = filepath.Join(uc, "resolver-git")
+	}
+	sum := sha256.Sum256([]byte(repo))
+	dir = filepath.Join(base, hex.EncodeToString(sum[:8]))
+
+	ttl := r.CacheTTL
+	if ttl == 0 {
+		ttl = time.Minute
+	}
+	key := repo + "@" + ref
+	if f, ok := r.fetched[key]; ok && ttl > 0 && r.now().Sub(f.at) < ttl {
+		return f.commit, dir, nil
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "HEAD")); err != nil {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return "", "", fmt.Errorf("create git cache dir: %w", err)
+		}
+		if _, err := r.git(ctx, dir, "init", "--quiet", "--bare"); err != nil {
+			return "", "", err
+		}
+	}
+	if _, err := r.git(ctx, dir, "fetch", "--quiet", "--depth=1", "--no-tags", "--", repo, ref); err != nil {
+		return "", "", fmt.Errorf("fetch %s@%s: %w", repo, ref, err)
+	}
+	out, err := r.git(ctx, dir, "rev-parse", "FETCH_HEAD^{commit}")
+	if err != nil {
+		return "", "", err
+	}
+	commit = strings.TrimSpace(string(out))
+	r.fetched[key] = gitFetch{commit: commit, at: r.now()}
+	return commit, dir, nil
+}
+
+// git runs a git subcommand in dir and returns stdout; stderr is included in errors.
+func (r *GitResolver) git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	bin := r.Git
+	if bin == "" {
+		bin = "git"
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin, append([]string{"-C", dir}, args...)...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
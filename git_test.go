@@ -0,0 +1,154 @@
+package resolver
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createGitTestRepo creates a repository with one commit on "main" and returns its path
+// and a function committing further file changes.
+func createGitTestRepo(t *testing.T, files map[string]string) (string, func(map[string]string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	commit := func(files map[string]string) {
+		for name, content := range files {
+			p := filepath.Join(dir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+			require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+		}
+		run("add", "-A")
+		run("commit", "--quiet", "-m", "update")
+	}
+	run("init", "--quiet", "--initial-branch=main")
+	commit(files)
+	return dir, commit
+}
+
+func TestGitResolver_Resolve(t *testing.T) {
+	repo, commit := createGitTestRepo(t, map[string]string{
+		"apps/api.yaml": "server:\n  host: api.local\n",
+		"README":        "hello\n",
+		"users.json":    `{"users":[{"email":"a@b.c","name":"ann"},{"email":"b@b.c","name":"bob"}]}`,
+	})
+	url := "file://" + repo
+
+	t.Run("Selector by extension", func(t *testing.T) {
+		r := &GitResolver{CacheDir: t.TempDir()}
+		val, err := r.Resolve(url + "@main:apps/api.yaml//server.host")
+		require.NoError(t, err)
+		assert.Equal(t, "api.local", val)
+	})
+
+	t.Run("Selector containing @", func(t *testing.T) {
+		r := &GitResolver{CacheDir: t.TempDir()}
+		val, err := r.Resolve(url + "@main:users.json//users.[email=b@b.c].name")
+		require.NoError(t, err)
+		assert.Equal(t, "bob", val)
+
+		val, err = r.Resolve(url + "@main:users.json//users.@sortdesc(name).0.email")
+		require.NoError(t, err)
+		assert.Equal(t, "b@b.c", val)
+	})
+
+	t.Run("Whole file", func(t *testing.T) {
+		r := &GitResolver{CacheDir: t.TempDir()}
+		val, err := r.Resolve(url + "@main:README")
+		require.NoError(t, err)
+		assert.Equal(t, "hello", val)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		r := &GitResolver{CacheDir: t.TempDir()}
+		_, err := r.Resolve(url + "@main:nope.yaml")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing ref", func(t *testing.T) {
+		r := &GitResolver{CacheDir: t.TempDir()}
+		_, err := r.Resolve(url + "@nope:README")
+		require.Error(t, err)
+	})
+
+	t.Run("Fetched refs are cached for the TTL", func(t *testing.T) {
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		r := &GitResolver{CacheDir: t.TempDir(), CacheTTL: time.Minute}
+		r.now = func() time.Time { return now }
+
+		val, err := r.Resolve(url + "@main:apps/api.yaml//server.host")
+		require.NoError(t, err)
+		assert.Equal(t, "api.local", val)
+
+		commit(map[string]string{"apps/api.yaml": "server:\n  host: api.example.com\n"})
+		val, err = r.Resolve(url + "@main:apps/api.yaml//server.host")
+		require.NoError(t, err)
+		assert.Equal(t, "api.local", val, "within TTL the cached commit is used")
+
+		now = now.Add(time.Minute)
+		val, err = r.Resolve(url + "@main:apps/api.yaml//server.host")
+		require.NoError(t, err)
+		assert.Equal(t, "api.example.com", val)
+	})
+}
+
+func TestParseGitRef(t *testing.T) {
+	t.Parallel()
+
+	t.Run("HTTPS URL", func(t *testing.T) {
+		t.Parallel()
+		repo, ref, file, key, err := parseGitRef("https://host/repo.git@main:config/app.yaml//server.host")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://host/repo.git", "main", "config/app.yaml", "server.host"}, []string{repo, ref, file, key})
+	})
+
+	t.Run("SSH URL without selector", func(t *testing.T) {
+		t.Parallel()
+		repo, ref, file, key, err := parseGitRef("git@github.com:org/repo.git@v1.2.0:app.env")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"git@github.com:org/repo.git", "v1.2.0", "app.env", ""}, []string{repo, ref, file, key})
+	})
+
+	t.Run("Keys containing @", func(t *testing.T) {
+		t.Parallel()
+		for in, want := range map[string][]string{
+			"https://host/repo.git@main:releases.json//items.@sortdesc(date).0": {
+				"https://host/repo.git", "main", "releases.json", "items.@sortdesc(date).0",
+			},
+			"git@github.com:org/repo.git@v1:users.yaml//users.[email=a@b.c].name": {
+				"git@github.com:org/repo.git", "v1", "users.yaml", "users.[email=a@b.c].name",
+			},
+			"file:///srv/repo@main:links.json//[url~=^https://x@y].id": {
+				"file:///srv/repo", "main", "links.json", "[url~=^https://x@y].id",
+			},
+		} {
+			repo, ref, file, key, err := parseGitRef(in)
+			require.NoError(t, err, in)
+			assert.Equal(t, want, []string{repo, ref, file, key}, in)
+		}
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		t.Parallel()
+		for _, in := range []string{"https://host/repo.git", "https://host/repo.git@main", "https://host/repo.git@:a", "repo@main:", "repo@--upload-pack=x:a", "https://host/repo.git//a@b:c"} {
+			_, _, _, _, err := parseGitRef(in)
+			require.ErrorIs(t, err, ErrBadPath, in)
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GitConfigResolver resolves a value from a git config file.
+// Format: "gitconfig:/path/config//section.subsection.key", e.g.
+// "gitconfig:~/.gitconfig//user.email" or "gitconfig:.git/config//remote.origin.url".
+//
+// As with git, section and key names are case-insensitive and subsections are
+// case-sensitive; the subsection is everything between the first and the last dot.
+// The last value of a key wins, [include] paths are followed (relative to the
+// including file) and [includeIf] sections are ignored. A key without a value is
+// "true". If no key is provided, returns the whole file as a string.
+type GitConfigResolver struct{}
+
+func (r *GitConfigResolver) Resolve(value string) (string, error) {
+	filePath, key := splitFileAndKey(value)
+	filePath = expandHome(os.ExpandEnv(filePath))
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	if key == "" {
+		data, err := readGitConfig(filePath)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	want, err := normalizeGitConfigKey(key)
+	if err != nil {
+		return "", err
+	}
+	vars := make(map[string]string)
+	if err := parseGitConfigFile(filePath, vars, 0); err != nil {
+		return "", err
+	}
+	v, ok := vars[want]
+	if !ok {
+		return "", fmt.Errorf("%w: key %q in %q", ErrNotFound, key, filePath)
+	}
+	return v, nil
+}
+
+// Describe reports the resolver metadata.
+func (r *GitConfigResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// maxGitConfigIncludeDepth bounds nested include directives, like git's own limit.
+const maxGitConfigIncludeDepth = 10
+
+// readGitConfig reads a git config file.
+func readGitConfig(p string) ([]byte, error) {
+	data, err := readTextFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, p)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, fmt.Errorf("%w: %s", ErrForbidden, p)
+		}
+		return nil, fmt.Errorf("failed to read git config file %q: %w", p, err)
+	}
+	return data, nil
+}
+
+// normalizeGitConfigKey lowercases the section and key name of "section[.sub].key".
+func normalizeGitConfigKey(key string) (string, error) {
+	first, last := strings.IndexByte(key, '.'), strings.LastIndexByte(key, '.')
+	if first <= 0 || last == len(key)-1 {
+		return "", fmt.Errorf("%w: git config key %q must be section.key or section.subsection.key", ErrBadPath, key)
+	}
+	section, name := strings.ToLower(key[:first]), strings.ToLower(key[last+1:])
+	if first == last {
+		return section + "." + name, nil
+	}
+	return section + "." + key[first+1:last] + "." + name, nil
+}
+
+// parseGitConfigFile adds the entries of the config file at p to vars, keyed by their
+// normalized name; later entries overwrite earlier ones.
+func parseGitConfigFile(p string, vars map[string]string, depth int) error {
+	if depth > maxGitConfigIncludeDepth {
+		return fmt.Errorf("%w: git config includes nested too deeply at %s", ErrBadPath, p)
+	}
+	data, err := readGitConfig(p)
+	if err != nil {
+		return err
+	}
+
+	section := ""
+	lines := strings.Split(stripBOM(string(data)), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			s, rest, err := parseGitConfigSection(line)
+			if err != nil {
+				return fmt.Errorf("invalid git config %q, line %d: %v", p, lineNo, err)
+			}
+			section = s
+			if line = strings.TrimSpace(rest); line == "" || line[0] == '#' || line[0] == ';' {
+				continue
+			}
+		}
+		if section == "" {
+			return fmt.Errorf("invalid git config %q, line %d: entry outside a section", p, lineNo)
+		}
+
+		// Values may continue on the next line after a trailing backslash.
+		for strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + lines[i]
+		}
+		name, raw, hasValue := strings.Cut(line, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			return fmt.Errorf("invalid git config %q, line %d: missing key name", p, lineNo)
+		}
+		val := "true"
+		if hasValue {
+			if val, err = parseGitConfigValue(raw); err != nil {
+				return fmt.Errorf("invalid git config %q, line %d: %v", p, lineNo, err)
+			}
+		}
+
+		if section == "include" && name == "path" {
+			inc := expandHome(val)
+			if !filepath.IsAbs(inc) {
+				inc = filepath.Join(filepath.Dir(p), inc)
+			}
+			if err := parseGitConfigFile(inc, vars, depth+1); err != nil && !errors.Is(err, ErrNotFound) {
+				return err // git ignores missing include files
+			}
+			continue
+		}
+		vars[section+"."+name] = val
+	}
+	return nil
+}
+
+// parseGitConfigSection parses a section header at the start of line and returns the
+// normalized section name and the text after the header.
+func parseGitConfigSection(line string) (string, string, error) {
+	end := strings.IndexByte(line, ']')
+	if q := strings.IndexByte(line, '"'); q >= 0 && q < end {
+		// [section "subsection"]: the subsection may contain ']' and escapes.
+		section := strings.ToLower(strings.TrimSpace(line[1:q]))
+		var sub strings.Builder
+		for i := q + 1; i < len(line); i++ {
+			switch c := line[i]; c {
+			case '\\':
+				if i+1 < len(line) {
+					i++
+					sub.WriteByte(line[i])
+				}
+			case '"':
+				rest := strings.TrimSpace(line[i+1:])
+				if !strings.HasPrefix(rest, "]") {
+					return "", "", errors.New("expected ']' after subsection")
+				}
+				return section + "." + sub.String(), rest[1:], nil
+			default:
+				sub.WriteByte(c)
+			}
+		}
+		return "", "", errors.New("unterminated subsection")
+	}
+	if end < 0 {
+		return "", "", errors.New("unterminated section header")
+	}
+	// [section] or the deprecated [section.subsection], which is lowercased.
+	name := strings.ToLower(strings.TrimSpace(line[1:end]))
+	if name == "" {
+		return "", "", errors.New("empty section name")
+	}
+	return name, line[end+1:], nil
+}
+
+// parseGitConfigValue unquotes a value, processing escapes and dropping comments.
+func parseGitConfigValue(raw string) (string, error) {
+	var (
+		b      strings.Builder
+		quoted bool
+		keep   int // length of b that trailing-space trimming must not touch
+	)
+	trimmed := func() string {
+		s := b.String()
+		return s[:keep] + strings.TrimRight(s[keep:], " \t")
+	}
+	raw = strings.TrimSpace(raw)
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; {
+		case c == '"':
+			quoted = !quoted
+			keep = b.Len()
+		case c == '\\':
+			if i+1 >= len(raw) {
+				return "", errors.New("trailing backslash")
+			}
+			i++
+			switch raw[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'b':
+				b.WriteByte('\b')
+			case '"', '\\':
+				b.WriteByte(raw[i])
+			default:
+				return "", fmt.Errorf("invalid escape \\%c", raw[i])
+			}
+			keep = b.Len()
+		case !quoted && (c == '#' || c == ';'):
+			return trimmed(), nil
+		default:
+			b.WriteByte(c)
+			if quoted {
+				keep = b.Len()
+			}
+		}
+	}
+	if quoted {
+		return "", errors.New("unterminated quote")
+	}
+	return trimmed(), nil
+}
@@ -0,0 +1,97 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGitConfig = `# global settings
+[user]
+	name = Jane Doe
+	email = jane@old.example.com
+[core]
+	autocrlf = input ; trailing comment
+	bare
+[remote "origin"]
+	url = git@github.com:acme/app.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+[branch "Feature.X"]
+	remote = origin
+[alias]
+	lg = "log --graph # not a comment"
+	quoted = "  padded  "
+	long = one \
+two
+	esc = "tab\there"
+[Include]
+	path = extra.inc
+	path = missing.inc
+[user]
+	email = jane@example.com
+`
+
+func TestGitConfigResolver_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(p, []byte(testGitConfig), 0o666))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "extra.inc"), []byte("[credential]\n\thelper = store\n"), 0o666))
+	r := &GitConfigResolver{}
+
+	cases := []struct {
+		name, key, want string
+	}{
+		{"Simple", "user.name", "Jane Doe"},
+		{"Last value wins", "user.email", "jane@example.com"},
+		{"Case-insensitive names", "USER.Name", "Jane Doe"},
+		{"Inline comment", "core.autocrlf", "input"},
+		{"Key without value", "core.bare", "true"},
+		{"Subsection", "remote.origin.url", "git@github.com:acme/app.git"},
+		{"Subsection with dot", "branch.Feature.X.remote", "origin"},
+		{"Quoted value keeps comment chars", "alias.lg", "log --graph # not a comment"},
+		{"Quoted whitespace", "alias.quoted", "  padded  "},
+		{"Line continuation", "alias.long", "one two"},
+		{"Escape", "alias.esc", "tab\there"},
+		{"Included file", "credential.helper", "store"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("Whole file", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(testGitConfig), val)
+	})
+
+	t.Run("Subsection is case-sensitive", func(t *testing.T) {
+		_, err := r.Resolve(p + "//branch.feature.x.remote")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid key", func(t *testing.T) {
+		_, err := r.Resolve(p + "//user")
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Malformed file", func(t *testing.T) {
+		bad := filepath.Join(dir, "bad")
+		require.NoError(t, os.WriteFile(bad, []byte("[user\nname = x\n"), 0o666))
+		_, err := r.Resolve(bad + "//user.name")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(dir, "nope") + "//user.name")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,156 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// HCLResolver resolves a value by loading an HCL file (Terraform, Packer, Nomad style)
+// and extracting a nested key. Format: "hcl:/path/file.hcl//key1.key2.keyN", e.g.
+// "hcl:/infra/main.tf//resource.aws_instance.web.ami".
+//
+// Attributes become keys; a block becomes a key of its type, nested under each of its
+// labels ("resource" "aws_instance" "web" { ... } is resource.aws_instance.web).
+// Repeated blocks with the same type and labels become a list. Expressions are
+// evaluated without variables or functions; those that need them (var.region,
+// "${local.name}-db", ...) are returned as their source text. Strings are returned
+// as-is, other values as JSON. If no key is provided, returns the whole HCL file as
+// a string.
+type HCLResolver struct{}
+
+func (r *HCLResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := readTextFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read HCL file %q: %w", filePath, err)
+	}
+
+	return selectHCL(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *HCLResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectHCL returns the value at keyPath in HCL data, or the whole document if keyPath is empty.
+// source names the document in error messages.
+func selectHCL(data []byte, keyPath, source string) (string, error) {
+	if keyPath == "" {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	file, diags := hclsyntax.ParseConfig(data, source, hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", fmt.Errorf("failed to parse HCL in %q: %w", source, diags)
+	}
+	content := hclBody(file.Body.(*hclsyntax.Body), data)
+
+	val, err := selectPath(content, keyPath)
+	if err != nil {
+		return "", fmt.Errorf("%w: key path %q in HCL %q: %v", ErrNotFound, keyPath, source, err)
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	out, _ := json.Marshal(val)
+	return string(out), nil
+}
+
+// hclBody converts a body into map[string]any; src is the file content, used for the
+// source text of expressions that cannot be evaluated.
+func hclBody(body *hclsyntax.Body, src []byte) map[string]any {
+	out := make(map[string]any, len(body.Attributes)+len(body.Blocks))
+	for name, attr := range body.Attributes {
+		out[name] = hclExpr(attr.Expr, src)
+	}
+	for _, block := range body.Blocks {
+		key := append([]string{block.Type}, block.Labels...)
+		parent := out
+		for _, k := range key[:len(key)-1] {
+			next, ok := parent[k].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				parent[k] = next
+			}
+			parent = next
+		}
+		last := key[len(key)-1]
+		content := hclBody(block.Body, src)
+		switch prev := parent[last].(type) {
+		case nil:
+			parent[last] = content
+		case []any:
+			parent[last] = append(prev, content)
+		default:
+			parent[last] = []any{prev, content}
+		}
+	}
+	return out
+}
+
+// hclExpr evaluates expr without variables or functions, falling back to its source text.
+func hclExpr(expr hclsyntax.Expression, src []byte) any {
+	v, diags := expr.Value(nil)
+	if diags.HasErrors() || !v.IsWhollyKnown() {
+		rng := expr.Range()
+		return string(rng.SliceBytes(src))
+	}
+	return ctyToGo(v)
+}
+
+// ctyToGo converts a known cty value to the generic JSON-like types selectPath walks.
+func ctyToGo(v cty.Value) any {
+	if v.IsNull() {
+		return nil
+	}
+	t := v.Type()
+	switch {
+	case t == cty.String:
+		return v.AsString()
+	case t == cty.Bool:
+		return v.True()
+	case t == cty.Number:
+		bf := v.AsBigFloat()
+		if i, acc := bf.Int64(); acc == 0 {
+			return i
+		}
+		f, _ := bf.Float64()
+		return f
+	case t.IsListType(), t.IsTupleType(), t.IsSetType():
+		out := make([]any, 0, v.LengthInt())
+		for it := v.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			out = append(out, ctyToGo(ev))
+		}
+		return out
+	case t.IsMapType(), t.IsObjectType():
+		out := make(map[string]any, v.LengthInt())
+		for it := v.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+			out[k.AsString()] = ctyToGo(ev)
+		}
+		return out
+	}
+	return nil
+}
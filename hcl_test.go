@@ -0,0 +1,95 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testHCL = `
+region = "eu-central-1"
+replicas = 3
+ratio = 0.5
+enabled = true
+zones = ["a", "b"]
+tags = {
+  team = "platform"
+}
+name = "${var.prefix}-db"
+
+resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t3.micro"
+}
+
+resource "aws_instance" "worker" {
+  ami = "ami-456"
+}
+
+ingress {
+  port = 80
+}
+
+ingress {
+  port = 443
+}
+`
+
+func TestHCLResolver_Resolve(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "main.tf")
+	require.NoError(t, os.WriteFile(p, []byte(testHCL), 0o666))
+	r := &HCLResolver{}
+
+	cases := []struct {
+		name, key, want string
+	}{
+		{"String attribute", "region", "eu-central-1"},
+		{"Number", "replicas", "3"},
+		{"Float", "ratio", "0.5"},
+		{"Bool", "enabled", "true"},
+		{"List", "zones", `["a","b"]`},
+		{"List index", "zones.1", "b"},
+		{"Object", "tags.team", "platform"},
+		{"Unevaluated expression", "name", `"${var.prefix}-db"`},
+		{"Labeled block", "resource.aws_instance.web.ami", "ami-123"},
+		{"Sibling block", "resource.aws_instance.worker.ami", "ami-456"},
+		{"Repeated block", "ingress.1.port", "443"},
+		{"Filter on repeated block", "ingress.[port=80].port", "80"},
+		{"Block as JSON", "resource.aws_instance.worker", `{"ami":"ami-456"}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("Whole file", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(testHCL), val)
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		_, err := r.Resolve(p + "//resource.aws_instance.nope")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid HCL", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.hcl")
+		require.NoError(t, os.WriteFile(bad, []byte("resource {"), 0o666))
+		_, err := r.Resolve(bad + "//resource")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "nope.hcl") + "//region")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,178 @@
+package resolver
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HtpasswdResolver resolves the password hash of a user in an Apache htpasswd or
+// htdigest file. Format: "htpasswd:/path/file//<user>", e.g.
+// "htpasswd:/etc/nginx/.htpasswd//alice", or "//<user>:<realm>" to pick a realm of
+// an htdigest file.
+//
+// With "?verify=<password>" after the user, the password is checked against the hash
+// instead and the result is "true" or "false". bcrypt ($2y$, $2a$, $2b$), MD5
+// ($apr1$, $1$) and {SHA} hashes of htpasswd files and the MD5 digests of htdigest
+// files can be verified; other hashes return ErrBadPath.
+type HtpasswdResolver struct{}
+
+func (r *HtpasswdResolver) Resolve(value string) (string, error) {
+	filePath, key := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+	key, verify, verifying := strings.Cut(key, "?verify=")
+	user, realm, byRealm := strings.Cut(key, ":")
+	if user == "" {
+		return "", fmt.Errorf("%w: missing user in %q", ErrBadPath, value)
+	}
+
+	data, err := readTextFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read htpasswd file %q: %w", filePath, err)
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, ":", 3)
+		if fields[0] != user || len(fields) < 2 {
+			continue
+		}
+		if len(fields) == 3 { // htdigest: user:realm:hash
+			if byRealm && fields[1] != realm {
+				continue
+			}
+			if !verifying {
+				return fields[2], nil
+			}
+			sum := md5.Sum([]byte(user + ":" + fields[1] + ":" + verify))
+			return strconv.FormatBool(subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(fields[2])) == 1), nil
+		}
+		if byRealm {
+			continue
+		}
+		if !verifying {
+			return fields[1], nil
+		}
+		ok, err := verifyHtpasswd(fields[1], verify)
+		if err != nil {
+			return "", fmt.Errorf("user %q in %q: %w", user, filePath, err)
+		}
+		return strconv.FormatBool(ok), nil
+	}
+	if err := sc.Err(); err != nil {
+		return "", fmt.Errorf("failed to read htpasswd file %q: %w", filePath, err)
+	}
+	return "", fmt.Errorf("%w: user %q in %s", ErrNotFound, key, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *HtpasswdResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilitySelector}}
+}
+
+// verifyHtpasswd reports whether password matches an htpasswd hash.
+func verifyHtpasswd(hash, password string) (bool, error) {
+	switch {
+	case strings.HasPrefix(hash, "$2y$"), strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	case strings.HasPrefix(hash, "$apr1$"), strings.HasPrefix(hash, "$1$"):
+		magic := hash[:strings.IndexByte(hash[1:], '$')+2]
+		salt, _, _ := strings.Cut(hash[len(magic):], "$")
+		return subtle.ConstantTimeCompare([]byte(md5Crypt(password, salt, magic)), []byte(hash)) == 1, nil
+	case strings.HasPrefix(hash, "{SHA}"):
+		sum := sha1.Sum([]byte(password))
+		return subtle.ConstantTimeCompare([]byte(base64.StdEncoding.EncodeToString(sum[:])), []byte(hash[5:])) == 1, nil
+	}
+	return false, fmt.Errorf("%w: unsupported password hash", ErrBadPath)
+}
+
+// cryptAlphabet is the base-64 alphabet of crypt(3).
+const cryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// md5Crypt computes the MD5-based crypt hash ("$1$" or Apache's "$apr1$" magic).
+func md5Crypt(password, salt, magic string) string {
+	if len(salt) > 8 {
+		salt = salt[:8]
+	}
+	pw := []byte(password)
+
+	alt := md5.Sum([]byte(password + salt + password))
+	ctx := md5.New()
+	ctx.Write([]byte(password + magic + salt))
+	for i := len(pw); i > 0; i -= 16 {
+		ctx.Write(alt[:min(i, 16)])
+	}
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 == 1 {
+			ctx.Write([]byte{0})
+		} else {
+			ctx.Write(pw[:1])
+		}
+	}
+	final := ctx.Sum(nil)
+
+	for i := range 1000 {
+		c := md5.New()
+		if i&1 == 1 {
+			c.Write(pw)
+		} else {
+			c.Write(final)
+		}
+		if i%3 != 0 {
+			c.Write([]byte(salt))
+		}
+		if i%7 != 0 {
+			c.Write(pw)
+		}
+		if i&1 == 1 {
+			c.Write(final)
+		} else {
+			c.Write(pw)
+		}
+		final = c.Sum(nil)
+	}
+
+	var b strings.Builder
+	b.WriteString(magic + salt + "$")
+	to64 := func(v uint32, n int) {
+		for range n {
+			b.WriteByte(cryptAlphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+	for _, g := range [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}} {
+		to64(uint32(final[g[0]])<<16|uint32(final[g[1]])<<8|uint32(final[g[2]]), 4)
+	}
+	to64(uint32(final[11]), 2)
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package resolver
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHtpasswdResolver_Resolve(t *testing.T) {
+	bc, err := bcrypt.GenerateFromPassword([]byte("bcrypt-pw"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	htpasswd := filepath.Join(dir, ".htpasswd")
+	require.NoError(t, os.WriteFile(htpasswd, []byte(
+		"# users\n"+
+			"alice:"+string(bc)+"\n"+
+			"bob:$apr1$qHDFfhPC$nITSVHgYbDAK1Y0acGRnY0\n"+
+			"carol:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=\n"+
+			"dave:$1$abc$Kb85XxsXB.VXinPhbS4431\n"+
+			"erin:abJnggxhB/yWI\n",
+	), 0o600))
+	htdigest := filepath.Join(dir, ".htdigest")
+	require.NoError(t, os.WriteFile(htdigest, []byte(
+		"alice:Public:0f0a34a56c2c4e2c2b4d0c4c4b41e2d4\n"+
+			"alice:Private:8e6a7e4c0f4bb3bd2ec6bb0e7c2bb58c\n",
+	), 0o600))
+	r := &HtpasswdResolver{}
+
+	t.Run("Hash", func(t *testing.T) {
+		val, err := r.Resolve(htpasswd + "//bob")
+		require.NoError(t, err)
+		assert.Equal(t, "$apr1$qHDFfhPC$nITSVHgYbDAK1Y0acGRnY0", val)
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		cases := []struct {
+			name, ref, want string
+		}{
+			{"bcrypt match", "alice?verify=bcrypt-pw", "true"},
+			{"bcrypt mismatch", "alice?verify=wrong", "false"},
+			{"apr1 match", "bob?verify=myPassword", "true"},
+			{"apr1 mismatch", "bob?verify=nope", "false"},
+			{"SHA match", "carol?verify=password", "true"},
+			{"MD5 crypt match", "dave?verify=pw", "true"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				val, err := r.Resolve(htpasswd + "//" + tc.ref)
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, val)
+			})
+		}
+	})
+
+	t.Run("Unsupported hash", func(t *testing.T) {
+		_, err := r.Resolve(htpasswd + "//erin?verify=x")
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("htdigest", func(t *testing.T) {
+		val, err := r.Resolve(htdigest + "//alice:Private")
+		require.NoError(t, err)
+		assert.Equal(t, "8e6a7e4c0f4bb3bd2ec6bb0e7c2bb58c", val)
+
+		val, err = r.Resolve(htdigest + "//alice")
+		require.NoError(t, err)
+		assert.Equal(t, "0f0a34a56c2c4e2c2b4d0c4c4b41e2d4", val)
+	})
+
+	t.Run("htdigest verify", func(t *testing.T) {
+		digest := filepath.Join(dir, "verify.htdigest")
+		require.NoError(t, os.WriteFile(digest, []byte("alice:Private:"+md5Hex("alice:Private:secret")+"\n"), 0o600))
+		val, err := r.Resolve(digest + "//alice:Private?verify=secret")
+		require.NoError(t, err)
+		assert.Equal(t, "true", val)
+		val, err = r.Resolve(digest + "//alice:Private?verify=nope")
+		require.NoError(t, err)
+		assert.Equal(t, "false", val)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		_, err := r.Resolve(htpasswd + "//mallory")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(dir, "nope") + "//alice")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestMD5Crypt(t *testing.T) {
+	// Reference values from openssl passwd -apr1 / -1.
+	assert.Equal(t, "$apr1$qHDFfhPC$nITSVHgYbDAK1Y0acGRnY0", md5Crypt("myPassword", "qHDFfhPC", "$apr1$"))
+	assert.Equal(t, "$1$abc$Kb85XxsXB.VXinPhbS4431", md5Crypt("pw", "abc", "$1$"))
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
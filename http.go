@@ -0,0 +1,44 @@
+package resolver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// defaultHTTPClient is used by HTTP-backed resolvers when no client is configured.
+var defaultHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// httpClientOrDefault returns c, or the package default client if c is nil.
+func httpClientOrDefault(c *http.Client) *http.Client {
+	if c != nil {
+		return c
+	}
+	return defaultHTTPClient
+}
+
+// doHTTP executes req and returns the response body.
+// Non-2xx responses are mapped to ErrNotFound (404) and ErrForbidden (401/403).
+func doHTTP(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := httpClientOrDefault(client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request %s %s: %w", req.Method, req.URL.Redacted(), err)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response from %s: %w", req.URL.Redacted(), err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, req.URL.Redacted())
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %s (%s)", ErrForbidden, req.URL.Redacted(), resp.Status)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, req.URL.Redacted())
+	}
+	return body, nil
+}
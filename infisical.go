@@ -0,0 +1,91 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// InfisicalResolver resolves secrets from an Infisical instance.
+// Format: "infisical:<workspaceID>/<environment>/<SECRET_NAME>" or
+// "infisical:<workspaceID>/<environment>/<folder/path>/<SECRET_NAME>".
+//
+// BaseURL defaults to $INFISICAL_API_URL (or https://app.infisical.com) and
+// Token defaults to $INFISICAL_TOKEN.
+type InfisicalResolver struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+func (r *InfisicalResolver) Resolve(value string) (string, error) {
+	return r.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context bounding the Infisical request.
+func (r *InfisicalResolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	parts := strings.Split(strings.Trim(strings.TrimSpace(value), "/"), "/")
+	if len(parts) < 3 {
+		return "", fmt.Errorf("%w: infisical reference %q must be workspace/environment/[path/]name", ErrBadPath, value)
+	}
+	for _, p := range parts {
+		if strings.TrimSpace(p) == "" {
+			return "", fmt.Errorf("%w: empty segment in infisical reference %q", ErrBadPath, value)
+		}
+	}
+	workspace, environment, name := parts[0], parts[1], parts[len(parts)-1]
+	secretPath := "/" + strings.Join(parts[2:len(parts)-1], "/")
+
+	token := r.Token
+	if token == "" {
+		token = os.Getenv("INFISICAL_TOKEN")
+	}
+	if token == "" {
+		return "", fmt.Errorf("%w: no Infisical token configured (set INFISICAL_TOKEN)", ErrForbidden)
+	}
+
+	base := r.BaseURL
+	if base == "" {
+		base = os.Getenv("INFISICAL_API_URL")
+	}
+	if base == "" {
+		base = "https://app.infisical.com"
+	}
+
+	q := url.Values{}
+	q.Set("workspaceId", workspace)
+	q.Set("environment", environment)
+	q.Set("secretPath", secretPath)
+	endpoint := strings.TrimRight(base, "/") + "/api/v3/secrets/raw/" + url.PathEscape(name) + "?" + q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("build Infisical request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	body, err := doHTTP(r.Client, req)
+	if err != nil {
+		return "", err
+	}
+
+	var payload struct {
+		Secret struct {
+			SecretValue string `json:"secretValue"`
+		} `json:"secret"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("failed to parse Infisical response for %q: %w", name, err)
+	}
+	return payload.Secret.SecretValue, nil
+}
+
+// Describe reports the resolver metadata.
+func (r *InfisicalResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilityRemote}}
+}
@@ -0,0 +1,102 @@
+package resolver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfisicalResolver_Resolve(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		q := req.URL.Query()
+		if req.URL.Path != "/api/v3/secrets/raw/DB_PASSWORD" || q.Get("workspaceId") != "ws1" || q.Get("environment") != "prod" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"secret":{"secretKey":"DB_PASSWORD","secretValue":"s3cr3t:` + q.Get("secretPath") + `"}}`))
+	}))
+	defer srv.Close()
+
+	t.Run("Root path", func(t *testing.T) {
+		r := &InfisicalResolver{BaseURL: srv.URL, Token: "tok"}
+		val, err := r.Resolve("ws1/prod/DB_PASSWORD")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t:/", val)
+	})
+
+	t.Run("Folder path", func(t *testing.T) {
+		r := &InfisicalResolver{BaseURL: srv.URL, Token: "tok"}
+		val, err := r.Resolve("ws1/prod/backend/db/DB_PASSWORD")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t:/backend/db", val)
+	})
+
+	t.Run("Token and URL from environment", func(t *testing.T) {
+		t.Setenv("INFISICAL_API_URL", srv.URL)
+		t.Setenv("INFISICAL_TOKEN", "tok")
+		reg := NewDefaultRegistry()
+		RegisterBackends(reg)
+		val, err := reg.ResolveVariable("infisical:ws1/prod/DB_PASSWORD")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t:/", val)
+	})
+
+	t.Run("Missing secret", func(t *testing.T) {
+		r := &InfisicalResolver{BaseURL: srv.URL, Token: "tok"}
+		_, err := r.Resolve("ws1/prod/NOPE")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Wrong token", func(t *testing.T) {
+		r := &InfisicalResolver{BaseURL: srv.URL, Token: "bad"}
+		_, err := r.Resolve("ws1/prod/DB_PASSWORD")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Missing token", func(t *testing.T) {
+		t.Setenv("INFISICAL_TOKEN", "")
+		r := &InfisicalResolver{BaseURL: srv.URL}
+		_, err := r.Resolve("ws1/prod/DB_PASSWORD")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Malformed reference", func(t *testing.T) {
+		r := &InfisicalResolver{BaseURL: srv.URL, Token: "tok"}
+		_, err := r.Resolve("ws1/prod")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrBadPath)
+
+		_, err = r.Resolve("ws1//DB_PASSWORD")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Context bounds the request", func(t *testing.T) {
+		blocked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			<-req.Context().Done()
+		}))
+		defer blocked.Close()
+
+		reg := NewRegistry()
+		reg.Register("infisical:", &InfisicalResolver{BaseURL: blocked.URL, Token: "tok"})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := reg.ResolveVariableContext(ctx, "infisical:ws1/prod/DB_PASSWORD")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
@@ -13,15 +13,17 @@ import (
 // INIResolver resolves a value by loading an INI file and extracting a section.key pair.
 // Format: "ini:/path/file.ini//Section.Key" or "ini:/path/file.ini//Key" (default section).
 // If no key is provided, returns the entire INI file as a string.
-type INIResolver struct{}
+type INIResolver struct {
+	// CaseInsensitiveKeys matches section and key names ignoring case.
+	CaseInsensitiveKeys bool
+}
 
 func (r *INIResolver) Resolve(value string) (string, error) {
 	filePath, keyPath := splitFileAndKey(value)
 	filePath = os.ExpandEnv(filePath)
 
-	cfg, err := ini.Load(filePath)
+	data, err := readTextFile(filePath)
 	if err != nil {
-		// ini.Load wraps os.Open errors; try to map to sentinels.
 		if errors.Is(err, fs.ErrNotExist) {
 			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
 		}
@@ -31,12 +33,29 @@ func (r *INIResolver) Resolve(value string) (string, error) {
 		return "", fmt.Errorf("failed to read INI file %q: %w", filePath, err)
 	}
 
+	return r.selectValue(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *INIResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectINI returns the value at "Section.Key" (or "Key" in the default section) in INI data,
+// or the whole document if keyPath is empty. source names the document in error messages.
+func selectINI(data []byte, keyPath, source string) (string, error) {
+	return (&INIResolver{}).selectValue(data, keyPath, source)
+}
+
+// selectValue is selectINI with the key matching options of r.
+func (r *INIResolver) selectValue(data []byte, keyPath, source string) (string, error) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{Insensitive: r.CaseInsensitiveKeys}, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse INI in %q: %w", source, err)
+	}
+
 	if keyPath == "" {
 		// No key path means return the entire INI file
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			return "", fmt.Errorf("failed to read INI file %q: %w", filePath, err)
-		}
 		return strings.TrimSpace(string(data)), nil
 	}
 
@@ -56,12 +75,12 @@ func (r *INIResolver) Resolve(value string) (string, error) {
 
 	section, err := cfg.GetSection(sectionName)
 	if err != nil {
-		return "", fmt.Errorf("%w: section %q in %q", ErrNotFound, sectionName, filePath)
+		return "", fmt.Errorf("%w: section %q in %q", ErrNotFound, sectionName, source)
 	}
 
 	k, err := section.GetKey(keyName)
 	if err != nil {
-		return "", fmt.Errorf("%w: key %q in section %q of %q", ErrNotFound, keyName, sectionName, filePath)
+		return "", fmt.Errorf("%w: key %q in section %q of %q", ErrNotFound, keyName, sectionName, source)
 	}
 	return k.String(), nil
 }
@@ -107,6 +107,30 @@ Key1=DefaultVal1
 		require.Error(t, err)
 	})
 
+	t.Run("Case-insensitive keys", func(t *testing.T) {
+		t.Parallel()
+
+		content := `
+Key1=DefaultVal1
+
+[Database]
+User=admin
+`
+		p := createIniTestFile(t, content)
+
+		_, err := (&INIResolver{}).Resolve(p + "//database.user")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		r := &INIResolver{CaseInsensitiveKeys: true}
+		val, err := r.Resolve(p + "//database.USER")
+		require.NoError(t, err)
+		assert.Equal(t, "admin", val)
+
+		val, err = r.Resolve(p + "//key1")
+		require.NoError(t, err)
+		assert.Equal(t, "DefaultVal1", val)
+	})
+
 	t.Run("File not found", func(t *testing.T) {
 		t.Parallel()
 		r := &INIResolver{}
@@ -1,21 +1,67 @@
 package resolver
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
 
+// DefaultMaxOutputSize is the default cap on the size of interpolated output (16 MiB).
+const DefaultMaxOutputSize = 16 << 20
+
 // ResolveString replaces ${...} tokens in s using the registry (max 8 passes).
 // Use \${ to emit a literal ${. A bare '$' not followed by '{' is literal.
+// A token may end in escape filters: ${env:PW#jsonstr}, ${env:PW#yamlstr} and
+// ${env:PW#shq} quote the value as a JSON string, YAML double-quoted scalar or
+// single-quoted shell word.
+// A token ending in ?optional or ?optional=fallback is non-critical: if it fails,
+// the fallback (default empty) is used and the error goes to the OnWarning hook.
 // Malformed tokens (missing '}' or empty ${}) return ErrBadPath.
+// Output larger than the registry's max output size returns ErrTooLarge.
 func (r *Registry) ResolveString(s string) (string, error) {
-	return r.resolveStringDepth(s, 8)
+	return r.ResolveStringContext(context.Background(), s)
+}
+
+// ResolveStringContext is ResolveString with a context. All tokens of s share one
+// resolution ID (see ResolveVariableContext), so their hook events and resolver logs
+// can be correlated.
+func (r *Registry) ResolveStringContext(ctx context.Context, s string) (string, error) {
+	return r.resolveStringDepth(r.ensureResolutionID(ctx), s, 8)
+}
+
+// SetMaxOutputSize caps the size in bytes of the output produced by ResolveString.
+// The cap is checked while expanding, so a token that keeps doubling its output fails
+// fast instead of exhausting memory. n == 0 restores the default (inherited from the
+// parent for child registries, otherwise DefaultMaxOutputSize); n < 0 disables the cap.
+func (r *Registry) SetMaxOutputSize(n int) {
+	r.mu.Lock()
+	r.maxOutput = n
+	r.mu.Unlock()
+}
+
+// maxOutputSize returns the effective output cap; 0 means unlimited.
+func (r *Registry) maxOutputSize() int {
+	r.mu.RLock()
+	n, parent := r.maxOutput, r.parent
+	r.mu.RUnlock()
+
+	switch {
+	case n < 0:
+		return 0
+	case n > 0:
+		return n
+	case parent != nil:
+		return parent.maxOutputSize()
+	default:
+		return DefaultMaxOutputSize
+	}
 }
 
 // resolveStringDepth performs up to maxDepth interpolation passes.
 // Each pass scans left-to-right, replacing tokens found in that pass.
-func (r *Registry) resolveStringDepth(s string, maxDepth int) (string, error) {
+func (r *Registry) resolveStringDepth(ctx context.Context, s string, maxDepth int) (string, error) {
 	out := s
+	limit := r.maxOutputSize()
 
 	for range maxDepth {
 		var b strings.Builder
@@ -56,15 +102,32 @@ func (r *Registry) resolveStringDepth(s string, maxDepth int) (string, error) {
 			}
 			token := out[start:end]
 
-			// resolve token
-			val, err := r.ResolveVariable(token)
+			// resolve token, then apply trailing #jsonstr/#yamlstr/#shq filters
+			expr, filters := splitFilters(token)
+			expr, fallback, optional := splitOptional(expr)
+			val, err := r.ResolveVariableContext(ctx, expr)
 			if err != nil {
-				return "", fmt.Errorf("resolve ${%s}: %w", token, err)
+				if !optional || ctx.Err() != nil {
+					return "", r.maskErr(fmt.Errorf("resolve ${%s}: %w", token, err))
+				}
+				r.warn(ctx, expr, err)
+				val = fallback
+			}
+			for _, f := range filters {
+				val = f(val)
 			}
 
 			b.WriteString(val)
 			p = end + 1
 			expanded = true
+
+			if limit > 0 && b.Len() > limit {
+				return "", fmt.Errorf("%w: interpolated output exceeds %d bytes", ErrTooLarge, limit)
+			}
+		}
+
+		if expanded && limit > 0 && b.Len() > limit {
+			return "", fmt.Errorf("%w: interpolated output exceeds %d bytes", ErrTooLarge, limit)
 		}
 
 		// If no ${...} expanded (only literals/escapes handled), return the built string.
@@ -95,13 +158,26 @@ func isTokenStart(out string, dollar int) bool {
 }
 
 // tokenBounds returns [start,end) of the token contents inside "${...}" and validates it.
+// Braces inside the token nest, so selector projections ("${json:f//db.{host,port}}")
+// stay part of it.
 func tokenBounds(out string, dollar int) (start, end int, err error) {
 	start = dollar + 2
-	closeRel := strings.IndexByte(out[start:], '}')
-	if closeRel < 0 {
+	end = -1
+	depth := 0
+	for i := start; i < len(out) && end < 0; i++ {
+		switch out[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				end = i
+			}
+			depth--
+		}
+	}
+	if end < 0 {
 		return 0, 0, fmt.Errorf("%w: missing closing '}' at offset %d", ErrBadPath, dollar)
 	}
-	end = start + closeRel
 	if strings.TrimSpace(out[start:end]) == "" {
 		return 0, 0, fmt.Errorf("%w: empty ${} at offset %d", ErrBadPath, dollar)
 	}
@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
@@ -33,6 +34,12 @@ func TestResolveString_Basics(t *testing.T) {
 		assert.Equal(t, "X(a)X(b)-X(c)", got)
 	})
 
+	t.Run("Braces inside a token nest", func(t *testing.T) {
+		got, err := r.ResolveString("a ${x:f//db.{host,port}} b }")
+		require.NoError(t, err)
+		assert.Equal(t, "a X(f//db.{host,port}) b }", got)
+	})
+
 	t.Run("Literal dollar (not a token)", func(t *testing.T) {
 		got, err := r.ResolveString("cost is $$5 or $5")
 		require.NoError(t, err)
@@ -71,6 +78,24 @@ func TestResolveString_Malformed(t *testing.T) {
 		require.Error(t, err)
 		assert.ErrorIs(t, err, ErrBadPath)
 	})
+
+	t.Run("Unbalanced brace inside a token", func(t *testing.T) {
+		_, err := r.ResolveString("oops ${x:a.{b}")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+}
+
+func TestResolveString_Projection(t *testing.T) {
+	p := createJSONTestFile(t)
+
+	got, err := NewDefaultRegistry().ResolveString("server=${json:" + p + "//server.{host,port}};")
+	require.NoError(t, err)
+	assert.Equal(t, `server={"host":"localhost","port":8080};`, got)
+
+	got, err = NewDefaultRegistry().ResolveString("${json:" + p + "//servers.0.{host}}")
+	require.NoError(t, err)
+	assert.Equal(t, `{"host":"example.com"}`, got)
 }
 
 func TestResolveString_UnknownSchemePolicy(t *testing.T) {
@@ -123,13 +148,13 @@ func TestResolveString_InternalDepthHelper(t *testing.T) {
 	in := "s=${a:foo}"
 
 	t.Run("Depth=1 fails (needs 2 passes)", func(t *testing.T) {
-		_, err := r.resolveStringDepth(in, 1)
+		_, err := r.resolveStringDepth(context.Background(), in, 1)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, ErrBadPath)
 	})
 
 	t.Run("Depth=2 succeeds", func(t *testing.T) {
-		got, err := r.resolveStringDepth(in, 2)
+		got, err := r.resolveStringDepth(context.Background(), in, 2)
 		require.NoError(t, err)
 		assert.Equal(t, "s=OK", got)
 	})
@@ -143,3 +168,79 @@ func TestResolveString_ErrPropagationFromResolvers(t *testing.T) {
 	require.Error(t, err)
 	assert.True(t, strings.Contains(err.Error(), "resolve ${fail:now}:"), "should prefix resolver errors with token context")
 }
+
+func TestResolveString_MaxOutputSize(t *testing.T) {
+	newDoubling := func() *Registry {
+		r := NewRegistry()
+		// Each pass doubles the number of tokens and grows the payload.
+		r.Register("dup:", ResolverFunc(func(v string) (string, error) {
+			return strings.Repeat("x", 64) + "${dup:x}${dup:x}", nil
+		}))
+		return r
+	}
+
+	t.Run("Exponential expansion hits the cap", func(t *testing.T) {
+		r := newDoubling()
+		r.SetMaxOutputSize(1024)
+
+		_, err := r.ResolveString("${dup:x}")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+
+	t.Run("Output within cap", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register("x:", ResolverFunc(func(v string) (string, error) { return v, nil }))
+		r.SetMaxOutputSize(8)
+
+		got, err := r.ResolveString("${x:abc}${x:de}")
+		require.NoError(t, err)
+		assert.Equal(t, "abcde", got)
+
+		_, err = r.ResolveString("${x:abc}${x:defghi}")
+		require.ErrorIs(t, err, ErrTooLarge)
+	})
+
+	t.Run("Default and unlimited", func(t *testing.T) {
+		r := NewRegistry()
+		assert.Equal(t, DefaultMaxOutputSize, r.maxOutputSize())
+		r.SetMaxOutputSize(-1)
+		assert.Equal(t, 0, r.maxOutputSize())
+		r.SetMaxOutputSize(0)
+		assert.Equal(t, DefaultMaxOutputSize, r.maxOutputSize())
+	})
+
+	t.Run("Child inherits cap", func(t *testing.T) {
+		parent := newDoubling()
+		parent.SetMaxOutputSize(1024)
+		child := parent.Child()
+
+		_, err := child.ResolveString("${dup:x}")
+		require.ErrorIs(t, err, ErrTooLarge)
+
+		child.SetMaxOutputSize(-1)
+		_, err = child.ResolveString("${dup:x}")
+		require.ErrorIs(t, err, ErrBadPath, "depth limit still applies without a size cap")
+	})
+}
+
+func FuzzResolveString(f *testing.F) {
+	for _, seed := range []string{
+		"", "$", "$$", "${", "${}", "${ }", "}", "${x:a}", "${x:a}${x:b}", "\\${x:a}", "\\\\${x:a}",
+		"${x:${x:a}}", "$${x:a}", "${x:a", "a${x:}b", "${nosuch:v}", "${x:\\${}", "\\$", "${x:$}",
+	} {
+		f.Add(seed)
+	}
+	r := NewRegistry()
+	r.Register("x:", ResolverFunc(func(v string) (string, error) { return "<" + v + ">", nil }))
+
+	f.Fuzz(func(t *testing.T, s string) {
+		got, err := r.ResolveString(s)
+		if err != nil {
+			return
+		}
+		if !strings.Contains(s, "${") {
+			assert.Equal(t, s, got, "input without tokens must be unchanged")
+		}
+	})
+}
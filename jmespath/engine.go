@@ -0,0 +1,29 @@
+package jmespath
+
+import (
+	"strings"
+
+	"github.com/containeroo/resolver/selector"
+)
+
+// Prefix marks a key path as a JMESPath expression. JMESPath syntax overlaps dotted
+// paths ("a.b", "a[0]"), so unlike JSONPath it needs a prefix of its own.
+const Prefix = "#jmes:"
+
+func init() {
+	selector.RegisterEngine("jmespath", engine{})
+}
+
+// engine plugs JMESPath into the selector package for key paths starting with Prefix.
+type engine struct{}
+
+func (engine) Matches(keyPath string) bool { return strings.HasPrefix(keyPath, Prefix) }
+
+// Select evaluates keyPath. A result of null selects nothing.
+func (engine) Select(data any, keyPath string) ([]any, error) {
+	v, err := Search(strings.TrimPrefix(keyPath, Prefix), data)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	return []any{v}, nil
+}
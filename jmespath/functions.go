@@ -0,0 +1,423 @@
+package jmespath
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// function is a built-in function and the number of arguments it takes (max -1 for
+// variadic).
+type function struct {
+	min, max int
+	call     func(args []any) (any, error)
+}
+
+var functions map[string]function
+
+func init() {
+	// Assigned in init: map and the *_by functions evaluate expression references,
+	// which refers back to this table.
+	functions = map[string]function{
+		"abs":         {1, 1, numberFunc(math.Abs)},
+		"avg":         {1, 1, fnAvg},
+		"ceil":        {1, 1, numberFunc(math.Ceil)},
+		"contains":    {2, 2, fnContains},
+		"ends_with":   {2, 2, stringsFunc(strings.HasSuffix)},
+		"floor":       {1, 1, numberFunc(math.Floor)},
+		"join":        {2, 2, fnJoin},
+		"keys":        {1, 1, fnKeys},
+		"length":      {1, 1, fnLength},
+		"map":         {2, 2, fnMap},
+		"max":         {1, 1, extremeFunc(1)},
+		"max_by":      {2, 2, extremeByFunc(1)},
+		"merge":       {0, -1, fnMerge},
+		"min":         {1, 1, extremeFunc(-1)},
+		"min_by":      {2, 2, extremeByFunc(-1)},
+		"not_null":    {1, -1, fnNotNull},
+		"reverse":     {1, 1, fnReverse},
+		"sort":        {1, 1, fnSort},
+		"sort_by":     {2, 2, fnSortBy},
+		"starts_with": {2, 2, stringsFunc(strings.HasPrefix)},
+		"sum":         {1, 1, fnSum},
+		"to_array":    {1, 1, fnToArray},
+		"to_number":   {1, 1, fnToNumber},
+		"to_string":   {1, 1, fnToString},
+		"type":        {1, 1, fnType},
+		"values":      {1, 1, fnValues},
+	}
+}
+
+// typeName returns the JMESPath type of v.
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case expref:
+		return "expref"
+	}
+	if _, ok := toFloat(v); ok {
+		return "number"
+	}
+	return "unknown"
+}
+
+func typeError(i int, want string, got any) error {
+	return fmt.Errorf("argument %d must be %s, got %s", i+1, want, typeName(got))
+}
+
+func numberFunc(f func(float64) float64) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		n, ok := toFloat(args[0])
+		if !ok {
+			return nil, typeError(0, "a number", args[0])
+		}
+		return number(f(n)), nil
+	}
+}
+
+func stringsFunc(f func(s, affix string) bool) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, typeError(0, "a string", args[0])
+		}
+		affix, ok := args[1].(string)
+		if !ok {
+			return nil, typeError(1, "a string", args[1])
+		}
+		return f(s, affix), nil
+	}
+}
+
+// numbers returns the elements of v, which must be an array of numbers.
+func numbers(v any) ([]float64, error) {
+	a, ok := v.([]any)
+	if !ok {
+		return nil, typeError(0, "an array of numbers", v)
+	}
+	out := make([]float64, len(a))
+	for i, e := range a {
+		if out[i], ok = toFloat(e); !ok {
+			return nil, typeError(0, "an array of numbers", v)
+		}
+	}
+	return out, nil
+}
+
+func fnAvg(args []any) (any, error) {
+	ns, err := numbers(args[0])
+	if err != nil || len(ns) == 0 {
+		return nil, err
+	}
+	var sum float64
+	for _, n := range ns {
+		sum += n
+	}
+	return number(sum / float64(len(ns))), nil
+}
+
+func fnSum(args []any) (any, error) {
+	ns, err := numbers(args[0])
+	if err != nil {
+		return nil, err
+	}
+	var sum float64
+	for _, n := range ns {
+		sum += n
+	}
+	return number(sum), nil
+}
+
+func fnContains(args []any) (any, error) {
+	switch subject := args[0].(type) {
+	case string:
+		s, ok := args[1].(string)
+		return ok && strings.Contains(subject, s), nil
+	case []any:
+		return slices.ContainsFunc(subject, func(e any) bool { return equal(e, args[1]) }), nil
+	}
+	return nil, typeError(0, "a string or array", args[0])
+}
+
+func fnJoin(args []any) (any, error) {
+	sep, ok := args[0].(string)
+	if !ok {
+		return nil, typeError(0, "a string", args[0])
+	}
+	a, ok := args[1].([]any)
+	if !ok {
+		return nil, typeError(1, "an array of strings", args[1])
+	}
+	parts := make([]string, len(a))
+	for i, e := range a {
+		if parts[i], ok = e.(string); !ok {
+			return nil, typeError(1, "an array of strings", args[1])
+		}
+	}
+	return strings.Join(parts, sep), nil
+}
+
+func fnKeys(args []any) (any, error) {
+	m, ok := args[0].(map[string]any)
+	if !ok {
+		return nil, typeError(0, "an object", args[0])
+	}
+	keys := []any{}
+	for _, k := range sortedKeys(m) {
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func fnValues(args []any) (any, error) {
+	m, ok := args[0].(map[string]any)
+	if !ok {
+		return nil, typeError(0, "an object", args[0])
+	}
+	values := []any{}
+	for _, k := range sortedKeys(m) {
+		values = append(values, m[k])
+	}
+	return values, nil
+}
+
+func fnLength(args []any) (any, error) {
+	switch v := args[0].(type) {
+	case string:
+		return len([]rune(v)), nil
+	case []any:
+		return len(v), nil
+	case map[string]any:
+		return len(v), nil
+	}
+	return nil, typeError(0, "a string, array or object", args[0])
+}
+
+func fnMap(args []any) (any, error) {
+	ref, ok := args[0].(expref)
+	if !ok {
+		return nil, typeError(0, "an expression reference", args[0])
+	}
+	a, ok := args[1].([]any)
+	if !ok {
+		return nil, typeError(1, "an array", args[1])
+	}
+	out := make([]any, len(a))
+	for i, e := range a {
+		v, err := eval(ref.n, e)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func fnMerge(args []any) (any, error) {
+	out := map[string]any{}
+	for i, a := range args {
+		m, ok := a.(map[string]any)
+		if !ok {
+			return nil, typeError(i, "an object", a)
+		}
+		for k, v := range m {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+func fnNotNull(args []any) (any, error) {
+	for _, a := range args {
+		if a != nil {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
+
+func fnReverse(args []any) (any, error) {
+	switch v := args[0].(type) {
+	case string:
+		r := []rune(v)
+		slices.Reverse(r)
+		return string(r), nil
+	case []any:
+		out := slices.Clone(v)
+		slices.Reverse(out)
+		return out, nil
+	}
+	return nil, typeError(0, "a string or array", args[0])
+}
+
+// sortKeys returns the values to order an array by, which must be all numbers or all
+// strings.
+func sortKeys(a []any, key func(any) (any, error)) ([]any, error) {
+	keys := make([]any, len(a))
+	kind := ""
+	for i, e := range a {
+		k, err := key(e)
+		if err != nil {
+			return nil, err
+		}
+		t := typeName(k)
+		if t != "number" && t != "string" || kind != "" && t != kind {
+			return nil, errors.New("values to sort by must be all numbers or all strings")
+		}
+		kind, keys[i] = t, k
+	}
+	return keys, nil
+}
+
+// compareKeys orders two sort keys of the same type.
+func compareKeys(a, b any) int {
+	if af, ok := toFloat(a); ok {
+		bf, _ := toFloat(b)
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a.(string), b.(string))
+}
+
+// sortedBy returns a stably sorted by the keys of its elements.
+func sortedBy(a []any, key func(any) (any, error)) ([]any, error) {
+	keys, err := sortKeys(a, key)
+	if err != nil {
+		return nil, err
+	}
+	idx := make([]int, len(a))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortStableFunc(idx, func(i, j int) int { return compareKeys(keys[i], keys[j]) })
+	out := make([]any, len(a))
+	for i, j := range idx {
+		out[i] = a[j]
+	}
+	return out, nil
+}
+
+func self(v any) (any, error) { return v, nil }
+
+// byRef returns a sort key function evaluating the expression reference v.
+func byRef(i int, v any) (func(any) (any, error), error) {
+	ref, ok := v.(expref)
+	if !ok {
+		return nil, typeError(i, "an expression reference", v)
+	}
+	return func(e any) (any, error) { return eval(ref.n, e) }, nil
+}
+
+func fnSort(args []any) (any, error) {
+	a, ok := args[0].([]any)
+	if !ok {
+		return nil, typeError(0, "an array", args[0])
+	}
+	return sortedBy(a, self)
+}
+
+func fnSortBy(args []any) (any, error) {
+	a, ok := args[0].([]any)
+	if !ok {
+		return nil, typeError(0, "an array", args[0])
+	}
+	key, err := byRef(1, args[1])
+	if err != nil {
+		return nil, err
+	}
+	return sortedBy(a, key)
+}
+
+// extremeFunc returns max (sign 1) or min (sign -1) of an array of numbers or strings.
+func extremeFunc(sign int) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		a, ok := args[0].([]any)
+		if !ok {
+			return nil, typeError(0, "an array", args[0])
+		}
+		return extreme(a, self, sign)
+	}
+}
+
+// extremeByFunc returns max_by (sign 1) or min_by (sign -1).
+func extremeByFunc(sign int) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		a, ok := args[0].([]any)
+		if !ok {
+			return nil, typeError(0, "an array", args[0])
+		}
+		key, err := byRef(1, args[1])
+		if err != nil {
+			return nil, err
+		}
+		return extreme(a, key, sign)
+	}
+}
+
+func extreme(a []any, key func(any) (any, error), sign int) (any, error) {
+	keys, err := sortKeys(a, key)
+	if err != nil || len(a) == 0 {
+		return nil, err
+	}
+	best := 0
+	for i := 1; i < len(a); i++ {
+		if compareKeys(keys[i], keys[best])*sign > 0 {
+			best = i
+		}
+	}
+	return a[best], nil
+}
+
+func fnToArray(args []any) (any, error) {
+	if a, ok := args[0].([]any); ok {
+		return a, nil
+	}
+	return []any{args[0]}, nil
+}
+
+func fnToNumber(args []any) (any, error) {
+	switch v := args[0].(type) {
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, nil
+		}
+		return number(f), nil
+	default:
+		if _, ok := toFloat(v); ok {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func fnToString(args []any) (any, error) {
+	if s, ok := args[0].(string); ok {
+		return s, nil
+	}
+	out, err := json.Marshal(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return string(out), nil
+}
+
+func fnType(args []any) (any, error) { return typeName(args[0]), nil }
@@ -0,0 +1,973 @@
+// Package jmespath implements JMESPath (https://jmespath.org/specification.html) over
+// decoded JSON/YAML/TOML values (map[string]any, []any and scalars).
+//
+// Supported syntax:
+//
+//	name  "quoted name"      field of an object
+//	a.b                      subexpression
+//	[0]  [-1]                array index (negative counts from the end)
+//	[1:3]  [::-1]            array slice (projection)
+//	[*]  *                   list and object projections (object values in sorted key order)
+//	[]                       flatten (projection)
+//	[?price < `10`]          filter projection with ==, !=, <, <=, >, >=, !, &&, || and parentheses
+//	[a, b]  {x: a, y: b}     multi-select list and hash
+//	a | b                    pipe
+//	@  `json`  'raw'         current node, JSON literal, raw string literal
+//	length(@)  sort_by(@, &n) functions and expression references
+//
+// Functions: abs, avg, ceil, contains, ends_with, floor, join, keys, length, map, max,
+// max_by, merge, min, min_by, not_null, reverse, sort, sort_by, starts_with, sum,
+// to_array, to_number, to_string, type and values.
+package jmespath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Expression is a compiled JMESPath expression.
+type Expression struct {
+	expr string
+	root *node
+}
+
+// Compile parses a JMESPath expression.
+func Compile(expr string) (*Expression, error) {
+	toks, err := lex(expr)
+	if err != nil {
+		return nil, fmt.Errorf("jmespath %q: %w", expr, err)
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseExpression(0)
+	if err == nil && p.peek().kind != tEOF {
+		err = p.errorf("unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jmespath %q: %w", expr, err)
+	}
+	return &Expression{expr: expr, root: root}, nil
+}
+
+// MustCompile is like Compile but panics on error.
+func MustCompile(expr string) *Expression {
+	e, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// String returns the source expression.
+func (e *Expression) String() string { return e.expr }
+
+// Search evaluates the expression against data. A path that selects nothing yields
+// nil, as in JMESPath; errors come from invalid function arguments.
+func (e *Expression) Search(data any) (any, error) {
+	v, err := eval(e.root, data)
+	if err != nil {
+		return nil, fmt.Errorf("jmespath %q: %w", e.expr, err)
+	}
+	return v, nil
+}
+
+// Search compiles expr and evaluates it against data.
+func Search(expr string, data any) (any, error) {
+	e, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return e.Search(data)
+}
+
+// tokenKind identifies a lexical token.
+type tokenKind int
+
+const (
+	tEOF tokenKind = iota
+	tUnquoted
+	tQuoted
+	tRaw
+	tLiteral
+	tNumber
+	tDot
+	tStar
+	tLbracket
+	tRbracket
+	tFilter  // "[?"
+	tFlatten // "[]"
+	tLbrace
+	tRbrace
+	tLparen
+	tRparen
+	tComma
+	tColon
+	tPipe
+	tOr
+	tAnd
+	tNot
+	tCmp
+	tCurrent
+	tExpref
+)
+
+// token is a lexical token and its offset in the expression.
+type token struct {
+	kind  tokenKind
+	text  string // identifier, comparator or number source
+	value any    // decoded literal
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at offset %d", t.text, t.pos)
+}
+
+// lex splits expr into tokens.
+func lex(expr string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		start := i
+		add := func(kind tokenKind, n int) {
+			toks = append(toks, token{kind: kind, text: expr[start : start+n], pos: start})
+			i += n
+		}
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			for i < len(expr) && isIdentByte(expr[i]) {
+				i++
+			}
+			toks = append(toks, token{kind: tUnquoted, text: expr[start:i], pos: start})
+		case c == '-' || c >= '0' && c <= '9':
+			i++
+			for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+				i++
+			}
+			if expr[start:i] == "-" {
+				return nil, fmt.Errorf("at offset %d: expected digits after '-'", start)
+			}
+			toks = append(toks, token{kind: tNumber, text: expr[start:i], pos: start})
+		case c == '"':
+			end, err := scanQuoted(expr, i, '"')
+			if err != nil {
+				return nil, err
+			}
+			var s string
+			if err := json.Unmarshal([]byte(expr[i:end]), &s); err != nil {
+				return nil, fmt.Errorf("at offset %d: invalid quoted identifier: %v", start, err)
+			}
+			toks = append(toks, token{kind: tQuoted, text: s, pos: start})
+			i = end
+		case c == '\'':
+			end, err := scanQuoted(expr, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			s := strings.ReplaceAll(expr[i+1:end-1], `\'`, `'`)
+			toks = append(toks, token{kind: tRaw, text: s, value: s, pos: start})
+			i = end
+		case c == '`':
+			end, err := scanQuoted(expr, i, '`')
+			if err != nil {
+				return nil, err
+			}
+			src := strings.ReplaceAll(expr[i+1:end-1], "\\`", "`")
+			var v any
+			if err := json.Unmarshal([]byte(src), &v); err != nil {
+				return nil, fmt.Errorf("at offset %d: invalid JSON literal: %v", start, err)
+			}
+			toks = append(toks, token{kind: tLiteral, text: src, value: v, pos: start})
+			i = end
+		case c == '[':
+			switch {
+			case strings.HasPrefix(expr[i:], "[?"):
+				add(tFilter, 2)
+			case strings.HasPrefix(expr[i:], "[]"):
+				add(tFlatten, 2)
+			default:
+				add(tLbracket, 1)
+			}
+		case c == '|':
+			if strings.HasPrefix(expr[i:], "||") {
+				add(tOr, 2)
+			} else {
+				add(tPipe, 1)
+			}
+		case c == '&':
+			if strings.HasPrefix(expr[i:], "&&") {
+				add(tAnd, 2)
+			} else {
+				add(tExpref, 1)
+			}
+		case c == '!':
+			if strings.HasPrefix(expr[i:], "!=") {
+				add(tCmp, 2)
+			} else {
+				add(tNot, 1)
+			}
+		case c == '<' || c == '>':
+			if strings.HasPrefix(expr[i+1:], "=") {
+				add(tCmp, 2)
+			} else {
+				add(tCmp, 1)
+			}
+		case c == '=':
+			if !strings.HasPrefix(expr[i:], "==") {
+				return nil, fmt.Errorf("at offset %d: expected '=='", start)
+			}
+			add(tCmp, 2)
+		default:
+			kind, ok := map[byte]tokenKind{
+				'.': tDot, '*': tStar, ']': tRbracket, '{': tLbrace, '}': tRbrace,
+				'(': tLparen, ')': tRparen, ',': tComma, ':': tColon, '@': tCurrent,
+			}[c]
+			if !ok {
+				return nil, fmt.Errorf("at offset %d: unexpected %q", start, c)
+			}
+			add(kind, 1)
+		}
+	}
+	return append(toks, token{kind: tEOF, pos: len(expr)}), nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// scanQuoted returns the offset just past the string quoted with q that starts at i.
+// A backslash escapes the next byte.
+func scanQuoted(expr string, i int, q byte) (int, error) {
+	for j := i + 1; j < len(expr); j++ {
+		switch expr[j] {
+		case '\\':
+			j++
+		case q:
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("at offset %d: unterminated %c", i, q)
+}
+
+// bindingPower orders the infix operators, following the specification's grammar.
+var bindingPower = map[tokenKind]int{
+	tPipe:     1,
+	tOr:       2,
+	tAnd:      3,
+	tCmp:      5,
+	tFlatten:  9,
+	tStar:     20,
+	tFilter:   21,
+	tDot:      40,
+	tNot:      45,
+	tLbrace:   50,
+	tLbracket: 55,
+	tLparen:   60,
+}
+
+// nodeKind identifies an AST node.
+type nodeKind int
+
+const (
+	nField nodeKind = iota
+	nSubexpr
+	nIndex
+	nSlice
+	nProjection
+	nValueProjection
+	nFilterProjection
+	nFlatten
+	nIdentity
+	nLiteral
+	nMultiList
+	nMultiHash
+	nPipe
+	nOr
+	nAnd
+	nNot
+	nCmp
+	nFunction
+	nExpref
+)
+
+// node is a parsed expression. children holds the operands; value the field name,
+// literal, comparator, function name, index or slice bounds.
+type node struct {
+	kind     nodeKind
+	value    any
+	keys     []string // multi-select hash keys
+	children []*node
+}
+
+var identity = &node{kind: nIdentity}
+
+// parser is a Pratt parser over the tokens of an expression.
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n < len(p.toks) {
+		return p.toks[p.pos+n]
+	}
+	return p.toks[len(p.toks)-1]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf(format, args...)
+}
+
+func (p *parser) expect(kind tokenKind, what string) error {
+	if t := p.next(); t.kind != kind {
+		return p.errorf("expected %s, got %s", what, t)
+	}
+	return nil
+}
+
+func (p *parser) parseExpression(bp int) (*node, error) {
+	left, err := p.nud(p.next())
+	if err != nil {
+		return nil, err
+	}
+	for bp < bindingPower[p.peek().kind] {
+		if left, err = p.led(p.next(), left); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// nud parses an expression starting with t.
+func (p *parser) nud(t token) (*node, error) {
+	switch t.kind {
+	case tLiteral, tRaw:
+		return &node{kind: nLiteral, value: t.value}, nil
+	case tUnquoted:
+		if p.peek().kind == tLparen {
+			p.next()
+			return p.parseFunction(t.text)
+		}
+		return &node{kind: nField, value: t.text}, nil
+	case tQuoted:
+		if p.peek().kind == tLparen {
+			return nil, p.errorf("quoted identifier %s cannot name a function", t)
+		}
+		return &node{kind: nField, value: t.text}, nil
+	case tStar:
+		right, err := p.parseProjectionRHS(bindingPower[tStar])
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: nValueProjection, children: []*node{identity, right}}, nil
+	case tFilter:
+		return p.parseFilter(identity)
+	case tFlatten:
+		right, err := p.parseProjectionRHS(bindingPower[tFlatten])
+		if err != nil {
+			return nil, err
+		}
+		flat := &node{kind: nFlatten, children: []*node{identity}}
+		return &node{kind: nProjection, children: []*node{flat, right}}, nil
+	case tLbracket:
+		switch k := p.peek().kind; {
+		case k == tNumber || k == tColon:
+			right, err := p.parseIndex()
+			if err != nil {
+				return nil, err
+			}
+			return p.projectIfSlice(identity, right)
+		case k == tStar && p.peekAt(1).kind == tRbracket:
+			p.next()
+			p.next()
+			right, err := p.parseProjectionRHS(bindingPower[tStar])
+			if err != nil {
+				return nil, err
+			}
+			return &node{kind: nProjection, children: []*node{identity, right}}, nil
+		}
+		return p.parseMultiList()
+	case tLbrace:
+		return p.parseMultiHash()
+	case tCurrent:
+		return identity, nil
+	case tExpref:
+		e, err := p.parseExpression(bindingPower[tExpref])
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: nExpref, children: []*node{e}}, nil
+	case tNot:
+		e, err := p.parseExpression(bindingPower[tNot])
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: nNot, children: []*node{e}}, nil
+	case tLparen:
+		e, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		return e, p.expect(tRparen, "')'")
+	}
+	return nil, p.errorf("unexpected %s", t)
+}
+
+// led parses the operator t applied to left.
+func (p *parser) led(t token, left *node) (*node, error) {
+	switch t.kind {
+	case tDot:
+		if p.peek().kind == tStar {
+			p.next()
+			right, err := p.parseProjectionRHS(bindingPower[tDot])
+			if err != nil {
+				return nil, err
+			}
+			return &node{kind: nValueProjection, children: []*node{left, right}}, nil
+		}
+		right, err := p.parseDotRHS(bindingPower[tDot])
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: nSubexpr, children: []*node{left, right}}, nil
+	case tPipe, tOr, tAnd, tCmp:
+		right, err := p.parseExpression(bindingPower[t.kind])
+		if err != nil {
+			return nil, err
+		}
+		kind := map[tokenKind]nodeKind{tPipe: nPipe, tOr: nOr, tAnd: nAnd, tCmp: nCmp}[t.kind]
+		return &node{kind: kind, value: t.text, children: []*node{left, right}}, nil
+	case tFilter:
+		return p.parseFilter(left)
+	case tFlatten:
+		right, err := p.parseProjectionRHS(bindingPower[tFlatten])
+		if err != nil {
+			return nil, err
+		}
+		flat := &node{kind: nFlatten, children: []*node{left}}
+		return &node{kind: nProjection, children: []*node{flat, right}}, nil
+	case tLbracket:
+		if k := p.peek().kind; k == tNumber || k == tColon {
+			right, err := p.parseIndex()
+			if err != nil {
+				return nil, err
+			}
+			return p.projectIfSlice(left, right)
+		}
+		if err := p.expect(tStar, "index, slice or '*'"); err != nil {
+			return nil, err
+		}
+		if err := p.expect(tRbracket, "']'"); err != nil {
+			return nil, err
+		}
+		right, err := p.parseProjectionRHS(bindingPower[tStar])
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: nProjection, children: []*node{left, right}}, nil
+	}
+	return nil, p.errorf("unexpected %s", t)
+}
+
+// parseIndex parses "N]" or a slice "start:stop:step]" after '['.
+func (p *parser) parseIndex() (*node, error) {
+	var parts [3]*int
+	n := 0
+	for {
+		switch t := p.peek(); t.kind {
+		case tNumber:
+			p.next()
+			v, err := strconv.Atoi(t.text)
+			if err != nil {
+				return nil, p.errorf("invalid number %s", t)
+			}
+			parts[n] = &v
+		case tColon:
+			if n == 2 {
+				return nil, p.errorf("too many colons in slice at %s", t)
+			}
+			p.next()
+			n++
+		case tRbracket:
+			p.next()
+			if n == 0 {
+				return &node{kind: nIndex, value: *parts[0]}, nil
+			}
+			if parts[2] != nil && *parts[2] == 0 {
+				return nil, p.errorf("slice step cannot be 0")
+			}
+			return &node{kind: nSlice, value: parts}, nil
+		default:
+			return nil, p.errorf("unexpected %s in index", t)
+		}
+	}
+}
+
+// projectIfSlice applies the index or slice right to left; a slice starts a projection.
+func (p *parser) projectIfSlice(left, right *node) (*node, error) {
+	idx := &node{kind: nSubexpr, children: []*node{left, right}}
+	if right.kind != nSlice {
+		return idx, nil
+	}
+	rhs, err := p.parseProjectionRHS(bindingPower[tStar])
+	if err != nil {
+		return nil, err
+	}
+	return &node{kind: nProjection, children: []*node{idx, rhs}}, nil
+}
+
+// parseFilter parses "condition]" after "[?" and the projection that follows.
+func (p *parser) parseFilter(left *node) (*node, error) {
+	cond, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(tRbracket, "']' to close filter"); err != nil {
+		return nil, err
+	}
+	right := identity
+	if p.peek().kind != tFlatten {
+		if right, err = p.parseProjectionRHS(bindingPower[tFilter]); err != nil {
+			return nil, err
+		}
+	}
+	return &node{kind: nFilterProjection, children: []*node{left, right, cond}}, nil
+}
+
+// parseDotRHS parses what may follow a dot.
+func (p *parser) parseDotRHS(bp int) (*node, error) {
+	switch p.peek().kind {
+	case tUnquoted, tQuoted, tStar:
+		return p.parseExpression(bp)
+	case tLbracket:
+		p.next()
+		return p.parseMultiList()
+	case tLbrace:
+		p.next()
+		return p.parseMultiHash()
+	}
+	return nil, p.errorf("unexpected %s after '.'", p.peek())
+}
+
+// parseProjectionRHS parses the expression applied to each element of a projection.
+func (p *parser) parseProjectionRHS(bp int) (*node, error) {
+	switch t := p.peek(); {
+	case bindingPower[t.kind] < 10:
+		return identity, nil
+	case t.kind == tLbracket || t.kind == tFilter:
+		return p.parseExpression(bp)
+	case t.kind == tDot:
+		p.next()
+		return p.parseDotRHS(bp)
+	}
+	return nil, p.errorf("unexpected %s in projection", p.peek())
+}
+
+// parseMultiList parses "a, b]" after '['.
+func (p *parser) parseMultiList() (*node, error) {
+	n := &node{kind: nMultiList}
+	for {
+		e, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		n.children = append(n.children, e)
+		if t := p.next(); t.kind == tRbracket {
+			return n, nil
+		} else if t.kind != tComma {
+			return nil, p.errorf("expected ',' or ']', got %s", t)
+		}
+	}
+}
+
+// parseMultiHash parses "key: a, ...}" after '{'.
+func (p *parser) parseMultiHash() (*node, error) {
+	n := &node{kind: nMultiHash}
+	for {
+		t := p.next()
+		if t.kind != tUnquoted && t.kind != tQuoted {
+			return nil, p.errorf("expected key name, got %s", t)
+		}
+		if err := p.expect(tColon, "':'"); err != nil {
+			return nil, err
+		}
+		e, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		n.keys = append(n.keys, t.text)
+		n.children = append(n.children, e)
+		if t := p.next(); t.kind == tRbrace {
+			return n, nil
+		} else if t.kind != tComma {
+			return nil, p.errorf("expected ',' or '}', got %s", t)
+		}
+	}
+}
+
+// parseFunction parses "args)" after "name(".
+func (p *parser) parseFunction(name string) (*node, error) {
+	fn, ok := functions[name]
+	if !ok {
+		return nil, p.errorf("unknown function %s()", name)
+	}
+	n := &node{kind: nFunction, value: name}
+	for p.peek().kind != tRparen {
+		if len(n.children) > 0 {
+			if err := p.expect(tComma, "',' or ')'"); err != nil {
+				return nil, err
+			}
+		}
+		e, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		n.children = append(n.children, e)
+	}
+	p.next()
+	if len(n.children) < fn.min || fn.max >= 0 && len(n.children) > fn.max {
+		return nil, p.errorf("wrong number of arguments to %s()", name)
+	}
+	return n, nil
+}
+
+// expref is an expression reference ("&expr") passed to a function.
+type expref struct{ n *node }
+
+// eval evaluates n against value.
+func eval(n *node, value any) (any, error) {
+	switch n.kind {
+	case nIdentity:
+		return value, nil
+	case nLiteral:
+		return n.value, nil
+	case nField:
+		if m, ok := value.(map[string]any); ok {
+			return m[n.value.(string)], nil
+		}
+		return nil, nil
+	case nSubexpr, nPipe:
+		left, err := eval(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		return eval(n.children[1], left)
+	case nIndex:
+		a, ok := value.([]any)
+		if !ok {
+			return nil, nil
+		}
+		i := n.value.(int)
+		if i < 0 {
+			i += len(a)
+		}
+		if i < 0 || i >= len(a) {
+			return nil, nil
+		}
+		return a[i], nil
+	case nSlice:
+		a, ok := value.([]any)
+		if !ok {
+			return nil, nil
+		}
+		return sliceOf(a, n.value.([3]*int)), nil
+	case nProjection, nValueProjection, nFilterProjection:
+		return evalProjection(n, value)
+	case nFlatten:
+		left, err := eval(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		a, ok := left.([]any)
+		if !ok {
+			return nil, nil
+		}
+		out := []any{}
+		for _, e := range a {
+			if inner, ok := e.([]any); ok {
+				out = append(out, inner...)
+			} else {
+				out = append(out, e)
+			}
+		}
+		return out, nil
+	case nMultiList:
+		if value == nil {
+			return nil, nil
+		}
+		out := make([]any, len(n.children))
+		for i, c := range n.children {
+			v, err := eval(c, value)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	case nMultiHash:
+		if value == nil {
+			return nil, nil
+		}
+		out := make(map[string]any, len(n.children))
+		for i, c := range n.children {
+			v, err := eval(c, value)
+			if err != nil {
+				return nil, err
+			}
+			out[n.keys[i]] = v
+		}
+		return out, nil
+	case nOr, nAnd:
+		left, err := eval(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(left) == (n.kind == nOr) {
+			return left, nil
+		}
+		return eval(n.children[1], value)
+	case nNot:
+		v, err := eval(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		return !truthy(v), nil
+	case nCmp:
+		left, err := eval(n.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		right, err := eval(n.children[1], value)
+		if err != nil {
+			return nil, err
+		}
+		return compare(n.value.(string), left, right), nil
+	case nExpref:
+		return expref{n.children[0]}, nil
+	case nFunction:
+		args := make([]any, len(n.children))
+		for i, c := range n.children {
+			v, err := eval(c, value)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = v
+		}
+		name := n.value.(string)
+		v, err := functions[name].call(args)
+		if err != nil {
+			return nil, fmt.Errorf("%s(): %w", name, err)
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("unknown node kind %d", n.kind)
+}
+
+// evalProjection evaluates a list, object or filter projection: the right-hand side
+// applies to each element selected on the left, and null results are dropped.
+func evalProjection(n *node, value any) (any, error) {
+	left, err := eval(n.children[0], value)
+	if err != nil {
+		return nil, err
+	}
+	var items []any
+	switch n.kind {
+	case nValueProjection:
+		m, ok := left.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		for _, k := range sortedKeys(m) {
+			items = append(items, m[k])
+		}
+	default:
+		a, ok := left.([]any)
+		if !ok {
+			return nil, nil
+		}
+		items = a
+	}
+
+	out := []any{}
+	for _, it := range items {
+		if n.kind == nFilterProjection {
+			ok, err := eval(n.children[2], it)
+			if err != nil {
+				return nil, err
+			}
+			if !truthy(ok) {
+				continue
+			}
+		}
+		v, err := eval(n.children[1], it)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// sliceOf returns a[start:stop:step] with Python semantics.
+func sliceOf(a []any, parts [3]*int) []any {
+	n := len(a)
+	step := 1
+	if parts[2] != nil {
+		step = *parts[2]
+	}
+	bound := func(p *int, def int) int {
+		if p == nil {
+			return def
+		}
+		v := *p
+		if v < 0 {
+			v += n
+		}
+		if step < 0 {
+			return min(max(v, -1), n-1)
+		}
+		return min(max(v, 0), n)
+	}
+	out := []any{}
+	if step > 0 {
+		for i := bound(parts[0], 0); i < bound(parts[1], n); i += step {
+			out = append(out, a[i])
+		}
+		return out
+	}
+	for i := bound(parts[0], n-1); i > bound(parts[1], -1); i += step {
+		out = append(out, a[i])
+	}
+	return out
+}
+
+// truthy applies JMESPath truthiness: false, null and empty strings, arrays and objects
+// are false.
+func truthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case []any:
+		return len(t) > 0
+	case map[string]any:
+		return len(t) > 0
+	}
+	return true
+}
+
+// compare applies a comparator. Ordering applies to numbers only and yields null for
+// other types.
+func compare(op string, l, r any) any {
+	switch op {
+	case "==":
+		return equal(l, r)
+	case "!=":
+		return !equal(l, r)
+	}
+	lf, lok := toFloat(l)
+	rf, rok := toFloat(r)
+	if !lok || !rok {
+		return nil
+	}
+	switch op {
+	case "<":
+		return lf < rf
+	case "<=":
+		return lf <= rf
+	case ">":
+		return lf > rf
+	}
+	return lf >= rf
+}
+
+// equal compares two values deeply, treating all numeric types alike.
+func equal(a, b any) bool {
+	if af, ok := toFloat(a); ok {
+		bf, ok := toFloat(b)
+		return ok && af == bf
+	}
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case []any:
+		bv, ok := b.([]any)
+		return ok && slices.EqualFunc(av, bv, equal)
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			w, ok := bv[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// number returns f as an int when it is integral, so results encode without a fraction.
+func number(f float64) any {
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int(f)
+	}
+	return f
+}
+
+// sortedKeys returns the keys of m in ascending order, for deterministic results.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,121 @@
+package jmespath
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const doc = `{
+  "servers": [
+    { "name": "web", "port": 80, "tags": ["public"], "env": "prod" },
+    { "name": "api-1", "port": 8080, "tags": ["internal", "v1"], "env": "prod" },
+    { "name": "api-2", "port": 8081, "tags": [], "env": "dev" }
+  ],
+  "limits": { "cpu": 2, "memory": "512Mi" },
+  "dotted.key": "yes",
+  "matrix": [[1, 2], [3, [4]]],
+  "empty": []
+}`
+
+func load(t *testing.T) any {
+	t.Helper()
+	var v any
+	require.NoError(t, json.Unmarshal([]byte(doc), &v))
+	return v
+}
+
+// encode renders a result as JSON for compact comparison.
+func encode(t *testing.T, v any) string {
+	t.Helper()
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestSearch(t *testing.T) {
+	data := load(t)
+
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"Field", "limits.cpu", `2`},
+		{"Quoted field", `"dotted.key"`, `"yes"`},
+		{"Missing field", "limits.gpu", `null`},
+		{"Index", "servers[0].name", `"web"`},
+		{"Negative index", "servers[-1].name", `"api-2"`},
+		{"Out of range", "servers[9].name", `null`},
+		{"List projection", "servers[*].name", `["web","api-1","api-2"]`},
+		{"Object projection", "limits.*", `[2,"512Mi"]`},
+		{"Slice", "servers[1:].port", `[8080,8081]`},
+		{"Reverse slice", "servers[::-1].name", `["api-2","api-1","web"]`},
+		{"Flatten", "servers[].tags[]", `["public","internal","v1"]`},
+		{"Nested flatten", "matrix[]", `[1,2,3,[4]]`},
+		{"Filter", "servers[?name=='api-1'].port", `[8080]`},
+		{"Filter then pipe", "servers[?name=='api-1'].port | [0]", `8080`},
+		{"Filter numeric", "servers[?port > `8000`].name", `["api-1","api-2"]`},
+		{"Filter and or", "servers[?env=='prod' && (port < `100` || name=='api-1')].name", `["web","api-1"]`},
+		{"Filter not", "servers[?!contains(tags, 'public')].name", `["api-1","api-2"]`},
+		{"Filter truthiness", "servers[?tags].name", `["web","api-1"]`},
+		{"Multi-select list", "servers[0].[name, port]", `["web",80]`},
+		{"Multi-select hash", "servers[*].{n: name, p: port}", `[{"n":"web","p":80},{"n":"api-1","p":8080},{"n":"api-2","p":8081}]`},
+		{"Current node", "servers[?@.port == `80`] | [0].name", `"web"`},
+		{"Or default", "limits.gpu || 'none'", `"none"`},
+		{"Length", "length(servers)", `3`},
+		{"Keys", "keys(limits)", `["cpu","memory"]`},
+		{"Join", "join(',', servers[*].name)", `"web,api-1,api-2"`},
+		{"Sort by", "sort_by(servers, &port)[-1].name", `"api-2"`},
+		{"Max by", "max_by(servers, &port).name", `"api-2"`},
+		{"Min", "min(servers[*].port)", `80`},
+		{"Sum and avg", "[sum(servers[*].port), avg(`[1, 2]`)]", `[16241,1.5]`},
+		{"Map", "map(&length(tags), servers)", `[1,2,0]`},
+		{"Starts with", "servers[?starts_with(name, 'api')].name", `["api-1","api-2"]`},
+		{"Not null", "not_null(limits.gpu, limits.cpu)", `2`},
+		{"Type and conversion", "[type(limits), to_number('42'), to_string(limits.cpu)]", `["object",42,"2"]`},
+		{"Merge", "merge(limits, `{\"cpu\": 4}`).cpu", `4`},
+		{"Projection on non-list", "limits[*]", `null`},
+		{"Empty projection", "empty[*].name", `[]`},
+		{"Raw string with quote", `'it\'s'`, `"it's"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Search(tc.expr, data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, encode(t, got))
+		})
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"", "servers[", "servers[?name=='a'", "a.", "a b", "{a}", "[0:1:0]", "a = b",
+		"unknown(a)", "length(a, b)", `"a"(b)`, "'unterminated", "`{bad`",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := Compile(expr)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSearch_FunctionErrors(t *testing.T) {
+	data := load(t)
+	for _, expr := range []string{
+		"length(limits.cpu)", "sort(servers)", "join(',', servers[*].port)", "abs('x')", "map(length, servers)",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := Search(expr, data)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestExpression_String(t *testing.T) {
+	assert.Equal(t, "a.b", MustCompile("a.b").String())
+	assert.Panics(t, func() { MustCompile("a[") })
+}
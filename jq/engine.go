@@ -0,0 +1,20 @@
+package jq
+
+import (
+	"strings"
+
+	"github.com/containeroo/resolver/selector"
+)
+
+func init() {
+	selector.RegisterEngine("jq", engine{})
+}
+
+// engine plugs jq into the selector package for key paths starting with Prefix.
+type engine struct{}
+
+func (engine) Matches(keyPath string) bool { return strings.HasPrefix(keyPath, Prefix) }
+
+func (engine) Select(data any, keyPath string) ([]any, error) {
+	return Run(strings.TrimPrefix(keyPath, Prefix), data)
+}
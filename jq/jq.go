@@ -0,0 +1,66 @@
+// Package jq evaluates jq programs (https://jqlang.github.io/jq/) over decoded
+// JSON/YAML/TOML values using github.com/itchyny/gojq.
+//
+// It backs the "#jq:" key path of the json:, yaml: and toml: resolvers for
+// reshaping that the dotted selector cannot express:
+//
+//	json:/config/app.json//#jq:.servers | map(.host) | join(",")
+package jq
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/itchyny/gojq"
+)
+
+// Prefix marks a key path as a jq program.
+const Prefix = "#jq:"
+
+// Run evaluates query against data and returns all outputs in order.
+// data is normalized to JSON types first, so values decoded by other formats
+// (int64, time.Time, ...) are accepted.
+func Run(query string, data any) ([]any, error) {
+	q, err := gojq.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("parse jq %q: %w", query, err)
+	}
+	code, err := gojq.Compile(q)
+	if err != nil {
+		return nil, fmt.Errorf("compile jq %q: %w", query, err)
+	}
+
+	input, err := normalize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []any
+	iter := code.Run(input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return out, nil
+		}
+		if err, ok := v.(error); ok {
+			if herr, ok := err.(*gojq.HaltError); ok && herr.Value() == nil {
+				return out, nil
+			}
+			return nil, fmt.Errorf("run jq %q: %w", query, err)
+		}
+		out = append(out, v)
+	}
+}
+
+// normalize converts v into the value types gojq understands by round-tripping through JSON.
+func normalize(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("jq input: %w", err)
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("jq input: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,53 @@
+package jq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	data := map[string]any{
+		"servers": []any{
+			map[string]any{"host": "a", "port": int64(80)},
+			map[string]any{"host": "b", "port": int64(443)},
+		},
+		"created": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	t.Run("Single output", func(t *testing.T) {
+		out, err := Run(`.servers | map(.host) | join(",")`, data)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"a,b"}, out)
+	})
+
+	t.Run("Several outputs", func(t *testing.T) {
+		out, err := Run(`.servers[] | select(.port > 100) | .host, .port`, data)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"b", float64(443)}, out)
+	})
+
+	t.Run("Non-JSON input types normalized", func(t *testing.T) {
+		out, err := Run(`.created`, data)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"2024-01-02T03:04:05Z"}, out)
+	})
+
+	t.Run("No output", func(t *testing.T) {
+		out, err := Run(`empty`, data)
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+
+	t.Run("Parse error", func(t *testing.T) {
+		_, err := Run(`.servers[`, data)
+		require.Error(t, err)
+	})
+
+	t.Run("Runtime error", func(t *testing.T) {
+		_, err := Run(`.servers | keys | .[0] | ascii_downcase`, data)
+		require.Error(t, err)
+	})
+}
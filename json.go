@@ -7,14 +7,19 @@ import (
 	"io/fs"
 	"os"
 	"strings"
-
-	"github.com/containeroo/resolver/selector"
 )
 
 // JSONResolver resolves a value by loading a JSON file and extracting a nested key.
 // Format: "json:/path/file.json//key1.key2.keyN"
 // If no key is provided, returns the whole JSON file as a string.
-type JSONResolver struct{}
+type JSONResolver struct {
+	// CaseInsensitiveKeys matches keys of dotted paths ignoring case when no key
+	// matches exactly (see selector.Options).
+	CaseInsensitiveKeys bool
+	// UniqueFilters makes a filter of a dotted path that matches several elements an
+	// ErrBadPath error instead of selecting the first (see selector.FilterUnique).
+	UniqueFilters bool
+}
 
 func (r *JSONResolver) Resolve(value string) (string, error) {
 	filePath, keyPath := splitFileAndKey(value)
@@ -24,7 +29,7 @@ func (r *JSONResolver) Resolve(value string) (string, error) {
 		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := readTextFile(filePath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
@@ -35,18 +40,34 @@ func (r *JSONResolver) Resolve(value string) (string, error) {
 		return "", fmt.Errorf("failed to read JSON file %q: %w", filePath, err)
 	}
 
+	return r.selectValue(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *JSONResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectJSON returns the value at keyPath in JSON data, or the whole document if keyPath is empty.
+// source names the document in error messages.
+func selectJSON(data []byte, keyPath, source string) (string, error) {
+	return (&JSONResolver{}).selectValue(data, keyPath, source)
+}
+
+// selectValue is selectJSON with the key matching options of r.
+func (r *JSONResolver) selectValue(data []byte, keyPath, source string) (string, error) {
 	if keyPath == "" {
 		return strings.TrimSpace(string(data)), nil
 	}
 
 	var content map[string]any
 	if err := json.Unmarshal(data, &content); err != nil {
-		return "", fmt.Errorf("failed to parse JSON in %q: %w", filePath, err)
+		return "", fmt.Errorf("failed to parse JSON in %q: %w", source, err)
 	}
 
-	val, err := selector.Navigate(content, selector.ParsePath(keyPath))
+	val, err := selectPathWith(content, keyPath, selectorOptions(r.CaseInsensitiveKeys, r.UniqueFilters))
 	if err != nil {
-		return "", fmt.Errorf("%w: key path %q in JSON %q: %v", ErrNotFound, keyPath, filePath, err)
+		return "", fmt.Errorf("%w: key path %q in JSON %q: %v", selectErr(err), keyPath, source, err)
 	}
 
 	if s, ok := val.(string); ok {
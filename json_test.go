@@ -5,6 +5,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	_ "github.com/containeroo/resolver/jmespath" // selector engines are opt-in
+	_ "github.com/containeroo/resolver/jq"
+	_ "github.com/containeroo/resolver/jsonpath"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -88,6 +91,19 @@ func TestJSONResolver_Resolve(t *testing.T) {
 		assert.Equal(t, "example.org", val)
 	})
 
+	t.Run("Bracketed array index", func(t *testing.T) {
+		r := &JSONResolver{}
+		p := createJSONTestFile(t)
+
+		val, err := r.Resolve(p + "//servers[1].host")
+		require.NoError(t, err)
+		assert.Equal(t, "example.org", val)
+
+		val, err = r.Resolve(p + "//servers[0]")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"host":"example.com","port":80}`, val)
+	})
+
 	t.Run("Array filter", func(t *testing.T) {
 		// Uses the selector filter form: [key=value]
 		r := &JSONResolver{}
@@ -99,6 +115,96 @@ func TestJSONResolver_Resolve(t *testing.T) {
 		assert.Equal(t, "443", val)
 	})
 
+	t.Run("Wildcard", func(t *testing.T) {
+		r := &JSONResolver{}
+		p := createJSONTestFile(t)
+
+		val, err := r.Resolve(p + "//servers.*.host")
+		require.NoError(t, err)
+		assert.Equal(t, `["example.com","example.org"]`, val)
+	})
+
+	t.Run("All matches", func(t *testing.T) {
+		r := &JSONResolver{}
+		p := createJSONTestFile(t)
+
+		val, err := r.Resolve(p + "//servers.[host~=^example].port[]")
+		require.NoError(t, err)
+		assert.Equal(t, `[80,443]`, val)
+
+		val, err = r.Resolve(p + "//servers.[port=80].host[]")
+		require.NoError(t, err)
+		assert.Equal(t, `["example.com"]`, val)
+	})
+
+	t.Run("Regex filter with URL", func(t *testing.T) {
+		r := &JSONResolver{}
+		p := filepath.Join(t.TempDir(), "links.json")
+		content := `{"links": [{"url": "http://api.local", "id": 1}, {"url": "https://api.example.org/v1", "id": 2}]}`
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o666))
+
+		val, err := r.Resolve(p + "//links.[url~=^https://api].id")
+		require.NoError(t, err)
+		assert.Equal(t, "2", val)
+	})
+
+	t.Run("Functions", func(t *testing.T) {
+		r := &JSONResolver{}
+		p := createJSONTestFile(t)
+
+		val, err := r.Resolve(p + "//servers.#length")
+		require.NoError(t, err)
+		assert.Equal(t, "2", val)
+
+		val, err = r.Resolve(p + "//server.#keys")
+		require.NoError(t, err)
+		assert.Equal(t, `["host","nested","port"]`, val)
+
+		val, err = r.Resolve(p + "//servers.#last.host")
+		require.NoError(t, err)
+		assert.Equal(t, "example.org", val)
+	})
+
+	t.Run("Sort", func(t *testing.T) {
+		r := &JSONResolver{}
+		p := createJSONTestFile(t)
+
+		val, err := r.Resolve(p + "//servers.@sortdesc(port).0.host")
+		require.NoError(t, err)
+		assert.Equal(t, "example.org", val)
+	})
+
+	t.Run("Projection", func(t *testing.T) {
+		r := &JSONResolver{}
+		p := createJSONTestFile(t)
+
+		val, err := r.Resolve(p + "//server.{host,port}")
+		require.NoError(t, err)
+		assert.Equal(t, `{"host":"localhost","port":8080}`, val)
+	})
+
+	t.Run("Slice", func(t *testing.T) {
+		r := &JSONResolver{}
+		p := createJSONTestFile(t)
+
+		val, err := r.Resolve(p + "//servers.[1:]")
+		require.NoError(t, err)
+		assert.Equal(t, `[{"host":"example.org","port":443}]`, val)
+	})
+
+	t.Run("Unique filters", func(t *testing.T) {
+		r := &JSONResolver{UniqueFilters: true}
+		p := createJSONTestFile(t)
+
+		val, err := r.Resolve(p + "//servers.[port=443].host")
+		require.NoError(t, err)
+		assert.Equal(t, "example.org", val)
+
+		_, err = r.Resolve(p + "//servers.[host~=^example].port")
+		require.ErrorIs(t, err, ErrBadPath)
+		assert.ErrorContains(t, err, "matches 2 elements")
+	})
+
 	t.Run("Empty string value", func(t *testing.T) {
 		r := &JSONResolver{}
 		p := createJSONTestFile(t)
@@ -131,3 +237,157 @@ func TestJSONResolver_Resolve(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestJSONResolver_JSONPath(t *testing.T) {
+	r := &JSONResolver{}
+	p := createJSONTestFile(t)
+
+	t.Run("Simple path translated", func(t *testing.T) {
+		val, err := r.Resolve(p + "//$.servers[1].host")
+		require.NoError(t, err)
+		assert.Equal(t, "example.org", val)
+	})
+
+	t.Run("Filter with single match", func(t *testing.T) {
+		val, err := r.Resolve(p + "//$.servers[?(@.port > 100)].host")
+		require.NoError(t, err)
+		assert.Equal(t, "example.org", val)
+	})
+
+	t.Run("Recursive descent with several matches", func(t *testing.T) {
+		val, err := r.Resolve(p + "//$..host")
+		require.NoError(t, err)
+		assert.Equal(t, `["localhost","example.com","example.org"]`, val)
+	})
+
+	t.Run("No match", func(t *testing.T) {
+		_, err := r.Resolve(p + "//$..missing")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid expression", func(t *testing.T) {
+		_, err := r.Resolve(p + "//$.servers[")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Keys starting with a dollar sign", func(t *testing.T) {
+		dir := t.TempDir()
+		schema := filepath.Join(dir, "schema.json")
+		content := `{"$schema": "https://json-schema.org/draft/2020-12/schema", "$id": {"x": "urn:cfg"}}`
+		require.NoError(t, os.WriteFile(schema, []byte(content), 0o666))
+
+		val, err := r.Resolve(schema + "//$schema")
+		require.NoError(t, err)
+		assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", val)
+
+		val, err = r.Resolve(schema + "//$id.x")
+		require.NoError(t, err)
+		assert.Equal(t, "urn:cfg", val)
+
+		val, err = r.Resolve(schema + `//$["$id"].x`)
+		require.NoError(t, err)
+		assert.Equal(t, "urn:cfg", val)
+	})
+
+	t.Run("Member names are literal keys", func(t *testing.T) {
+		dir := t.TempDir()
+		special := filepath.Join(dir, "special.json")
+		content := `{"a": {"*": "star", "x": 1, "y": 2}, "#length": "len", "a|b": "pipe", "x": {"#keys": "keys", "k": 1}}`
+		require.NoError(t, os.WriteFile(special, []byte(content), 0o666))
+
+		cases := map[string]string{
+			`$.a['*']`:     "star",
+			`$['#length']`: "len",
+			`$['a|b']`:     "pipe",
+			`$.x['#keys']`: "keys",
+			`$["x"].#keys`: "keys",
+			`$['a']["*"]`:  "star",
+		}
+		for path, want := range cases {
+			val, err := r.Resolve(special + "//" + path)
+			require.NoError(t, err, path)
+			assert.Equal(t, want, val, path)
+		}
+
+		_, err := r.Resolve(special + "//$['#first']")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestJSONResolver_JMESPath(t *testing.T) {
+	r := &JSONResolver{}
+	p := createJSONTestFile(t)
+
+	t.Run("Filter and pipe", func(t *testing.T) {
+		val, err := r.Resolve(p + "//#jmes:servers[?host=='example.org'].port | [0]")
+		require.NoError(t, err)
+		assert.Equal(t, "443", val)
+	})
+
+	t.Run("Function", func(t *testing.T) {
+		val, err := r.Resolve(p + "//#jmes:join(',', servers[*].host)")
+		require.NoError(t, err)
+		assert.Equal(t, "example.com,example.org", val)
+	})
+
+	t.Run("Literal containing a key delimiter", func(t *testing.T) {
+		val, err := r.Resolve(p + "//#jmes:join('//', servers[*].host)")
+		require.NoError(t, err)
+		assert.Equal(t, "example.com//example.org", val)
+	})
+
+	t.Run("Null result", func(t *testing.T) {
+		_, err := r.Resolve(p + "//#jmes:server.missing")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid expression", func(t *testing.T) {
+		_, err := r.Resolve(p + "//#jmes:servers[?")
+		require.Error(t, err)
+	})
+
+	t.Run("Dotted paths are unaffected", func(t *testing.T) {
+		val, err := r.Resolve(p + "//servers.0.host")
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", val)
+	})
+}
+
+func TestJSONResolver_JQ(t *testing.T) {
+	r := &JSONResolver{}
+	p := createJSONTestFile(t)
+
+	t.Run("Join hosts", func(t *testing.T) {
+		val, err := r.Resolve(p + `//#jq:.servers | map(.host) | join(",")`)
+		require.NoError(t, err)
+		assert.Equal(t, "example.com,example.org", val)
+	})
+
+	t.Run("Alternative operator", func(t *testing.T) {
+		val, err := r.Resolve(p + `//#jq:.server.missing // "fallback"`)
+		require.NoError(t, err)
+		assert.Equal(t, "fallback", val)
+	})
+
+	t.Run("Non-string result encoded", func(t *testing.T) {
+		val, err := r.Resolve(p + `//#jq:[.servers[].port]`)
+		require.NoError(t, err)
+		assert.Equal(t, "[80,443]", val)
+	})
+
+	t.Run("Several outputs", func(t *testing.T) {
+		val, err := r.Resolve(p + `//#jq:.servers[].host`)
+		require.NoError(t, err)
+		assert.Equal(t, `["example.com","example.org"]`, val)
+	})
+
+	t.Run("No output", func(t *testing.T) {
+		_, err := r.Resolve(p + `//#jq:empty`)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid program", func(t *testing.T) {
+		_, err := r.Resolve(p + `//#jq:.servers[`)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,106 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// JSONCResolver resolves a value from a JSON-with-comments file (VS Code style
+// settings, tsconfig.json, devcontainer.json) and extracts a nested key.
+// Format: "jsonc:/path/file.jsonc//key1.key2.keyN", e.g.
+// "jsonc:/workspace/.devcontainer/devcontainer.json//remoteUser".
+//
+// Line (//) and block (/* */) comments and trailing commas are ignored; otherwise the
+// file must be valid JSON, and keys are selected as for "json:". If no key is provided,
+// returns the whole file as a string, comments included.
+type JSONCResolver struct{}
+
+func (r *JSONCResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := readTextFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read JSONC file %q: %w", filePath, err)
+	}
+
+	return selectJSONC(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *JSONCResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectJSONC returns the value at keyPath in JSONC data, or the whole document if
+// keyPath is empty. source names the document in error messages.
+func selectJSONC(data []byte, keyPath, source string) (string, error) {
+	if keyPath == "" {
+		return strings.TrimSpace(string(data)), nil
+	}
+	clean, err := stripJSONC(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse JSONC in %q: %w", source, err)
+	}
+	return selectJSON(clean, keyPath, source)
+}
+
+// stripJSONC turns JSONC into JSON: comments become blanks (newlines are kept, so
+// parse errors keep their offsets) and trailing commas before '}' or ']' are dropped.
+func stripJSONC(data []byte) ([]byte, error) {
+	out := []byte(stripBOM(string(data)))
+	comma := -1 // offset of a ',' that is only followed by blanks so far
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case c == '"':
+			comma = -1
+			for i++; i < len(out) && out[i] != '"'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(out) {
+				return nil, errors.New("unterminated string")
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := strings.Index(string(out[i+2:]), "*/")
+			if end < 0 {
+				return nil, errors.New("unterminated block comment")
+			}
+			for j := i; j < i+2+end+2; j++ {
+				if out[j] != '\n' {
+					out[j] = ' '
+				}
+			}
+			i += 2 + end + 1
+		case c == ',':
+			comma = i
+		case c == '}' || c == ']':
+			if comma >= 0 {
+				out[comma] = ' '
+			}
+			comma = -1
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+		default:
+			comma = -1
+		}
+	}
+	return out, nil
+}
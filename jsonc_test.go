@@ -0,0 +1,73 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJSONC = `// devcontainer settings
+{
+  /* the user inside
+     the container */
+  "remoteUser": "vscode",
+  "url": "http://example.com/*not-a-comment*/", // trailing comment
+  "features": {
+    "docker": { "version": "latest", },
+  },
+  "ports": [3000, 8080,],
+  "quote": "a \"// b\"",
+}
+`
+
+func TestJSONCResolver_Resolve(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "devcontainer.json")
+	require.NoError(t, os.WriteFile(p, []byte(testJSONC), 0o666))
+	r := &JSONCResolver{}
+
+	cases := []struct {
+		name, key, want string
+	}{
+		{"String", "remoteUser", "vscode"},
+		{"Comment markers inside string", "url", "http://example.com/*not-a-comment*/"},
+		{"Nested with trailing commas", "features.docker.version", "latest"},
+		{"Array with trailing comma", "ports.1", "8080"},
+		{"Escaped quote", "quote", `a "// b"`},
+		{"Object", "features", `{"docker":{"version":"latest"}}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("Whole file", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(testJSONC), val)
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		_, err := r.Resolve(p + "//nope")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Unterminated comment", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.jsonc")
+		require.NoError(t, os.WriteFile(bad, []byte(`{"a": 1 /* oops`), 0o666))
+		_, err := r.Resolve(bad + "//a")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "nope.jsonc") + "//a")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
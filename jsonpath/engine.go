@@ -0,0 +1,33 @@
+package jsonpath
+
+import (
+	"strings"
+
+	"github.com/containeroo/resolver/selector"
+)
+
+func init() {
+	selector.RegisterEngine("jsonpath", engine{})
+}
+
+// engine plugs JSONPath into the selector package for key paths starting with '$'.
+type engine struct{}
+
+func (engine) Matches(keyPath string) bool { return IsExpression(keyPath) }
+
+// Select evaluates keyPath. Paths made only of child members and indices run on
+// selector.Navigate, keeping its error messages for missing keys.
+func (engine) Select(data any, keyPath string) ([]any, error) {
+	p, err := Compile(strings.TrimSpace(keyPath))
+	if err != nil {
+		return nil, err
+	}
+	if tokens, ok := p.Translate(); ok {
+		v, err := selector.Navigate(data, tokens)
+		if err != nil {
+			return nil, err
+		}
+		return []any{v}, nil
+	}
+	return p.Eval(data), nil
+}
@@ -0,0 +1,800 @@
+// Package jsonpath implements the commonly used subset of JSONPath
+// (https://goessner.net/articles/JsonPath/) over decoded JSON/YAML/TOML values
+// (map[string]any, []any and scalars).
+//
+// Supported syntax:
+//
+//	$                 root
+//	.name  ['name']   child member
+//	.*  [*]           all children (map members in sorted key order)
+//	..name  ..*       recursive descent
+//	[0]  [-1]         array index (negative counts from the end)
+//	[0,2]  ['a','b']  union
+//	[1:3]  [:2]  [::2] array slice
+//	[?(@.price < 10)] filter with ==, !=, <, <=, >, >=, =~ (regexp), !, &&, || and parentheses
+//	[?(@.isbn)]       existence filter
+//
+// Expressions that only use child members and indices can be translated to
+// selector tokens with Translate, so callers can reuse selector.Navigate.
+package jsonpath
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Path is a compiled JSONPath expression.
+type Path struct {
+	expr     string
+	segments []segment
+}
+
+// segment is one step of a path, applied to every node selected so far.
+type segment struct {
+	recursive bool        // ".." - apply the selector to the node and all descendants
+	sel       selectorFun // selects children of a node
+	simple    string      // selector token for a plain child name or index (see Translate)
+}
+
+// selectorFun appends the children of node selected by a segment.
+type selectorFun func(node any, out []any) []any
+
+// Compile parses a JSONPath expression.
+func Compile(expr string) (*Path, error) {
+	p := &parser{src: expr}
+	segs, err := p.parsePath()
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath %q: %w", expr, err)
+	}
+	return &Path{expr: expr, segments: segs}, nil
+}
+
+// MustCompile is like Compile but panics on error.
+func MustCompile(expr string) *Path {
+	p, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the source expression.
+func (p *Path) String() string { return p.expr }
+
+// Eval returns all nodes matched by the path, in document order.
+func (p *Path) Eval(data any) []any {
+	nodes := []any{data}
+	for _, s := range p.segments {
+		var next []any
+		for _, n := range nodes {
+			if s.recursive {
+				for _, d := range descendants(n, nil) {
+					next = s.sel(d, next)
+				}
+				continue
+			}
+			next = s.sel(n, next)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// Translate returns the selector tokens equivalent to the path if it only uses
+// child members and non-negative indices; ok is false otherwise. Member names become
+// quoted keys (["name"]), so names such as "*", "#length" or "a|b" stay literal.
+func (p *Path) Translate() (tokens []string, ok bool) {
+	tokens = make([]string, 0, len(p.segments))
+	for _, s := range p.segments {
+		if s.recursive || s.simple == "" {
+			return nil, false
+		}
+		tokens = append(tokens, s.simple)
+	}
+	return tokens, true
+}
+
+// selectorKey returns name as a quoted selector key, or "" if it contains both `"]`
+// and `']` and cannot be quoted.
+func selectorKey(name string) string {
+	for _, q := range []string{`"`, `'`} {
+		if !strings.Contains(name, q+"]") {
+			return "[" + q + name + q + "]"
+		}
+	}
+	return ""
+}
+
+// IsExpression reports whether s looks like a JSONPath expression: '$' alone or
+// followed by '.' or '['. Keys that merely start with '$' ("$schema", "$id.x") are not.
+func IsExpression(s string) bool {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(s), "$")
+	return ok && (rest == "" || rest[0] == '.' || rest[0] == '[')
+}
+
+// descendants appends n and all nodes below it in document order.
+func descendants(n any, out []any) []any {
+	out = append(out, n)
+	switch v := n.(type) {
+	case map[string]any:
+		for _, k := range sortedKeys(v) {
+			out = descendants(v[k], out)
+		}
+	case []any:
+		for _, e := range v {
+			out = descendants(e, out)
+		}
+	}
+	return out
+}
+
+// sortedKeys returns the keys of m in ascending order, for deterministic results.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// child selects member name of a map.
+func child(name string) selectorFun {
+	return func(node any, out []any) []any {
+		if m, ok := node.(map[string]any); ok {
+			if v, ok := m[name]; ok {
+				out = append(out, v)
+			}
+		}
+		return out
+	}
+}
+
+// wildcard selects all members of a map or elements of an array.
+func wildcard(node any, out []any) []any {
+	switch v := node.(type) {
+	case map[string]any:
+		for _, k := range sortedKeys(v) {
+			out = append(out, v[k])
+		}
+	case []any:
+		out = append(out, v...)
+	}
+	return out
+}
+
+// index selects element i of an array; negative i counts from the end.
+func index(i int) selectorFun {
+	return func(node any, out []any) []any {
+		if a, ok := node.([]any); ok {
+			if i < 0 {
+				i += len(a)
+			}
+			if i >= 0 && i < len(a) {
+				out = append(out, a[i])
+			}
+		}
+		return out
+	}
+}
+
+// slice selects a[start:end:step] with Python-like semantics.
+func slice(start, end, step *int) selectorFun {
+	return func(node any, out []any) []any {
+		a, ok := node.([]any)
+		if !ok {
+			return out
+		}
+		n := len(a)
+		st := 1
+		if step != nil {
+			st = *step
+		}
+		if st <= 0 {
+			return out
+		}
+		norm := func(p *int, def int) int {
+			if p == nil {
+				return def
+			}
+			v := *p
+			if v < 0 {
+				v += n
+			}
+			return min(max(v, 0), n)
+		}
+		for i := norm(start, 0); i < norm(end, n); i += st {
+			out = append(out, a[i])
+		}
+		return out
+	}
+}
+
+// union applies several selectors and concatenates their results.
+func union(sels []selectorFun) selectorFun {
+	return func(node any, out []any) []any {
+		for _, s := range sels {
+			out = s(node, out)
+		}
+		return out
+	}
+}
+
+// filter selects the elements (or map members) for which pred is true.
+func filter(pred expr) selectorFun {
+	return func(node any, out []any) []any {
+		var items []any
+		switch v := node.(type) {
+		case []any:
+			items = v
+		case map[string]any:
+			for _, k := range sortedKeys(v) {
+				items = append(items, v[k])
+			}
+		}
+		for _, it := range items {
+			if truthy(pred.eval(it)) {
+				out = append(out, it)
+			}
+		}
+		return out
+	}
+}
+
+// parser is a recursive-descent parser over the expression source.
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) consume(s string) bool {
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *parser) parsePath() ([]segment, error) {
+	p.skipSpace()
+	if !p.consume("$") {
+		return nil, p.errorf("expression must start with '$'")
+	}
+	var segs []segment
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return segs, nil
+		}
+		seg, err := p.parseSegment()
+		if err != nil {
+			return nil, err
+		}
+		segs = append(segs, seg)
+	}
+}
+
+func (p *parser) parseSegment() (segment, error) {
+	switch {
+	case p.consume(".."):
+		if p.peek() == '[' {
+			seg, err := p.parseBracket()
+			seg.recursive, seg.simple = true, ""
+			return seg, err
+		}
+		seg, err := p.parseDotName()
+		seg.recursive, seg.simple = true, ""
+		return seg, err
+	case p.consume("."):
+		return p.parseDotName()
+	case p.peek() == '[':
+		return p.parseBracket()
+	default:
+		return segment{}, p.errorf("unexpected %q", p.peek())
+	}
+}
+
+func (p *parser) parseDotName() (segment, error) {
+	if p.consume("*") {
+		return segment{sel: wildcard}, nil
+	}
+	name := p.readName()
+	if name == "" {
+		return segment{}, p.errorf("expected member name")
+	}
+	return segment{sel: child(name), simple: selectorKey(name)}, nil
+}
+
+// readName reads an unquoted member name.
+func (p *parser) readName() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.src[p.pos]
+		if c == '.' || c == '[' || c == ' ' || c == '\t' || c == ')' || c == '=' || c == '!' ||
+			c == '<' || c == '>' || c == '&' || c == '|' || c == ']' || c == ',' {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) parseBracket() (segment, error) {
+	p.pos++ // '['
+	p.skipSpace()
+
+	if p.consume("*") {
+		return p.closeBracket(segment{sel: wildcard})
+	}
+	if p.consume("?") {
+		p.skipSpace()
+		if !p.consume("(") {
+			return segment{}, p.errorf("expected '(' after '?'")
+		}
+		e, err := p.parseOr()
+		if err != nil {
+			return segment{}, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return segment{}, p.errorf("expected ')' to close filter")
+		}
+		return p.closeBracket(segment{sel: filter(e)})
+	}
+
+	var sels []selectorFun
+	var simple string
+	for {
+		p.skipSpace()
+		switch c := p.peek(); {
+		case c == '\'' || c == '"':
+			s, err := p.readQuoted()
+			if err != nil {
+				return segment{}, err
+			}
+			sels = append(sels, child(s))
+			simple = selectorKey(s)
+		case c == '-' || c == ':' || (c >= '0' && c <= '9'):
+			sel, idx, err := p.parseIndexOrSlice()
+			if err != nil {
+				return segment{}, err
+			}
+			sels = append(sels, sel)
+			simple = idx
+		default:
+			return segment{}, p.errorf("unexpected %q in brackets", c)
+		}
+		p.skipSpace()
+		if !p.consume(",") {
+			break
+		}
+	}
+	if len(sels) == 1 {
+		return p.closeBracket(segment{sel: sels[0], simple: simple})
+	}
+	return p.closeBracket(segment{sel: union(sels)})
+}
+
+func (p *parser) closeBracket(seg segment) (segment, error) {
+	p.skipSpace()
+	if !p.consume("]") {
+		return segment{}, p.errorf("expected ']'")
+	}
+	return seg, nil
+}
+
+// parseIndexOrSlice parses "N" or "start:end[:step]". For non-negative plain
+// indices it also returns the index as a selector token.
+func (p *parser) parseIndexOrSlice() (selectorFun, string, error) {
+	var parts [3]*int
+	n := 0
+	for {
+		p.skipSpace()
+		if v, ok := p.readInt(); ok {
+			parts[n] = &v
+		}
+		p.skipSpace()
+		if n < 2 && p.consume(":") {
+			n++
+			continue
+		}
+		break
+	}
+	if n == 0 {
+		if parts[0] == nil {
+			return nil, "", p.errorf("expected index")
+		}
+		tok := ""
+		if *parts[0] >= 0 {
+			tok = strconv.Itoa(*parts[0])
+		}
+		return index(*parts[0]), tok, nil
+	}
+	return slice(parts[0], parts[1], parts[2]), "", nil
+}
+
+func (p *parser) readInt() (int, bool) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for !p.eof() && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	v, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, false
+	}
+	return v, true
+}
+
+// readQuoted reads a single- or double-quoted string with backslash escapes.
+func (p *parser) readQuoted() (string, error) {
+	q := p.src[p.pos]
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.src[p.pos]
+		p.pos++
+		switch {
+		case c == '\\' && !p.eof():
+			b.WriteByte(p.src[p.pos])
+			p.pos++
+		case c == q:
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// expr is a filter expression node.
+type expr interface {
+	eval(current any) any
+}
+
+// missing marks a relative path that selected nothing.
+type missing struct{}
+
+type (
+	literalExpr struct{ v any }
+	relExpr     struct{ sels []selectorFun }
+	notExpr     struct{ e expr }
+	andExpr     struct{ l, r expr }
+	orExpr      struct{ l, r expr }
+	cmpExpr     struct {
+		op   string
+		l, r expr
+		re   *regexp.Regexp
+	}
+)
+
+func (e literalExpr) eval(any) any { return e.v }
+
+func (e relExpr) eval(cur any) any {
+	nodes := []any{cur}
+	for _, s := range e.sels {
+		var next []any
+		for _, n := range nodes {
+			next = s(n, next)
+		}
+		nodes = next
+	}
+	if len(nodes) == 0 {
+		return missing{}
+	}
+	return nodes[0]
+}
+
+func (e notExpr) eval(cur any) any { return !truthy(e.e.eval(cur)) }
+func (e andExpr) eval(cur any) any { return truthy(e.l.eval(cur)) && truthy(e.r.eval(cur)) }
+func (e orExpr) eval(cur any) any  { return truthy(e.l.eval(cur)) || truthy(e.r.eval(cur)) }
+
+func (e cmpExpr) eval(cur any) any {
+	l, r := e.l.eval(cur), e.r.eval(cur)
+	if _, ok := l.(missing); ok {
+		return false
+	}
+	if _, ok := r.(missing); ok {
+		return false
+	}
+	if e.op == "=~" {
+		s, ok := l.(string)
+		return ok && e.re.MatchString(s)
+	}
+	if lf, ok := toFloat(l); ok {
+		if rf, ok := toFloat(r); ok {
+			switch e.op {
+			case "==":
+				return lf == rf
+			case "!=":
+				return lf != rf
+			case "<":
+				return lf < rf
+			case "<=":
+				return lf <= rf
+			case ">":
+				return lf > rf
+			case ">=":
+				return lf >= rf
+			}
+		}
+	}
+	if ls, ok := l.(string); ok {
+		if rs, ok := r.(string); ok {
+			switch e.op {
+			case "==":
+				return ls == rs
+			case "!=":
+				return ls != rs
+			case "<":
+				return ls < rs
+			case "<=":
+				return ls <= rs
+			case ">":
+				return ls > rs
+			case ">=":
+				return ls >= rs
+			}
+		}
+	}
+	switch e.op {
+	case "==":
+		return fmt.Sprint(l) == fmt.Sprint(r) && typeClass(l) == typeClass(r)
+	case "!=":
+		return fmt.Sprint(l) != fmt.Sprint(r) || typeClass(l) != typeClass(r)
+	}
+	return false
+}
+
+// truthy reports whether a filter result selects the element. Existence of a
+// relative path counts as true, except for an explicit false value.
+func truthy(v any) bool {
+	switch b := v.(type) {
+	case missing:
+		return false
+	case bool:
+		return b
+	case nil:
+		return false
+	}
+	return true
+}
+
+func typeClass(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	}
+	if _, ok := toFloat(v); ok {
+		return "number"
+	}
+	return "other"
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func (p *parser) parseOr() (expr, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("||") {
+			return l, nil
+		}
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = orExpr{l, r}
+	}
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("&&") {
+			return l, nil
+		}
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = andExpr{l, r}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	p.skipSpace()
+	if p.peek() == '!' && !strings.HasPrefix(p.src[p.pos:], "!=") {
+		p.pos++
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+	}
+	if p.consume("(") {
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return e, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	l, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "=~", "<", ">"} {
+		if !p.consume(op) {
+			continue
+		}
+		p.skipSpace()
+		if op == "=~" {
+			pat, err := p.readRegexp()
+			if err != nil {
+				return nil, err
+			}
+			re, err := regexp.Compile(pat)
+			if err != nil {
+				return nil, p.errorf("invalid regexp: %v", err)
+			}
+			return cmpExpr{op: op, l: l, r: literalExpr{pat}, re: re}, nil
+		}
+		r, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return cmpExpr{op: op, l: l, r: r}, nil
+	}
+	return l, nil
+}
+
+// readRegexp reads /pattern/ or a quoted pattern.
+func (p *parser) readRegexp() (string, error) {
+	switch p.peek() {
+	case '\'', '"':
+		return p.readQuoted()
+	case '/':
+		p.pos++
+		start := p.pos
+		for !p.eof() && p.src[p.pos] != '/' {
+			if p.src[p.pos] == '\\' {
+				p.pos++
+			}
+			p.pos++
+		}
+		if p.eof() {
+			return "", p.errorf("unterminated regexp")
+		}
+		pat := p.src[start:p.pos]
+		p.pos++
+		return pat, nil
+	}
+	return "", p.errorf("expected /regexp/ or quoted pattern")
+}
+
+func (p *parser) parseOperand() (expr, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '@':
+		p.pos++
+		var sels []selectorFun
+		for {
+			switch {
+			case p.peek() == '.' && !strings.HasPrefix(p.src[p.pos:], ".."):
+				p.pos++
+				name := p.readName()
+				if name == "" {
+					return nil, p.errorf("expected member name after '@.'")
+				}
+				sels = append(sels, child(name))
+			case p.peek() == '[':
+				p.pos++
+				p.skipSpace()
+				var sel selectorFun
+				if q := p.peek(); q == '\'' || q == '"' {
+					s, err := p.readQuoted()
+					if err != nil {
+						return nil, err
+					}
+					sel = child(s)
+				} else {
+					i, ok := p.readInt()
+					if !ok {
+						return nil, p.errorf("expected index or quoted name")
+					}
+					sel = index(i)
+				}
+				p.skipSpace()
+				if !p.consume("]") {
+					return nil, p.errorf("expected ']'")
+				}
+				sels = append(sels, sel)
+			default:
+				return relExpr{sels}, nil
+			}
+		}
+	case c == '\'' || c == '"':
+		s, err := p.readQuoted()
+		if err != nil {
+			return nil, err
+		}
+		return literalExpr{s}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for !p.eof() && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.src[start:p.pos])
+		}
+		return literalExpr{f}, nil
+	case p.consume("true"):
+		return literalExpr{true}, nil
+	case p.consume("false"):
+		return literalExpr{false}, nil
+	case p.consume("null"):
+		return literalExpr{nil}, nil
+	}
+	return nil, p.errorf("expected operand")
+}
@@ -0,0 +1,104 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const store = `{
+  "store": {
+    "book": [
+      { "category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95 },
+      { "category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99 },
+      { "category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99 },
+      { "category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99 }
+    ],
+    "bicycle": { "color": "red", "price": 19.95 }
+  }
+}`
+
+func load(t *testing.T) any {
+	t.Helper()
+	var v any
+	require.NoError(t, json.Unmarshal([]byte(store), &v))
+	return v
+}
+
+func TestPath_Eval(t *testing.T) {
+	data := load(t)
+
+	tests := []struct {
+		name string
+		expr string
+		want []any
+	}{
+		{"Root member", "$.store.bicycle.color", []any{"red"}},
+		{"Bracket member", "$['store']['bicycle']['color']", []any{"red"}},
+		{"Index", "$.store.book[0].author", []any{"Nigel Rees"}},
+		{"Negative index", "$.store.book[-1].title", []any{"The Lord of the Rings"}},
+		{"Wildcard", "$.store.book[*].author", []any{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{"Recursive descent", "$..author", []any{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{"Recursive descent on prices", "$.store..price", []any{19.95, 8.95, 12.99, 8.99, 22.99}},
+		{"Slice", "$.store.book[1:3].title", []any{"Sword of Honour", "Moby Dick"}},
+		{"Slice with step", "$.store.book[::2].title", []any{"Sayings of the Century", "Moby Dick"}},
+		{"Union", "$.store.book[0,3].title", []any{"Sayings of the Century", "The Lord of the Rings"}},
+		{"Filter less than", "$..book[?(@.price<10)].title", []any{"Sayings of the Century", "Moby Dick"}},
+		{"Filter string equality", "$..book[?(@.author == 'Evelyn Waugh')].price", []any{12.99}},
+		{"Filter existence", "$..book[?(@.isbn)].title", []any{"Moby Dick", "The Lord of the Rings"}},
+		{"Filter negation", "$..book[?(!@.isbn)].title", []any{"Sayings of the Century", "Sword of Honour"}},
+		{"Filter and/or", "$..book[?(@.category=='fiction' && (@.price < 10 || @.price > 20))].title", []any{"Moby Dick", "The Lord of the Rings"}},
+		{"Filter regexp", "$..book[?(@.author =~ /^J\\./)].title", []any{"The Lord of the Rings"}},
+		{"No match", "$.store.nothing", nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := Compile(tc.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, p.Eval(data))
+		})
+	}
+}
+
+func TestPath_Translate(t *testing.T) {
+	t.Run("Simple path", func(t *testing.T) {
+		tokens, ok := MustCompile("$.store.book[2]['title']").Translate()
+		require.True(t, ok)
+		assert.Equal(t, []string{`["store"]`, `["book"]`, "2", `["title"]`}, tokens)
+	})
+
+	t.Run("Names are quoted", func(t *testing.T) {
+		tokens, ok := MustCompile(`$['*']['#length']['a|b']['say "hi"]']`).Translate()
+		require.True(t, ok)
+		assert.Equal(t, []string{`["*"]`, `["#length"]`, `["a|b"]`, `['say "hi"]']`}, tokens)
+	})
+
+	for _, expr := range []string{"$..title", "$.store.*", "$.book[-1]", "$.book[0:1]", "$.book[?(@.x)]", "$.book[0,1]"} {
+		t.Run("Not translatable "+expr, func(t *testing.T) {
+			_, ok := MustCompile(expr).Translate()
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, expr := range []string{"", "store.book", "$.", "$[", "$.book[?(@.x", "$.book[?(@.x =~ /[/)]", "$.a b"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := Compile(expr)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestIsExpression(t *testing.T) {
+	assert.True(t, IsExpression("$.a"))
+	assert.True(t, IsExpression(" $..a"))
+	assert.False(t, IsExpression("a.b"))
+	assert.True(t, IsExpression("$"))
+	assert.True(t, IsExpression("$['a']"))
+	assert.False(t, IsExpression("$schema"))
+	assert.False(t, IsExpression("$id.x"))
+}
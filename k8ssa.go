@@ -0,0 +1,243 @@
+package resolver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	defaultDownwardDir       = "/etc/podinfo"
+)
+
+// K8sServiceAccountResolver resolves the identity of the pod it runs in.
+// Formats:
+//   - "k8s-sa:token": the mounted (projected) service-account token
+//   - "k8s-sa:token:<audience>": a fresh token for audience, requested from the API
+//     server with the TokenRequest API (the service account needs no extra RBAC)
+//   - "k8s-sa:namespace", "k8s-sa:ca.crt": the other service-account files
+//   - "k8s-sa:pod-name": $POD_NAME, falling back to the hostname
+//   - "k8s-sa:downward:<file>[//<key>]": a downward API volume file; "//key" selects
+//     one entry of the labels and annotations files
+//
+// Dir defaults to /var/run/secrets/kubernetes.io/serviceaccount and DownwardDir to
+// $K8S_DOWNWARD_DIR or /etc/podinfo. APIServer defaults to the in-cluster address from
+// $KUBERNETES_SERVICE_HOST and $KUBERNETES_SERVICE_PORT; Client defaults to one that
+// trusts Dir/ca.crt.
+type K8sServiceAccountResolver struct {
+	Dir             string
+	DownwardDir     string
+	APIServer       string
+	TokenExpiration time.Duration // lifetime of requested tokens; defaults to 1h
+	Client          *http.Client
+}
+
+func (r *K8sServiceAccountResolver) Resolve(value string) (string, error) {
+	return r.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context bounding the TokenRequest call.
+func (r *K8sServiceAccountResolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	kind, arg, _ := strings.Cut(strings.TrimSpace(value), ":")
+	switch kind {
+	case "token":
+		if arg == "" {
+			return r.readFile(r.dir(), "token")
+		}
+		return r.requestToken(ctx, arg)
+	case "namespace", "ca.crt":
+		if arg != "" {
+			break
+		}
+		return r.readFile(r.dir(), kind)
+	case "pod-name":
+		if arg != "" {
+			break
+		}
+		if name := os.Getenv("POD_NAME"); name != "" {
+			return name, nil
+		}
+		return os.Hostname()
+	case "downward":
+		return r.downward(arg)
+	}
+	return "", fmt.Errorf("%w: unknown k8s-sa reference %q", ErrBadPath, value)
+}
+
+// Describe reports the resolver metadata.
+func (r *K8sServiceAccountResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilitySelector, CapabilityRemote}}
+}
+
+func (r *K8sServiceAccountResolver) dir() string {
+	return firstNonEmpty(r.Dir, defaultServiceAccountDir)
+}
+
+// readFile returns the trimmed content of dir/name.
+func (r *K8sServiceAccountResolver) readFile(dir, name string) (string, error) {
+	p := filepath.Join(dir, name)
+	data, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s (not running in a pod?)", ErrNotFound, p)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, p)
+		}
+		return "", fmt.Errorf("failed to read %q: %w", p, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// downward reads a downward API file, optionally selecting one key="value" entry.
+func (r *K8sServiceAccountResolver) downward(value string) (string, error) {
+	file, key := splitFileAndKey(value)
+	file = strings.TrimSpace(file)
+	if file == "" || strings.Contains(file, "..") || filepath.IsAbs(file) {
+		return "", fmt.Errorf("%w: invalid downward API file %q", ErrBadPath, file)
+	}
+	dir := firstNonEmpty(r.DownwardDir, os.Getenv("K8S_DOWNWARD_DIR"), defaultDownwardDir)
+	content, err := r.readFile(dir, file)
+	if err != nil || key == "" {
+		return content, err
+	}
+
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for sc.Scan() {
+		k, v, ok := strings.Cut(sc.Text(), "=")
+		if !ok || k != key {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(v); err == nil {
+			return unquoted, nil
+		}
+		return v, nil
+	}
+	return "", fmt.Errorf("%w: key %q in downward API file %q", ErrNotFound, key, file)
+}
+
+// requestToken asks the API server for a token of the pod's service account with the
+// given audience.
+func (r *K8sServiceAccountResolver) requestToken(ctx context.Context, audience string) (string, error) {
+	current, err := r.readFile(r.dir(), "token")
+	if err != nil {
+		return "", err
+	}
+	namespace, name, err := serviceAccountFromToken(current)
+	if err != nil {
+		return "", err
+	}
+
+	server := r.APIServer
+	if server == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return "", fmt.Errorf("%w: no API server configured (KUBERNETES_SERVICE_HOST is unset)", ErrNotFound)
+		}
+		server = "https://" + net.JoinHostPort(host, port)
+	}
+	client, err := r.client()
+	if err != nil {
+		return "", err
+	}
+
+	expiration := r.TokenExpiration
+	if expiration <= 0 {
+		expiration = time.Hour
+	}
+	payload, _ := json.Marshal(map[string]any{
+		"apiVersion": "authentication.k8s.io/v1",
+		"kind":       "TokenRequest",
+		"spec": map[string]any{
+			"audiences":         []string{audience},
+			"expirationSeconds": int64(expiration / time.Second),
+		},
+	})
+	endpoint := strings.TrimRight(server, "/") + "/api/v1/namespaces/" + url.PathEscape(namespace) +
+		"/serviceaccounts/" + url.PathEscape(name) + "/token"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("build TokenRequest: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+current)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	body, err := doHTTP(client, req)
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		Status struct {
+			Token string `json:"token"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse TokenRequest response: %w", err)
+	}
+	if resp.Status.Token == "" {
+		return "", fmt.Errorf("TokenRequest for audience %q returned no token", audience)
+	}
+	return resp.Status.Token, nil
+}
+
+// client returns r.Client, or a client trusting the cluster CA.
+func (r *K8sServiceAccountResolver) client() (*http.Client, error) {
+	if r.Client != nil {
+		return r.Client, nil
+	}
+	ca, err := r.readFile(r.dir(), "ca.crt")
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(ca)) {
+		return nil, fmt.Errorf("no certificates in %s", filepath.Join(r.dir(), "ca.crt"))
+	}
+	return &http.Client{
+		Timeout:   defaultHTTPClient.Timeout,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}},
+	}, nil
+}
+
+// serviceAccountFromToken reads the namespace and name of the service account from
+// the subject of a service-account JWT ("system:serviceaccount:<ns>:<name>"). The
+// token is not verified; the API server does that.
+func serviceAccountFromToken(token string) (namespace, name string, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", "", errors.New("service-account token is not a JWT")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", "", fmt.Errorf("decode service-account token: %w", err)
+	}
+	var claims struct {
+		Sub string `json:"sub"`
+	}
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return "", "", fmt.Errorf("decode service-account token: %w", err)
+	}
+	rest, ok := strings.CutPrefix(claims.Sub, "system:serviceaccount:")
+	if namespace, name, found := strings.Cut(rest, ":"); ok && found && namespace != "" && name != "" {
+		return namespace, name, nil
+	}
+	return "", "", fmt.Errorf("unexpected service-account token subject %q", claims.Sub)
+}
@@ -0,0 +1,114 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServiceAccountToken returns an unsigned JWT for namespace/name.
+func fakeServiceAccountToken(namespace, name string) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	claims, _ := json.Marshal(map[string]string{"sub": "system:serviceaccount:" + namespace + ":" + name})
+	return enc([]byte(`{"alg":"RS256"}`)) + "." + enc(claims) + ".sig"
+}
+
+func TestK8sServiceAccountResolver_Resolve(t *testing.T) {
+	saDir := t.TempDir()
+	token := fakeServiceAccountToken("shop", "api")
+	require.NoError(t, os.WriteFile(filepath.Join(saDir, "token"), []byte(token), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(saDir, "namespace"), []byte("shop\n"), 0o600))
+
+	downward := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(downward, "labels"), []byte("app=\"web\"\ntier=\"front \\\"end\\\"\"\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(downward, "cpu_limit"), []byte("2\n"), 0o600))
+
+	var gotReq map[string]any
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.Method != http.MethodPost || req.URL.Path != "/api/v1/namespaces/shop/serviceaccounts/api/token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(req.Body).Decode(&gotReq)
+		_, _ = w.Write([]byte(`{"status":{"token":"aud-token","expirationTimestamp":"2030-01-01T00:00:00Z"}}`))
+	}))
+	defer srv.Close()
+
+	r := &K8sServiceAccountResolver{Dir: saDir, DownwardDir: downward, APIServer: srv.URL, Client: srv.Client()}
+
+	t.Run("Mounted token", func(t *testing.T) {
+		val, err := r.Resolve("token")
+		require.NoError(t, err)
+		assert.Equal(t, token, val)
+	})
+
+	t.Run("Namespace", func(t *testing.T) {
+		val, err := r.Resolve("namespace")
+		require.NoError(t, err)
+		assert.Equal(t, "shop", val)
+	})
+
+	t.Run("Token for audience", func(t *testing.T) {
+		val, err := r.Resolve("token:https://vault.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "aud-token", val)
+		spec := gotReq["spec"].(map[string]any)
+		assert.Equal(t, []any{"https://vault.example.com"}, spec["audiences"])
+		assert.Equal(t, float64(3600), spec["expirationSeconds"])
+	})
+
+	t.Run("Pod name", func(t *testing.T) {
+		t.Setenv("POD_NAME", "web-7d9f")
+		val, err := r.Resolve("pod-name")
+		require.NoError(t, err)
+		assert.Equal(t, "web-7d9f", val)
+	})
+
+	t.Run("Downward API", func(t *testing.T) {
+		val, err := r.Resolve("downward:cpu_limit")
+		require.NoError(t, err)
+		assert.Equal(t, "2", val)
+
+		val, err = r.Resolve("downward:labels//tier")
+		require.NoError(t, err)
+		assert.Equal(t, `front "end"`, val)
+
+		_, err = r.Resolve("downward:labels//nope")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		_, err = r.Resolve("downward:../token")
+		require.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Not in a pod", func(t *testing.T) {
+		r := &K8sServiceAccountResolver{Dir: t.TempDir()}
+		_, err := r.Resolve("token")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Unknown reference", func(t *testing.T) {
+		_, err := r.Resolve("secrets")
+		require.ErrorIs(t, err, ErrBadPath)
+	})
+}
+
+func TestServiceAccountFromToken(t *testing.T) {
+	ns, name, err := serviceAccountFromToken(fakeServiceAccountToken("kube-system", "coredns"))
+	require.NoError(t, err)
+	assert.Equal(t, "kube-system", ns)
+	assert.Equal(t, "coredns", name)
+
+	_, _, err = serviceAccountFromToken("not-a-jwt")
+	require.Error(t, err)
+}
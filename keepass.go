@@ -0,0 +1,149 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/tobischo/gokeepasslib/v3"
+)
+
+// KeePassResolver resolves entry fields from a KeePass 2 (.kdbx) database.
+// Format: "keepass:<group>/<subgroup>/<entry title>//<field>" (field defaults to "Password").
+//
+// The database path, password and key file default to $KEEPASS_DATABASE,
+// $KEEPASS_PASSWORD and $KEEPASS_KEYFILE. At least one of password or key file is required.
+// The top-level root group is implicit, so "Internet/GitHub" and "Root/Internet/GitHub" are equivalent.
+type KeePassResolver struct {
+	Database string
+	Password string
+	KeyFile  string
+}
+
+func (r *KeePassResolver) Resolve(value string) (string, error) {
+	entryPath, field := splitFileAndKey(value)
+	entryPath = strings.Trim(strings.TrimSpace(entryPath), "/")
+	if entryPath == "" {
+		return "", fmt.Errorf("%w: empty KeePass entry path", ErrBadPath)
+	}
+	if field == "" {
+		if strings.HasSuffix(value, "//") {
+			return "", fmt.Errorf("%w: empty field after // in %q", ErrBadPath, value)
+		}
+		field = "Password"
+	}
+
+	db, err := r.open()
+	if err != nil {
+		return "", err
+	}
+
+	entry, err := findKeePassEntry(db, strings.Split(entryPath, "/"))
+	if err != nil {
+		return "", err
+	}
+	v := entry.Get(field)
+	if v == nil {
+		return "", fmt.Errorf("%w: field %q in KeePass entry %q", ErrNotFound, field, entryPath)
+	}
+	return v.Value.Content, nil
+}
+
+// Describe reports the resolver metadata.
+func (r *KeePassResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilitySelector}}
+}
+
+// open decodes and unlocks the configured database.
+func (r *KeePassResolver) open() (*gokeepasslib.Database, error) {
+	path := firstNonEmpty(r.Database, os.Getenv("KEEPASS_DATABASE"))
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("%w: no KeePass database configured (set KEEPASS_DATABASE)", ErrBadPath)
+	}
+	path = os.ExpandEnv(path)
+	password := firstNonEmpty(r.Password, os.Getenv("KEEPASS_PASSWORD"))
+	keyFile := os.ExpandEnv(firstNonEmpty(r.KeyFile, os.Getenv("KEEPASS_KEYFILE")))
+
+	var (
+		creds *gokeepasslib.DBCredentials
+		err   error
+	)
+	switch {
+	case password != "" && keyFile != "":
+		creds, err = gokeepasslib.NewPasswordAndKeyCredentials(password, keyFile)
+	case keyFile != "":
+		creds, err = gokeepasslib.NewKeyCredentials(keyFile)
+	case password != "":
+		creds = gokeepasslib.NewPasswordCredentials(password)
+	default:
+		return nil, fmt.Errorf("%w: no KeePass password or key file configured", ErrForbidden)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load KeePass key file %q: %w", keyFile, err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, fmt.Errorf("%w: %s", ErrForbidden, path)
+		}
+		return nil, fmt.Errorf("failed to open KeePass database %q: %w", path, err)
+	}
+	defer f.Close() // nolint:errcheck
+
+	db := gokeepasslib.NewDatabase()
+	db.Credentials = creds
+	if err := gokeepasslib.NewDecoder(f).Decode(db); err != nil {
+		return nil, fmt.Errorf("failed to decode KeePass database %q: %w", path, err)
+	}
+	if err := db.UnlockProtectedEntries(); err != nil {
+		return nil, fmt.Errorf("failed to unlock KeePass database %q: %w", path, err)
+	}
+	return db, nil
+}
+
+// findKeePassEntry walks groups by name and returns the entry titled like the last segment.
+func findKeePassEntry(db *gokeepasslib.Database, parts []string) (*gokeepasslib.Entry, error) {
+	if db.Content == nil || db.Content.Root == nil {
+		return nil, fmt.Errorf("%w: KeePass database has no root group", ErrNotFound)
+	}
+	groups := db.Content.Root.Groups
+	// A single top-level group is the implicit root; allow naming it explicitly.
+	if len(groups) == 1 {
+		if len(parts) > 1 && parts[0] == groups[0].Name {
+			parts = parts[1:]
+		}
+		return walkKeePassGroups(groups[0].Groups, groups[0].Entries, parts)
+	}
+	return walkKeePassGroups(groups, nil, parts)
+}
+
+// walkKeePassGroups descends into subgroups for all but the last segment.
+func walkKeePassGroups(groups []gokeepasslib.Group, entries []gokeepasslib.Entry, parts []string) (*gokeepasslib.Entry, error) {
+	for i, name := range parts[:len(parts)-1] {
+		found := false
+		for gi := range groups {
+			if groups[gi].Name == name {
+				entries = groups[gi].Entries
+				groups = groups[gi].Groups
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: KeePass group %q", ErrNotFound, strings.Join(parts[:i+1], "/"))
+		}
+	}
+	title := parts[len(parts)-1]
+	for ei := range entries {
+		if entries[ei].GetTitle() == title {
+			return &entries[ei], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: KeePass entry %q", ErrNotFound, strings.Join(parts, "/"))
+}
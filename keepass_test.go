@@ -0,0 +1,138 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tobischo/gokeepasslib/v3"
+	w "github.com/tobischo/gokeepasslib/v3/wrappers"
+)
+
+func createKeePassTestFile(t *testing.T, password string) string {
+	t.Helper()
+
+	value := func(k, v string) gokeepasslib.ValueData {
+		return gokeepasslib.ValueData{Key: k, Value: gokeepasslib.V{Content: v}}
+	}
+	entry := func(title, user, pass string) gokeepasslib.Entry {
+		e := gokeepasslib.NewEntry()
+		e.Values = append(e.Values,
+			value("Title", title),
+			value("UserName", user),
+			gokeepasslib.ValueData{Key: "Password", Value: gokeepasslib.V{Content: pass, Protected: w.NewBoolWrapper(true)}},
+		)
+		return e
+	}
+
+	internet := gokeepasslib.NewGroup()
+	internet.Name = "Internet"
+	internet.Entries = append(internet.Entries, entry("GitHub", "alice", "hunter2"))
+
+	root := gokeepasslib.NewGroup()
+	root.Name = "Root"
+	root.Entries = append(root.Entries, entry("Top", "bob", "toplevel"))
+	root.Groups = append(root.Groups, internet)
+
+	db := &gokeepasslib.Database{
+		Header:      gokeepasslib.NewHeader(),
+		Credentials: gokeepasslib.NewPasswordCredentials(password),
+		Content: &gokeepasslib.DBContent{
+			Meta: gokeepasslib.NewMetaData(),
+			Root: &gokeepasslib.RootData{Groups: []gokeepasslib.Group{root}},
+		},
+	}
+	require.NoError(t, db.LockProtectedEntries())
+
+	p := filepath.Join(t.TempDir(), "test.kdbx")
+	f, err := os.Create(p)
+	require.NoError(t, err)
+	defer f.Close() // nolint:errcheck
+	require.NoError(t, gokeepasslib.NewEncoder(f).Encode(db))
+	return p
+}
+
+func TestKeePassResolver_Resolve(t *testing.T) {
+	p := createKeePassTestFile(t, "master")
+
+	t.Run("Default password field", func(t *testing.T) {
+		r := &KeePassResolver{Database: p, Password: "master"}
+		val, err := r.Resolve("Internet/GitHub")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", val)
+	})
+
+	t.Run("Explicit field", func(t *testing.T) {
+		r := &KeePassResolver{Database: p, Password: "master"}
+		val, err := r.Resolve("Internet/GitHub//UserName")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", val)
+	})
+
+	t.Run("Explicit root group", func(t *testing.T) {
+		r := &KeePassResolver{Database: p, Password: "master"}
+		val, err := r.Resolve("Root/Internet/GitHub")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", val)
+	})
+
+	t.Run("Entry in root group", func(t *testing.T) {
+		r := &KeePassResolver{Database: p, Password: "master"}
+		val, err := r.Resolve("Top//UserName")
+		require.NoError(t, err)
+		assert.Equal(t, "bob", val)
+	})
+
+	t.Run("Settings from environment", func(t *testing.T) {
+		t.Setenv("KEEPASS_DATABASE", p)
+		t.Setenv("KEEPASS_PASSWORD", "master")
+		val, err := NewExtendedRegistry().ResolveVariable("keepass:Internet/GitHub")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", val)
+	})
+
+	t.Run("Missing entry", func(t *testing.T) {
+		r := &KeePassResolver{Database: p, Password: "master"}
+		_, err := r.Resolve("Internet/GitLab")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing group", func(t *testing.T) {
+		r := &KeePassResolver{Database: p, Password: "master"}
+		_, err := r.Resolve("Email/GitHub")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing field", func(t *testing.T) {
+		r := &KeePassResolver{Database: p, Password: "master"}
+		_, err := r.Resolve("Internet/GitHub//URL")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		r := &KeePassResolver{Database: p, Password: "wrong"}
+		_, err := r.Resolve("Internet/GitHub")
+		require.Error(t, err)
+	})
+
+	t.Run("No credentials", func(t *testing.T) {
+		t.Setenv("KEEPASS_PASSWORD", "")
+		t.Setenv("KEEPASS_KEYFILE", "")
+		r := &KeePassResolver{Database: p}
+		_, err := r.Resolve("Internet/GitHub")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Missing database", func(t *testing.T) {
+		r := &KeePassResolver{Database: filepath.Join(t.TempDir(), "nope.kdbx"), Password: "master"}
+		_, err := r.Resolve("Internet/GitHub")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
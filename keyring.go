@@ -0,0 +1,40 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// KeyringResolver resolves secrets from the operating system keyring: the macOS
+// Keychain, the Windows Credential Manager, or the Secret Service on Linux/BSD.
+// Format: "keyring:<service>/<user>" (the last '/' separates the user), e.g.
+// "keyring:myapp/alice", matching `keyring.Set("myapp", "alice", secret)`.
+type KeyringResolver struct{}
+
+func (r *KeyringResolver) Resolve(value string) (string, error) {
+	i := strings.LastIndexByte(value, '/')
+	if i < 0 {
+		return "", fmt.Errorf("%w: expected keyring:<service>/<user>, got %q", ErrBadPath, value)
+	}
+	service, user := strings.TrimSpace(value[:i]), strings.TrimSpace(value[i+1:])
+	if service == "" || user == "" {
+		return "", fmt.Errorf("%w: empty keyring service or user in %q", ErrBadPath, value)
+	}
+
+	secret, err := keyring.Get(service, user)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return "", fmt.Errorf("%w: keyring item %s/%s", ErrNotFound, service, user)
+		}
+		return "", fmt.Errorf("read keyring item %s/%s: %w", service, user, err)
+	}
+	return secret, nil
+}
+
+// Describe reports the resolver metadata.
+func (r *KeyringResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true}
+}
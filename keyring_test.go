@@ -0,0 +1,42 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestKeyringResolver_Resolve(t *testing.T) {
+	keyring.MockInit()
+	require.NoError(t, keyring.Set("myapp", "alice", "s3cr3t"))
+	require.NoError(t, keyring.Set("github.com/cli", "bob", "gh-token"))
+
+	r := &KeyringResolver{}
+
+	t.Run("Service and user", func(t *testing.T) {
+		val, err := r.Resolve("myapp/alice")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", val)
+	})
+
+	t.Run("Service containing slashes", func(t *testing.T) {
+		val, err := r.Resolve("github.com/cli/bob")
+		require.NoError(t, err)
+		assert.Equal(t, "gh-token", val)
+	})
+
+	t.Run("Missing item", func(t *testing.T) {
+		_, err := r.Resolve("myapp/carol")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing user", func(t *testing.T) {
+		_, err := r.Resolve("myapp")
+		require.ErrorIs(t, err, ErrBadPath)
+
+		_, err = r.Resolve("myapp/")
+		require.ErrorIs(t, err, ErrBadPath)
+	})
+}
@@ -0,0 +1,333 @@
+package resolver
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/subtle"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// KeystoreResolver resolves a certificate or private key from a PKCS#12 (.p12/.pfx) or
+// Java (JKS) keystore, re-encoded as PEM. Format: "keystore:/path/store//<alias>.<part>",
+// e.g. "keystore:/etc/tls/app.p12//app.key".
+//
+// Parts are cert (the entry's certificate, the default when the alias has no part), key
+// (the private key as PKCS#8) and chain (the certificate followed by its issuers).
+// Aliases are matched case-insensitively; unnamed PKCS#12 entries use their local key ID
+// in hex. The store password is read from the environment variable named by
+// PasswordEnv (KEYSTORE_PASSWORD by default); JKS keys must use the store password.
+// Without an alias, the aliases are returned as a JSON list.
+//
+// PKCS#12 files may use PBES2 with AES (the default of OpenSSL 3 and current keytool)
+// or the legacy 3DES/RC2 encryption.
+type KeystoreResolver struct {
+	PasswordEnv string
+}
+
+func (r *KeystoreResolver) Resolve(value string) (string, error) {
+	filePath, selector := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read keystore %q: %w", filePath, err)
+	}
+
+	password := os.Getenv(firstNonEmpty(r.PasswordEnv, "KEYSTORE_PASSWORD"))
+	var entries map[string]*keystoreEntry
+	if bytes.HasPrefix(data, jksMagic) {
+		entries, err = parseJKS(data, password)
+	} else {
+		entries, err = parsePKCS12(data, password)
+	}
+	if err != nil {
+		return "", fmt.Errorf("keystore %q: %w", filePath, err)
+	}
+
+	if selector == "" {
+		aliases := make([]string, 0, len(entries))
+		for alias := range entries {
+			aliases = append(aliases, alias)
+		}
+		slices.Sort(aliases)
+		out, _ := json.Marshal(aliases)
+		return string(out), nil
+	}
+
+	alias, part := strings.ToLower(selector), "cert"
+	if i := strings.LastIndexByte(alias, '.'); i >= 0 {
+		switch alias[i+1:] {
+		case "cert", "key", "chain":
+			alias, part = alias[:i], alias[i+1:]
+		}
+	}
+	entry, ok := entries[alias]
+	if !ok {
+		return "", fmt.Errorf("%w: alias %q in keystore %q", ErrNotFound, alias, filePath)
+	}
+
+	var out []byte
+	switch {
+	case part == "key" && entry.key != nil:
+		out = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: entry.key})
+	case part == "cert" && len(entry.certs) > 0:
+		out = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: entry.certs[0]})
+	case part == "chain" && len(entry.certs) > 0:
+		for _, c := range entry.certs {
+			out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c})...)
+		}
+	default:
+		return "", fmt.Errorf("%w: no %s for alias %q in keystore %q", ErrNotFound, part, alias, filePath)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Describe reports the resolver metadata.
+func (r *KeystoreResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilitySelector}}
+}
+
+// keystoreEntry is an alias of a keystore.
+type keystoreEntry struct {
+	key   []byte   // PKCS#8 DER, nil for trusted certificates
+	certs [][]byte // DER, leaf first
+}
+
+// keystoreAlias returns the entry for alias in entries, adding it if needed.
+func keystoreAlias(entries map[string]*keystoreEntry, alias string) *keystoreEntry {
+	e, ok := entries[alias]
+	if !ok {
+		e = &keystoreEntry{}
+		entries[alias] = e
+	}
+	return e
+}
+
+// parsePKCS12 returns the entries of a PKCS#12 file. Bags are grouped by friendly name
+// or, failing that, by local key ID; certificates with neither (the CA certificates
+// added by "openssl pkcs12 -certfile") complete the chains of the key entries.
+func parsePKCS12(data []byte, password string) (map[string]*keystoreEntry, error) {
+	// DecodeChain would drop the friendly names and allows a single key, so read the
+	// bags with ToPEM, whose non-PKCS#8 keys are normalized below.
+	blocks, err := pkcs12.ToPEM(data, password) // nolint:staticcheck // see above
+	if err != nil {
+		if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+			return nil, fmt.Errorf("%w: %v", ErrForbidden, err)
+		}
+		return nil, fmt.Errorf("failed to parse PKCS#12: %w", err)
+	}
+
+	names := make(map[string]string) // local key ID -> alias
+	for _, b := range blocks {
+		if name, id := b.Headers["friendlyName"], b.Headers["localKeyId"]; name != "" && id != "" {
+			names[id] = strings.ToLower(name)
+		}
+	}
+
+	entries := make(map[string]*keystoreEntry)
+	var issuers [][]byte
+	for _, b := range blocks {
+		alias := strings.ToLower(b.Headers["friendlyName"])
+		if id := b.Headers["localKeyId"]; alias == "" && id != "" {
+			alias = firstNonEmpty(names[id], id)
+		}
+		switch {
+		case b.Type == "CERTIFICATE" && alias == "":
+			issuers = append(issuers, b.Bytes)
+		case b.Type == "CERTIFICATE":
+			e := keystoreAlias(entries, alias)
+			e.certs = append(e.certs, b.Bytes)
+		default:
+			// ToPEM returns PKCS#1 or SEC 1 keys; normalize them to PKCS#8.
+			var key any
+			if key, err = x509.ParsePKCS1PrivateKey(b.Bytes); err != nil {
+				if key, err = x509.ParseECPrivateKey(b.Bytes); err != nil {
+					return nil, errors.New("unsupported private key type in PKCS#12")
+				}
+			}
+			e := keystoreAlias(entries, alias)
+			if e.key, err = x509.MarshalPKCS8PrivateKey(key); err != nil {
+				return nil, fmt.Errorf("failed to encode private key %q: %w", alias, err)
+			}
+		}
+	}
+	for _, e := range entries {
+		if e.key != nil {
+			e.certs = append(e.certs, issuers...)
+		}
+	}
+	return entries, nil
+}
+
+// jksMagic starts every JKS keystore.
+var jksMagic = []byte{0xfe, 0xed, 0xfe, 0xed}
+
+// oidJKSKeyProtector identifies Sun's proprietary JKS key protection algorithm.
+var oidJKSKeyProtector = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 42, 2, 17, 1, 1}
+
+// parseJKS returns the entries of a JKS keystore after checking its integrity digest.
+func parseJKS(data []byte, password string) (map[string]*keystoreEntry, error) {
+	pw := jksPassword(password)
+	if len(data) < 12+sha1.Size {
+		return nil, errors.New("truncated JKS keystore")
+	}
+	body := data[:len(data)-sha1.Size]
+	h := sha1.New()
+	h.Write(pw)
+	h.Write([]byte("Mighty Aphrodite"))
+	h.Write(body)
+	if subtle.ConstantTimeCompare(h.Sum(nil), data[len(body):]) != 1 {
+		return nil, fmt.Errorf("%w: wrong password or corrupted JKS keystore", ErrForbidden)
+	}
+
+	rd := &jksReader{data: body[4:]}
+	version := rd.uint32()
+	if version != 1 && version != 2 {
+		return nil, fmt.Errorf("unsupported JKS version %d", version)
+	}
+	cert := func() []byte {
+		if version == 2 {
+			rd.utf() // certificate type, always X.509
+		}
+		return rd.bytes(int(rd.uint32()))
+	}
+
+	entries := make(map[string]*keystoreEntry)
+	for n := rd.uint32(); n > 0 && rd.err == nil; n-- {
+		tag := rd.uint32()
+		e := keystoreAlias(entries, strings.ToLower(rd.utf()))
+		rd.uint64() // creation date
+		switch tag {
+		case 1: // private key and its chain
+			protected := rd.bytes(int(rd.uint32()))
+			for c := rd.uint32(); c > 0 && rd.err == nil; c-- {
+				e.certs = append(e.certs, cert())
+			}
+			if rd.err != nil {
+				break
+			}
+			key, err := jksRecoverKey(protected, pw)
+			if err != nil {
+				return nil, err
+			}
+			e.key = key
+		case 2: // trusted certificate
+			e.certs = append(e.certs, cert())
+		default:
+			return nil, fmt.Errorf("unsupported JKS entry type %d", tag)
+		}
+	}
+	if rd.err != nil {
+		return nil, rd.err
+	}
+	return entries, nil
+}
+
+// jksRecoverKey decrypts a private key protected with the JKS key protector and returns
+// the PKCS#8 key.
+func jksRecoverKey(protected, pw []byte) ([]byte, error) {
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		Data      []byte
+	}
+	if _, err := asn1.Unmarshal(protected, &info); err != nil {
+		return nil, fmt.Errorf("invalid JKS private key: %w", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidJKSKeyProtector) {
+		return nil, fmt.Errorf("unsupported JKS key protection %s", info.Algorithm.Algorithm)
+	}
+	if len(info.Data) < 2*sha1.Size {
+		return nil, errors.New("invalid JKS private key: too short")
+	}
+
+	salt, enc, check := info.Data[:sha1.Size], info.Data[sha1.Size:len(info.Data)-sha1.Size], info.Data[len(info.Data)-sha1.Size:]
+	key := make([]byte, len(enc))
+	digest := salt
+	for i := range enc {
+		if i%sha1.Size == 0 {
+			sum := sha1.Sum(append(slices.Clip(pw), digest...))
+			digest = sum[:]
+		}
+		key[i] = enc[i] ^ digest[i%sha1.Size]
+	}
+	sum := sha1.Sum(append(slices.Clip(pw), key...))
+	if subtle.ConstantTimeCompare(sum[:], check) != 1 {
+		return nil, fmt.Errorf("%w: JKS private key is protected by another password", ErrForbidden)
+	}
+	return key, nil
+}
+
+// jksPassword encodes a password as JKS does: UTF-16 big-endian, without terminator.
+func jksPassword(password string) []byte {
+	var b []byte
+	for _, c := range utf16.Encode([]rune(password)) {
+		b = binary.BigEndian.AppendUint16(b, c)
+	}
+	return b
+}
+
+// jksReader reads the big-endian fields of a JKS keystore; the first error sticks.
+type jksReader struct {
+	data []byte
+	err  error
+}
+
+func (r *jksReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data) {
+		r.err = errors.New("truncated JKS keystore")
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *jksReader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *jksReader) uint64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+// utf reads a length-prefixed string (Java's modified UTF-8, which equals UTF-8 for
+// common aliases).
+func (r *jksReader) utf() string {
+	if b := r.bytes(2); b != nil {
+		return string(r.bytes(int(binary.BigEndian.Uint16(b))))
+	}
+	return ""
+}
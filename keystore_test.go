@@ -0,0 +1,298 @@
+package resolver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testP12 holds the key and certificate "app" (CN=app) and its issuer (CN=ca), created
+// with "openssl pkcs12 -export -legacy -name app ... -certfile ca.crt" and the password
+// "changeit".
+const testP12 = "" +
+	"MIIE7QIBAzCCBLMGCSqGSIb3DQEHAaCCBKQEggSgMIIEnDCCA3cGCSqGSIb3DQEHBqCCA2gwggNkAgEAMIIDXQYJKoZIhvcN" +
+	"AQcBMBwGCiqGSIb3DQEMAQYwDgQIjW53pi4D+X4CAggAgIIDMKy5h1Pv5Xn1e9VgVAyeqTRyBv6na1BTEpwpQ+wz26AGBGRJ" +
+	"M74Xy6NSckzTKSBi8SbbGx+85fw1vZLGl8yUnzfocrBMk4ApGSk76RR5WPMF9mZZCoiAz7tzbRG4b8MNPPVzvtpJp/2JlHgo" +
+	"2OZUFWPJoClWWkkK2TMrfC5TV7m26YueaNkY3pprvAx/fgRk4CDHXUy6s3ecGwuu96AlFmKN7VWJqNNRmYZvqd+BRgfCgMKU" +
+	"/dnzmdD763o5UuUouGofzfoNB7aDdjHyG5tHlk8C6xzmmr7+0iL0Oy83yhvweZD3uWUqU0+LQfhGttfNjdeMFsO22luxbcoW" +
+	"Mbq5276zIjiuBy63u7bSzfvge3vNrwnjAM3WTEbHxBomzyXli8pNfARM1J0fKK+nOT7UZKccnR6Iy5q3IT86gO/pqs/bd/5O" +
+	"GMKzbbeac48LE52kR5INJTC1cZA5E99Oes79Akgta4KwcQt5Z8HWABs39PQqeE1Gfc9s1DFmnnjrWz+3hNLo+iuzY900uBYd" +
+	"shkp+2Fyphz3eXWDK4S++xdNF9s0ZNB9qYrmoW/rImUPuUl9t7aPc724qgReGQNk/8k7XNN1Lu62XJJ5zf5WA3Fa5YlRm/0x" +
+	"QbQXKmZk4wPy/fNZp2pAU5MU2//lCTXTQwWGlusOhg3IRmyUmCS/wiayFcM4hpIxHYsspxFgYGoNAA0swt1AWnmI6Fvddtzj" +
+	"EdgzMBKEUIHjwyagZaLTKZ22INTM1K3Fr9nRbI0qEH8hL/zsyqKOzNq2Hjp510ap9IZ4uUpr7LdxMKSMhB2nVcnTUeYRqDPa" +
+	"jWmcI46t9kRRQtFcFY/nEyZ+RvvvdOcdjvHbARRa5BQFsYZ/Aj4v5uoFuDgjx3wiG7Om+1hm32o5By4SttCsginzpvI9uWX+" +
+	"oxDDfumsuruuIHWeREC4jJO0kaD1YwPeM2BvPCG+0VbrqU8oJlBdeAdxcSVfakmSFuu77MIRJQ9pNkAVw8t+F8cPstQH/lXA" +
+	"XRj079QHixufn7L9yNSlzDvilsHQRaSBG6ddhP2Vl5e/DZofnq9b7pizMklAsCNF2upChBEDwPQWM3zkhjCCAR0GCSqGSIb3" +
+	"DQEHAaCCAQ4EggEKMIIBBjCCAQIGCyqGSIb3DQEMCgECoIG0MIGxMBwGCiqGSIb3DQEMAQMwDgQIZxZa5vPjHn0CAggABIGQ" +
+	"GdOyY4Xquu1oLo5sE62VzLh28McFhiW/mGtDfhDC7+Y8RwgQxCgyxdAfcY60lsisOKfUxspHHw+Ud2akbpmJVUnJ4eNlRHhC" +
+	"HTftfZUL/fedOwtkRRI2eoC27gz8VpxGcLpon3+w7Audutsg3xmYbkFMMUckZ+mliXwltk/XiQdSlL7D/9R5gO8NIawmiHxZ" +
+	"MTwwFQYJKoZIhvcNAQkUMQgeBgBhAHAAcDAjBgkqhkiG9w0BCRUxFgQUFrs4k9MRaNfWV+a5yzg9u7E2okgwMTAhMAkGBSsO" +
+	"AwIaBQAEFKnjgrgwjMESaJ2MVaFgJCM8aucXBAhkLX2GGS3daQICCAA="
+
+// testP12PBES2 holds the same entries as testP12, but with new keys, created with
+// OpenSSL 3's defaults (PBES2 with AES-256-CBC and PBKDF2, SHA-256 MAC).
+const testP12PBES2 = "" +
+	"MIIFcwIBAzCCBSkGCSqGSIb3DQEHAaCCBRoEggUWMIIFEjCCA7IGCSqGSIb3DQEHBqCCA6MwggOfAgEAMIIDmAYJKoZIhvcN" +
+	"AQcBMFcGCSqGSIb3DQEFDTBKMCkGCSqGSIb3DQEFDDAcBAhJjUiNB7GB2QICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQME" +
+	"ASoEEBH4Gvl40cpxqW7iNeyI+SSAggMwQIJ1Jc5h9A7Mi6Qsjy7ZW1NfUf0otk450Vrji5oMXTiQSnH3nkgkreYoQk/YsKDN" +
+	"I/yiPJY3UC1EHjmzcMyMgA8kfXpMdRpA0b1LVqKhfmAGsJnAsO55kJuLGXoVM6+COEmbhipE38D/stTgrSNPwW3w6nWc5U+1" +
+	"H4zFpApP76GTcIQpxQK3P+4H4sEt6bE2TY9V1Lj7VzBmOjJr+BCCF29LlWU19V3HGnhZ2pVdATDtll5Eq7GYI3igI3V8B868" +
+	"99yq/Emie1PtMwcKDXtPpV5qsR6npb2rB5rppGZUwZb6CgJci1w69Qd3wx6RuqO6guQtyttFRW1m4ChlsgjA0oJzKkuOOGQr" +
+	"t6YzFvUTYqbG43k9EUcsUh2VN8+ErqfSqYSKagQn5W3UgqyAg2tzsw2s/gei/+XFGi7Xt8OJsIuGkBR5FfiR9H0tChq76sMF" +
+	"TPi7bcAKohMMx6EYDxmMbgSbIfjGZ73XH2IEwesdTnbbD/so7ZyiG3VawwAM6eyD/HcQio28/WARR6vtr3nhxOuRl8VY3z4A" +
+	"yNS+b9rc7DzXOxYpTPtxRvpsSd67SFIgQu8wtraVUdSkat326k59WxkgUATnZFmIfpwc/E5/2lC9zXtsyEJjyZrQ42PPXC22" +
+	"tBOwsP55yeG13240+lSVtdNIJdbpFcbzvXm+an8RusIVbDLU5DykUHyD/fEe+P3kJaDebYmHCsID7K4+utVPc8HBtmLNByGe" +
+	"K9ltnZRlpEgdfy4IjQD5H8GM0O+2DnCVK31vM5k94OLKQc6ca5azSzSULqZoPhFCSEFcyegfQFFHvuqtjFW9ptiOQTPJXo+4" +
+	"0lUawO01tmvQied/pqaBt56kampH4BzcFsXyvYUwezpO56pqVZ8FzySxEONysn+BCnRGWOsX5pVHIW+/n+W2qxfgvO8isnTk" +
+	"jP+1jnI1ZHSsfC2C3wURISgDMYYOIe6txVdUpgOJmSAW3oh/26uqI/viTqzV1PUiIfY8fuTNUK+yaOroP+g0rYOKIysuNj8H" +
+	"fl7x/OwEo8cVQSXMtVYh8QuXcfFTLzTA1nneRgJnfHHXV9+gUM7GEHOpRkiTBLP8MIIBWAYJKoZIhvcNAQcBoIIBSQSCAUUw" +
+	"ggFBMIIBPQYLKoZIhvcNAQwKAQKgge8wgewwVwYJKoZIhvcNAQUNMEowKQYJKoZIhvcNAQUMMBwECK/uHFnqecIzAgIIADAM" +
+	"BggqhkiG9w0CCQUAMB0GCWCGSAFlAwQBKgQQjP4gKT0VpjlGaRH2kD+ZOASBkGWHWe68JB94sgm9D11iJFRGq6eAKn5o5NA7" +
+	"WacBTzMlpE2/Hw+u4nmFVb9GMi0wkkAPvn3PAMYhL1wrYv1LzjEfVocRGRwoXzbspHaucs3wrJkzok4CdZfonARPfKDQbcw5" +
+	"1/VDDGtSyrt2Hw6y3PjHYAQc84boU9NlX/T2TFDMZRCXQQJcILYSETQ0EfiEzTE8MBUGCSqGSIb3DQEJFDEIHgYAYQBwAHAw" +
+	"IwYJKoZIhvcNAQkVMRYEFIucal+1UDdd0J3kFhwjiJGrXqHCMEEwMTANBglghkgBZQMEAgEFAAQgitg54Gpo3oAca86Lvr5t" +
+	"gyt8vg0PKZOVI6XT4Sgy2vEECBFtkTZM7VGBAgIIAA=="
+
+// createKeyPair returns a PKCS#8 EC key and a self-signed certificate for cn.
+func createKeyPair(t *testing.T, cn string) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pkcs8, cert
+}
+
+// jksEntry is an entry for writeJKS; entries without a key are trusted certificates.
+type jksEntry struct {
+	alias string
+	key   []byte
+	certs [][]byte
+}
+
+// writeJKS writes a version 2 JKS keystore protected by password.
+func writeJKS(t *testing.T, password string, entries ...jksEntry) string {
+	t.Helper()
+	pw := jksPassword(password)
+	u32 := func(b []byte, v int) []byte { return binary.BigEndian.AppendUint32(b, uint32(v)) }
+	utf := func(b []byte, s string) []byte { return append(binary.BigEndian.AppendUint16(b, uint16(len(s))), s...) }
+
+	data := u32(append([]byte(nil), jksMagic...), 2)
+	data = u32(data, len(entries))
+	for _, e := range entries {
+		if e.key == nil {
+			data = utf(u32(data, 2), e.alias)
+			data = binary.BigEndian.AppendUint64(data, 0)
+			data = append(u32(utf(data, "X.509"), len(e.certs[0])), e.certs[0]...)
+			continue
+		}
+		// Protect the key like the JKS key protector: XOR with a SHA-1 keystream.
+		salt := make([]byte, sha1.Size)
+		_, err := rand.Read(salt)
+		require.NoError(t, err)
+		protected := append([]byte(nil), salt...)
+		digest := salt
+		for i, c := range e.key {
+			if i%sha1.Size == 0 {
+				sum := sha1.Sum(append(append([]byte(nil), pw...), digest...))
+				digest = sum[:]
+			}
+			protected = append(protected, c^digest[i%sha1.Size])
+		}
+		check := sha1.Sum(append(append([]byte(nil), pw...), e.key...))
+		protected = append(protected, check[:]...)
+		info, err := asn1.Marshal(struct {
+			Algorithm pkix.AlgorithmIdentifier
+			Data      []byte
+		}{pkix.AlgorithmIdentifier{Algorithm: oidJKSKeyProtector, Parameters: asn1.NullRawValue}, protected})
+		require.NoError(t, err)
+
+		data = utf(u32(data, 1), e.alias)
+		data = binary.BigEndian.AppendUint64(data, 0)
+		data = append(u32(data, len(info)), info...)
+		data = u32(data, len(e.certs))
+		for _, c := range e.certs {
+			data = append(u32(utf(data, "X.509"), len(c)), c...)
+		}
+	}
+	h := sha1.New()
+	h.Write(pw)
+	h.Write([]byte("Mighty Aphrodite"))
+	h.Write(data)
+	data = h.Sum(data)
+
+	p := filepath.Join(t.TempDir(), "store.jks")
+	require.NoError(t, os.WriteFile(p, data, 0o600))
+	return p
+}
+
+// decodePEMChain returns the certificates of PEM data.
+func decodePEMChain(t *testing.T, data string) []*x509.Certificate {
+	t.Helper()
+	var certs []*x509.Certificate
+	for rest := []byte(data); ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			return certs
+		}
+		require.Equal(t, "CERTIFICATE", block.Type)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		certs = append(certs, cert)
+	}
+}
+
+func TestKeystoreResolver_JKS(t *testing.T) {
+	t.Setenv("KEYSTORE_PASSWORD", "changeit")
+	r := &KeystoreResolver{}
+
+	key, cert := createKeyPair(t, "server")
+	_, root := createKeyPair(t, "root")
+	p := writeJKS(t, "changeit",
+		jksEntry{alias: "server", key: key, certs: [][]byte{cert, root}},
+		jksEntry{alias: "rootca", certs: [][]byte{root}},
+	)
+
+	t.Run("Aliases", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.JSONEq(t, `["rootca","server"]`, val)
+	})
+
+	t.Run("Key", func(t *testing.T) {
+		val, err := r.Resolve(p + "//server.key")
+		require.NoError(t, err)
+		assert.Equal(t, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key})), val+"\n")
+	})
+
+	t.Run("Certificate", func(t *testing.T) {
+		val, err := r.Resolve(p + "//SERVER")
+		require.NoError(t, err)
+		certs := decodePEMChain(t, val)
+		require.Len(t, certs, 1)
+		assert.Equal(t, "server", certs[0].Subject.CommonName)
+	})
+
+	t.Run("Chain", func(t *testing.T) {
+		val, err := r.Resolve(p + "//server.chain")
+		require.NoError(t, err)
+		certs := decodePEMChain(t, val)
+		require.Len(t, certs, 2)
+		assert.Equal(t, "root", certs[1].Subject.CommonName)
+	})
+
+	t.Run("Trusted certificate", func(t *testing.T) {
+		val, err := r.Resolve(p + "//rootca.cert")
+		require.NoError(t, err)
+		assert.Equal(t, "root", decodePEMChain(t, val)[0].Subject.CommonName)
+	})
+
+	t.Run("No key for trusted certificate", func(t *testing.T) {
+		_, err := r.Resolve(p + "//rootca.key")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing alias", func(t *testing.T) {
+		_, err := r.Resolve(p + "//client")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		t.Setenv("APP_STORE_PASSWORD", "secret")
+		_, err := (&KeystoreResolver{PasswordEnv: "APP_STORE_PASSWORD"}).Resolve(p + "//server")
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		data, err := os.ReadFile(p)
+		require.NoError(t, err)
+		bad := filepath.Join(t.TempDir(), "bad.jks")
+		require.NoError(t, os.WriteFile(bad, data[:40], 0o600))
+		_, err = r.Resolve(bad + "//server")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestKeystoreResolver_PKCS12(t *testing.T) {
+	for name, store := range map[string]string{"Legacy": testP12, "PBES2": testP12PBES2} {
+		t.Run(name, func(t *testing.T) {
+			testKeystorePKCS12(t, store)
+		})
+	}
+}
+
+func testKeystorePKCS12(t *testing.T, store string) {
+	t.Setenv("KEYSTORE_PASSWORD", "changeit")
+	r := &KeystoreResolver{}
+
+	data, err := base64.StdEncoding.DecodeString(store)
+	require.NoError(t, err)
+	p := filepath.Join(t.TempDir(), "app.p12")
+	require.NoError(t, os.WriteFile(p, data, 0o600))
+
+	t.Run("Aliases", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.JSONEq(t, `["app"]`, val)
+	})
+
+	t.Run("Key matches certificate", func(t *testing.T) {
+		keyPEM, err := r.Resolve(p + "//app.key")
+		require.NoError(t, err)
+		block, _ := pem.Decode([]byte(keyPEM))
+		require.NotNil(t, block)
+		assert.Equal(t, "PRIVATE KEY", block.Type)
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		require.NoError(t, err)
+
+		certPEM, err := r.Resolve(p + "//app.cert")
+		require.NoError(t, err)
+		cert := decodePEMChain(t, certPEM)[0]
+		assert.Equal(t, "app", cert.Subject.CommonName)
+		assert.True(t, key.(*ecdsa.PrivateKey).PublicKey.Equal(cert.PublicKey))
+	})
+
+	t.Run("Chain", func(t *testing.T) {
+		val, err := r.Resolve(p + "//app.chain")
+		require.NoError(t, err)
+		certs := decodePEMChain(t, val)
+		require.Len(t, certs, 2)
+		assert.Equal(t, "app", certs[0].Subject.CommonName)
+		assert.Equal(t, "ca", certs[1].Subject.CommonName)
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		t.Setenv("KEYSTORE_PASSWORD", "wrong")
+		_, err := r.Resolve(p + "//app")
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "missing.p12") + "//app")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,77 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_ResolveLayered(t *testing.T) {
+	dir := t.TempDir()
+	defaults := filepath.Join(dir, "defaults.yaml")
+	require.NoError(t, os.WriteFile(defaults, []byte("db:\n  host: localhost\n  port: 5432\n"), 0o600))
+	envFile := filepath.Join(dir, "app.env")
+	require.NoError(t, os.WriteFile(envFile, []byte("DB_PORT=6432\n"), 0o600))
+
+	layers := []string{
+		"env:{KEY}",
+		"file:" + envFile + "//{KEY}",
+		"yaml:" + defaults,
+	}
+	r := NewDefaultRegistry()
+
+	t.Run("Env var wins", func(t *testing.T) {
+		t.Setenv("DB_HOST", "db.prod")
+		res, err := r.ResolveLayered("db.host", layers)
+		require.NoError(t, err)
+		assert.Equal(t, LayeredResult{Value: "db.prod", Token: "env:DB_HOST", Layer: 0}, res)
+	})
+
+	t.Run("Env file before defaults", func(t *testing.T) {
+		res, err := r.ResolveLayered("db.port", layers)
+		require.NoError(t, err)
+		assert.Equal(t, "6432", res.Value)
+		assert.Equal(t, 1, res.Layer)
+	})
+
+	t.Run("Falls back to defaults", func(t *testing.T) {
+		res, err := r.ResolveLayered("db.host", layers)
+		require.NoError(t, err)
+		assert.Equal(t, LayeredResult{Value: "localhost", Token: "yaml:" + defaults + "//db.host", Layer: 2}, res)
+	})
+
+	t.Run("Missing files are skipped", func(t *testing.T) {
+		res, err := r.ResolveLayered("db.host", []string{"file:" + filepath.Join(dir, "missing.env") + "//{KEY}", "yaml:" + defaults})
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", res.Value)
+	})
+
+	t.Run("Not found anywhere", func(t *testing.T) {
+		_, err := r.ResolveLayered("db.user", layers)
+		require.ErrorIs(t, err, ErrNotFound)
+		assert.Contains(t, err.Error(), "env:DB_USER")
+	})
+
+	t.Run("Other errors abort", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register("deny:", ResolverFunc(func(string) (string, error) { return "", ErrForbidden }))
+		r.Register("ok:", ResolverFunc(func(v string) (string, error) { return v, nil }))
+		_, err := r.ResolveLayered("k", []string{"deny:", "ok:"})
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Unknown scheme", func(t *testing.T) {
+		_, err := r.ResolveLayered("db.host", []string{"nosuch:"})
+		require.ErrorIs(t, err, ErrBadPath)
+	})
+}
+
+func TestLayerToken(t *testing.T) {
+	assert.Equal(t, "env:DB_HOST_1", layerToken("env:{KEY}", "db.host-1"))
+	assert.Equal(t, "env:db.host", layerToken("env:", "db.host"))
+	assert.Equal(t, "json:/a.json//db.host", layerToken("json:/a.json", "db.host"))
+	assert.Equal(t, "mem:app/db.host", layerToken("mem:app/{key}", "db.host"))
+}
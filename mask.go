@@ -0,0 +1,143 @@
+package resolver
+
+import (
+	"slices"
+	"strings"
+)
+
+// Masked replaces known secret values in the output of Mask.
+const Masked = "******"
+
+// minMaskLen is the shortest value Mask replaces; shorter values would mask common
+// substrings (e.g. "1" or "on") all over the output.
+const minMaskLen = 4
+
+// SetMasking toggles masking. While on, the registry remembers every value resolved by
+// a scheme whose resolver reports Sensitive (see Describer), and Mask replaces those
+// values in text such as rendered output before it is shown or logged. The registry
+// masks whole files returned by non-sensitive schemes (a token without key path for a
+// resolver with CapabilityWholeFile), its own error messages and the tokens and errors
+// it passes to hooks; the errors still match their causes with errors.Is and errors.As.
+//
+// Child registries inherit the setting and remember secrets resolved through them;
+// their Mask also covers secrets known to their parents.
+func (r *Registry) SetMasking(on bool) {
+	r.mu.Lock()
+	r.masking = on
+	r.maskingSet = true
+	if !on {
+		r.secrets = nil
+	}
+	r.mu.Unlock()
+}
+
+// Mask returns s with every known secret value replaced by Masked. Longer secrets are
+// replaced first, so a secret containing another is masked as a whole.
+func (r *Registry) Mask(s string) string {
+	secrets := r.knownSecrets()
+	if len(secrets) == 0 {
+		return s
+	}
+	slices.SortFunc(secrets, func(a, b string) int { return len(b) - len(a) })
+
+	pairs := make([]string, 0, 2*len(secrets))
+	for _, v := range secrets {
+		pairs = append(pairs, v, Masked)
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// maskText is Mask while masking is on.
+func (r *Registry) maskText(s string) string {
+	if !r.maskingMode() {
+		return s
+	}
+	return r.Mask(s)
+}
+
+// maskErr returns err with known secrets masked in its message while masking is on.
+func (r *Registry) maskErr(err error) error {
+	if err == nil || !r.maskingMode() {
+		return err
+	}
+	msg := err.Error()
+	if masked := r.Mask(msg); masked != msg {
+		return &maskedError{msg: masked, err: err}
+	}
+	return err
+}
+
+// maskedError is an error whose message had secrets masked. It unwraps to the
+// original error, so errors.Is and errors.As see through it.
+type maskedError struct {
+	msg string
+	err error
+}
+
+func (e *maskedError) Error() string { return e.msg }
+func (e *maskedError) Unwrap() error { return e.err }
+
+// maskingMode returns the effective masking setting, inheriting from the parent unless set.
+func (r *Registry) maskingMode() bool {
+	r.mu.RLock()
+	on, set, parent := r.masking, r.maskingSet, r.parent
+	r.mu.RUnlock()
+
+	if set || parent == nil {
+		return on
+	}
+	return parent.maskingMode()
+}
+
+// rememberSecret records the value of resp for Mask if masking is on and it is sensitive.
+func (r *Registry) rememberSecret(resp Response) {
+	value := resp.Value
+	if len(value) < minMaskLen || !resp.Sensitive || !r.maskingMode() {
+		return
+	}
+	r.mu.Lock()
+	if r.secrets == nil {
+		r.secrets = make(map[string]struct{})
+	}
+	r.secrets[value] = struct{}{}
+	r.mu.Unlock()
+}
+
+// knownSecrets returns the secrets remembered by r and its ancestors.
+func (r *Registry) knownSecrets() []string {
+	r.mu.RLock()
+	out := make([]string, 0, len(r.secrets))
+	for v := range r.secrets {
+		out = append(out, v)
+	}
+	parent := r.parent
+	r.mu.RUnlock()
+
+	if parent != nil {
+		out = append(out, parent.knownSecrets()...)
+	}
+	return out
+}
+
+// maskWholeFile returns resp with known secrets masked while masking is on, if it is a
+// whole file (no key path) returned by a non-sensitive resolver with CapabilityWholeFile.
+func (r *Registry) maskWholeFile(res Resolver, rest string, resp Response) Response {
+	if resp.Sensitive || !r.maskingMode() {
+		return resp
+	}
+	d, ok := res.(Describer)
+	if !ok || !slices.Contains(d.Describe().Capabilities, CapabilityWholeFile) {
+		return resp
+	}
+	if _, key := splitFileAndKey(rest); key != "" {
+		return resp
+	}
+	resp.Value = r.Mask(resp.Value)
+	return resp
+}
+
+// isSensitive reports whether res describes itself as Sensitive.
+func isSensitive(res Resolver) bool {
+	d, ok := res.(Describer)
+	return ok && d.Describe().Sensitive
+}
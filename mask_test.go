@@ -0,0 +1,152 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Mask(t *testing.T) {
+	newRegistry := func() *Registry {
+		r := NewRegistry()
+		r.Register("secret:", &metaStub{meta: ResolverMeta{Sensitive: true}})
+		r.Register("plain:", ResolverFunc(func(v string) (string, error) { return v, nil }))
+		return r
+	}
+
+	t.Run("Masks sensitive values in whole file output", func(t *testing.T) {
+		r := newRegistry()
+		r.SetMasking(true)
+
+		pw, err := r.ResolveVariable("secret:pw")
+		require.NoError(t, err)
+		file, err := r.ResolveVariable("plain:user=admin\npassword=" + pw + "\n")
+		require.NoError(t, err)
+
+		assert.Equal(t, "user=admin\npassword="+Masked+"\n", r.Mask(file))
+	})
+
+	t.Run("Whole files from non-sensitive schemes are masked", func(t *testing.T) {
+		r := newRegistry()
+		r.Register(filePrefix, &KeyValueFileResolver{})
+		r.Register(jsonPrefix, &JSONResolver{})
+		r.SetMasking(true)
+
+		pw, err := r.ResolveVariable("secret:pw")
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "app.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"user": "admin", "password": "`+pw+`"}`), 0o600))
+
+		file, err := r.ResolveVariable(filePrefix + path)
+		require.NoError(t, err)
+		assert.Equal(t, `{"user": "admin", "password": "`+Masked+`"}`, file)
+
+		doc, err := r.ResolveVariable(jsonPrefix + path)
+		require.NoError(t, err)
+		assert.NotContains(t, doc, pw)
+
+		val, err := r.ResolveVariable(jsonPrefix + path + "//password")
+		require.NoError(t, err)
+		assert.Equal(t, pw, val, "selected values are returned as they are")
+	})
+
+	t.Run("Non-sensitive values are not remembered", func(t *testing.T) {
+		r := newRegistry()
+		r.SetMasking(true)
+
+		_, err := r.ResolveVariable("plain:hostname")
+		require.NoError(t, err)
+		assert.Equal(t, "hostname", r.Mask("hostname"))
+	})
+
+	t.Run("Off by default", func(t *testing.T) {
+		r := newRegistry()
+		_, err := r.ResolveVariable("secret:pw")
+		require.NoError(t, err)
+		assert.Equal(t, "real-pw", r.Mask("real-pw"))
+	})
+
+	t.Run("Longest secret wins", func(t *testing.T) {
+		r := newRegistry()
+		r.SetMasking(true)
+		for _, tok := range []string{"secret:abc", "secret:abcdef"} {
+			_, err := r.ResolveVariable(tok)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, "x="+Masked+" y="+Masked, r.Mask("x=real-abcdef y=real-abc"))
+	})
+
+	t.Run("Short values are not masked", func(t *testing.T) {
+		r := newRegistry()
+		r.SetMasking(true)
+		r.rememberSecret(Response{Value: "on", Sensitive: true})
+		assert.Equal(t, "debug=on", r.Mask("debug=on"))
+	})
+
+	t.Run("Child sees parent secrets", func(t *testing.T) {
+		parent := newRegistry()
+		parent.SetMasking(true)
+		_, err := parent.ResolveVariable("secret:pw")
+		require.NoError(t, err)
+
+		child := parent.Child()
+		_, err = child.ResolveVariable("secret:token")
+		require.NoError(t, err)
+
+		assert.Equal(t, Masked+" "+Masked, child.Mask("real-pw real-token"))
+		assert.Equal(t, Masked+" real-token", parent.Mask("real-pw real-token"))
+	})
+
+	t.Run("Errors and hook events are masked", func(t *testing.T) {
+		r := newRegistry()
+		r.Register("fail:", ResolverFunc(func(v string) (string, error) {
+			return "", fmt.Errorf("%w: no entry for %q", ErrNotFound, v)
+		}))
+		r.SetMasking(true)
+		var events []ResolveEvent
+		var warnings []WarningEvent
+		r.SetHooks(Hooks{
+			OnResolve: func(_ context.Context, ev ResolveEvent) { events = append(events, ev) },
+			OnWarning: func(_ context.Context, ev WarningEvent) { warnings = append(warnings, ev) },
+		})
+
+		pw, err := r.ResolveVariable("secret:pw")
+		require.NoError(t, err)
+
+		_, err = r.ResolveVariable("fail:" + pw)
+		require.ErrorIs(t, err, ErrNotFound)
+		assert.NotContains(t, err.Error(), pw)
+		assert.Contains(t, err.Error(), Masked)
+
+		_, err = r.ResolveString("x=${fail:" + pw + "}")
+		require.ErrorIs(t, err, ErrNotFound)
+		assert.NotContains(t, err.Error(), pw)
+
+		_, err = r.ResolveString("x=${fail:" + pw + "?optional}")
+		require.NoError(t, err)
+
+		require.Len(t, events, 4)
+		for _, ev := range events[1:] {
+			assert.Equal(t, "fail:"+Masked, ev.Token)
+			require.ErrorIs(t, ev.Err, ErrNotFound)
+			assert.NotContains(t, ev.Err.Error(), pw)
+		}
+		require.Len(t, warnings, 1)
+		assert.Equal(t, "fail:"+Masked, warnings[0].Token)
+		assert.NotContains(t, warnings[0].Err.Error(), pw)
+	})
+
+	t.Run("Errors are left alone while masking is off", func(t *testing.T) {
+		r := newRegistry()
+		r.Register("fail:", ResolverFunc(func(v string) (string, error) { return "", fmt.Errorf("bad %s", v) }))
+		_, err := r.ResolveVariable("secret:pw")
+		require.NoError(t, err)
+		_, err = r.ResolveVariable("fail:real-pw")
+		assert.EqualError(t, err, "bad real-pw")
+	})
+}
@@ -0,0 +1,92 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemResolver resolves values from an in-process key/value store.
+// Format: "mem:<key>".
+// It is not registered by default; register an instance to use it:
+//
+//	mem := NewMemResolver()
+//	RegisterResolver("mem:", mem)
+//	mem.Set("log.level", "debug")
+type MemResolver struct {
+	mu      sync.RWMutex
+	entries map[string]memEntry
+	now     func() time.Time
+}
+
+// memEntry is a stored value with an optional expiry (zero means none).
+type memEntry struct {
+	value   string
+	expires time.Time
+}
+
+// NewMemResolver creates an empty in-memory store.
+func NewMemResolver() *MemResolver {
+	return &MemResolver{
+		entries: make(map[string]memEntry),
+		now:     time.Now,
+	}
+}
+
+// SetClock sets the clock that decides expiry; the default is SystemClock.
+func (m *MemResolver) SetClock(c Clock) {
+	m.mu.Lock()
+	m.now = c.Now
+	m.mu.Unlock()
+}
+
+// Set stores value under key without expiry, replacing any previous value.
+func (m *MemResolver) Set(key, value string) {
+	m.SetWithTTL(key, value, 0)
+}
+
+// SetWithTTL stores value under key; it expires after ttl (ttl <= 0 means never).
+func (m *MemResolver) SetWithTTL(key, value string, ttl time.Duration) {
+	e := memEntry{value: value}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ttl > 0 {
+		e.expires = m.now().Add(ttl)
+	}
+	m.entries[key] = e
+}
+
+// Delete removes key from the store. Deleting a missing key is a no-op.
+func (m *MemResolver) Delete(key string) {
+	m.mu.Lock()
+	delete(m.entries, key)
+	m.mu.Unlock()
+}
+
+// Resolve returns the stored value, or ErrNotFound if key is missing or expired.
+func (m *MemResolver) Resolve(value string) (string, error) {
+	key := strings.TrimSpace(value)
+	if key == "" {
+		return "", fmt.Errorf("%w: empty mem key", ErrBadPath)
+	}
+
+	m.mu.RLock()
+	e, ok := m.entries[key]
+	now := m.now()
+	m.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("%w: mem key %q", ErrNotFound, key)
+	}
+	if !e.expires.IsZero() && !now.Before(e.expires) {
+		// Drop the expired entry unless it was replaced meanwhile.
+		m.mu.Lock()
+		if cur, ok := m.entries[key]; ok && cur == e {
+			delete(m.entries, key)
+		}
+		m.mu.Unlock()
+		return "", fmt.Errorf("%w: mem key %q (expired)", ErrNotFound, key)
+	}
+	return e.value, nil
+}
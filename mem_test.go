@@ -0,0 +1,75 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemResolver_Resolve(t *testing.T) {
+	t.Run("Set and resolve", func(t *testing.T) {
+		m := NewMemResolver()
+		m.Set("log.level", "debug")
+
+		val, err := m.Resolve("log.level")
+		require.NoError(t, err)
+		assert.Equal(t, "debug", val)
+	})
+
+	t.Run("Set replaces value", func(t *testing.T) {
+		m := NewMemResolver()
+		m.Set("k", "a")
+		m.Set("k", "b")
+
+		val, err := m.Resolve("k")
+		require.NoError(t, err)
+		assert.Equal(t, "b", val)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		m := NewMemResolver()
+		m.Set("k", "v")
+		m.Delete("k")
+		m.Delete("never-set")
+
+		_, err := m.Resolve("k")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("TTL expiry", func(t *testing.T) {
+		m := NewMemResolver()
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		m.now = func() time.Time { return now }
+		m.SetWithTTL("k", "v", time.Minute)
+
+		val, err := m.Resolve("k")
+		require.NoError(t, err)
+		assert.Equal(t, "v", val)
+
+		now = now.Add(time.Minute)
+		_, err = m.Resolve("k")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Empty key", func(t *testing.T) {
+		m := NewMemResolver()
+		_, err := m.Resolve(" ")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Participates in interpolation", func(t *testing.T) {
+		m := NewMemResolver()
+		reg := NewRegistry()
+		reg.Register("mem:", m)
+		m.Set("host", "db.local")
+
+		got, err := reg.ResolveString("postgres://${mem:host}:5432")
+		require.NoError(t, err)
+		assert.Equal(t, "postgres://db.local:5432", got)
+	})
+}
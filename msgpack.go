@@ -0,0 +1,282 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// MsgpackResolver resolves a value by decoding a MessagePack file and extracting a
+// nested key. Format: "msgpack:/path/file.msgpack//key1.key2.keyN", e.g.
+// "msgpack:/cfg/app.msgpack//server.port".
+//
+// Maps and arrays are navigated like JSON; non-string map keys are formatted as
+// text. Binary data is returned as base64, timestamps in RFC 3339 (UTC) and other
+// extension types as {"type": n, "data": base64}. Strings are returned as-is, other
+// values as JSON. If no key is provided, returns the whole document as JSON.
+type MsgpackResolver struct{}
+
+func (r *MsgpackResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read MessagePack file %q: %w", filePath, err)
+	}
+
+	return selectMsgpack(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *MsgpackResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectMsgpack returns the value at keyPath in MessagePack data, or the whole
+// document if keyPath is empty. source names the document in error messages.
+func selectMsgpack(data []byte, keyPath, source string) (string, error) {
+	d := &msgpackDecoder{data: data}
+	content, err := d.value(0)
+	if err == nil && d.pos != len(data) {
+		err = fmt.Errorf("%d trailing bytes", len(data)-d.pos)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to parse MessagePack in %q: %w", source, err)
+	}
+
+	val := content
+	if keyPath != "" {
+		val, err = selectPath(content, keyPath)
+		if err != nil {
+			return "", fmt.Errorf("%w: key path %q in MessagePack %q: %v", ErrNotFound, keyPath, source, err)
+		}
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	out, _ := json.Marshal(val)
+	return string(out), nil
+}
+
+// maxMsgpackDepth bounds the nesting of maps and arrays.
+const maxMsgpackDepth = 512
+
+// msgpackDecoder decodes MessagePack values from data.
+type msgpackDecoder struct {
+	data []byte
+	pos  int
+}
+
+// next returns the next n bytes.
+func (d *msgpackDecoder) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errors.New("unexpected end of data")
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// uint reads an n-byte big-endian unsigned integer.
+func (d *msgpackDecoder) uint(n uint64) (uint64, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return 0, err
+	}
+	return readBigEndian(b), nil
+}
+
+// value decodes the next value.
+func (d *msgpackDecoder) value(depth int) (any, error) {
+	if depth > maxMsgpackDepth {
+		return nil, errors.New("nested too deeply")
+	}
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c <= 0x8f:
+		return d.mapOf(uint64(c&0x0f), depth)
+	case c <= 0x9f:
+		return d.arrayOf(uint64(c&0x0f), depth)
+	case c <= 0xbf:
+		return d.str(uint64(c & 0x1f))
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6: // bin 8/16/32
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(raw), nil
+	case 0xc7, 0xc8, 0xc9: // ext 8/16/32
+		n, err := d.uint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.ext(n)
+	case 0xca:
+		v, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := d.uint(8)
+		return math.Float64frombits(v), err
+	case 0xcc, 0xcd, 0xce: // uint 8/16/32
+		v, err := d.uint(1 << (c - 0xcc))
+		return int64(v), err
+	case 0xcf: // uint 64
+		v, err := d.uint(8)
+		if v > math.MaxInt64 {
+			return v, err
+		}
+		return int64(v), err
+	case 0xd0:
+		v, err := d.uint(1)
+		return int64(int8(v)), err
+	case 0xd1:
+		v, err := d.uint(2)
+		return int64(int16(v)), err
+	case 0xd2:
+		v, err := d.uint(4)
+		return int64(int32(v)), err
+	case 0xd3:
+		v, err := d.uint(8)
+		return int64(v), err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8: // fixext 1/2/4/8/16
+		return d.ext(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb: // str 8/16/32
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(n)
+	case 0xdc, 0xdd: // array 16/32
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.arrayOf(n, depth)
+	case 0xde, 0xdf: // map 16/32
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapOf(n, depth)
+	}
+	return nil, fmt.Errorf("invalid type byte 0x%02x", c)
+}
+
+// str reads an n-byte string.
+func (d *msgpackDecoder) str(n uint64) (any, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// ext reads an extension value with n bytes of data. Type -1 is a timestamp.
+func (d *msgpackDecoder) ext(n uint64) (any, error) {
+	t, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	typ := int8(t[0])
+	if typ == -1 {
+		var sec, nsec int64
+		switch n {
+		case 4:
+			sec = int64(binary.BigEndian.Uint32(raw))
+		case 8:
+			v := binary.BigEndian.Uint64(raw)
+			sec, nsec = int64(v&(1<<34-1)), int64(v>>34)
+		case 12:
+			nsec = int64(binary.BigEndian.Uint32(raw))
+			sec = int64(binary.BigEndian.Uint64(raw[4:]))
+		default:
+			return nil, fmt.Errorf("invalid timestamp length %d", n)
+		}
+		return time.Unix(sec, nsec).UTC().Format(time.RFC3339Nano), nil
+	}
+	return map[string]any{"type": int64(typ), "data": base64.StdEncoding.EncodeToString(raw)}, nil
+}
+
+// arrayOf reads n array elements.
+func (d *msgpackDecoder) arrayOf(n uint64, depth int) (any, error) {
+	if n > uint64(len(d.data)-d.pos) { // every element takes at least one byte
+		return nil, errors.New("unexpected end of data")
+	}
+	arr := make([]any, n)
+	for i := range arr {
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		arr[i] = v
+	}
+	return arr, nil
+}
+
+// mapOf reads n key/value pairs.
+func (d *msgpackDecoder) mapOf(n uint64, depth int) (any, error) {
+	if n > uint64(len(d.data)-d.pos)/2 {
+		return nil, errors.New("unexpected end of data")
+	}
+	m := make(map[string]any, n)
+	for range n {
+		k, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		ks, ok := k.(string)
+		if !ok {
+			ks = fmt.Sprint(k)
+		}
+		m[ks] = v
+	}
+	return m, nil
+}
@@ -0,0 +1,85 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMsgpack encodes {"server": {"host": "db", "port": 5432}, "ratio": 0.5,
+// "debug": false, "neg": -3, "tags": ["a", "b"], "blob": bin("hi"), "at": timestamp
+// 1700000000, 1: "one"}.
+var testMsgpack = []byte{
+	0x88,
+	0xa6, 's', 'e', 'r', 'v', 'e', 'r',
+	0x82, 0xa4, 'h', 'o', 's', 't', 0xa2, 'd', 'b', 0xa4, 'p', 'o', 'r', 't', 0xcd, 0x15, 0x38,
+	0xa5, 'r', 'a', 't', 'i', 'o', 0xcb, 0x3f, 0xe0, 0, 0, 0, 0, 0, 0,
+	0xa5, 'd', 'e', 'b', 'u', 'g', 0xc2,
+	0xa3, 'n', 'e', 'g', 0xfd,
+	0xa4, 't', 'a', 'g', 's', 0x92, 0xa1, 'a', 0xa1, 'b',
+	0xa4, 'b', 'l', 'o', 'b', 0xc4, 0x02, 'h', 'i',
+	0xa2, 'a', 't', 0xd6, 0xff, 0x65, 0x53, 0xf1, 0x00,
+	0x01, 0xa3, 'o', 'n', 'e',
+}
+
+func TestMsgpackResolver_Resolve(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "app.msgpack")
+	require.NoError(t, os.WriteFile(p, testMsgpack, 0o666))
+	r := &MsgpackResolver{}
+
+	cases := []struct {
+		name, key, want string
+	}{
+		{"Nested string", "server.host", "db"},
+		{"Integer", "server.port", "5432"},
+		{"Float", "ratio", "0.5"},
+		{"Bool", "debug", "false"},
+		{"Negative fixint", "neg", "-3"},
+		{"Array index", "tags.1", "b"},
+		{"Binary", "blob", "aGk="},
+		{"Timestamp", "at", "2023-11-14T22:13:20Z"},
+		{"Integer key", "1", "one"},
+		{"Map as JSON", "server", `{"host":"db","port":5432}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("Whole file as JSON", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.Contains(t, val, `"server":{"host":"db","port":5432}`)
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		_, err := r.Resolve(p + "//server.nope")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.msgpack")
+		require.NoError(t, os.WriteFile(bad, testMsgpack[:20], 0o666))
+		_, err := r.Resolve(bad + "//server")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Huge length", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "huge.msgpack")
+		require.NoError(t, os.WriteFile(bad, []byte{0xdd, 0xff, 0xff, 0xff, 0xff}, 0o666))
+		_, err := r.Resolve(bad)
+		require.Error(t, err)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "nope.msgpack") + "//server")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,117 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// NATSKVResolver resolves a value from a NATS JetStream Key-Value bucket.
+// Format: "nats-kv:<bucket>/<key>" or "natskv:<bucket>//<key>" (keys may contain
+// dots and slashes, e.g. "config.db.host").
+// URL defaults to $NATS_URL, falling back to nats://127.0.0.1:4222.
+// CredsFile defaults to $NATS_CREDS; when set, it authenticates with a .creds file.
+type NATSKVResolver struct {
+	URL       string
+	CredsFile string
+	Timeout   time.Duration // per lookup; defaults to 10s
+	Options   []nats.Option // extra connection options (TLS, ...)
+
+	// lookup replaces the NATS round trip in tests.
+	lookup func(ctx context.Context, bucket, key string) ([]byte, error)
+}
+
+func (r *NATSKVResolver) Resolve(value string) (string, error) {
+	return r.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context bounding the NATS round trip.
+func (r *NATSKVResolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	bucket, key := splitFileAndKey(value)
+	if !strings.Contains(value, "//") {
+		// Bucket names cannot contain '/', so the first one separates the key.
+		bucket, key, _ = strings.Cut(value, "/")
+	}
+	return r.get(ctx, bucket, key)
+}
+
+// Describe reports the resolver metadata.
+func (r *NATSKVResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilityRemote}}
+}
+
+// get validates bucket/key and fetches the latest revision of key.
+func (r *NATSKVResolver) get(ctx context.Context, bucket, key string) (string, error) {
+	bucket, key = strings.TrimSpace(bucket), strings.TrimSpace(key)
+	if bucket == "" {
+		return "", fmt.Errorf("%w: empty NATS KV bucket", ErrBadPath)
+	}
+	if key == "" {
+		return "", fmt.Errorf("%w: empty NATS KV key in bucket %q", ErrBadPath, bucket)
+	}
+
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	lookup := r.lookup
+	if lookup == nil {
+		lookup = r.fetch
+	}
+	data, err := lookup(ctx, bucket, key)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// fetch connects to NATS and reads key from bucket.
+func (r *NATSKVResolver) fetch(ctx context.Context, bucket, key string) ([]byte, error) {
+	url := r.URL
+	if url == "" {
+		url = os.Getenv("NATS_URL")
+	}
+	if url == "" {
+		url = nats.DefaultURL
+	}
+
+	opts := r.Options
+	if creds := firstNonEmpty(r.CredsFile, os.Getenv("NATS_CREDS")); creds != "" {
+		opts = append([]nats.Option{nats.UserCredentials(creds)}, opts...)
+	}
+
+	nc, err := nats.Connect(url, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("connect to NATS %q: %w", url, err)
+	}
+	defer nc.Close()
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, fmt.Errorf("create JetStream context: %w", err)
+	}
+	kv, err := js.KeyValue(ctx, bucket)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrBucketNotFound) {
+			return nil, fmt.Errorf("%w: NATS KV bucket %q", ErrNotFound, bucket)
+		}
+		return nil, fmt.Errorf("open NATS KV bucket %q: %w", bucket, err)
+	}
+	entry, err := kv.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return nil, fmt.Errorf("%w: key %q in NATS KV bucket %q", ErrNotFound, key, bucket)
+		}
+		return nil, fmt.Errorf("read key %q from NATS KV bucket %q: %w", key, bucket, err)
+	}
+	return entry.Value(), nil
+}
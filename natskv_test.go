@@ -0,0 +1,94 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKV returns a lookup function backed by an in-memory bucket map.
+func fakeKV(buckets map[string]map[string]string) func(context.Context, string, string) ([]byte, error) {
+	return func(_ context.Context, bucket, key string) ([]byte, error) {
+		b, ok := buckets[bucket]
+		if !ok {
+			return nil, fmt.Errorf("%w: NATS KV bucket %q", ErrNotFound, bucket)
+		}
+		v, ok := b[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: key %q in NATS KV bucket %q", ErrNotFound, key, bucket)
+		}
+		return []byte(v), nil
+	}
+}
+
+func TestNATSKVResolver_Resolve(t *testing.T) {
+	r := &NATSKVResolver{lookup: fakeKV(map[string]map[string]string{
+		"edge": {"config.db.host": "db.local"},
+	})}
+
+	t.Run("Dotted key", func(t *testing.T) {
+		val, err := r.Resolve("edge//config.db.host")
+		require.NoError(t, err)
+		assert.Equal(t, "db.local", val)
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		_, err := r.Resolve("edge//config.nope")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing bucket", func(t *testing.T) {
+		_, err := r.Resolve("other//config.db.host")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing key separator", func(t *testing.T) {
+		_, err := r.Resolve("edge")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Empty bucket", func(t *testing.T) {
+		_, err := r.Resolve("//config.db.host")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Single slash separates bucket and key", func(t *testing.T) {
+		val, err := r.Resolve("edge/config.db.host")
+		require.NoError(t, err)
+		assert.Equal(t, "db.local", val)
+	})
+
+	t.Run("Key containing slashes", func(t *testing.T) {
+		r := &NATSKVResolver{lookup: fakeKV(map[string]map[string]string{
+			"edge": {"svc/api/token": "t0k3n"},
+		})}
+		val, err := r.Resolve("edge/svc/api/token")
+		require.NoError(t, err)
+		assert.Equal(t, "t0k3n", val)
+	})
+
+	t.Run("Missing credentials file", func(t *testing.T) {
+		r := &NATSKVResolver{URL: "nats://127.0.0.1:1", CredsFile: filepath.Join(t.TempDir(), "none.creds")}
+		_, err := r.Resolve("edge/config.db.host")
+		require.Error(t, err)
+	})
+
+	t.Run("Unreachable server", func(t *testing.T) {
+		r := &NATSKVResolver{URL: "nats://127.0.0.1:1"}
+		_, err := r.Resolve("edge//config.db.host")
+		require.Error(t, err)
+	})
+
+	t.Run("No selector capability", func(t *testing.T) {
+		// "//" separates bucket and key, so values are never navigated.
+		assert.Equal(t, []Capability{CapabilityRemote}, r.Describe().Capabilities)
+	})
+}
@@ -0,0 +1,79 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// NDJSONResolver resolves a value from a newline-delimited JSON (JSON Lines) file,
+// where each line is a JSON value. Format: "ndjson:/path/file.jsonl//selector", e.g.
+// "ndjson:/var/log/events.jsonl//[type=boot].version" or "ndjson:/data/rows.ndjson//0.id".
+//
+// The lines form a list: select a line by its 0-based index (blank lines are skipped
+// and not counted) or with a filter, then navigate it as with "json:". Strings are
+// returned as-is, other values as JSON. If no key is provided, returns the whole file
+// as a string.
+type NDJSONResolver struct{}
+
+func (r *NDJSONResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := readTextFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read NDJSON file %q: %w", filePath, err)
+	}
+
+	return selectNDJSON(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *NDJSONResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectNDJSON returns the value at keyPath in the list of lines of NDJSON data, or
+// the whole document if keyPath is empty. source names the document in error messages.
+func selectNDJSON(data []byte, keyPath, source string) (string, error) {
+	if keyPath == "" {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	var lines []any
+	for i, line := range bytes.Split([]byte(stripBOM(string(data))), []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var v any
+		if err := json.Unmarshal(line, &v); err != nil {
+			return "", fmt.Errorf("failed to parse NDJSON in %q, line %d: %w", source, i+1, err)
+		}
+		lines = append(lines, v)
+	}
+
+	val, err := selectPath(lines, keyPath)
+	if err != nil {
+		return "", fmt.Errorf("%w: key path %q in NDJSON %q: %v", ErrNotFound, keyPath, source, err)
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	out, _ := json.Marshal(val)
+	return string(out), nil
+}
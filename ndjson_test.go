@@ -0,0 +1,66 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNDJSON = `{"type":"start","version":"1.0.0","ts":1}
+{"type":"boot","version":"1.2.3","ts":2,"node":{"id":7}}
+
+{"type":"stop","version":"1.2.3","ts":3}
+`
+
+func TestNDJSONResolver_Resolve(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "events.jsonl")
+	require.NoError(t, os.WriteFile(p, []byte(testNDJSON), 0o666))
+	r := &NDJSONResolver{}
+
+	cases := []struct {
+		name, key, want string
+	}{
+		{"Line index", "0.type", "start"},
+		{"Index after blank line", "2.type", "stop"},
+		{"Filter", "[type=boot].version", "1.2.3"},
+		{"Nested", "[type=boot].node.id", "7"},
+		{"Typed filter", "[ts=int:3].type", "stop"},
+		{"Whole line as JSON", "1.node", `{"id":7}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("Whole file", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(testNDJSON), val)
+	})
+
+	t.Run("No match", func(t *testing.T) {
+		_, err := r.Resolve(p + "//[type=crash].version")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid line", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.jsonl")
+		require.NoError(t, os.WriteFile(bad, []byte("{\"a\":1}\n{oops\n"), 0o666))
+		_, err := r.Resolve(bad + "//0.a")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "nope.jsonl") + "//0")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
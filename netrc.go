@@ -0,0 +1,182 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// NetrcResolver resolves credentials from a .netrc file.
+// Format: "netrc:<machine>//<field>", where field is login, password or account, e.g.
+// "netrc:api.example.com//password".
+//
+// If no machine entry matches, the "default" entry is used. macdef definitions are
+// skipped, '#' starts a comment and double-quoted tokens may contain blanks. Without
+// a field, the entry is returned as JSON. Path defaults to $NETRC, then ~/.netrc.
+type NetrcResolver struct {
+	Path string
+}
+
+func (r *NetrcResolver) Resolve(value string) (string, error) {
+	machine, field := splitFileAndKey(value)
+	machine = strings.TrimSpace(machine)
+	if machine == "" {
+		return "", fmt.Errorf("%w: empty machine name", ErrBadPath)
+	}
+	switch field {
+	case "", "login", "password", "account":
+	default:
+		return "", fmt.Errorf("%w: unknown netrc field %q (want login, password or account)", ErrBadPath, field)
+	}
+
+	p := expandHome(os.ExpandEnv(firstNonEmpty(r.Path, os.Getenv("NETRC"), "~/.netrc")))
+	data, err := readTextFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, p)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, p)
+		}
+		return "", fmt.Errorf("failed to read netrc file %q: %w", p, err)
+	}
+
+	entry, err := findNetrcEntry(string(data), machine)
+	if err != nil {
+		return "", fmt.Errorf("invalid netrc file %q: %w", p, err)
+	}
+	if entry == nil {
+		return "", fmt.Errorf("%w: machine %q in %s", ErrNotFound, machine, p)
+	}
+	if field == "" {
+		out, _ := json.Marshal(entry)
+		return string(out), nil
+	}
+	v, ok := entry[field]
+	if !ok {
+		return "", fmt.Errorf("%w: %s for machine %q in %s", ErrNotFound, field, machine, p)
+	}
+	return v, nil
+}
+
+// Describe reports the resolver metadata.
+func (r *NetrcResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// findNetrcEntry returns the fields of the entry for machine, the default entry if
+// there is none, or nil.
+func findNetrcEntry(src, machine string) (map[string]string, error) {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var (
+		tokens   []string
+		inMacdef bool
+	)
+	for _, line := range lines {
+		if inMacdef {
+			// A macro definition ends at the first blank line.
+			inMacdef = strings.TrimSpace(line) != ""
+			continue
+		}
+		lineTokens, err := netrcTokens(line)
+		if err != nil {
+			return nil, err
+		}
+		for i, tok := range lineTokens {
+			if tok == "macdef" && (len(tokens) == 0 || !netrcTakesValue(tokens[len(tokens)-1])) {
+				if i+1 >= len(lineTokens) {
+					return nil, errors.New("macdef without a name")
+				}
+				inMacdef = true
+				break
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+
+	var found, fallback, cur map[string]string
+	for i := 0; i < len(tokens); i++ {
+		switch tok := tokens[i]; tok {
+		case "machine":
+			if i+1 >= len(tokens) {
+				return nil, errors.New("machine without a name")
+			}
+			i++
+			cur = map[string]string{}
+			if found == nil && tokens[i] == machine {
+				found = cur
+			}
+		case "default":
+			cur = map[string]string{}
+			if fallback == nil {
+				fallback = cur
+			}
+		case "login", "password", "account", "port":
+			if i+1 >= len(tokens) {
+				return nil, fmt.Errorf("%s without a value", tok)
+			}
+			i++
+			if cur == nil {
+				return nil, fmt.Errorf("%s outside a machine entry", tok)
+			}
+			if tok != "port" {
+				cur[tok] = tokens[i]
+			}
+		default:
+			return nil, fmt.Errorf("unexpected token %q", tok)
+		}
+	}
+	if found != nil {
+		return found, nil
+	}
+	return fallback, nil
+}
+
+// netrcTakesValue reports whether the keyword tok is followed by a value.
+func netrcTakesValue(tok string) bool {
+	switch tok {
+	case "machine", "login", "password", "account", "port":
+		return true
+	}
+	return false
+}
+
+// netrcTokens splits a line into blank-separated tokens, stopping at a '#' comment.
+// Double-quoted tokens may contain blanks and backslash escapes.
+func netrcTokens(line string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(line); {
+		switch c := line[i]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return tokens, nil
+		case c == '"':
+			var b strings.Builder
+			for i++; ; i++ {
+				if i >= len(line) {
+					return nil, errors.New("unterminated quoted token")
+				}
+				if line[i] == '\\' && i+1 < len(line) {
+					i++
+				} else if line[i] == '"' {
+					break
+				}
+				b.WriteByte(line[i])
+			}
+			tokens = append(tokens, b.String())
+			i++
+		default:
+			end := strings.IndexAny(line[i:], " \t\r")
+			if end < 0 {
+				end = len(line) - i
+			}
+			tokens = append(tokens, line[i:i+end])
+			i += end
+		}
+	}
+	return tokens, nil
+}
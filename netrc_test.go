@@ -0,0 +1,81 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNetrc = `# credentials
+machine api.example.com
+  login deploy
+  password s3cret
+  account ops
+
+machine other.example.com login bob password "with space" # trailing comment
+
+macdef init
+machine fake.example.com login evil
+
+machine pass#word.example.com login hash password a#b
+
+default login anonymous password guest@
+`
+
+func TestNetrcResolver_Resolve(t *testing.T) {
+	p := filepath.Join(t.TempDir(), ".netrc")
+	require.NoError(t, os.WriteFile(p, []byte(testNetrc), 0o600))
+	r := &NetrcResolver{Path: p}
+
+	cases := []struct {
+		name, ref, want string
+	}{
+		{"Password", "api.example.com//password", "s3cret"},
+		{"Login", "api.example.com//login", "deploy"},
+		{"Account", "api.example.com//account", "ops"},
+		{"Single line with quotes", "other.example.com//password", "with space"},
+		{"Hash inside token", "pass#word.example.com//password", "a#b"},
+		{"Default entry", "unknown.example.com//login", "anonymous"},
+		{"Macro body skipped", "fake.example.com//login", "anonymous"},
+		{"Entry as JSON", "api.example.com", `{"account":"ops","login":"deploy","password":"s3cret"}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(tc.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("NETRC variable", func(t *testing.T) {
+		t.Setenv("NETRC", p)
+		val, err := (&NetrcResolver{}).Resolve("api.example.com//login")
+		require.NoError(t, err)
+		assert.Equal(t, "deploy", val)
+	})
+
+	t.Run("Missing field", func(t *testing.T) {
+		_, err := r.Resolve("other.example.com//account")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		_, err := r.Resolve("api.example.com//token")
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("No match without default", func(t *testing.T) {
+		np := filepath.Join(t.TempDir(), ".netrc")
+		require.NoError(t, os.WriteFile(np, []byte("machine a login b\n"), 0o600))
+		_, err := (&NetrcResolver{Path: np}).Resolve("c//login")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := (&NetrcResolver{Path: filepath.Join(t.TempDir(), "nope")}).Resolve("a//login")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,164 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuth2Client configures one client-credentials client of OAuth2Resolver.
+type OAuth2Client struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// Audience is sent as the "audience" parameter, which some providers
+	// (e.g. Auth0) require; empty omits it.
+	Audience string
+	// AuthInBody sends the client credentials as form parameters instead of HTTP
+	// basic auth, for providers that do not support the latter.
+	AuthInBody bool
+}
+
+// OAuth2Resolver returns access tokens obtained with the OAuth2 client-credentials
+// flow. Format: "oauth2:<client>", e.g. "oauth2:billing".
+//
+// Clients are looked up in Clients, falling back to the environment:
+// OAUTH2_<CLIENT>_TOKEN_URL, OAUTH2_<CLIENT>_CLIENT_ID, OAUTH2_<CLIENT>_CLIENT_SECRET,
+// OAUTH2_<CLIENT>_SCOPES (space-separated) and OAUTH2_<CLIENT>_AUDIENCE, where
+// <CLIENT> is the client name in upper case with other characters than letters and
+// digits replaced by '_'.
+//
+// Tokens are cached in memory until shortly before they expire. It is safe for
+// concurrent use.
+type OAuth2Resolver struct {
+	Clients map[string]OAuth2Client
+	Client  *http.Client
+	// Clock decides token expiry; defaults to SystemClock.
+	Clock Clock
+
+	mu     sync.Mutex
+	tokens map[string]oauth2Token // client name -> cached token
+}
+
+// oauth2Token is a cached access token.
+type oauth2Token struct {
+	value   string
+	expires time.Time // zero: no expiry reported
+}
+
+// oauth2ExpirySkew renews tokens this long before they expire.
+const oauth2ExpirySkew = 30 * time.Second
+
+func (r *OAuth2Resolver) Resolve(value string) (string, error) {
+	return r.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context bounding the token request.
+func (r *OAuth2Resolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	name := strings.TrimSpace(value)
+	if name == "" {
+		return "", fmt.Errorf("%w: empty OAuth2 client name", ErrBadPath)
+	}
+	cfg, err := r.client(name)
+	if err != nil {
+		return "", err
+	}
+
+	clock := r.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.tokens[name]; ok && (t.expires.IsZero() || clock.Now().Before(t.expires)) {
+		return t.value, nil
+	}
+
+	t, err := r.fetch(ctx, name, cfg, clock.Now())
+	if err != nil {
+		return "", err
+	}
+	if r.tokens == nil {
+		r.tokens = make(map[string]oauth2Token)
+	}
+	r.tokens[name] = t
+	return t.value, nil
+}
+
+// Describe reports the resolver metadata.
+func (r *OAuth2Resolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilityRemote}}
+}
+
+// client returns the configuration of the named client.
+func (r *OAuth2Resolver) client(name string) (OAuth2Client, error) {
+	if cfg, ok := r.Clients[name]; ok {
+		return cfg, nil
+	}
+	prefix := "OAUTH2_" + envVarName(name) + "_"
+	cfg := OAuth2Client{
+		TokenURL:     os.Getenv(prefix + "TOKEN_URL"),
+		ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+		ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+		Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		Audience:     os.Getenv(prefix + "AUDIENCE"),
+	}
+	if cfg.TokenURL == "" || cfg.ClientID == "" {
+		return OAuth2Client{}, fmt.Errorf("%w: OAuth2 client %q is not configured (set %sTOKEN_URL and %sCLIENT_ID)", ErrNotFound, name, prefix, prefix)
+	}
+	return cfg, nil
+}
+
+// fetch requests a new token for cfg.
+func (r *OAuth2Resolver) fetch(ctx context.Context, name string, cfg OAuth2Client, now time.Time) (oauth2Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(cfg.Scopes, " "))
+	}
+	if cfg.Audience != "" {
+		form.Set("audience", cfg.Audience)
+	}
+	if cfg.AuthInBody {
+		form.Set("client_id", cfg.ClientID)
+		form.Set("client_secret", cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauth2Token{}, fmt.Errorf("build OAuth2 token request for %q: %w", name, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if !cfg.AuthInBody {
+		req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+	}
+
+	body, err := doHTTP(r.Client, req)
+	if err != nil {
+		return oauth2Token{}, err
+	}
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return oauth2Token{}, fmt.Errorf("failed to parse OAuth2 token response for %q: %w", name, err)
+	}
+	if resp.AccessToken == "" {
+		return oauth2Token{}, fmt.Errorf("OAuth2 token response for %q has no access_token", name)
+	}
+
+	t := oauth2Token{value: resp.AccessToken}
+	if resp.ExpiresIn > 0 {
+		t.expires = now.Add(time.Duration(resp.ExpiresIn)*time.Second - oauth2ExpirySkew)
+	}
+	return t, nil
+}
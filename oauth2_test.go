@@ -0,0 +1,90 @@
+package resolver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuth2Resolver_Resolve(t *testing.T) {
+	var issued atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_ = req.ParseForm()
+		id, secret, ok := req.BasicAuth()
+		if !ok {
+			id, secret = req.PostForm.Get("client_id"), req.PostForm.Get("client_secret")
+		}
+		if id != "billing" || secret != "s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.PostForm.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n := issued.Add(1)
+		_, _ = fmt.Fprintf(w, `{"access_token":"tok-%d-%s","token_type":"Bearer","expires_in":300}`, n, req.PostForm.Get("scope"))
+	}))
+	defer srv.Close()
+
+	cfg := OAuth2Client{TokenURL: srv.URL, ClientID: "billing", ClientSecret: "s3cr3t", Scopes: []string{"read", "write"}}
+
+	t.Run("Cached until expiry", func(t *testing.T) {
+		issued.Store(0)
+		clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		r := &OAuth2Resolver{Clients: map[string]OAuth2Client{"billing": cfg}, Clock: clock}
+
+		val, err := r.Resolve("billing")
+		require.NoError(t, err)
+		assert.Equal(t, "tok-1-read write", val)
+
+		clock.Advance(4 * time.Minute)
+		val, err = r.Resolve("billing")
+		require.NoError(t, err)
+		assert.Equal(t, "tok-1-read write", val)
+
+		clock.Advance(time.Minute) // within the expiry skew
+		val, err = r.Resolve("billing")
+		require.NoError(t, err)
+		assert.Equal(t, "tok-2-read write", val)
+	})
+
+	t.Run("Credentials in body", func(t *testing.T) {
+		issued.Store(0)
+		c := cfg
+		c.AuthInBody = true
+		r := &OAuth2Resolver{Clients: map[string]OAuth2Client{"billing": c}}
+		val, err := r.Resolve("billing")
+		require.NoError(t, err)
+		assert.Equal(t, "tok-1-read write", val)
+	})
+
+	t.Run("Client from environment", func(t *testing.T) {
+		issued.Store(0)
+		t.Setenv("OAUTH2_BILLING_API_TOKEN_URL", srv.URL)
+		t.Setenv("OAUTH2_BILLING_API_CLIENT_ID", "billing")
+		t.Setenv("OAUTH2_BILLING_API_CLIENT_SECRET", "s3cr3t")
+		t.Setenv("OAUTH2_BILLING_API_SCOPES", "read")
+		val, err := (&OAuth2Resolver{}).Resolve("billing-api")
+		require.NoError(t, err)
+		assert.Equal(t, "tok-1-read", val)
+	})
+
+	t.Run("Unknown client", func(t *testing.T) {
+		_, err := (&OAuth2Resolver{}).Resolve("nope")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Wrong secret", func(t *testing.T) {
+		c := cfg
+		c.ClientSecret = "bad"
+		_, err := (&OAuth2Resolver{Clients: map[string]OAuth2Client{"billing": c}}).Resolve("billing")
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+}
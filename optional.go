@@ -0,0 +1,31 @@
+package resolver
+
+import (
+	"context"
+	"strings"
+)
+
+// optionalSuffix marks a ${...} token as non-critical.
+const optionalSuffix = "?optional"
+
+// splitOptional separates a trailing "?optional" or "?optional=fallback" from a token,
+// e.g. "env:PORT?optional=8080" -> ("env:PORT", "8080", true). The fallback is
+// everything after '=' and may be empty.
+func splitOptional(token string) (expr, fallback string, optional bool) {
+	i := strings.LastIndex(token, optionalSuffix)
+	if i < 0 {
+		return token, "", false
+	}
+	rest := token[i+len(optionalSuffix):]
+	if rest != "" && rest[0] != '=' {
+		return token, "", false
+	}
+	return token[:i], strings.TrimPrefix(rest, "="), true
+}
+
+// warn reports a degraded token to the OnWarning hook, if any.
+func (r *Registry) warn(ctx context.Context, token string, err error) {
+	if h := r.effectiveHooks(); h.OnWarning != nil {
+		h.OnWarning(ctx, WarningEvent{ResolutionID: ResolutionID(ctx), Token: r.maskText(token), Err: r.maskErr(err)})
+	}
+}
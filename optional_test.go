@@ -0,0 +1,82 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitOptional(t *testing.T) {
+	cases := []struct {
+		in, expr, fallback string
+		optional           bool
+	}{
+		{"env:PORT", "env:PORT", "", false},
+		{"env:PORT?optional", "env:PORT", "", true},
+		{"env:PORT?optional=8080", "env:PORT", "8080", true},
+		{"env:PORT?optional=", "env:PORT", "", true},
+		{"env:PORT?optionally", "env:PORT?optionally", "", false},
+	}
+	for _, tc := range cases {
+		expr, fallback, optional := splitOptional(tc.in)
+		assert.Equal(t, tc.expr, expr, tc.in)
+		assert.Equal(t, tc.fallback, fallback, tc.in)
+		assert.Equal(t, tc.optional, optional, tc.in)
+	}
+}
+
+func TestResolveString_Optional(t *testing.T) {
+	boom := errors.New("backend down")
+	r := NewRegistry()
+	r.Register("ok:", ResolverFunc(func(v string) (string, error) { return "OK(" + v + ")", nil }))
+	r.Register("bad:", ResolverFunc(func(string) (string, error) { return "", boom }))
+
+	var warnings []WarningEvent
+	r.SetHooks(Hooks{OnWarning: func(_ context.Context, ev WarningEvent) { warnings = append(warnings, ev) }})
+
+	t.Run("Success ignores fallback", func(t *testing.T) {
+		warnings = nil
+		got, err := r.ResolveString("v=${ok:a?optional=x}")
+		require.NoError(t, err)
+		assert.Equal(t, "v=OK(a)", got)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("Failure uses empty fallback", func(t *testing.T) {
+		warnings = nil
+		got, err := r.ResolveString("v=[${bad:a?optional}]")
+		require.NoError(t, err)
+		assert.Equal(t, "v=[]", got)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, "bad:a", warnings[0].Token)
+		assert.ErrorIs(t, warnings[0].Err, boom)
+		assert.NotEmpty(t, warnings[0].ResolutionID)
+	})
+
+	t.Run("Failure uses default", func(t *testing.T) {
+		got, err := r.ResolveString("port=${bad:a?optional=8080}")
+		require.NoError(t, err)
+		assert.Equal(t, "port=8080", got)
+	})
+
+	t.Run("Filters apply to fallback", func(t *testing.T) {
+		got, err := r.ResolveString("${bad:a?optional=x\"y#jsonstr}")
+		require.NoError(t, err)
+		assert.Equal(t, `"x\"y"`, got)
+	})
+
+	t.Run("Required token still fails", func(t *testing.T) {
+		_, err := r.ResolveString("${ok:a?optional} ${bad:b}")
+		assert.ErrorIs(t, err, boom)
+	})
+
+	t.Run("Canceled context still fails", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := r.ResolveStringContext(ctx, "${ok:a?optional}")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
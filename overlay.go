@@ -0,0 +1,219 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containeroo/resolver/selector"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay batches writes to JSON, YAML and TOML files and commits them together:
+// Set changes a document in memory, Commit validates every changed document and
+// writes them all at once, and Discard drops pending writes. Nothing reaches disk
+// unless every document encodes and validates, and if replacing a file fails, files
+// already replaced are restored, as for Apply.
+//
+// Documents are re-encoded on commit (JSON indented by two spaces, keys sorted), so
+// comments and formatting of the original files are not kept.
+//
+//	o := resolver.NewOverlay()
+//	_ = o.Set("/etc/app/config.yaml", "server.port", 8443)
+//	_ = o.Set("/etc/app/db.json", "pool.max", 20)
+//	if err := o.Commit(); err != nil { ... } // both files or neither
+type Overlay struct {
+	// Validate, if set, checks the encoded content of each changed file before
+	// anything is written; an error aborts the commit.
+	Validate func(path string, content []byte) error
+
+	docs  map[string]*overlayDoc // by cleaned path
+	order []string               // paths in the order they were first set
+}
+
+// overlayDoc is a loaded document and whether it has pending writes.
+type overlayDoc struct {
+	data  any
+	mode  fs.FileMode
+	dirty bool
+}
+
+// NewOverlay returns an empty overlay.
+func NewOverlay() *Overlay {
+	return &Overlay{docs: make(map[string]*overlayDoc)}
+}
+
+// Set stores value at keyPath (a dotted selector path, see selector.Set) in the
+// document at path, loading it on first use. A missing file starts as an empty
+// document. A failed Set leaves the pending document unchanged.
+func (o *Overlay) Set(path, keyPath string, value any) error {
+	doc, err := o.load(path)
+	if err != nil {
+		return err
+	}
+	keys, err := selector.ParsePathStrict(keyPath)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadPath, err)
+	}
+	data := deepCopy(doc.data)
+	if err := selector.Set(data, keys, value); err != nil {
+		return fmt.Errorf("%w: set %q in %q: %v", ErrBadPath, keyPath, path, err)
+	}
+	doc.data = data
+	if !doc.dirty {
+		doc.dirty = true
+		o.order = append(o.order, filepath.Clean(path))
+	}
+	return nil
+}
+
+// Get returns the value at keyPath in the document at path, including pending writes.
+func (o *Overlay) Get(path, keyPath string) (any, error) {
+	doc, err := o.load(path)
+	if err != nil {
+		return nil, err
+	}
+	val, err := selector.Navigate(doc.data, selector.ParsePath(keyPath))
+	if err != nil {
+		return nil, fmt.Errorf("%w: key path %q in %q: %v", ErrNotFound, keyPath, path, err)
+	}
+	return val, nil
+}
+
+// Pending returns the paths with pending writes, in the order they were first set.
+func (o *Overlay) Pending() []string {
+	return append([]string(nil), o.order...)
+}
+
+// Discard drops all pending writes.
+func (o *Overlay) Discard() {
+	o.docs = make(map[string]*overlayDoc)
+	o.order = nil
+}
+
+// Commit encodes and validates every changed document, then writes them all at once.
+// On success the overlay is empty; on failure nothing was changed on disk and the
+// pending writes are kept.
+func (o *Overlay) Commit() error {
+	staged := make([]stagedFile, 0, len(o.order))
+	for _, path := range o.order {
+		content, err := encodeDocument(path, o.docs[path].data)
+		if err != nil {
+			return fmt.Errorf("%w: encode %q: %v", ErrInvalidOutput, path, err)
+		}
+		if err := validateOutput(filepath.Ext(path), string(content)); err != nil {
+			return fmt.Errorf("%w: %q: %v", ErrInvalidOutput, path, err)
+		}
+		if o.Validate != nil {
+			if err := o.Validate(path, content); err != nil {
+				return fmt.Errorf("%w: %q: %v", ErrInvalidOutput, path, err)
+			}
+		}
+		staged = append(staged, stagedFile{path: path, content: string(content), mode: o.docs[path].mode})
+	}
+	if err := commitFiles(staged); err != nil {
+		return err
+	}
+	o.Discard()
+	return nil
+}
+
+// load returns the document at path, reading it on first use.
+func (o *Overlay) load(path string) (*overlayDoc, error) {
+	if o.docs == nil {
+		o.docs = make(map[string]*overlayDoc)
+	}
+	path = filepath.Clean(path)
+	if doc, ok := o.docs[path]; ok {
+		return doc, nil
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json", ".yaml", ".yml", ".toml":
+	default:
+		return nil, fmt.Errorf("%w: cannot write %q documents", ErrBadPath, ext)
+	}
+
+	doc := &overlayDoc{data: map[string]any{}, mode: 0o600}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case errors.Is(err, fs.ErrPermission):
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, path)
+	case err != nil:
+		return nil, fmt.Errorf("failed to read %q: %w", path, err)
+	default:
+		if doc.data, err = decodeDocument(path, data); err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil {
+			doc.mode = info.Mode().Perm()
+		}
+	}
+	o.docs[path] = doc
+	return doc, nil
+}
+
+// decodeDocument parses data by the extension of path (.json, .yaml/.yml or .toml)
+// into a map.
+func decodeDocument(path string, data []byte) (any, error) {
+	var doc map[string]any
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &doc)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	default:
+		err = toml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", path, err)
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return doc, nil
+}
+
+// encodeDocument encodes doc in the format of path's extension.
+func encodeDocument(path string, doc any) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		err := enc.Encode(doc)
+		return buf.Bytes(), err
+	case ".yaml", ".yml":
+		return yaml.Marshal(doc)
+	case ".toml":
+		return toml.Marshal(doc)
+	}
+	return nil, fmt.Errorf("cannot write %q", path)
+}
+
+// deepCopy copies the maps and slices of a decoded document.
+func deepCopy(v any) any {
+	switch vv := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(vv))
+		for k, e := range vv {
+			out[k] = deepCopy(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(vv))
+		for i, e := range vv {
+			out[i] = deepCopy(e)
+		}
+		return out
+	}
+	return v
+}
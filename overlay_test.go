@@ -0,0 +1,110 @@
+package resolver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlay(t *testing.T) {
+	t.Run("Commits every document", func(t *testing.T) {
+		dir := t.TempDir()
+		cfg := writeTemplate(t, dir, "config.yaml", "server:\n  host: localhost\n  port: 8080\n")
+		db := writeTemplate(t, dir, "db.json", `{"pool": {"max": 5}, "hosts": ["a"]}`)
+		app := filepath.Join(dir, "app.toml")
+
+		o := NewOverlay()
+		require.NoError(t, o.Set(cfg, "server.port", 8443))
+		require.NoError(t, o.Set(db, "pool.max", 20))
+		require.NoError(t, o.Set(db, "hosts.1", "b"))
+		require.NoError(t, o.Set(app, "log.level", "debug"))
+		assert.Equal(t, []string{cfg, db, app}, o.Pending())
+
+		val, err := o.Get(db, "pool.max")
+		require.NoError(t, err)
+		assert.Equal(t, 20, val, "reads see pending writes")
+		assertFile(t, db, `{"pool": {"max": 5}, "hosts": ["a"]}`, 0o640)
+
+		require.NoError(t, o.Commit())
+		assert.Empty(t, o.Pending())
+
+		assertFile(t, cfg, "server:\n    host: localhost\n    port: 8443\n", 0o640)
+		assertFile(t, db, "{\n  \"hosts\": [\n    \"a\",\n    \"b\"\n  ],\n  \"pool\": {\n    \"max\": 20\n  }\n}\n", 0o640)
+		assertFile(t, app, "[log]\nlevel = 'debug'\n", 0o600)
+	})
+
+	t.Run("Failed Set leaves the document unchanged", func(t *testing.T) {
+		dir := t.TempDir()
+		db := writeTemplate(t, dir, "db.json", `{"pool": {"max": 5}}`)
+
+		o := NewOverlay()
+		err := o.Set(db, "pool.new.*", 1) // creates pool.new before failing at "*"
+		require.ErrorIs(t, err, ErrBadPath)
+		_, err = o.Get(db, "pool.new")
+		require.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, o.Pending())
+
+		require.ErrorIs(t, o.Set(db, "pool.[", 1), ErrBadPath)
+	})
+
+	t.Run("Validation failure writes nothing", func(t *testing.T) {
+		dir := t.TempDir()
+		cfg := writeTemplate(t, dir, "config.yaml", "port: 1\n")
+		db := writeTemplate(t, dir, "db.json", `{"max": 5}`)
+
+		o := NewOverlay()
+		o.Validate = func(path string, content []byte) error {
+			if path == db {
+				return errors.New("max must be below 10")
+			}
+			return nil
+		}
+		require.NoError(t, o.Set(cfg, "port", 2))
+		require.NoError(t, o.Set(db, "max", 50))
+
+		require.ErrorIs(t, o.Commit(), ErrInvalidOutput)
+		assertFile(t, cfg, "port: 1\n", 0o640)
+		assertFile(t, db, `{"max": 5}`, 0o640)
+		assert.Len(t, o.Pending(), 2, "pending writes are kept")
+	})
+
+	t.Run("Failed replace rolls back", func(t *testing.T) {
+		dir := t.TempDir()
+		first := writeTemplate(t, dir, "first.json", `{"a": 1}`)
+		blocker := filepath.Join(dir, "blocker.json")
+
+		o := NewOverlay()
+		require.NoError(t, o.Set(first, "a", 2))
+		require.NoError(t, o.Set(filepath.Join(dir, "created.yaml"), "b", 1))
+		require.NoError(t, o.Set(blocker, "c", 1))
+		require.NoError(t, os.MkdirAll(filepath.Join(blocker, "child"), 0o755)) // renaming a file over a non-empty dir fails
+
+		require.Error(t, o.Commit())
+		assertFile(t, first, `{"a": 1}`, 0o640)
+		assert.NoFileExists(t, filepath.Join(dir, "created.yaml"))
+	})
+
+	t.Run("Discard", func(t *testing.T) {
+		dir := t.TempDir()
+		db := writeTemplate(t, dir, "db.json", `{"max": 5}`)
+
+		o := NewOverlay()
+		require.NoError(t, o.Set(db, "max", 50))
+		o.Discard()
+		require.NoError(t, o.Commit())
+		assertFile(t, db, `{"max": 5}`, 0o640)
+
+		val, err := o.Get(db, "max")
+		require.NoError(t, err)
+		assert.Equal(t, float64(5), val)
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		o := NewOverlay()
+		require.ErrorIs(t, o.Set(filepath.Join(t.TempDir(), "app.ini"), "a", 1), ErrBadPath)
+	})
+}
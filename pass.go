@@ -0,0 +1,109 @@
+package resolver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PassResolver resolves entries from a password-store (https://www.passwordstore.org).
+// Format: "pass:<entry>" (whole entry), "pass:<entry>//<N>" (1-based line, 1 is the password),
+// or "pass:<entry>//<field>" (value of a "field: value" line).
+//
+// StoreDir defaults to $PASSWORD_STORE_DIR, falling back to ~/.password-store.
+// GPG defaults to "gpg" on $PATH.
+type PassResolver struct {
+	StoreDir string
+	GPG      string
+}
+
+func (r *PassResolver) Resolve(value string) (string, error) {
+	entry, sel := splitFileAndKey(value)
+	entry = strings.Trim(strings.TrimSpace(entry), "/")
+	if entry == "" {
+		return "", fmt.Errorf("%w: empty pass entry", ErrBadPath)
+	}
+	if sel == "" && strings.HasSuffix(value, "//") {
+		return "", fmt.Errorf("%w: empty selector after // in %q", ErrBadPath, value)
+	}
+
+	dir, err := r.storeDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, filepath.FromSlash(entry)+".gpg")
+	if rel, err := filepath.Rel(dir, path); err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%w: pass entry %q escapes the store", ErrBadPath, entry)
+	}
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: pass entry %q", ErrNotFound, entry)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: pass entry %q", ErrForbidden, entry)
+		}
+		return "", fmt.Errorf("failed to stat pass entry %q: %w", entry, err)
+	}
+
+	gpg := r.GPG
+	if gpg == "" {
+		gpg = "gpg"
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(gpg, "--quiet", "--batch", "--decrypt", path)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to decrypt pass entry %q: %w: %s", entry, err, strings.TrimSpace(stderr.String()))
+	}
+
+	content := strings.ReplaceAll(stdout.String(), "\r\n", "\n")
+	if sel == "" {
+		return strings.TrimRight(content, "\n"), nil
+	}
+	return selectPassLine(content, sel, entry)
+}
+
+// Describe reports the resolver metadata.
+func (r *PassResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// storeDir returns the configured or conventional password-store directory.
+func (r *PassResolver) storeDir() (string, error) {
+	if r.StoreDir != "" {
+		return os.ExpandEnv(r.StoreDir), nil
+	}
+	if d := os.Getenv("PASSWORD_STORE_DIR"); d != "" {
+		return d, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locate password store: %w", err)
+	}
+	return filepath.Join(home, ".password-store"), nil
+}
+
+// selectPassLine returns line N (1-based) or the value of a "field: value" line.
+func selectPassLine(content, sel, entry string) (string, error) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if n, err := strconv.Atoi(sel); err == nil {
+		if n < 1 || n > len(lines) {
+			return "", fmt.Errorf("%w: line %d in pass entry %q (%d lines)", ErrNotFound, n, entry, len(lines))
+		}
+		return lines[n-1], nil
+	}
+	// The first line is the password and never a field.
+	for _, line := range lines[1:] {
+		k, v, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(k), sel) {
+			return strings.TrimSpace(v), nil
+		}
+	}
+	return "", fmt.Errorf("%w: field %q in pass entry %q", ErrNotFound, sel, entry)
+}
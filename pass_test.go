@@ -0,0 +1,98 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createPassStore creates a store whose entries are plain text and a fake gpg that cats them.
+func createPassStore(t *testing.T, entries map[string]string) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	store := filepath.Join(dir, "store")
+	for name, content := range entries {
+		p := filepath.Join(store, filepath.FromSlash(name)+".gpg")
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	}
+
+	gpg := filepath.Join(dir, "gpg")
+	script := "#!/bin/sh\nfor last; do :; done\ncase \"$last\" in *broken*) echo 'decryption failed' >&2; exit 2;; esac\ncat \"$last\"\n"
+	require.NoError(t, os.WriteFile(gpg, []byte(script), 0o755))
+	return store, gpg
+}
+
+func TestPassResolver_Resolve(t *testing.T) {
+	store, gpg := createPassStore(t, map[string]string{
+		"web/github": "hunter2\nlogin: alice\nurl: https://github.com\n",
+		"broken":     "x",
+	})
+	r := &PassResolver{StoreDir: store, GPG: gpg}
+
+	t.Run("Whole entry", func(t *testing.T) {
+		val, err := r.Resolve("web/github")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2\nlogin: alice\nurl: https://github.com", val)
+	})
+
+	t.Run("Password line", func(t *testing.T) {
+		val, err := r.Resolve("web/github//1")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", val)
+	})
+
+	t.Run("Second line", func(t *testing.T) {
+		val, err := r.Resolve("web/github//2")
+		require.NoError(t, err)
+		assert.Equal(t, "login: alice", val)
+	})
+
+	t.Run("Field", func(t *testing.T) {
+		val, err := r.Resolve("web/github//login")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", val)
+	})
+
+	t.Run("Line out of range", func(t *testing.T) {
+		_, err := r.Resolve("web/github//9")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing field", func(t *testing.T) {
+		_, err := r.Resolve("web/github//otp")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing entry", func(t *testing.T) {
+		_, err := r.Resolve("web/gitlab")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Entry outside store", func(t *testing.T) {
+		_, err := r.Resolve("../gpg")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Decryption failure", func(t *testing.T) {
+		_, err := r.Resolve("broken")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "decryption failed")
+	})
+
+	t.Run("Store dir from environment", func(t *testing.T) {
+		t.Setenv("PASSWORD_STORE_DIR", store)
+		r := &PassResolver{GPG: gpg}
+		val, err := r.Resolve("web/github//url")
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com", val)
+	})
+}
@@ -0,0 +1,97 @@
+package resolver
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PEMResolver resolves a block of a PEM bundle, re-encoded as PEM.
+// Format: "pem:/path/bundle.pem//<selector>", e.g.
+// "pem:/etc/tls/ca-bundle.crt//CERTIFICATE.1".
+//
+// The selector is a 0-based index over all blocks ("2"), a block type for the first
+// block of that type ("RSA PRIVATE KEY"), or a type and a 0-based index among the
+// blocks of that type ("CERTIFICATE.1"). Types are matched case-insensitively. If no
+// selector is provided, returns the whole file as a string.
+type PEMResolver struct{}
+
+func (r *PEMResolver) Resolve(value string) (string, error) {
+	filePath, selector := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read PEM file %q: %w", filePath, err)
+	}
+	if selector == "" {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	var blocks []*pem.Block
+	for rest := data; ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	if len(blocks) == 0 {
+		return "", fmt.Errorf("no PEM blocks in %q", filePath)
+	}
+
+	block, err := selectPEMBlock(blocks, selector)
+	if err != nil {
+		return "", fmt.Errorf("%w in %q", err, filePath)
+	}
+	return strings.TrimSpace(string(pem.EncodeToMemory(block))), nil
+}
+
+// Describe reports the resolver metadata.
+func (r *PEMResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectPEMBlock returns the block of blocks picked by selector ("N", "TYPE" or
+// "TYPE.N").
+func selectPEMBlock(blocks []*pem.Block, selector string) (*pem.Block, error) {
+	if n, err := strconv.Atoi(selector); err == nil {
+		if n < 0 || n >= len(blocks) {
+			return nil, fmt.Errorf("%w: block %d of %d", ErrNotFound, n, len(blocks))
+		}
+		return blocks[n], nil
+	}
+
+	typ, n := selector, 0
+	if i := strings.LastIndexByte(selector, '.'); i >= 0 {
+		idx, err := strconv.Atoi(selector[i+1:])
+		if err != nil || idx < 0 {
+			return nil, fmt.Errorf("%w: invalid block index in %q", ErrBadPath, selector)
+		}
+		typ, n = selector[:i], idx
+	}
+	for _, b := range blocks {
+		if !strings.EqualFold(b.Type, typ) {
+			continue
+		}
+		if n == 0 {
+			return b, nil
+		}
+		n--
+	}
+	return nil, fmt.Errorf("%w: PEM block %q", ErrNotFound, selector)
+}
@@ -0,0 +1,84 @@
+package resolver
+
+import (
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPEMResolver_Resolve(t *testing.T) {
+	r := &PEMResolver{}
+
+	blocks := []*pem.Block{
+		{Type: "CERTIFICATE", Bytes: []byte("leaf")},
+		{Type: "CERTIFICATE", Bytes: []byte("intermediate")},
+		{Type: "EC PRIVATE KEY", Bytes: []byte("key")},
+		{Type: "CERTIFICATE", Bytes: []byte("root")},
+	}
+	var bundle []byte
+	for _, b := range blocks {
+		bundle = append(bundle, pem.EncodeToMemory(b)...)
+	}
+	p := filepath.Join(t.TempDir(), "bundle.pem")
+	require.NoError(t, os.WriteFile(p, append([]byte("# comment\n"), bundle...), 0o600))
+
+	encoded := func(b *pem.Block) string { return strings.TrimSpace(string(pem.EncodeToMemory(b))) }
+
+	cases := []struct {
+		name     string
+		selector string
+		expected *pem.Block
+	}{
+		{"Index", "2", blocks[2]},
+		{"Type", "CERTIFICATE", blocks[0]},
+		{"Type and index", "CERTIFICATE.2", blocks[3]},
+		{"Type with spaces", "EC PRIVATE KEY", blocks[2]},
+		{"Case-insensitive type", "certificate.1", blocks[1]},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.selector)
+			require.NoError(t, err)
+			assert.Equal(t, encoded(tc.expected), val)
+		})
+	}
+
+	t.Run("Whole file", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace("# comment\n"+string(bundle)), val)
+	})
+
+	t.Run("Index out of range", func(t *testing.T) {
+		_, err := r.Resolve(p + "//4")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing type", func(t *testing.T) {
+		_, err := r.Resolve(p + "//CERTIFICATE REQUEST")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid index", func(t *testing.T) {
+		_, err := r.Resolve(p + "//CERTIFICATE.x")
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("No PEM blocks", func(t *testing.T) {
+		empty := filepath.Join(t.TempDir(), "empty.pem")
+		require.NoError(t, os.WriteFile(empty, []byte("not pem"), 0o600))
+		_, err := r.Resolve(empty + "//0")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "missing.pem") + "//0")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
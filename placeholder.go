@@ -0,0 +1,44 @@
+package resolver
+
+import "slices"
+
+// SetPlaceholderMode toggles placeholder mode. In placeholder mode, schemes whose
+// resolvers report themselves as Sensitive or CapabilityRemote (see Describer) are not
+// called; tokens using them resolve to "<token>", e.g. "<vault:secret/app//token>".
+// Local, non-sensitive schemes still resolve. This renders documentation, previews and
+// PR diffs of templated config without contacting backends or leaking secrets.
+//
+// Child registries inherit the mode unless they set it themselves.
+func (r *Registry) SetPlaceholderMode(on bool) {
+	r.mu.Lock()
+	r.placeholders = on
+	r.placeholdersSet = true
+	r.mu.Unlock()
+}
+
+// placeholderMode returns the effective placeholder mode, inheriting from the parent unless set.
+func (r *Registry) placeholderMode() bool {
+	r.mu.RLock()
+	on, set, parent := r.placeholders, r.placeholdersSet, r.parent
+	r.mu.RUnlock()
+
+	if set || parent == nil {
+		return on
+	}
+	return parent.placeholderMode()
+}
+
+// Placeholder returns the value a token resolves to in placeholder mode.
+func Placeholder(token string) string {
+	return "<" + token + ">"
+}
+
+// needsPlaceholder reports whether res is replaced by a placeholder in placeholder mode.
+func needsPlaceholder(res Resolver) bool {
+	d, ok := res.(Describer)
+	if !ok {
+		return false
+	}
+	meta := d.Describe()
+	return meta.Sensitive || slices.Contains(meta.Capabilities, CapabilityRemote)
+}
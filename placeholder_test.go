@@ -0,0 +1,75 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metaStub is a resolver reporting fixed metadata.
+type metaStub struct {
+	meta  ResolverMeta
+	calls int
+}
+
+func (s *metaStub) Resolve(v string) (string, error) { s.calls++; return "real-" + v, nil }
+func (s *metaStub) Describe() ResolverMeta           { return s.meta }
+
+func TestRegistry_PlaceholderMode(t *testing.T) {
+	newRegistry := func() (*Registry, *metaStub, *metaStub, *metaStub) {
+		secret := &metaStub{meta: ResolverMeta{Sensitive: true}}
+		remote := &metaStub{meta: ResolverMeta{Capabilities: []Capability{CapabilityRemote}}}
+		local := &metaStub{meta: ResolverMeta{Capabilities: []Capability{CapabilitySelector}}}
+		r := NewRegistry()
+		r.Register("vault:", secret)
+		r.Register("zk:", remote)
+		r.Register("json:", local)
+		return r, secret, remote, local
+	}
+
+	t.Run("Sensitive and remote schemes are stubbed", func(t *testing.T) {
+		r, secret, remote, local := newRegistry()
+		r.SetPlaceholderMode(true)
+
+		got, err := r.ResolveString("token=${vault:secret/app//token} host=${zk:/cfg//host} port=${json:a.json//port}")
+		require.NoError(t, err)
+		assert.Equal(t, "token=<vault:secret/app//token> host=<zk:/cfg//host> port=real-a.json//port", got)
+		assert.Zero(t, secret.calls)
+		assert.Zero(t, remote.calls)
+		assert.Equal(t, 1, local.calls)
+	})
+
+	t.Run("Off by default", func(t *testing.T) {
+		r, secret, _, _ := newRegistry()
+		got, err := r.ResolveVariable("vault:x")
+		require.NoError(t, err)
+		assert.Equal(t, "real-x", got)
+		assert.Equal(t, 1, secret.calls)
+	})
+
+	t.Run("Child inherits mode", func(t *testing.T) {
+		parent, _, _, _ := newRegistry()
+		parent.SetPlaceholderMode(true)
+		child := parent.Child()
+
+		got, err := child.ResolveVariable("vault:x")
+		require.NoError(t, err)
+		assert.Equal(t, "<vault:x>", got)
+
+		child.SetPlaceholderMode(false)
+		got, err = child.ResolveVariable("vault:x")
+		require.NoError(t, err)
+		assert.Equal(t, "real-x", got)
+	})
+
+	t.Run("Cached resolver reports inner metadata", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register("vault:", NewCachedResolver(&metaStub{meta: ResolverMeta{Sensitive: true}}, CacheOptions{}))
+		r.SetPlaceholderMode(true)
+
+		got, err := r.ResolveVariable("vault:x")
+		require.NoError(t, err)
+		assert.Equal(t, "<vault:x>", got)
+	})
+}
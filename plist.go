@@ -0,0 +1,449 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// PlistResolver resolves a value by loading an Apple property list (XML or binary)
+// and extracting a nested key. Format: "plist:/path/file.plist//key1.key2.keyN", e.g.
+// "plist:/Library/Preferences/com.example.agent.plist//Server.URL".
+//
+// Dictionaries and arrays are navigated like JSON; dates are returned in RFC 3339
+// (UTC) and data as base64. Strings are returned as-is, other values as JSON. If no
+// key is provided, returns the whole file as a string (XML) or as JSON (binary).
+type PlistResolver struct{}
+
+func (r *PlistResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read plist file %q: %w", filePath, err)
+	}
+
+	return selectPlist(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *PlistResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectPlist returns the value at keyPath in plist data, or the whole document if
+// keyPath is empty. source names the document in error messages.
+func selectPlist(data []byte, keyPath, source string) (string, error) {
+	binaryPlist := bytes.HasPrefix(data, []byte("bplist00"))
+	if keyPath == "" && !binaryPlist {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	var (
+		content any
+		err     error
+	)
+	if binaryPlist {
+		content, err = parseBinaryPlist(data)
+	} else {
+		content, err = parseXMLPlist(data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to parse plist in %q: %w", source, err)
+	}
+
+	val := content
+	if keyPath != "" {
+		val, err = selectPath(content, keyPath)
+		if err != nil {
+			return "", fmt.Errorf("%w: key path %q in plist %q: %v", ErrNotFound, keyPath, source, err)
+		}
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	out, _ := json.Marshal(val)
+	return string(out), nil
+}
+
+// plistEpoch is the reference date of plist dates.
+var plistEpoch = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// parseXMLPlist decodes an XML property list.
+func parseXMLPlist(data []byte) (any, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("no plist element")
+			}
+			return nil, err
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			if se.Name.Local != "plist" {
+				return nil, fmt.Errorf("unexpected root element <%s>", se.Name.Local)
+			}
+			el, err := nextPlistElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			if el == nil {
+				return nil, errors.New("empty plist")
+			}
+			return decodeXMLPlistValue(dec, *el)
+		}
+	}
+}
+
+// nextPlistElement returns the next start element, or nil at the parent's end.
+func nextPlistElement(dec *xml.Decoder) (*xml.StartElement, error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			return &t, nil
+		case xml.EndElement:
+			return nil, nil
+		}
+	}
+}
+
+// decodeXMLPlistValue decodes the element se has opened.
+func decodeXMLPlistValue(dec *xml.Decoder, se xml.StartElement) (any, error) {
+	switch se.Name.Local {
+	case "dict":
+		m := make(map[string]any)
+		for {
+			el, err := nextPlistElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			if el == nil {
+				return m, nil
+			}
+			if el.Name.Local != "key" {
+				return nil, fmt.Errorf("expected <key> in <dict>, got <%s>", el.Name.Local)
+			}
+			var key string
+			if err := dec.DecodeElement(&key, el); err != nil {
+				return nil, err
+			}
+			vel, err := nextPlistElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			if vel == nil {
+				return nil, fmt.Errorf("missing value for key %q", key)
+			}
+			v, err := decodeXMLPlistValue(dec, *vel)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+		}
+	case "array":
+		arr := []any{}
+		for {
+			el, err := nextPlistElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			if el == nil {
+				return arr, nil
+			}
+			v, err := decodeXMLPlistValue(dec, *el)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+	case "true", "false":
+		if err := dec.Skip(); err != nil {
+			return nil, err
+		}
+		return se.Name.Local == "true", nil
+	}
+
+	var text string
+	if err := dec.DecodeElement(&text, &se); err != nil {
+		return nil, err
+	}
+	text = strings.TrimSpace(text)
+	switch se.Name.Local {
+	case "string":
+		return text, nil
+	case "integer":
+		return strconv.ParseInt(text, 10, 64)
+	case "real":
+		return strconv.ParseFloat(text, 64)
+	case "date":
+		t, err := time.Parse(time.RFC3339, text)
+		if err != nil {
+			return nil, err
+		}
+		return t.UTC().Format(time.RFC3339), nil
+	case "data":
+		raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(raw), nil
+	}
+	return nil, fmt.Errorf("unsupported plist element <%s>", se.Name.Local)
+}
+
+// maxPlistDepth bounds nesting in binary plists, which could otherwise reference
+// themselves.
+const maxPlistDepth = 512
+
+// binaryPlist holds the parsed trailer of a binary property list.
+type binaryPlist struct {
+	data    []byte
+	offsets []uint64 // object offsets
+	refSize int
+	// nodes counts decoded objects. Objects may be referenced from several places, so
+	// a small file of arrays sharing their elements could expand exponentially; each
+	// reference takes at least a byte, so a tree never decodes to more objects than the
+	// file has bytes.
+	nodes int
+}
+
+// parseBinaryPlist decodes a "bplist00" property list.
+func parseBinaryPlist(data []byte) (any, error) {
+	if len(data) < 8+32 {
+		return nil, errors.New("binary plist too short")
+	}
+	trailer := data[len(data)-32:]
+	offsetSize := int(trailer[6])
+	refSize := int(trailer[7])
+	numObjects := binary.BigEndian.Uint64(trailer[8:])
+	top := binary.BigEndian.Uint64(trailer[16:])
+	tableOffset := binary.BigEndian.Uint64(trailer[24:])
+
+	if offsetSize < 1 || offsetSize > 8 || refSize < 1 || refSize > 8 ||
+		numObjects == 0 || top >= numObjects ||
+		tableOffset > uint64(len(data)) || numObjects > (uint64(len(data))-tableOffset)/uint64(offsetSize) {
+		return nil, errors.New("invalid binary plist trailer")
+	}
+	p := &binaryPlist{data: data, refSize: refSize, offsets: make([]uint64, numObjects)}
+	for i := range p.offsets {
+		start := tableOffset + uint64(i*offsetSize)
+		p.offsets[i] = readBigEndian(data[start : start+uint64(offsetSize)])
+	}
+	return p.object(top, 0)
+}
+
+// readBigEndian reads an unsigned big-endian integer of up to 8 bytes.
+func readBigEndian(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// bytesAt returns n bytes at off, or an error if they are out of range.
+func (p *binaryPlist) bytesAt(off, n uint64) ([]byte, error) {
+	if off > uint64(len(p.data)) || n > uint64(len(p.data))-off {
+		return nil, errors.New("binary plist object out of range")
+	}
+	return p.data[off : off+n], nil
+}
+
+// length reads the element count of the object with marker at off, returning the
+// count and the offset of the object's content.
+func (p *binaryPlist) length(marker byte, off uint64) (uint64, uint64, error) {
+	if n := marker & 0x0f; n != 0x0f {
+		return uint64(n), off + 1, nil
+	}
+	b, err := p.bytesAt(off+1, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	if b[0]&0xf0 != 0x10 {
+		return 0, 0, errors.New("invalid binary plist length")
+	}
+	size := uint64(1) << (b[0] & 0x0f)
+	if size > 8 {
+		return 0, 0, errors.New("invalid binary plist length")
+	}
+	raw, err := p.bytesAt(off+2, size)
+	if err != nil {
+		return 0, 0, err
+	}
+	return readBigEndian(raw), off + 2 + size, nil
+}
+
+// refs reads n object references at off.
+func (p *binaryPlist) refs(off, n uint64) ([]uint64, error) {
+	if n > uint64(len(p.data)) {
+		return nil, errors.New("binary plist object out of range")
+	}
+	raw, err := p.bytesAt(off, n*uint64(p.refSize))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]uint64, n)
+	for i := range out {
+		out[i] = readBigEndian(raw[i*p.refSize : (i+1)*p.refSize])
+	}
+	return out, nil
+}
+
+// object decodes object number idx.
+func (p *binaryPlist) object(idx uint64, depth int) (any, error) {
+	if idx >= uint64(len(p.offsets)) {
+		return nil, errors.New("binary plist reference out of range")
+	}
+	if depth > maxPlistDepth {
+		return nil, errors.New("binary plist nested too deeply")
+	}
+	if p.nodes++; p.nodes > len(p.data) {
+		return nil, errors.New("binary plist expands to too many objects")
+	}
+	off := p.offsets[idx]
+	mb, err := p.bytesAt(off, 1)
+	if err != nil {
+		return nil, err
+	}
+	marker := mb[0]
+
+	switch marker >> 4 {
+	case 0x0:
+		switch marker {
+		case 0x00:
+			return nil, nil
+		case 0x08:
+			return false, nil
+		case 0x09:
+			return true, nil
+		}
+	case 0x1: // int: 2^n bytes, 8-byte ints are signed
+		size := uint64(1) << (marker & 0x0f)
+		raw, err := p.bytesAt(off+1, size)
+		if err != nil || size > 16 {
+			return nil, errors.New("invalid binary plist integer")
+		}
+		if size == 16 { // 128-bit: the low 8 bytes hold the value
+			raw = raw[8:]
+		}
+		return int64(readBigEndian(raw)), nil
+	case 0x2: // real
+		size := uint64(1) << (marker & 0x0f)
+		raw, err := p.bytesAt(off+1, size)
+		if err != nil {
+			return nil, err
+		}
+		switch size {
+		case 4:
+			return float64(math.Float32frombits(uint32(readBigEndian(raw)))), nil
+		case 8:
+			return math.Float64frombits(readBigEndian(raw)), nil
+		}
+	case 0x3: // date
+		raw, err := p.bytesAt(off+1, 8)
+		if err != nil {
+			return nil, err
+		}
+		secs := math.Float64frombits(readBigEndian(raw))
+		return plistEpoch.Add(time.Duration(secs * float64(time.Second))).Format(time.RFC3339), nil
+	case 0x4, 0x5, 0x6: // data, ASCII string, UTF-16 string
+		n, start, err := p.length(marker, off)
+		if err != nil {
+			return nil, err
+		}
+		size := n
+		if marker>>4 == 0x6 {
+			size = n * 2
+		}
+		raw, err := p.bytesAt(start, size)
+		if err != nil {
+			return nil, err
+		}
+		switch marker >> 4 {
+		case 0x4:
+			return base64.StdEncoding.EncodeToString(raw), nil
+		case 0x5:
+			return string(raw), nil
+		}
+		units := make([]uint16, n)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(raw[i*2:])
+		}
+		return string(utf16.Decode(units)), nil
+	case 0x8: // UID
+		raw, err := p.bytesAt(off+1, uint64(marker&0x0f)+1)
+		if err != nil {
+			return nil, err
+		}
+		return int64(readBigEndian(raw)), nil
+	case 0xa: // array
+		n, start, err := p.length(marker, off)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := p.refs(start, n)
+		if err != nil {
+			return nil, err
+		}
+		arr := make([]any, len(refs))
+		for i, ref := range refs {
+			if arr[i], err = p.object(ref, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	case 0xd: // dict: n key refs, then n value refs
+		n, start, err := p.length(marker, off)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := p.refs(start, 2*n)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]any, n)
+		for i := uint64(0); i < n; i++ {
+			k, err := p.object(refs[i], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			ks, ok := k.(string)
+			if !ok {
+				return nil, errors.New("binary plist dictionary key is not a string")
+			}
+			if m[ks], err = p.object(refs[n+i], depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("unsupported binary plist object type 0x%02x", marker)
+}
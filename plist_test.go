@@ -0,0 +1,201 @@
+package resolver
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Server</key>
+	<dict>
+		<key>URL</key>
+		<string>https://example.com</string>
+		<key>Port</key>
+		<integer>8443</integer>
+	</dict>
+	<key>Ratio</key>
+	<real>0.5</real>
+	<key>Enabled</key>
+	<true/>
+	<key>Debug</key>
+	<false/>
+	<key>Updated</key>
+	<date>2024-03-01T12:00:00Z</date>
+	<key>Token</key>
+	<data>
+	aGVsbG8=
+	</data>
+	<key>Hosts</key>
+	<array>
+		<dict>
+			<key>name</key>
+			<string>api</string>
+		</dict>
+		<dict>
+			<key>name</key>
+			<string>web</string>
+		</dict>
+	</array>
+</dict>
+</plist>
+`
+
+// binaryTestPlist encodes {"Name": "agent", "Port": 8080, "Enabled": true,
+// "Tags": ["a", "b"], "Title": "Ünïcode"} as a bplist00 document.
+func binaryTestPlist() []byte {
+	ascii := func(s string) []byte { return append([]byte{0x50 | byte(len(s))}, s...) }
+	objects := [][]byte{
+		{0xd5, 1, 3, 5, 7, 11, 2, 4, 6, 8, 12}, // dict with 5 entries
+		ascii("Name"),
+		ascii("agent"),
+		ascii("Port"),
+		{0x11, 0x1f, 0x90}, // int 8080
+		ascii("Enabled"),
+		{0x09},
+		ascii("Tags"),
+		{0xa2, 9, 10}, // array of 2
+		ascii("a"),
+		ascii("b"),
+		ascii("Title"),
+		{0x67, 0, 0xdc, 0, 'n', 0, 0xef, 0, 'c', 0, 'o', 0, 'd', 0, 'e'}, // UTF-16 "Ünïcode"
+	}
+	return encodeTestBplist(objects)
+}
+
+// encodeTestBplist encodes objects (object 0 is the top) as a bplist00 document with
+// one-byte offsets and references.
+func encodeTestBplist(objects [][]byte) []byte {
+	out := []byte("bplist00")
+	var offsets []byte
+	for _, o := range objects {
+		offsets = append(offsets, byte(len(out)))
+		out = append(out, o...)
+	}
+	table := len(out)
+	out = append(out, offsets...)
+	trailer := make([]byte, 32)
+	trailer[6], trailer[7] = 1, 1
+	binary.BigEndian.PutUint64(trailer[8:], uint64(len(objects)))
+	binary.BigEndian.PutUint64(trailer[24:], uint64(table))
+	return append(out, trailer...)
+}
+
+func TestPlistResolver_Resolve(t *testing.T) {
+	r := &PlistResolver{}
+
+	t.Run("XML", func(t *testing.T) {
+		p := filepath.Join(t.TempDir(), "prefs.plist")
+		require.NoError(t, os.WriteFile(p, []byte(testPlist), 0o666))
+
+		cases := []struct {
+			name, key, want string
+		}{
+			{"Nested string", "Server.URL", "https://example.com"},
+			{"Integer", "Server.Port", "8443"},
+			{"Real", "Ratio", "0.5"},
+			{"True", "Enabled", "true"},
+			{"False", "Debug", "false"},
+			{"Date", "Updated", "2024-03-01T12:00:00Z"},
+			{"Data", "Token", "aGVsbG8="},
+			{"Array index", "Hosts.1.name", "web"},
+			{"Filter", "Hosts.[name=api].name", "api"},
+			{"Dict as JSON", "Server", `{"Port":8443,"URL":"https://example.com"}`},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				val, err := r.Resolve(p + "//" + tc.key)
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, val)
+			})
+		}
+
+		t.Run("Whole file", func(t *testing.T) {
+			val, err := r.Resolve(p)
+			require.NoError(t, err)
+			assert.Equal(t, strings.TrimSpace(testPlist), val)
+		})
+
+		t.Run("Missing key", func(t *testing.T) {
+			_, err := r.Resolve(p + "//Server.Nope")
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		p := filepath.Join(t.TempDir(), "prefs.plist")
+		require.NoError(t, os.WriteFile(p, binaryTestPlist(), 0o666))
+
+		cases := []struct {
+			name, key, want string
+		}{
+			{"ASCII string", "Name", "agent"},
+			{"Integer", "Port", "8080"},
+			{"Bool", "Enabled", "true"},
+			{"Array index", "Tags.1", "b"},
+			{"UTF-16 string", "Title", "Ünïcode"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				val, err := r.Resolve(p + "//" + tc.key)
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, val)
+			})
+		}
+
+		t.Run("Whole file as JSON", func(t *testing.T) {
+			val, err := r.Resolve(p)
+			require.NoError(t, err)
+			assert.Equal(t, `{"Enabled":true,"Name":"agent","Port":8080,"Tags":["a","b"],"Title":"Ünïcode"}`, val)
+		})
+
+		t.Run("Shared references", func(t *testing.T) {
+			// 40 levels of two-element arrays whose elements are both the next level:
+			// a couple of hundred bytes that expand to 2^40 objects.
+			var objects [][]byte
+			for i := range 40 {
+				objects = append(objects, []byte{0xa2, byte(i + 1), byte(i + 1)})
+			}
+			objects = append(objects, []byte{0x09})
+			data := encodeTestBplist(objects)
+			bomb := filepath.Join(t.TempDir(), "bomb.plist")
+			require.NoError(t, os.WriteFile(bomb, data, 0o666))
+
+			start := time.Now()
+			_, err := r.Resolve(bomb)
+			require.ErrorContains(t, err, "too many objects")
+			assert.Less(t, time.Since(start), time.Second)
+		})
+
+		t.Run("Truncated", func(t *testing.T) {
+			data := binaryTestPlist()
+			bad := filepath.Join(t.TempDir(), "bad.plist")
+			require.NoError(t, os.WriteFile(bad, data[:len(data)-40], 0o666))
+			_, err := r.Resolve(bad + "//Name")
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, ErrNotFound)
+		})
+	})
+
+	t.Run("Invalid XML plist", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.plist")
+		require.NoError(t, os.WriteFile(bad, []byte("<plist><dict><string>x</string></dict></plist>"), 0o666))
+		_, err := r.Resolve(bad + "//x")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "nope.plist") + "//Name")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,302 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containeroo/resolver"
+)
+
+// BinaryPrefix is prepended to a plugin name to find its binary.
+const BinaryPrefix = "resolver-plugin-"
+
+// DefaultTimeout bounds the handshake and each request when Options.Timeout is unset.
+const DefaultTimeout = 10 * time.Second
+
+// Options configures a host-side Resolver.
+type Options struct {
+	// Plugins maps plugin names to binaries and takes precedence over discovery.
+	Plugins map[string]string
+	// Dir is searched for "resolver-plugin-<name>" binaries; empty searches $PATH.
+	Dir string
+	// Env is added to the environment of plugin processes.
+	Env []string
+	// Stderr receives the plugins' stderr; defaults to os.Stderr.
+	Stderr io.Writer
+	// Timeout bounds the handshake and each request; defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Resolver serves the "plugin:" scheme by forwarding to plugin processes.
+// Format: "plugin:<name>:<value>", e.g. "plugin:onepassword:vault/item/field".
+// Plugins are started on first use and kept running until Close; a plugin that
+// fails or times out is restarted on the next request.
+//
+// It is not registered by default since it runs external binaries.
+type Resolver struct {
+	opts  Options
+	mu    sync.Mutex
+	procs map[string]*process
+}
+
+// NewResolver creates a host-side plugin resolver.
+func NewResolver(opts Options) *Resolver {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+	return &Resolver{opts: opts, procs: make(map[string]*process)}
+}
+
+// validName restricts plugin names so they cannot escape Dir.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+func (r *Resolver) Resolve(value string) (string, error) {
+	name, rest, ok := strings.Cut(value, ":")
+	if !ok {
+		return "", fmt.Errorf("%w: expected plugin:<name>:<value>, got %q", resolver.ErrBadPath, value)
+	}
+	resp, err := r.call(name, Request{Method: MethodResolve, Value: rest})
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		if sentinel, ok := errorCodes[resp.Code]; ok {
+			return "", fmt.Errorf("%w: plugin %q: %s", sentinel, name, resp.Error)
+		}
+		return "", fmt.Errorf("plugin %q: %s", name, resp.Error)
+	}
+	return resp.Value, nil
+}
+
+// Describe reports the resolver metadata. Plugin values are treated as secrets.
+func (r *Resolver) Describe() resolver.ResolverMeta {
+	return resolver.ResolverMeta{Sensitive: true}
+}
+
+// Health starts the named plugin if needed and checks that it answers.
+func (r *Resolver) Health(name string) error {
+	resp, err := r.call(name, Request{Method: MethodHealth})
+	if err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("plugin %q unhealthy: %s", name, resp.Error)
+	}
+	return nil
+}
+
+// Close stops all running plugins.
+func (r *Resolver) Close() error {
+	r.mu.Lock()
+	procs := r.procs
+	r.procs = make(map[string]*process)
+	r.mu.Unlock()
+
+	var errs []error
+	for _, p := range procs {
+		errs = append(errs, p.stop())
+	}
+	return errors.Join(errs...)
+}
+
+// call sends req to the named plugin, starting it if necessary.
+func (r *Resolver) call(name string, req Request) (Response, error) {
+	p, err := r.process(name)
+	if err != nil {
+		return Response{}, err
+	}
+	resp, err := p.roundTrip(req, r.opts.Timeout)
+	if err != nil {
+		r.drop(name, p)
+		return Response{}, fmt.Errorf("plugin %q: %w", name, err)
+	}
+	return resp, nil
+}
+
+// process returns the running process for name, starting it if needed.
+func (r *Resolver) process(name string) (*process, error) {
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("%w: invalid plugin name %q", resolver.ErrBadPath, name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.procs[name]; ok {
+		return p, nil
+	}
+
+	bin, err := r.binary(name)
+	if err != nil {
+		return nil, err
+	}
+	p, err := startProcess(bin, r.opts)
+	if err != nil {
+		return nil, fmt.Errorf("start plugin %q: %w", name, err)
+	}
+	r.procs[name] = p
+	return p, nil
+}
+
+// drop stops p and forgets it so the next request restarts the plugin.
+func (r *Resolver) drop(name string, p *process) {
+	r.mu.Lock()
+	if r.procs[name] == p {
+		delete(r.procs, name)
+	}
+	r.mu.Unlock()
+	_ = p.stop()
+}
+
+// binary locates the executable for plugin name.
+func (r *Resolver) binary(name string) (string, error) {
+	if bin, ok := r.opts.Plugins[name]; ok {
+		return bin, nil
+	}
+	file := BinaryPrefix + name
+	if r.opts.Dir != "" {
+		bin := filepath.Join(r.opts.Dir, file)
+		if _, err := os.Stat(bin); err != nil {
+			return "", fmt.Errorf("%w: plugin %q in %s", resolver.ErrNotFound, name, r.opts.Dir)
+		}
+		return bin, nil
+	}
+	bin, err := exec.LookPath(file)
+	if err != nil {
+		return "", fmt.Errorf("%w: plugin %q: %v", resolver.ErrNotFound, name, err)
+	}
+	return bin, nil
+}
+
+// process is a running plugin. Requests are serialized.
+type process struct {
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	lines  chan []byte   // lines read from stdout; closed on EOF
+	done   chan struct{} // closed by stop
+	once   sync.Once
+	err    error // result of stop
+	nextID uint64
+}
+
+// startProcess launches bin and completes the handshake.
+func startProcess(bin string, opts Options) (*process, error) {
+	cmd := exec.Command(bin)
+	cmd.Env = append(append(os.Environ(), opts.Env...), MagicCookieKey+"="+MagicCookieValue)
+	cmd.Stderr = opts.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &process{cmd: cmd, stdin: stdin, lines: make(chan []byte), done: make(chan struct{})}
+	go p.readLines(stdout)
+
+	line, err := p.readLine(opts.Timeout)
+	if err != nil {
+		_ = p.stop()
+		return nil, fmt.Errorf("handshake: %w", err)
+	}
+	var hs Handshake
+	if err := json.Unmarshal(line, &hs); err != nil {
+		_ = p.stop()
+		return nil, fmt.Errorf("handshake: %w", err)
+	}
+	if hs.Protocol != ProtocolVersion {
+		_ = p.stop()
+		return nil, fmt.Errorf("handshake: plugin speaks protocol %d, host speaks %d", hs.Protocol, ProtocolVersion)
+	}
+	return p, nil
+}
+
+// readLines forwards stdout lines to p.lines until EOF.
+func (p *process) readLines(stdout io.Reader) {
+	defer close(p.lines)
+	sc := bufio.NewScanner(stdout)
+	sc.Buffer(make([]byte, 64*1024), 16<<20)
+	for sc.Scan() {
+		select {
+		case p.lines <- append([]byte(nil), sc.Bytes()...):
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// readLine waits for the next stdout line.
+func (p *process) readLine(timeout time.Duration) ([]byte, error) {
+	select {
+	case line, ok := <-p.lines:
+		if !ok {
+			return nil, errors.New("plugin exited")
+		}
+		return line, nil
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("no response within %s", timeout)
+	}
+}
+
+// roundTrip sends req and waits for the matching response.
+func (p *process) roundTrip(req Request, timeout time.Duration) (Response, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextID++
+	req.ID = p.nextID
+	b, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, err
+	}
+	if _, err := p.stdin.Write(append(b, '\n')); err != nil {
+		return Response{}, fmt.Errorf("write request: %w", err)
+	}
+
+	line, err := p.readLine(timeout)
+	if err != nil {
+		return Response{}, err
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return Response{}, fmt.Errorf("decode response: %w", err)
+	}
+	if resp.ID != req.ID {
+		return Response{}, fmt.Errorf("response id %d does not match request id %d", resp.ID, req.ID)
+	}
+	return resp, nil
+}
+
+// stop closes stdin and kills the plugin. It is safe to call more than once.
+func (p *process) stop() error {
+	p.once.Do(func() {
+		close(p.done)
+		_ = p.stdin.Close()
+		_ = p.cmd.Process.Kill()
+		err := p.cmd.Wait()
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) { // an exit status is expected after Kill
+			p.err = err
+		}
+	})
+	return p.err
+}
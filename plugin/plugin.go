@@ -0,0 +1,150 @@
+// Package plugin lets external binaries serve resolver schemes without the host
+// program recompiling, in the spirit of hashicorp/go-plugin but over a small
+// line-delimited JSON protocol on the plugin's stdin/stdout.
+//
+// Host side:
+//
+//	p := plugin.NewResolver(plugin.Options{Dir: "/usr/lib/resolver/plugins"})
+//	defer p.Close()
+//	resolver.RegisterResolver("plugin:", p)
+//	v, err := resolver.ResolveVariable("plugin:onepassword:vault/item/field")
+//
+// Plugin side (a binary named "resolver-plugin-onepassword"):
+//
+//	func main() {
+//		if err := plugin.Serve(myResolver); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+// Protocol:
+//
+//  1. The host starts the plugin with MagicCookieKey=MagicCookieValue in its
+//     environment; Serve refuses to run without it.
+//
+//  2. The plugin writes a handshake line: {"protocol":1}. The host rejects
+//     plugins speaking a different ProtocolVersion.
+//
+//  3. The host sends one request per line and the plugin answers each with one
+//     response line carrying the same id:
+//
+//     {"id":1,"method":"health"}               -> {"id":1}
+//     {"id":2,"method":"resolve","value":"x"}  -> {"id":2,"value":"..."}
+//     {"id":3,"method":"resolve","value":"y"}  -> {"id":3,"error":"...","code":"not_found"}
+//
+// Error codes map to resolver.ErrNotFound, ErrForbidden, ErrBadPath and ErrTooLarge
+// on both sides, so errors.Is keeps working across the process boundary.
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/containeroo/resolver"
+)
+
+// ProtocolVersion is the protocol version spoken by this package.
+const ProtocolVersion = 1
+
+// MagicCookieKey and MagicCookieValue mark a process as started by a resolver host.
+// They are not a security measure, only a guard against running a plugin by hand.
+const (
+	MagicCookieKey   = "RESOLVER_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "b5c8a8e4-7d4a-4a8c-9a55-2f0f1c3e6d21"
+)
+
+// Methods understood by Serve.
+const (
+	MethodHealth  = "health"
+	MethodResolve = "resolve"
+)
+
+// ErrNotLaunchedByHost is returned by Serve when the magic cookie is missing.
+var ErrNotLaunchedByHost = errors.New("this binary is a resolver plugin and must be started by a resolver host")
+
+// Handshake is the first line a plugin writes.
+type Handshake struct {
+	Protocol int `json:"protocol"`
+}
+
+// Request is a host-to-plugin message.
+type Request struct {
+	ID     uint64 `json:"id"`
+	Method string `json:"method"`
+	Value  string `json:"value,omitempty"`
+}
+
+// Response is a plugin-to-host message.
+type Response struct {
+	ID    uint64 `json:"id"`
+	Value string `json:"value,omitempty"`
+	Error string `json:"error,omitempty"`
+	Code  string `json:"code,omitempty"` // see errorCode
+}
+
+// errorCodes maps wire codes to resolver sentinel errors.
+var errorCodes = map[string]error{
+	"not_found": resolver.ErrNotFound,
+	"forbidden": resolver.ErrForbidden,
+	"bad_path":  resolver.ErrBadPath,
+	"too_large": resolver.ErrTooLarge,
+}
+
+// errorCode returns the wire code for err, or "" if it wraps no known sentinel.
+func errorCode(err error) string {
+	for code, sentinel := range errorCodes {
+		if errors.Is(err, sentinel) {
+			return code
+		}
+	}
+	return ""
+}
+
+// Serve answers host requests on stdin/stdout with r until stdin is closed.
+// It returns ErrNotLaunchedByHost when the process was not started by a host.
+func Serve(r resolver.Resolver) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return ErrNotLaunchedByHost
+	}
+	return serve(r, os.Stdin, os.Stdout)
+}
+
+// serve runs the protocol over in/out.
+func serve(r resolver.Resolver, in io.Reader, out io.Writer) error {
+	enc := json.NewEncoder(out)
+	if err := enc.Encode(Handshake{Protocol: ProtocolVersion}); err != nil {
+		return fmt.Errorf("write handshake: %w", err)
+	}
+
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 64*1024), 16<<20)
+	for sc.Scan() {
+		var req Request
+		if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+			return fmt.Errorf("decode request: %w", err)
+		}
+
+		resp := Response{ID: req.ID}
+		switch req.Method {
+		case MethodHealth:
+		case MethodResolve:
+			v, err := r.Resolve(req.Value)
+			if err != nil {
+				resp.Error, resp.Code = err.Error(), errorCode(err)
+			} else {
+				resp.Value = v
+			}
+		default:
+			resp.Error = fmt.Sprintf("unknown method %q", req.Method)
+		}
+
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("write response: %w", err)
+		}
+	}
+	return sc.Err()
+}
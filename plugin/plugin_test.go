@@ -0,0 +1,172 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containeroo/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// helperEnv makes the test binary act as a plugin (see TestMain).
+const helperEnv = "RESOLVER_PLUGIN_TEST_HELPER"
+
+// helperResolver is served by the test binary when started as a plugin.
+var helperResolver = resolver.ResolverFunc(func(v string) (string, error) {
+	switch {
+	case v == "missing":
+		return "", fmt.Errorf("%w: %s", resolver.ErrNotFound, v)
+	case v == "crash":
+		os.Exit(3)
+	case v == "hang":
+		time.Sleep(time.Minute)
+	case strings.HasPrefix(v, "fail"):
+		return "", fmt.Errorf("boom")
+	}
+	return "plugin(" + v + ")", nil
+})
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(helperEnv) {
+	case "serve":
+		if err := Serve(helperResolver); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "bad-protocol":
+		fmt.Println(`{"protocol":99}`)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// newHelper returns a host resolver whose "helper" plugin is this test binary.
+func newHelper(t *testing.T, mode string) *Resolver {
+	t.Helper()
+	r := NewResolver(Options{
+		Plugins: map[string]string{"helper": os.Args[0]},
+		Env:     []string{helperEnv + "=" + mode},
+		Timeout: 2 * time.Second,
+	})
+	t.Cleanup(func() { _ = r.Close() })
+	return r
+}
+
+func TestResolver(t *testing.T) {
+	r := newHelper(t, "serve")
+
+	t.Run("Resolve", func(t *testing.T) {
+		val, err := r.Resolve("helper:some/value:with:colons")
+		require.NoError(t, err)
+		assert.Equal(t, "plugin(some/value:with:colons)", val)
+	})
+
+	t.Run("Health", func(t *testing.T) {
+		require.NoError(t, r.Health("helper"))
+	})
+
+	t.Run("Sentinel errors cross the boundary", func(t *testing.T) {
+		_, err := r.Resolve("helper:missing")
+		require.ErrorIs(t, err, resolver.ErrNotFound)
+	})
+
+	t.Run("Plain errors", func(t *testing.T) {
+		_, err := r.Resolve("helper:fail")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+	})
+
+	t.Run("Crashed plugin is restarted", func(t *testing.T) {
+		_, err := r.Resolve("helper:crash")
+		require.Error(t, err)
+
+		val, err := r.Resolve("helper:again")
+		require.NoError(t, err)
+		assert.Equal(t, "plugin(again)", val)
+	})
+
+	t.Run("Unknown plugin", func(t *testing.T) {
+		r := NewResolver(Options{Dir: t.TempDir()})
+		_, err := r.Resolve("nope:x")
+		require.ErrorIs(t, err, resolver.ErrNotFound)
+	})
+
+	t.Run("Invalid name", func(t *testing.T) {
+		_, err := r.Resolve("../evil:x")
+		require.ErrorIs(t, err, resolver.ErrBadPath)
+	})
+
+	t.Run("Missing value separator", func(t *testing.T) {
+		_, err := r.Resolve("helper")
+		require.ErrorIs(t, err, resolver.ErrBadPath)
+	})
+
+	t.Run("Registered scheme", func(t *testing.T) {
+		reg := resolver.NewRegistry()
+		reg.Register("plugin:", r)
+		val, err := reg.ResolveString("v=${plugin:helper:x}")
+		require.NoError(t, err)
+		assert.Equal(t, "v=plugin(x)", val)
+	})
+}
+
+func TestResolver_Timeout(t *testing.T) {
+	r := NewResolver(Options{
+		Plugins: map[string]string{"helper": os.Args[0]},
+		Env:     []string{helperEnv + "=serve"},
+		Timeout: 200 * time.Millisecond,
+	})
+	defer func() { _ = r.Close() }()
+
+	_, err := r.Resolve("helper:hang")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no response")
+}
+
+func TestResolver_ProtocolMismatch(t *testing.T) {
+	r := newHelper(t, "bad-protocol")
+	err := r.Health("helper")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "protocol 99")
+}
+
+func TestServe(t *testing.T) {
+	t.Run("Requires magic cookie", func(t *testing.T) {
+		t.Setenv(MagicCookieKey, "")
+		require.ErrorIs(t, Serve(helperResolver), ErrNotLaunchedByHost)
+	})
+
+	t.Run("Protocol", func(t *testing.T) {
+		in := strings.NewReader(`{"id":1,"method":"health"}
+{"id":2,"method":"resolve","value":"x"}
+{"id":3,"method":"resolve","value":"missing"}
+{"id":4,"method":"bogus"}
+`)
+		var out bytes.Buffer
+		require.NoError(t, serve(helperResolver, in, &out))
+
+		dec := json.NewDecoder(&out)
+		var hs Handshake
+		require.NoError(t, dec.Decode(&hs))
+		assert.Equal(t, ProtocolVersion, hs.Protocol)
+
+		var got []Response
+		for dec.More() {
+			var resp Response
+			require.NoError(t, dec.Decode(&resp))
+			got = append(got, resp)
+		}
+		require.Len(t, got, 4)
+		assert.Equal(t, Response{ID: 1}, got[0])
+		assert.Equal(t, Response{ID: 2, Value: "plugin(x)"}, got[1])
+		assert.Equal(t, "not_found", got[2].Code)
+		assert.Equal(t, `unknown method "bogus"`, got[3].Error)
+	})
+}
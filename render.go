@@ -0,0 +1,360 @@
+package resolver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v3"
+)
+
+// RenderOptions configures ResolveFile and RenderDir.
+type RenderOptions struct {
+	// Provenance emits a comment above every line that contained ${...} tokens,
+	// naming the tokens and the render time, e.g.
+	//
+	//	# resolved from vault:secret/app//token at 2024-05-01T10:00Z
+	//
+	// With Provenance, tokens must not span lines.
+	Provenance bool
+
+	// ProvenanceFormat overrides the comment format per file extension (".yaml", ".ini", ...).
+	// The "" entry applies to extensions without a built-in formatter. A formatter
+	// returning "" omits the comment. See DefaultProvenanceFormat for the built-ins.
+	ProvenanceFormat map[string]ProvenanceFormatter
+
+	// HashHeader embeds "# resolver-hash: sha256:<hex>" as the first line (after a "#!"
+	// line) of formats with '#' comments. The hash covers the output without provenance
+	// comments, so RenderDir can skip files whose content did not change.
+	HashHeader bool
+
+	// Validate parses rendered .json, .yaml/.yml, .toml and .ini files and fails with
+	// ErrInvalidOutput if expansion broke their syntax (e.g. a secret containing quotes).
+	Validate bool
+
+	// DryRun makes RenderDir report what would change without writing anything.
+	DryRun bool
+
+	// Now returns the render time; defaults to the registry's clock.
+	Now func() time.Time
+}
+
+// Provenance describes the substitutions made on one line of a rendered file.
+type Provenance struct {
+	Path   string    // file being rendered
+	Line   int       // 1-based line number in the source
+	Tokens []string  // token contents in order of appearance, without "${" and "}"
+	At     time.Time // render time
+}
+
+// String returns the comment text without comment markers.
+func (p Provenance) String() string {
+	return fmt.Sprintf("resolved from %s at %s", strings.Join(p.Tokens, ", "), p.At.UTC().Format("2006-01-02T15:04Z07:00"))
+}
+
+// ProvenanceFormatter renders a provenance comment line (without indentation or newline).
+type ProvenanceFormatter func(p Provenance) string
+
+// HashComment formats provenance as a "# ..." comment.
+func HashComment(p Provenance) string { return "# " + p.String() }
+
+// XMLComment formats provenance as a "<!-- ... -->" comment.
+func XMLComment(p Provenance) string {
+	// "--" is not allowed inside XML comments.
+	return "<!-- " + strings.ReplaceAll(p.String(), "--", "- -") + " -->"
+}
+
+// DefaultProvenanceFormat returns the built-in formatter for a file extension, or nil
+// for formats without comments (such as .json) and unknown extensions.
+func DefaultProvenanceFormat(ext string) ProvenanceFormatter {
+	if isHashCommentFormat(ext) {
+		return HashComment
+	}
+	switch strings.ToLower(ext) {
+	case ".xml", ".html", ".htm":
+		return XMLComment
+	}
+	return nil
+}
+
+// HashHeaderPrefix starts the content hash header written by RenderOptions.HashHeader.
+const HashHeaderPrefix = "# resolver-hash: sha256:"
+
+// RenderReport lists the files RenderDir wrote (or would write, with DryRun) and the
+// files it skipped because their content hash was unchanged. Paths are relative to
+// the source directory and use forward slashes.
+type RenderReport struct {
+	Changed   []string
+	Unchanged []string
+}
+
+// ResolveFile reads path and returns its content with all ${...} tokens resolved.
+func (r *Registry) ResolveFile(path string, opts RenderOptions) (string, error) {
+	out, _, err := r.renderFile(context.Background(), path, opts)
+	return out, err
+}
+
+// RenderDir renders every regular file below src into the same relative path below dst,
+// creating directories as needed and keeping file modes.
+//
+// A target is left untouched when its content matches the rendered output, or, with
+// HashHeader, when its embedded hash matches. With DryRun nothing is written; the
+// report then lists the files that would change, which makes RenderDir usable for
+// drift detection.
+func (r *Registry) RenderDir(src, dst string, opts RenderOptions) (*RenderReport, error) {
+	report := &RenderReport{}
+	ctx := r.ensureResolutionID(context.Background())
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if opts.DryRun {
+				return nil
+			}
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		out, sum, err := r.renderFile(ctx, path, opts)
+		if err != nil {
+			return fmt.Errorf("render %q: %w", rel, err)
+		}
+
+		if unchanged(target, out, sum) {
+			report.Unchanged = append(report.Unchanged, filepath.ToSlash(rel))
+			return nil
+		}
+		report.Changed = append(report.Changed, filepath.ToSlash(rel))
+		if opts.DryRun {
+			return nil
+		}
+		return os.WriteFile(target, []byte(out), info.Mode().Perm())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// unchanged reports whether target already holds the rendered output, comparing the
+// embedded hash header when both sides have one and the full content otherwise.
+func unchanged(target, out, sum string) bool {
+	existing, err := os.ReadFile(target)
+	if err != nil {
+		return false
+	}
+	if sum != "" {
+		if old, ok := readHashHeader(string(existing)); ok {
+			return old == sum
+		}
+	}
+	return string(existing) == out
+}
+
+// renderFile reads and renders path. sum is the content hash embedded in the header,
+// or "" when no header was written.
+func (r *Registry) renderFile(ctx context.Context, path string, opts RenderOptions) (out, sum string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", "", fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", "", fmt.Errorf("%w: %s", ErrForbidden, path)
+		}
+		return "", "", fmt.Errorf("failed to read template %q: %w", path, err)
+	}
+
+	out, stable, err := r.render(ctx, path, string(data), opts)
+	if err != nil {
+		return "", "", err
+	}
+	if opts.Validate {
+		if err := validateOutput(filepath.Ext(path), out); err != nil {
+			return "", "", fmt.Errorf("%w: %s: %v", ErrInvalidOutput, path, err)
+		}
+	}
+	if !opts.HashHeader || !isHashCommentFormat(filepath.Ext(path)) {
+		return out, "", nil
+	}
+	digest := sha256.Sum256([]byte(stable))
+	sum = hex.EncodeToString(digest[:])
+	return addHashHeader(out, sum), sum, nil
+}
+
+// render resolves the tokens in content, adding provenance comments if requested.
+// stable is the output without provenance comments; it is what the hash header covers,
+// so a re-render at a different time hashes the same.
+func (r *Registry) render(ctx context.Context, path, content string, opts RenderOptions) (out, stable string, err error) {
+	if !opts.Provenance {
+		out, err = r.ResolveStringContext(ctx, content)
+		return out, out, err
+	}
+
+	format := opts.provenanceFormatter(filepath.Ext(path))
+	now := r.effectiveClock().Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	at := now()
+	ctx = r.ensureResolutionID(ctx)
+
+	var b, plain strings.Builder
+	for i, line := range strings.SplitAfter(content, "\n") {
+		tokens, err := lineTokens(line)
+		if err != nil {
+			return "", "", fmt.Errorf("line %d: %w", i+1, err)
+		}
+		resolved, err := r.ResolveStringContext(ctx, line)
+		if err != nil {
+			return "", "", fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if len(tokens) > 0 && format != nil {
+			if c := format(Provenance{Path: path, Line: i + 1, Tokens: tokens, At: at}); c != "" {
+				b.WriteString(leadingSpace(line))
+				b.WriteString(c)
+				b.WriteString(lineEnding(line))
+			}
+		}
+		b.WriteString(resolved)
+		plain.WriteString(resolved)
+	}
+	return b.String(), plain.String(), nil
+}
+
+// validateOutput parses out according to ext; unknown extensions are not checked.
+func validateOutput(ext, out string) error {
+	switch strings.ToLower(ext) {
+	case ".json":
+		var v any
+		return json.Unmarshal([]byte(out), &v)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(strings.NewReader(out))
+		for {
+			var v any
+			if err := dec.Decode(&v); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+		}
+	case ".toml":
+		var v map[string]any
+		return toml.Unmarshal([]byte(out), &v)
+	case ".ini":
+		_, err := ini.Load([]byte(out))
+		return err
+	}
+	return nil
+}
+
+// isHashCommentFormat reports whether files with ext accept a leading "# ..." line.
+func isHashCommentFormat(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml", ".toml", ".ini", ".env", ".conf", ".cfg", ".properties", ".sh", ".hcl", ".tf":
+		return true
+	}
+	return false
+}
+
+// addHashHeader inserts the hash header as the first line, or the second after a "#!" line.
+func addHashHeader(out, sum string) string {
+	header := HashHeaderPrefix + sum + "\n"
+	if strings.HasPrefix(out, "#!") {
+		if nl := strings.IndexByte(out, '\n'); nl >= 0 {
+			return out[:nl+1] + header + out[nl+1:]
+		}
+		return out + "\n" + header
+	}
+	return header + out
+}
+
+// readHashHeader extracts the hash from a header written by addHashHeader.
+func readHashHeader(content string) (string, bool) {
+	for range 2 {
+		line, rest, _ := strings.Cut(content, "\n")
+		if sum, ok := strings.CutPrefix(strings.TrimSuffix(line, "\r"), HashHeaderPrefix); ok {
+			return sum, true
+		}
+		if !strings.HasPrefix(line, "#!") {
+			break
+		}
+		content = rest
+	}
+	return "", false
+}
+
+// provenanceFormatter picks the formatter for ext: an override, the built-in, or the "" fallback.
+func (o RenderOptions) provenanceFormatter(ext string) ProvenanceFormatter {
+	if f, ok := o.ProvenanceFormat[ext]; ok {
+		return f
+	}
+	if f := DefaultProvenanceFormat(ext); f != nil {
+		return f
+	}
+	return o.ProvenanceFormat[""]
+}
+
+// lineTokens returns the contents of the unescaped ${...} tokens in line.
+func lineTokens(line string) ([]string, error) {
+	var tokens []string
+	for p := 0; p < len(line); {
+		dollarRel := strings.IndexByte(line[p:], '$')
+		if dollarRel < 0 {
+			break
+		}
+		dollar := p + dollarRel
+		switch {
+		case isEscapedDollarBrace(line, p, dollar):
+			p = dollar + 2
+		case !isTokenStart(line, dollar):
+			p = dollar + 1
+		default:
+			start, end, err := tokenBounds(line, dollar)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, line[start:end])
+			p = end + 1
+		}
+	}
+	return tokens, nil
+}
+
+// leadingSpace returns the indentation of line.
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// lineEnding returns the line terminator to use after a comment inserted before line.
+func lineEnding(line string) string {
+	if strings.HasSuffix(line, "\r\n") {
+		return "\r\n"
+	}
+	return "\n"
+}
@@ -0,0 +1,262 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRenderRegistry() *Registry {
+	r := NewRegistry()
+	r.Register("x:", ResolverFunc(func(v string) (string, error) { return "X(" + v + ")", nil }))
+	return r
+}
+
+func writeTemplate(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o640))
+	return p
+}
+
+func TestRegistry_ResolveFile(t *testing.T) {
+	r := newRenderRegistry()
+	at := func() time.Time { return time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC) }
+
+	t.Run("Plain", func(t *testing.T) {
+		p := writeTemplate(t, t.TempDir(), "app.yaml", "a: ${x:one}\nb: plain\n")
+		out, err := r.ResolveFile(p, RenderOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "a: X(one)\nb: plain\n", out)
+	})
+
+	t.Run("Provenance in YAML keeps indentation", func(t *testing.T) {
+		p := writeTemplate(t, t.TempDir(), "app.yaml", "db:\n  user: ${x:u}\n  pass: ${x:p}-${x:q}\n  port: 5432\n")
+		out, err := r.ResolveFile(p, RenderOptions{Provenance: true, Now: at})
+		require.NoError(t, err)
+		assert.Equal(t, "db:\n"+
+			"  # resolved from x:u at 2024-05-01T10:00Z\n"+
+			"  user: X(u)\n"+
+			"  # resolved from x:p, x:q at 2024-05-01T10:00Z\n"+
+			"  pass: X(p)-X(q)\n"+
+			"  port: 5432\n", out)
+	})
+
+	t.Run("No comments for JSON", func(t *testing.T) {
+		p := writeTemplate(t, t.TempDir(), "app.json", `{"a": "${x:one}"}`)
+		out, err := r.ResolveFile(p, RenderOptions{Provenance: true, Now: at})
+		require.NoError(t, err)
+		assert.Equal(t, `{"a": "X(one)"}`, out)
+	})
+
+	t.Run("Escaped tokens are not reported", func(t *testing.T) {
+		p := writeTemplate(t, t.TempDir(), "app.env", "A=\\${x:lit}\r\nB=${x:b}\r\n")
+		out, err := r.ResolveFile(p, RenderOptions{Provenance: true, Now: at})
+		require.NoError(t, err)
+		assert.Equal(t, "A=${x:lit}\r\n# resolved from x:b at 2024-05-01T10:00Z\r\nB=X(b)\r\n", out)
+	})
+
+	t.Run("Format hook per extension", func(t *testing.T) {
+		p := writeTemplate(t, t.TempDir(), "app.lua", "k = '${x:k}'\n")
+		out, err := r.ResolveFile(p, RenderOptions{
+			Provenance: true,
+			Now:        at,
+			ProvenanceFormat: map[string]ProvenanceFormatter{
+				".lua": func(p Provenance) string { return "-- " + p.String() },
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "-- resolved from x:k at 2024-05-01T10:00Z\nk = 'X(k)'\n", out)
+	})
+
+	t.Run("Fallback formatter and opt-out", func(t *testing.T) {
+		p := writeTemplate(t, t.TempDir(), "app.custom", "k=${x:k}\n")
+		out, err := r.ResolveFile(p, RenderOptions{
+			Provenance:       true,
+			Now:              at,
+			ProvenanceFormat: map[string]ProvenanceFormatter{"": HashComment},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "# resolved from x:k at 2024-05-01T10:00Z\nk=X(k)\n", out)
+
+		p = writeTemplate(t, t.TempDir(), "app.yaml", "k: ${x:k}\n")
+		out, err = r.ResolveFile(p, RenderOptions{
+			Provenance:       true,
+			ProvenanceFormat: map[string]ProvenanceFormatter{".yaml": func(Provenance) string { return "" }},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "k: X(k)\n", out)
+	})
+
+	t.Run("Error names the line", func(t *testing.T) {
+		p := writeTemplate(t, t.TempDir(), "app.yaml", "a: 1\nb: ${x:open\n}\n")
+		_, err := r.ResolveFile(p, RenderOptions{Provenance: true})
+		require.ErrorIs(t, err, ErrBadPath)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.ResolveFile(filepath.Join(t.TempDir(), "nope.yaml"), RenderOptions{})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestRegistry_RenderDir(t *testing.T) {
+	r := newRenderRegistry()
+	src, dst := t.TempDir(), filepath.Join(t.TempDir(), "out")
+	writeTemplate(t, src, "app.yaml", "a: ${x:a}\n")
+	writeTemplate(t, src, "nested/db.ini", "[db]\nuser=${x:u}\n")
+
+	report, err := r.RenderDir(src, dst, RenderOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app.yaml", "nested/db.ini"}, report.Changed)
+
+	got, err := os.ReadFile(filepath.Join(dst, "app.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "a: X(a)\n", string(got))
+
+	got, err = os.ReadFile(filepath.Join(dst, "nested", "db.ini"))
+	require.NoError(t, err)
+	assert.Equal(t, "[db]\nuser=X(u)\n", string(got))
+
+	info, err := os.Stat(filepath.Join(dst, "nested", "db.ini"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+
+	t.Run("Failing file aborts with its path", func(t *testing.T) {
+		writeTemplate(t, src, "bad.yaml", "a: ${}\n")
+		_, err := r.RenderDir(src, dst, RenderOptions{})
+		require.ErrorIs(t, err, ErrBadPath)
+		assert.Contains(t, err.Error(), "bad.yaml")
+	})
+}
+
+func TestRegistry_RenderDir_HashHeader(t *testing.T) {
+	values := map[string]string{"a": "1", "b": "2"}
+	r := NewRegistry()
+	r.Register("v:", ResolverFunc(func(k string) (string, error) { return values[k], nil }))
+
+	src, dst := t.TempDir(), t.TempDir()
+	writeTemplate(t, src, "app.yaml", "a: ${v:a}\n")
+	writeTemplate(t, src, "run.sh", "#!/bin/sh\necho ${v:b}\n")
+	writeTemplate(t, src, "app.json", `{"a": "${v:a}"}`)
+
+	clock := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	opts := RenderOptions{HashHeader: true, Provenance: true, Now: func() time.Time { return clock }}
+
+	report, err := r.RenderDir(src, dst, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app.json", "app.yaml", "run.sh"}, report.Changed)
+
+	got, err := os.ReadFile(filepath.Join(dst, "run.sh"))
+	require.NoError(t, err)
+	assert.Regexp(t, `^#!/bin/sh\n# resolver-hash: sha256:[0-9a-f]{64}\n# resolved from v:b at 2024-05-01T10:00Z\necho 2\n$`, string(got))
+
+	got, err = os.ReadFile(filepath.Join(dst, "app.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a": "1"}`, string(got), "formats without '#' comments get no header")
+
+	t.Run("Unchanged files are skipped despite new provenance times", func(t *testing.T) {
+		clock = clock.Add(time.Hour)
+		report, err := r.RenderDir(src, dst, opts)
+		require.NoError(t, err)
+		assert.Empty(t, report.Changed)
+		assert.Equal(t, []string{"app.json", "app.yaml", "run.sh"}, report.Unchanged)
+
+		got, err := os.ReadFile(filepath.Join(dst, "run.sh"))
+		require.NoError(t, err)
+		assert.Contains(t, string(got), "at 2024-05-01T10:00Z", "skipped file is not rewritten")
+	})
+
+	t.Run("Dry run reports changes without writing", func(t *testing.T) {
+		values["a"] = "changed"
+		dryOpts := opts
+		dryOpts.DryRun = true
+
+		report, err := r.RenderDir(src, dst, dryOpts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app.json", "app.yaml"}, report.Changed)
+		assert.Equal(t, []string{"run.sh"}, report.Unchanged)
+
+		got, err := os.ReadFile(filepath.Join(dst, "app.yaml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(got), "a: 1")
+	})
+
+	t.Run("Dry run into missing directory", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "missing")
+		report, err := r.RenderDir(src, out, RenderOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Len(t, report.Changed, 3)
+		assert.NoDirExists(t, out)
+	})
+}
+
+func TestHashHeader(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		sum, ok := readHashHeader(addHashHeader("a: 1\n", "abc"))
+		require.True(t, ok)
+		assert.Equal(t, "abc", sum)
+	})
+
+	t.Run("After shebang", func(t *testing.T) {
+		out := addHashHeader("#!/bin/sh\r\necho\n", "abc")
+		assert.True(t, strings.HasPrefix(out, "#!/bin/sh\r\n"+HashHeaderPrefix+"abc\n"))
+		sum, ok := readHashHeader(out)
+		require.True(t, ok)
+		assert.Equal(t, "abc", sum)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, ok := readHashHeader("a: 1\n# resolver-hash: sha256:abc\n")
+		assert.False(t, ok)
+	})
+}
+
+func TestRegistry_ResolveFile_Validate(t *testing.T) {
+	r := NewRegistry()
+	r.Register("v:", ResolverFunc(func(v string) (string, error) { return v, nil }))
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		valid   bool
+	}{
+		{"JSON valid", "app.json", `{"pw": "${v:abc}"}`, true},
+		{"JSON broken by quote", "app.json", `{"pw": "${v:a"b}"}`, false},
+		{"YAML valid", "app.yaml", "pw: ${v:abc}\n---\nother: 1\n", true},
+		{"YAML broken by newline and indent", "app.yml", "a:\n  pw: ${v:x\n y: [}\n", false},
+		{"TOML valid", "app.toml", "pw = \"${v:abc}\"\n", true},
+		{"TOML broken by quote", "app.toml", "pw = \"${v:a\"b}\"\n", false},
+		{"INI broken section", "app.ini", "[${v:db}\nk=v\n", false},
+		{"Unknown extension unchecked", "app.txt", `{"pw": "${v:a"b}"}`, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := writeTemplate(t, t.TempDir(), tc.file, tc.content)
+
+			_, err := r.ResolveFile(p, RenderOptions{Validate: true})
+			if tc.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidOutput)
+
+			_, err = r.ResolveFile(p, RenderOptions{})
+			require.NoError(t, err, "validation is opt-in")
+		})
+	}
+
+	t.Run("Hash header keeps YAML valid", func(t *testing.T) {
+		p := writeTemplate(t, t.TempDir(), "app.yaml", "pw: ${v:abc}\n")
+		_, err := r.ResolveFile(p, RenderOptions{Validate: true, HashHeader: true, Provenance: true})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,87 @@
+package resolver
+
+import "context"
+
+// Request is a structured resolution request, the input of RequestResolver.
+type Request struct {
+	Scheme string // matched scheme including the trailing ':', e.g. "vault:"
+	Value  string // token with the scheme stripped
+}
+
+// Token returns the full token, scheme included.
+func (q Request) Token() string { return q.Scheme + q.Value }
+
+// Response is the result of a RequestResolver.
+type Response struct {
+	Value     string
+	Sensitive bool // Value is a secret; it is remembered for Mask
+}
+
+// RequestResolver is a structured alternative to Resolver: it receives the context
+// alongside the Request and reports per call whether the value is sensitive. Register
+// implementations with RegisterRequestResolver; the registry prefers ResolveRequest
+// over ResolveContext and Resolve when a registered resolver implements several of them.
+type RequestResolver interface {
+	ResolveRequest(ctx context.Context, req Request) (Response, error)
+}
+
+// RequestResolverFunc adapts a plain function to the RequestResolver interface.
+type RequestResolverFunc func(ctx context.Context, req Request) (Response, error)
+
+// ResolveRequest implements RequestResolver by invoking the function.
+func (f RequestResolverFunc) ResolveRequest(ctx context.Context, req Request) (Response, error) {
+	return f(ctx, req)
+}
+
+// AdaptResolver wraps a Resolver as a RequestResolver. Contexts reach resolvers that
+// implement ContextResolver, and Response.Sensitive follows the resolver's metadata.
+func AdaptResolver(res Resolver) RequestResolver {
+	if rr, ok := res.(RequestResolver); ok {
+		return rr
+	}
+	return &resolverAdapter{res: res}
+}
+
+// resolverAdapter is a v1 Resolver seen through the RequestResolver interface.
+type resolverAdapter struct {
+	res Resolver
+}
+
+func (a *resolverAdapter) ResolveRequest(ctx context.Context, req Request) (Response, error) {
+	v, err := resolveWith(ctx, a.res, req.Value)
+	if err != nil {
+		return Response{}, err
+	}
+	return Response{Value: v, Sensitive: isSensitive(a.res)}, nil
+}
+
+// RegisterRequestResolver adds or replaces a RequestResolver for scheme, like Register.
+// meta is reported by SchemeInfos and drives placeholder mode and masking.
+func (r *Registry) RegisterRequestResolver(scheme string, rr RequestResolver, meta ResolverMeta) {
+	r.Register(scheme, &requestAdapter{rr: rr, scheme: scheme, meta: meta})
+}
+
+// requestAdapter lets a RequestResolver be registered and called as a v1 Resolver.
+type requestAdapter struct {
+	rr     RequestResolver
+	scheme string
+	meta   ResolverMeta
+}
+
+func (a *requestAdapter) Resolve(value string) (string, error) {
+	return a.ResolveContext(context.Background(), value)
+}
+
+func (a *requestAdapter) ResolveContext(ctx context.Context, value string) (string, error) {
+	resp, err := a.rr.ResolveRequest(ctx, Request{Scheme: a.scheme, Value: value})
+	return resp.Value, err
+}
+
+func (a *requestAdapter) ResolveRequest(ctx context.Context, req Request) (Response, error) {
+	resp, err := a.rr.ResolveRequest(ctx, req)
+	resp.Sensitive = resp.Sensitive || a.meta.Sensitive
+	return resp, err
+}
+
+// Describe reports the metadata given to RegisterRequestResolver.
+func (a *requestAdapter) Describe() ResolverMeta { return a.meta }
@@ -0,0 +1,77 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_RequestResolver(t *testing.T) {
+	t.Run("Receives structured request", func(t *testing.T) {
+		r := NewRegistry()
+		var got Request
+		r.RegisterRequestResolver("kv:", RequestResolverFunc(func(_ context.Context, req Request) (Response, error) {
+			got = req
+			return Response{Value: "v-" + req.Value}, nil
+		}), ResolverMeta{})
+
+		val, err := r.ResolveString("x=${kv:app/db}")
+		require.NoError(t, err)
+		assert.Equal(t, "x=v-app/db", val)
+		assert.Equal(t, Request{Scheme: "kv:", Value: "app/db"}, got)
+		assert.Equal(t, "kv:app/db", got.Token())
+	})
+
+	t.Run("Sensitive response is masked", func(t *testing.T) {
+		r := NewRegistry()
+		r.SetMasking(true)
+		r.RegisterRequestResolver("kv:", RequestResolverFunc(func(_ context.Context, req Request) (Response, error) {
+			return Response{Value: "s3cr3t-" + req.Value, Sensitive: req.Value == "pw"}, nil
+		}), ResolverMeta{})
+
+		for _, tok := range []string{"kv:pw", "kv:user"} {
+			_, err := r.ResolveVariable(tok)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, Masked+" s3cr3t-user", r.Mask("s3cr3t-pw s3cr3t-user"))
+	})
+
+	t.Run("Metadata drives placeholders", func(t *testing.T) {
+		r := NewRegistry()
+		r.SetPlaceholderMode(true)
+		r.RegisterRequestResolver("kv:", RequestResolverFunc(func(context.Context, Request) (Response, error) {
+			return Response{}, errors.New("must not be called")
+		}), ResolverMeta{Capabilities: []Capability{CapabilityRemote}})
+
+		val, err := r.ResolveVariable("kv:a")
+		require.NoError(t, err)
+		assert.Equal(t, "<kv:a>", val)
+		assert.Equal(t, "*resolver.requestAdapter", r.SchemeInfos()[0].Type)
+	})
+
+	t.Run("ResolveRequest reports sensitivity of v1 resolvers", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register("secret:", &metaStub{meta: ResolverMeta{Sensitive: true}})
+
+		resp, err := r.ResolveRequest(context.Background(), "secret:x")
+		require.NoError(t, err)
+		assert.Equal(t, Response{Value: "real-x", Sensitive: true}, resp)
+
+		resp, err = r.ResolveRequest(context.Background(), "plain")
+		require.NoError(t, err)
+		assert.Equal(t, Response{Value: "plain"}, resp)
+	})
+
+	t.Run("AdaptResolver forwards context", func(t *testing.T) {
+		stub := &ctxStub{}
+		ctx := WithResolutionID(context.Background(), "req-1")
+
+		resp, err := AdaptResolver(stub).ResolveRequest(ctx, Request{Scheme: "c:", Value: "a"})
+		require.NoError(t, err)
+		assert.Equal(t, "a", resp.Value)
+		assert.Equal(t, []string{"req-1"}, stub.ids)
+	})
+}
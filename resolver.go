@@ -1,14 +1,23 @@
 package resolver
 
+import (
+	"context"
+	"sync/atomic"
+)
+
 // Package-level default registry and convenience functions.
 // This preserves the original simple API while allowing advanced users
 // to construct custom registries with NewRegistry/NewDefaultRegistry.
-var defaultRegistry = NewDefaultRegistry()
+var defaultRegistry atomic.Pointer[Registry]
+
+func init() {
+	defaultRegistry.Store(NewDefaultRegistry())
+}
 
 // RegisterResolver adds or replaces a resolver in the default registry.
 // scheme must include a trailing colon, e.g. "json:".
 func RegisterResolver(scheme string, r Resolver) {
-	defaultRegistry.Register(scheme, r)
+	DefaultRegistry().Register(scheme, r)
 }
 
 // ResolveVariable attempts to resolve a variable string using a registered resolver
@@ -22,27 +31,87 @@ func RegisterResolver(scheme string, r Resolver) {
 //	ResolveVariable("yaml:${CONFIG}//servers.[name=app].addr")
 //	ResolveVariable("file:/etc/app.conf//USERNAME")
 func ResolveVariable(value string) (string, error) {
-	return defaultRegistry.ResolveVariable(value)
+	return DefaultRegistry().ResolveVariable(value)
 }
 
 // ResolveSlice resolves each string in values using the default registry.
 // It returns a new slice; the input is not modified. If any element fails
 // to resolve, the function returns that error (strict mode).
-func ResolveSlice(values []string) ([]string, error) {
-	return defaultRegistry.ResolveSlice(values)
+func ResolveSlice(values []string, opts ...BatchOption) ([]string, error) {
+	return DefaultRegistry().ResolveSlice(values, opts...)
+}
+
+// ResolveMap resolves each value of values using the default registry (strict). Keys
+// are kept as they are.
+func ResolveMap(values map[string]string, opts ...BatchOption) (map[string]string, error) {
+	return DefaultRegistry().ResolveMap(values, opts...)
 }
 
 // ResolveSliceBestEffort resolves all values and returns the results plus a list of per-index errors.
 // The output slice always has len(values). Callers can decide what to do with errors.
-func ResolveSliceBestEffort(values []string) ([]string, []error) {
-	return defaultRegistry.ResolveSliceBestEffort(values)
+func ResolveSliceBestEffort(values []string, opts ...BatchOption) ([]string, []error) {
+	return DefaultRegistry().ResolveSliceBestEffort(values, opts...)
 }
 
 // ResolveString replaces ${...} tokens in s using the default registry.
-func ResolveString(s string) (string, error) { return defaultRegistry.ResolveString(s) }
+func ResolveString(s string) (string, error) { return DefaultRegistry().ResolveString(s) }
+
+// ResolveVariableContext resolves value with the default registry, passing ctx to
+// context-aware resolvers and hooks.
+func ResolveVariableContext(ctx context.Context, value string) (string, error) {
+	return DefaultRegistry().ResolveVariableContext(ctx, value)
+}
+
+// ResolveStringContext replaces ${...} tokens in s using the default registry; all
+// tokens share one resolution ID.
+func ResolveStringContext(ctx context.Context, s string) (string, error) {
+	return DefaultRegistry().ResolveStringContext(ctx, s)
+}
 
-// DefaultRegistry returns the global default registry.
+// ResolveLayered looks up key in each layer of the default registry in priority order
+// and returns the first hit (see Registry.ResolveLayered).
+func ResolveLayered(key string, layers []string) (LayeredResult, error) {
+	return DefaultRegistry().ResolveLayered(key, layers)
+}
+
+// ResolveFile reads path and resolves its ${...} tokens using the default registry.
+func ResolveFile(path string, opts RenderOptions) (string, error) {
+	return DefaultRegistry().ResolveFile(path, opts)
+}
+
+// RenderDir renders every file below src into dst using the default registry.
+func RenderDir(src, dst string, opts RenderOptions) (*RenderReport, error) {
+	return DefaultRegistry().RenderDir(src, dst, opts)
+}
+
+// Apply resolves and writes the targets in m using the default registry (see Registry.Apply).
+func Apply(ctx context.Context, m *ApplyManifest) (*ApplyResult, error) {
+	return DefaultRegistry().Apply(ctx, m)
+}
+
+// WaitFor resolves tokens with the default registry, retrying until all succeed (see Registry.WaitFor).
+func WaitFor(ctx context.Context, tokens []string, opts WaitOptions) ([]string, error) {
+	return DefaultRegistry().WaitFor(ctx, tokens, opts)
+}
+
+// Verify checks the tokens in m against the default registry (see Registry.Verify).
+func Verify(ctx context.Context, m *Manifest) (*Report, error) {
+	return DefaultRegistry().Verify(ctx, m)
+}
+
+// DefaultRegistry returns the global default registry (see SetDefaultRegistry).
 // Mutating it is safe for concurrent use.
 func DefaultRegistry() *Registry {
-	return defaultRegistry
+	return defaultRegistry.Load()
+}
+
+// SetDefaultRegistry replaces the global default registry, so that a registry built
+// at startup serves all package-level functions. The swap is atomic: calls already
+// running finish on the old registry, later calls use r. A nil r installs a fresh
+// NewDefaultRegistry.
+func SetDefaultRegistry(r *Registry) {
+	if r == nil {
+		r = NewDefaultRegistry()
+	}
+	defaultRegistry.Store(r)
 }
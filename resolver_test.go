@@ -2,6 +2,7 @@ package resolver
 
 import (
 	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -118,6 +119,57 @@ func TestDefaultRegistry(t *testing.T) {
 	})
 }
 
+func TestSetDefaultRegistry(t *testing.T) {
+	prev := DefaultRegistry()
+	t.Cleanup(func() { SetDefaultRegistry(prev) })
+
+	t.Run("Package functions use the new registry", func(t *testing.T) {
+		custom := NewRegistry()
+		custom.Register("custom:", ResolverFunc(func(v string) (string, error) { return "C(" + v + ")", nil }))
+		SetDefaultRegistry(custom)
+		assert.Same(t, custom, DefaultRegistry())
+
+		got, err := ResolveVariable("custom:a")
+		require.NoError(t, err)
+		assert.Equal(t, "C(a)", got)
+
+		s, err := ResolveString("x=${custom:b}")
+		require.NoError(t, err)
+		assert.Equal(t, "x=C(b)", s)
+
+		out, err := ResolveSlice([]string{"custom:c"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"C(c)"}, out)
+
+		RegisterResolver("added:", ResolverFunc(func(string) (string, error) { return "added", nil }))
+		assert.Contains(t, custom.Schemes(), "added:")
+	})
+
+	t.Run("Nil installs a fresh default registry", func(t *testing.T) {
+		SetDefaultRegistry(nil)
+		reg := DefaultRegistry()
+		require.NotNil(t, reg)
+		assert.NotSame(t, prev, reg)
+		assert.Contains(t, reg.Schemes(), "env:")
+	})
+
+	t.Run("Concurrent swaps", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				SetDefaultRegistry(NewDefaultRegistry())
+			}()
+			go func() {
+				defer wg.Done()
+				_, _ = ResolveVariable("plain")
+			}()
+		}
+		wg.Wait()
+	})
+}
+
 func TestResolveSlice(t *testing.T) {
 	t.Run("Empty slice returns empty", func(t *testing.T) {
 		got, err := ResolveSlice(nil)
@@ -217,3 +269,82 @@ func TestResolveSliceBestEffort(t *testing.T) {
 		assert.Equal(t, 2, ok.count, "both sliceok:* entries should be resolved")
 	})
 }
+
+func TestRegistry_Child(t *testing.T) {
+	t.Run("Inherits parent schemes", func(t *testing.T) {
+		parent := NewRegistry()
+		parent.Register("p:", &stubResolver{out: "from-parent"})
+		child := parent.Child()
+
+		got, err := child.ResolveVariable("p:x")
+		require.NoError(t, err)
+		assert.Equal(t, "from-parent", got)
+	})
+
+	t.Run("Parent changes are visible", func(t *testing.T) {
+		parent := NewRegistry()
+		child := parent.Child()
+		parent.Register("late:", &stubResolver{out: "late"})
+
+		got, err := child.ResolveVariable("late:x")
+		require.NoError(t, err)
+		assert.Equal(t, "late", got)
+	})
+
+	t.Run("Child registrations shadow parent without modifying it", func(t *testing.T) {
+		parent := NewRegistry()
+		parent.Register("s:", &stubResolver{out: "parent"})
+		child := parent.Child()
+		child.Register("s:", &stubResolver{out: "child"})
+
+		got, err := child.ResolveVariable("s:x")
+		require.NoError(t, err)
+		assert.Equal(t, "child", got)
+
+		got, err = parent.ResolveVariable("s:x")
+		require.NoError(t, err)
+		assert.Equal(t, "parent", got)
+	})
+
+	t.Run("Policy is inherited until overridden", func(t *testing.T) {
+		parent := NewRegistry()
+		child := parent.Child()
+		grandchild := child.Child()
+
+		parent.SetUnknownSchemePolicy(ErrorOnUnknown)
+		_, err := grandchild.ResolveVariable("nosuch:x")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		child.SetUnknownSchemePolicy(PassThrough)
+		got, err := grandchild.ResolveVariable("nosuch:x")
+		require.NoError(t, err)
+		assert.Equal(t, "nosuch:x", got)
+
+		_, err = parent.ResolveVariable("nosuch:x")
+		require.Error(t, err)
+	})
+
+	t.Run("Schemes lists own then inherited", func(t *testing.T) {
+		parent := NewRegistry()
+		parent.Register("a:", &stubResolver{})
+		parent.Register("b:", &stubResolver{})
+		child := parent.Child()
+		child.Register("c:", &stubResolver{})
+		child.Register("a:", &stubResolver{})
+
+		assert.Equal(t, []string{"c:", "a:", "b:"}, child.Schemes())
+		assert.Equal(t, []string{"a:", "b:"}, parent.Schemes())
+	})
+
+	t.Run("Interpolation uses inherited schemes", func(t *testing.T) {
+		parent := NewRegistry()
+		parent.Register("p:", ResolverFunc(func(v string) (string, error) { return "P" + v, nil }))
+		child := parent.Child()
+		child.Register("c:", ResolverFunc(func(v string) (string, error) { return "C" + v, nil }))
+
+		got, err := child.ResolveString("${p:1}-${c:2}")
+		require.NoError(t, err)
+		assert.Equal(t, "P1-C2", got)
+	})
+}
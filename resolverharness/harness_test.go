@@ -0,0 +1,85 @@
+package resolverharness
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/containeroo/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarness_Files(t *testing.T) {
+	h := New(t)
+
+	p := h.WriteJSON("conf/app.json", map[string]any{"db": map[string]any{"host": "db.test", "port": 5432}})
+	assert.FileExists(t, p)
+	assert.Equal(t, "db.test", h.MustResolve("json:"+p+"//db.host"))
+	assert.Equal(t, "5432", h.MustResolve(h.Token("json:", "conf/app.json", "db.port")))
+
+	h.WriteYAML("app.yaml", map[string]any{"log": map[string]any{"level": "debug"}})
+	assert.Equal(t, "debug", h.MustResolve(h.Token("yaml:", "app.yaml", "log.level")))
+
+	h.WriteFile("app.env", "TOKEN=abc\n")
+	assert.Equal(t, "abc", h.MustResolve(h.Token("file:", "app.env", "TOKEN")))
+
+	h.ExpectError(h.Token("json:", "conf/app.json", "db.user"), resolver.ErrNotFound)
+	h.ExpectError(h.Token("json:", "missing.json", "db.host"), resolver.ErrNotFound)
+}
+
+func TestHarness_SecretsAndEnv(t *testing.T) {
+	h := New(t)
+	h.SetSecret("db/password", "s3cr3t")
+	h.Setenv("RESOLVERHARNESS_DB_USER", "app")
+
+	got := h.MustResolveString("postgres://${env:RESOLVERHARNESS_DB_USER}:${mem:db/password}@db/app")
+	assert.Equal(t, "postgres://app:s3cr3t@db/app", got)
+
+	// The secret store is private to the harness: the default registry has no "mem:".
+	v, _ := resolver.ResolveVariable("mem:db/password")
+	assert.NotEqual(t, "s3cr3t", v)
+}
+
+func TestHarness_ServeRoutes(t *testing.T) {
+	h := New(t)
+	srv := h.ServeRoutes(map[string]any{
+		"/raw":  "plain text",
+		"/json": map[string]any{"ok": true},
+	})
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close() // nolint:errcheck
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get("/raw")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "plain text", body)
+
+	code, body = get("/json")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"ok":true}`, body)
+
+	code, _ = get("/missing")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	require.Len(t, srv.Requests(), 3)
+	assert.Equal(t, "/raw", srv.Requests()[0].URL.Path)
+}
+
+func TestHarness_Cleanup(t *testing.T) {
+	var dir string
+	t.Run("inner", func(t *testing.T) {
+		h := New(t)
+		dir = h.Dir
+		h.WriteFile("x.txt", "x")
+	})
+	_, err := os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,78 @@
+package resolver
+
+import "fmt"
+
+// Capability names an optional feature of a resolver, reported by SchemeInfos.
+type Capability string
+
+const (
+	// CapabilitySelector means the resolver accepts a "//key.path" selector.
+	CapabilitySelector Capability = "selector"
+	// CapabilityWholeFile means the resolver returns the whole source when no selector is given.
+	CapabilityWholeFile Capability = "whole-file"
+	// CapabilityRemote means the resolver contacts a network backend.
+	CapabilityRemote Capability = "remote"
+)
+
+// ResolverMeta is the metadata a resolver reports about itself.
+type ResolverMeta struct {
+	Sensitive    bool         // resolved values are secrets and should not be logged
+	Capabilities []Capability // optional features supported by the resolver
+}
+
+// Describer is implemented by resolvers that report metadata for SchemeInfos.
+// Resolvers that don't implement it are reported with zero metadata.
+type Describer interface {
+	Describe() ResolverMeta
+}
+
+// SchemeInfo describes one entry of a registry's effective resolver table.
+type SchemeInfo struct {
+	Scheme       string       // scheme including trailing ':'
+	Type         string       // Go type of the resolver, e.g. "*resolver.JSONResolver"
+	Sensitive    bool         // see ResolverMeta.Sensitive
+	Capabilities []Capability // see ResolverMeta.Capabilities
+	Inherited    bool         // registered on a parent registry (see Child)
+}
+
+// SchemeInfos returns the effective resolver table in resolution order, which is the
+// order in which ResolveVariable tries the schemes:
+//   - schemes appear in first-registration order; re-registering replaces the resolver in place
+//   - for child registries, own schemes come first, followed by inherited ones not shadowed
+//
+// The result is a snapshot; later registrations do not affect it.
+func (r *Registry) SchemeInfos() []SchemeInfo {
+	r.mu.RLock()
+	out := make([]SchemeInfo, 0, len(r.order))
+	for _, scheme := range r.order {
+		out = append(out, describeScheme(scheme, r.backing[scheme]))
+	}
+	parent := r.parent
+	r.mu.RUnlock()
+
+	if parent == nil {
+		return out
+	}
+	seen := make(map[string]struct{}, len(out))
+	for _, info := range out {
+		seen[info.Scheme] = struct{}{}
+	}
+	for _, info := range parent.SchemeInfos() {
+		if _, shadowed := seen[info.Scheme]; !shadowed {
+			info.Inherited = true
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// describeScheme builds the SchemeInfo for a registered resolver.
+func describeScheme(scheme string, res Resolver) SchemeInfo {
+	info := SchemeInfo{Scheme: scheme, Type: fmt.Sprintf("%T", res)}
+	if d, ok := res.(Describer); ok {
+		meta := d.Describe()
+		info.Sensitive = meta.Sensitive
+		info.Capabilities = append([]Capability(nil), meta.Capabilities...)
+	}
+	return info
+}
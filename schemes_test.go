@@ -0,0 +1,97 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_SchemeInfos(t *testing.T) {
+	t.Run("Default registry order and metadata", func(t *testing.T) {
+		reg := NewDefaultRegistry()
+		infos := reg.SchemeInfos()
+
+		schemes := make([]string, len(infos))
+		for i, info := range infos {
+			schemes[i] = info.Scheme
+		}
+		assert.Equal(t, reg.Schemes(), schemes)
+		assert.Equal(t, envPrefix, infos[0].Scheme)
+		assert.Equal(t, "*resolver.EnvResolver", infos[0].Type)
+		assert.False(t, infos[0].Sensitive)
+		assert.Empty(t, infos[0].Capabilities)
+
+		byScheme := map[string]SchemeInfo{}
+		for _, info := range infos {
+			byScheme[info.Scheme] = info
+		}
+		assert.Equal(t, "*resolver.JSONResolver", byScheme[jsonPrefix].Type)
+		assert.Equal(t, []Capability{CapabilitySelector, CapabilityWholeFile}, byScheme[jsonPrefix].Capabilities)
+	})
+
+	t.Run("Default registry has no side-effecting schemes", func(t *testing.T) {
+		assert.Equal(t, []string{envPrefix, jsonPrefix, yamlPrefix, iniPrefix, filePrefix, tomlPrefix}, NewDefaultRegistry().Schemes())
+
+		for _, info := range NewExtendedRegistry().SchemeInfos() {
+			assert.NotContains(t, info.Capabilities, CapabilityRemote, info.Scheme)
+		}
+		for _, token := range []string{"stdin:", "infisical:ws/prod/X", "git:https://example.com/r@main:f"} {
+			val, err := NewExtendedRegistry().ResolveVariable(token)
+			require.NoError(t, err)
+			assert.Equal(t, token, val, "passed through")
+		}
+	})
+
+	t.Run("Backends", func(t *testing.T) {
+		reg := NewExtendedRegistry()
+		RegisterBackends(reg)
+		byScheme := map[string]SchemeInfo{}
+		for _, info := range reg.SchemeInfos() {
+			byScheme[info.Scheme] = info
+		}
+		assert.True(t, byScheme[infisicalPrefix].Sensitive)
+		assert.Contains(t, byScheme[infisicalPrefix].Capabilities, CapabilityRemote)
+		assert.Contains(t, byScheme, stdinPrefix)
+	})
+
+	t.Run("Re-registration keeps position", func(t *testing.T) {
+		reg := NewRegistry()
+		reg.Register("a:", &stubResolver{})
+		reg.Register("b:", &stubResolver{})
+		reg.Register("a:", ResolverFunc(func(string) (string, error) { return "", nil }))
+
+		infos := reg.SchemeInfos()
+		require.Len(t, infos, 2)
+		assert.Equal(t, "a:", infos[0].Scheme)
+		assert.Equal(t, "resolver.ResolverFunc", infos[0].Type)
+		assert.Equal(t, "b:", infos[1].Scheme)
+	})
+
+	t.Run("Child marks inherited entries", func(t *testing.T) {
+		parent := NewRegistry()
+		parent.Register("a:", &stubResolver{})
+		parent.Register("b:", &stubResolver{})
+		child := parent.Child()
+		child.Register("b:", &JSONResolver{})
+
+		infos := child.SchemeInfos()
+		require.Len(t, infos, 2)
+		assert.Equal(t, SchemeInfo{
+			Scheme:       "b:",
+			Type:         "*resolver.JSONResolver",
+			Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile},
+		}, infos[0])
+		assert.Equal(t, "a:", infos[1].Scheme)
+		assert.True(t, infos[1].Inherited)
+	})
+
+	t.Run("Snapshot is not shared", func(t *testing.T) {
+		reg := NewRegistry()
+		reg.Register("j:", &JSONResolver{})
+		infos := reg.SchemeInfos()
+		infos[0].Capabilities[0] = CapabilityRemote
+
+		assert.Equal(t, CapabilitySelector, reg.SchemeInfos()[0].Capabilities[0])
+	})
+}
@@ -0,0 +1,120 @@
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// SecretServiceResolver resolves secrets from the freedesktop Secret Service API
+// (GNOME Keyring, KWallet, KeePassXC) over the D-Bus session bus.
+// Format: "secretservice:<attr>=<value>[,<attr>=<value>...]", matching the attributes
+// used by `secret-tool store`, e.g. "secretservice:service=myapp,username=alice".
+//
+// The first matching unlocked item is returned. Locked items are not unlocked, since
+// that requires an interactive prompt; they yield ErrForbidden.
+type SecretServiceResolver struct {
+	// lookup replaces the D-Bus round trip in tests.
+	lookup func(attrs map[string]string) ([]byte, error)
+}
+
+func (r *SecretServiceResolver) Resolve(value string) (string, error) {
+	attrs, err := parseSecretServiceAttrs(value)
+	if err != nil {
+		return "", err
+	}
+	lookup := r.lookup
+	if lookup == nil {
+		lookup = lookupSecretService
+	}
+	secret, err := lookup(attrs)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// Describe reports the resolver metadata.
+func (r *SecretServiceResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true}
+}
+
+// parseSecretServiceAttrs parses "k=v,k2=v2" into an attribute map.
+func parseSecretServiceAttrs(value string) (map[string]string, error) {
+	attrs := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("%w: secret service attribute %q must be key=value", ErrBadPath, pair)
+		}
+		attrs[k] = strings.TrimSpace(v)
+	}
+	return attrs, nil
+}
+
+// describeAttrs formats attrs deterministically for error messages.
+func describeAttrs(attrs map[string]string) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + attrs[k]
+	}
+	return strings.Join(parts, ",")
+}
+
+const (
+	secretServiceName = "org.freedesktop.secrets"
+	secretServicePath = dbus.ObjectPath("/org/freedesktop/secrets")
+)
+
+// secretServiceSecret mirrors the Secret Service (oayays) secret struct.
+type secretServiceSecret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// lookupSecretService searches the session bus for an item with attrs and returns its secret.
+func lookupSecretService(attrs map[string]string) ([]byte, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("connect to D-Bus session bus: %w", err)
+	}
+	defer conn.Close() // nolint:errcheck
+
+	svc := conn.Object(secretServiceName, secretServicePath)
+
+	var unlocked, locked []dbus.ObjectPath
+	if err := svc.Call("org.freedesktop.Secret.Service.SearchItems", 0, attrs).Store(&unlocked, &locked); err != nil {
+		return nil, fmt.Errorf("search secret service items: %w", err)
+	}
+	if len(unlocked) == 0 {
+		if len(locked) > 0 {
+			return nil, fmt.Errorf("%w: secret service item %q is locked", ErrForbidden, describeAttrs(attrs))
+		}
+		return nil, fmt.Errorf("%w: secret service item %q", ErrNotFound, describeAttrs(attrs))
+	}
+
+	// The "plain" algorithm transfers the secret unencrypted over the local bus.
+	var output dbus.Variant
+	var session dbus.ObjectPath
+	if err := svc.Call("org.freedesktop.Secret.Service.OpenSession", 0, "plain", dbus.MakeVariant("")).Store(&output, &session); err != nil {
+		return nil, fmt.Errorf("open secret service session: %w", err)
+	}
+	defer conn.Object(secretServiceName, session).Call("org.freedesktop.Secret.Session.Close", 0) // nolint:errcheck
+
+	var secret secretServiceSecret
+	item := conn.Object(secretServiceName, unlocked[0])
+	if err := item.Call("org.freedesktop.Secret.Item.GetSecret", 0, session).Store(&secret); err != nil {
+		return nil, fmt.Errorf("read secret service item %q: %w", describeAttrs(attrs), err)
+	}
+	return secret.Value, nil
+}
@@ -0,0 +1,48 @@
+package resolver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretServiceResolver_Resolve(t *testing.T) {
+	r := &SecretServiceResolver{lookup: func(attrs map[string]string) ([]byte, error) {
+		if attrs["service"] == "myapp" && attrs["username"] == "alice" {
+			return []byte("t0ken"), nil
+		}
+		if attrs["service"] == "locked" {
+			return nil, fmt.Errorf("%w: locked", ErrForbidden)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, describeAttrs(attrs))
+	}}
+
+	t.Run("Attribute match", func(t *testing.T) {
+		val, err := r.Resolve("service=myapp, username=alice")
+		require.NoError(t, err)
+		assert.Equal(t, "t0ken", val)
+	})
+
+	t.Run("No match", func(t *testing.T) {
+		_, err := r.Resolve("username=bob,service=myapp")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Contains(t, err.Error(), "service=myapp,username=bob")
+	})
+
+	t.Run("Locked item", func(t *testing.T) {
+		_, err := r.Resolve("service=locked")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Malformed attributes", func(t *testing.T) {
+		for _, in := range []string{"", "service", "=x", "service=myapp,"} {
+			_, err := r.Resolve(in)
+			require.Error(t, err, in)
+			assert.ErrorIs(t, err, ErrBadPath, in)
+		}
+	})
+}
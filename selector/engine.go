@@ -0,0 +1,67 @@
+package selector
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Engine evaluates key paths written in an alternative syntax, such as JSONPath or jq.
+// Engines live in their own packages and register themselves with RegisterEngine from
+// an init function, so a program only links (and depends on) the engines it imports.
+type Engine interface {
+	// Matches reports whether keyPath is written in the engine's syntax.
+	Matches(keyPath string) bool
+	// Select evaluates keyPath against data and returns all results in order.
+	Select(data any, keyPath string) ([]any, error)
+}
+
+var (
+	enginesMu sync.RWMutex
+	engines   []namedEngine
+)
+
+type namedEngine struct {
+	name string
+	e    Engine
+}
+
+// RegisterEngine adds an engine under name. Engines are consulted in registration
+// order; the first one whose Matches accepts a key path evaluates it, and key paths no
+// engine accepts are dotted paths for Navigate.
+// Panics if name is empty or already registered, or if e is nil.
+func RegisterEngine(name string, e Engine) {
+	if name == "" || e == nil {
+		panic("selector: RegisterEngine needs a name and an engine")
+	}
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+	for _, ne := range engines {
+		if ne.name == name {
+			panic(fmt.Sprintf("selector: engine %q registered twice", name))
+		}
+	}
+	engines = append(engines, namedEngine{name: name, e: e})
+}
+
+// LookupEngine returns the first registered engine that matches keyPath and its name.
+func LookupEngine(keyPath string) (string, Engine, bool) {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	for _, ne := range engines {
+		if ne.e.Matches(keyPath) {
+			return ne.name, ne.e, true
+		}
+	}
+	return "", nil, false
+}
+
+// Engines returns the names of the registered engines in registration order.
+func Engines() []string {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	names := make([]string, len(engines))
+	for i, ne := range engines {
+		names[i] = ne.name
+	}
+	return names
+}
@@ -0,0 +1,38 @@
+package selector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upperEngine returns the upper-cased key path after "#upper:".
+type upperEngine struct{}
+
+func (upperEngine) Matches(keyPath string) bool { return strings.HasPrefix(keyPath, "#upper:") }
+
+func (upperEngine) Select(_ any, keyPath string) ([]any, error) {
+	return []any{strings.ToUpper(strings.TrimPrefix(keyPath, "#upper:"))}, nil
+}
+
+func TestRegisterEngine(t *testing.T) {
+	RegisterEngine("test-upper", upperEngine{})
+
+	assert.Contains(t, Engines(), "test-upper")
+
+	name, e, ok := LookupEngine("#upper:abc")
+	require.True(t, ok)
+	assert.Equal(t, "test-upper", name)
+	got, err := e.Select(nil, "#upper:abc")
+	require.NoError(t, err)
+	assert.Equal(t, []any{"ABC"}, got)
+
+	_, _, ok = LookupEngine("server.host")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { RegisterEngine("test-upper", upperEngine{}) })
+	assert.Panics(t, func() { RegisterEngine("", upperEngine{}) })
+	assert.Panics(t, func() { RegisterEngine("test-nil", nil) })
+}
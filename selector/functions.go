@@ -0,0 +1,75 @@
+package selector
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+)
+
+// functions are the path segments that compute a value from the current one instead of
+// selecting a child. A map key with the same name is reachable as a quoted key
+// (`["#length"]`).
+var functions = map[string]func(v any) (any, error){
+	"#length": fnLength,
+	"#keys":   fnKeys,
+	"#first":  fnFirst,
+	"#last":   fnLast,
+}
+
+// fnLength returns the number of elements of a slice or map, or of characters of a
+// string.
+func fnLength(v any) (any, error) {
+	if m, ok := asMap(v); ok {
+		return len(m), nil
+	}
+	switch curr := v.(type) {
+	case []any:
+		return len(curr), nil
+	case string:
+		return utf8.RuneCountInString(curr), nil
+	}
+	return nil, fmt.Errorf("#length needs an array, map or string, got %T", v)
+}
+
+// fnKeys returns the keys of a map in sorted order.
+func fnKeys(v any) (any, error) {
+	m, ok := asMap(v)
+	if !ok {
+		return nil, fmt.Errorf("#keys needs a map, got %T", v)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	out := make([]any, len(keys))
+	for i, k := range keys {
+		out[i] = k
+	}
+	return out, nil
+}
+
+// fnFirst returns the first element of a slice.
+func fnFirst(v any) (any, error) {
+	a, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("#first needs an array, got %T", v)
+	}
+	if len(a) == 0 {
+		return nil, errors.New("#first of an empty array")
+	}
+	return a[0], nil
+}
+
+// fnLast returns the last element of a slice.
+func fnLast(v any) (any, error) {
+	a, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("#last needs an array, got %T", v)
+	}
+	if len(a) == 0 {
+		return nil, errors.New("#last of an empty array")
+	}
+	return a[len(a)-1], nil
+}
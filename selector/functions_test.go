@@ -0,0 +1,55 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNavigateFunctions(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"servers": []any{
+			map[string]any{"name": "web", "port": 80},
+			map[string]any{"name": "api", "port": 443},
+		},
+		"limits": map[string]any{"memory": "512Mi", "cpu": 2},
+		"name":   "grüß",
+		"empty":  []any{},
+		"tricky": map[string]any{"#length": "literal"},
+	}
+
+	cases := []struct {
+		name string
+		path string
+		want any
+	}{
+		{"length of array", "servers.#length", 2},
+		{"length of map", "limits.#length", 2},
+		{"length of string in characters", "name.#length", 4},
+		{"keys sorted", "limits.#keys", []any{"cpu", "memory"}},
+		{"first", "servers.#first.name", "web"},
+		{"last", "servers.#last.port", 443},
+		{"after wildcard", "servers.*.name.#length", []any{3, 3}},
+		{"after filter", "servers.[name=api].#keys", []any{"name", "port"}},
+		{"quoted key is literal", `tricky.["#length"]`, "literal"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			val, err := Navigate(data, ParsePath(tc.path))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		for _, path := range []string{"limits.cpu.#length", "servers.#keys", "limits.#first", "empty.#last", "empty.#first"} {
+			_, err := Navigate(data, ParsePath(path))
+			require.Error(t, err, path)
+		}
+	})
+}
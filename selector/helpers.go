@@ -2,6 +2,7 @@ package selector
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -14,68 +15,319 @@ import (
 //	"server.host"                  → ["server", "host"]
 //	"servers.0.host"               → ["servers", "0", "host"]
 //	"servers.[name=example.org].ip" → ["servers", "[name=example.org]", "ip"]
+//	"servers[1].host"              → ["servers", "1", "host"]
+//	"..password"                   → ["..", "password"]
+//	"db..password"                 → ["db", "..", "password"]
+//	"servers[1:3]"                 → ["servers", "[1:3]"]
+//	`labels.["app.kubernetes.io/name"]` → ["labels", `["app.kubernetes.io/name"]`]
+//	"server.timeout|30s"           → ["server", "timeout", "|30s"]
+//	"releases.@sort(meta.date)"    → ["releases", "@sort(meta.date)"]
+//	"servers.0.{host,meta.name}"   → ["servers", "0", "{host,meta.name}"]
+//
+// Bracketed indices ("servers[1]", "matrix[0][2]", "[0]") are accepted as an
+// alternative to dotted indices, for compatibility with JSONPath/jq-style paths.
+// Bracketed slices ("servers[1:3]") and quoted keys (`labels["app.kubernetes.io/name"]`)
+// are split off the same way but keep their brackets; a quoted key may contain dots and
+// brackets and ends at the first matching quote followed by ']'.
+//
+// Dots inside the parentheses of a modifier ("@sort(...)") or the braces of a
+// projection ("{...}") do not split either.
+//
+// A '|' outside brackets ends the path: the rest, including any dots, is a default
+// token (see Navigate).
 //
 // This allows array filters and nested fields to coexist without breaking on dots
 // inside the filter expression.
+//
+// ParsePath accepts any input; use ParsePathStrict to reject malformed paths.
 func ParsePath(s string) []string {
 	var out []string
-	var buf []rune
-	depth := 0 // bracket nesting depth
+	for _, seg := range splitPath(s) {
+		if seg.text == RecursiveDescent || strings.HasPrefix(seg.text, DefaultSeparator) {
+			out = append(out, seg.text)
+			continue
+		}
+		out = appendToken(out, seg.text)
+	}
+	return out
+}
+
+// pathSegment is a dot-separated part of a path and its byte offset in the path.
+type pathSegment struct {
+	text string
+	off  int
+}
+
+// splitPath splits s into segments as ParsePath does, before bracketed indices are
+// split off: recursive descents and a default are segments of their own.
+func splitPath(s string) []pathSegment {
+	var out []pathSegment
+	start := 0 // start of the current segment
+	depth := 0 // bracket (or modifier parenthesis, projection brace) nesting depth
 
-	for _, r := range s {
-		switch r {
+	// Work on bytes: all delimiters are ASCII, and byte slicing keeps
+	// non-UTF-8 input intact instead of replacing it with U+FFFD.
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
 		case '[':
-			depth++              // entering filter → disable splitting on dots
-			buf = append(buf, r) // keep the rune
+			if end, ok := quotedKeyEnd(s, i); ok {
+				i = end - 1 // quoted key: skip to its closing bracket
+				continue
+			}
+			depth++ // entering filter → disable splitting on dots
 		case ']':
 			if depth > 0 {
 				depth-- // leaving filter
 			}
-			buf = append(buf, r)
-		case '.':
+		case '(':
+			if s[start] == '@' {
+				depth++ // modifier argument such as "@sort(meta.date)"
+			}
+		case ')':
+			if depth > 0 && s[start] == '@' {
+				depth--
+			}
+		case '{':
+			if s[start] == '{' {
+				depth++ // projection such as "{host,meta.name}"
+			}
+		case '}':
+			if depth > 0 && s[start] == '{' {
+				depth--
+			}
+		case '|':
 			if depth == 0 {
+				// the rest of the path is its default value
+				out = append(out, pathSegment{s[start:i], start})
+				return append(out, pathSegment{s[i:], i})
+			}
+		case '.':
+			if depth == 0 && i+1 < len(s) && s[i+1] == '.' {
+				// ".." is a recursive descent segment of its own
+				if i > start {
+					out = append(out, pathSegment{s[start:i], start})
+				}
+				out = append(out, pathSegment{RecursiveDescent, i})
+				i++
+				start = i + 1
+			} else if depth == 0 {
 				// split on dot only if not inside filter brackets
-				out = append(out, string(buf))
-				buf = buf[:0]
-				continue
+				out = append(out, pathSegment{s[start:i], start})
+				start = i + 1
 			}
-			// inside filter → keep dot literal
-			buf = append(buf, r)
-		default:
-			// normal character
-			buf = append(buf, r)
 		}
 	}
-	// flush the last token
-	out = append(out, string(buf))
-	return out
+
+	// flush the last segment
+	return append(out, pathSegment{s[start:], start})
+}
+
+// appendToken appends tok to out, splitting bracketed index suffixes such as
+// "servers[1][2]" into "servers", "1", "2". Other tokens are appended unchanged.
+func appendToken(out []string, tok string) []string {
+	open := strings.IndexByte(tok, '[')
+	if open < 0 {
+		return append(out, tok)
+	}
+	indices, ok := parseBracketIndices(tok[open:])
+	if !ok {
+		return append(out, tok)
+	}
+	if open > 0 {
+		out = append(out, tok[:open])
+	}
+	return append(out, indices...)
+}
+
+// parseBracketIndices parses one or more "[N]" groups (N a non-negative integer) or
+// "[A:B]" slices, which are returned with their brackets.
+func parseBracketIndices(s string) ([]string, bool) {
+	var out []string
+	for s != "" {
+		if s[0] != '[' {
+			return nil, false
+		}
+		end := strings.IndexByte(s, ']')
+		if end < 2 {
+			return nil, false
+		}
+		if qend, ok := quotedKeyEnd(s, 0); ok {
+			out = append(out, s[:qend])
+			s = s[qend:]
+			continue
+		}
+		digits := s[1:end]
+		if isSliceToken(s[:end+1]) {
+			out = append(out, s[:end+1])
+			s = s[end+1:]
+			continue
+		}
+		for i := 0; i < len(digits); i++ {
+			if digits[i] < '0' || digits[i] > '9' {
+				return nil, false
+			}
+		}
+		out = append(out, digits)
+		s = s[end+1:]
+	}
+	return out, true
+}
+
+// quotedKeyEnd returns the offset just past the quoted key (["key"] or ['key']) that
+// starts at s[i], if there is one.
+func quotedKeyEnd(s string, i int) (int, bool) {
+	if i+1 >= len(s) || s[i] != '[' || (s[i+1] != '"' && s[i+1] != '\'') {
+		return 0, false
+	}
+	end := strings.Index(s[i+2:], s[i+1:i+2]+"]")
+	if end < 0 {
+		return 0, false
+	}
+	return i + 2 + end + 2, true
+}
+
+// quotedKey returns the key of a quoted key token (["key"] or ['key']).
+func quotedKey(tok string) (string, bool) {
+	if end, ok := quotedKeyEnd(tok, 0); !ok || end != len(tok) {
+		return "", false
+	}
+	return tok[2 : len(tok)-2], true
+}
+
+// isSliceToken reports whether tok looks like [start:end], with either bound optional.
+func isSliceToken(tok string) bool {
+	_, _, ok := sliceBounds(tok, 0)
+	return ok
+}
+
+// sliceBounds returns the bounds of the slice token [start:end] applied to a slice of
+// length n. As in Python, a missing start is 0, a missing end is n, negative bounds
+// count from the end and bounds out of range are clamped.
+func sliceBounds(tok string, n int) (int, int, bool) {
+	inner, ok := strings.CutPrefix(tok, "[")
+	if !ok {
+		return 0, 0, false
+	}
+	if inner, ok = strings.CutSuffix(inner, "]"); !ok {
+		return 0, 0, false
+	}
+	from, to, ok := strings.Cut(inner, ":")
+	if !ok {
+		return 0, 0, false
+	}
+	bound := func(s string, def int) (int, bool) {
+		if s = strings.TrimSpace(s); s == "" {
+			return def, true
+		}
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, false
+		}
+		if i < 0 {
+			i += n
+		}
+		return min(max(i, 0), n), true
+	}
+	lo, ok1 := bound(from, 0)
+	hi, ok2 := bound(to, n)
+	if !ok1 || !ok2 {
+		return 0, 0, false
+	}
+	return lo, max(lo, hi), true
 }
 
-// isFilterToken reports whether tok looks like [key=value] (optional quotes around value).
+// isFilterToken reports whether tok looks like [key=value] (optional quotes around value),
+// [?key] or [!key].
 func isFilterToken(tok string) bool {
-	return strings.HasPrefix(tok, "[") && strings.HasSuffix(tok, "]") && strings.Contains(tok, "=")
+	if len(tok) < 3 || tok[0] != '[' || tok[len(tok)-1] != ']' {
+		return false
+	}
+	return strings.Contains(tok, "=") || tok[1] == '?' || tok[1] == '!'
+}
+
+// filterCond is one condition of a filter: the field it tests and whether a field
+// value matches. A condition without match tests only whether the field exists, or
+// with absent set, that it does not.
+type filterCond struct {
+	field  string
+	match  func(any) bool
+	absent bool
 }
 
-// parseFilterToken parses [key=value] and returns key, value (unquoted).
-func parseFilterToken(tok string) (string, string, error) {
+// parseFilter parses a filter token of one or more conditions joined by "&", as in
+// [name=api&enabled=true]. A condition is key=value, key~=pattern, ?key (the field
+// exists) or !key (the field is absent).
+func parseFilter(tok string) ([]filterCond, error) {
 	inner := strings.TrimSuffix(strings.TrimPrefix(tok, "["), "]")
-	kv := strings.SplitN(inner, "=", 2)
+	var conds []filterCond
+	for _, part := range splitConditions(inner) {
+		if p := strings.TrimSpace(part); p != "" && (p[0] == '?' || p[0] == '!') && !strings.Contains(p, "=") {
+			field := strings.TrimSpace(p[1:])
+			if field == "" {
+				return nil, fmt.Errorf("empty key in filter %q", part)
+			}
+			conds = append(conds, filterCond{field: field, absent: p[0] == '!'})
+			continue
+		}
+		key, raw, err := parseCondition(part)
+		if err != nil {
+			return nil, err
+		}
+		field, match, err := filterMatcher(key, raw)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, filterCond{field: field, match: match})
+	}
+	return conds, nil
+}
+
+// splitConditions splits the inside of a filter on "&", except within a value quoted
+// right after its "=".
+func splitConditions(s string) []string {
+	var out []string
+	start := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if strings.HasSuffix(strings.TrimSpace(s[start:i]), "=") {
+				quote = c
+			}
+		case c == '&':
+			out = append(out, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, s[start:])
+}
+
+// parseCondition parses key=value and returns key, value (unquoted).
+func parseCondition(cond string) (string, string, error) {
+	kv := strings.SplitN(cond, "=", 2)
 	if len(kv) != 2 {
-		return "", "", fmt.Errorf("invalid filter token %q", tok)
+		return "", "", fmt.Errorf("invalid filter condition %q", cond)
 	}
 	key := strings.TrimSpace(kv[0])
-	val := strings.TrimSpace(kv[1])
-	// Strip optional quotes
-	if (strings.HasPrefix(val, "\"") && strings.HasSuffix(val, "\"")) ||
-		(strings.HasPrefix(val, "'") && strings.HasSuffix(val, "'")) {
-		val = strings.Trim(val, "\"'")
-	}
+	val := unquote(strings.TrimSpace(kv[1]))
 	if key == "" {
-		return "", "", fmt.Errorf("empty key in filter %q", tok)
+		return "", "", fmt.Errorf("empty key in filter %q", cond)
 	}
 	return key, val, nil
 }
 
+// unquote strips one pair of matching quotes from s; a lone quote is literal.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
 // coerce tries int, float, then explicit bool ("true"/"false"); otherwise returns the raw string.
 // Important: do NOT treat "1"/"0" as booleans, so numeric IDs match correctly.
 func coerce(val string) any {
@@ -93,8 +345,87 @@ func coerce(val string) any {
 	return val
 }
 
+// filterValue returns the value a filter compares against and whether the comparison
+// is strict. A type prefix ("int:80", "float:0.5", "bool:true", "str:\"1\"") fixes the
+// type and disables coercion, so only values of that type match; otherwise the value
+// is coerced (see coerce) and compared loosely (see equalCoerced).
+func filterValue(raw string) (any, bool, error) {
+	typ, val, ok := strings.Cut(raw, ":")
+	if !ok {
+		return coerce(raw), false, nil
+	}
+	switch typ {
+	case "int":
+		i, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid int filter value %q", val)
+		}
+		return i, true, nil
+	case "float":
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid float filter value %q", val)
+		}
+		return f, true, nil
+	case "bool":
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid bool filter value %q", val)
+		}
+		return b, true, nil
+	case "str":
+		return unquote(val), true, nil
+	}
+	return coerce(raw), false, nil
+}
+
+// filterMatcher returns the field a filter tests and a function reporting whether a
+// field value matches. A key ending in "~" ("[name~=^api-]") makes raw a regular
+// expression matched against the value's text; otherwise raw is compared as by
+// filterValue.
+func filterMatcher(key, raw string) (string, func(any) bool, error) {
+	if field, ok := strings.CutSuffix(key, "~"); ok {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			return "", nil, fmt.Errorf("empty key in filter %q", key+"="+raw)
+		}
+		re, err := regexp.Compile(raw)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid filter pattern %q: %w", raw, err)
+		}
+		return field, func(v any) bool {
+			switch v.(type) {
+			case map[string]any, []any, nil:
+				return false
+			}
+			return re.MatchString(fmt.Sprint(v))
+		}, nil
+	}
+
+	want, strict, err := filterValue(raw) // typed ("int:80") or coerced
+	if err != nil {
+		return "", nil, err
+	}
+	equal := equalCoerced
+	if strict {
+		equal = equalTyped
+	}
+	return key, func(v any) bool { return equal(v, want) }, nil
+}
+
 // equalCoerced compares v (from YAML/JSON) with want (already coerced).
 func equalCoerced(v any, want any) bool {
+	if equalTyped(v, want) {
+		return true
+	}
+	// last resort: string compare
+	return fmt.Sprint(v) == fmt.Sprint(want)
+}
+
+// equalTyped compares v with want without converting between types, except between
+// numeric representations (int, int64 and integral float64 for an int want; float64
+// for a float want).
+func equalTyped(v any, want any) bool {
 	switch w := want.(type) {
 	case bool:
 		if vb, ok := v.(bool); ok {
@@ -110,14 +441,18 @@ func equalCoerced(v any, want any) bool {
 			return int(vv) == w && float64(int(vv)) == vv
 		}
 	case float64:
-		if vf, ok := v.(float64); ok {
-			return vf == w
+		switch vv := v.(type) {
+		case float64:
+			return vv == w
+		case int:
+			return float64(vv) == w
+		case int64:
+			return float64(vv) == w
 		}
 	case string:
 		if vs, ok := v.(string); ok {
 			return vs == w
 		}
 	}
-	// last resort: string compare
-	return fmt.Sprint(v) == fmt.Sprint(want)
+	return false
 }
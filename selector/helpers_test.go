@@ -1,6 +1,7 @@
 package selector
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -45,6 +46,87 @@ func TestParsePath(t *testing.T) {
 		got := ParsePath("servers.[host=example.org].port")
 		assert.Equal(t, []string{"servers", "[host=example.org]", "port"}, got)
 	})
+
+	t.Run("bracketed index", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"servers", "1", "host"}, ParsePath("servers[1].host"))
+		assert.Equal(t, []string{"servers", "1"}, ParsePath("servers[1]"))
+		assert.Equal(t, []string{"matrix", "0", "2"}, ParsePath("matrix[0][2]"))
+		assert.Equal(t, []string{"0", "name"}, ParsePath("[0].name"))
+		assert.Equal(t, []string{"servers", "1", "host"}, ParsePath("servers.[1].host"))
+	})
+
+	t.Run("recursive descent", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"..", "password"}, ParsePath("..password"))
+		assert.Equal(t, []string{"db", "..", "password"}, ParsePath("db..password"))
+		assert.Equal(t, []string{"servers", "..", "[name=api]", "port"}, ParsePath("servers..[name=api].port"))
+		assert.Equal(t, []string{"a", "[k=x..y]"}, ParsePath("a.[k=x..y]"))
+	})
+
+	t.Run("slices", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"servers", "[1:3]"}, ParsePath("servers.[1:3]"))
+		assert.Equal(t, []string{"servers", "[1:3]", "0"}, ParsePath("servers[1:3][0]"))
+		assert.Equal(t, []string{"nums", "[-2:]"}, ParsePath("nums[-2:]"))
+	})
+
+	t.Run("quoted keys", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"annotations", `["app.kubernetes.io/name"]`}, ParsePath(`annotations.["app.kubernetes.io/name"]`))
+		assert.Equal(t, []string{"annotations", `['a.b']`, "c"}, ParsePath(`annotations['a.b'].c`))
+		assert.Equal(t, []string{"m", `["x].y"]`, "z"}, ParsePath(`m.["x].y"].z`))
+		assert.Equal(t, []string{"m", `["a"]`, "0"}, ParsePath(`m["a"][0]`))
+	})
+
+	t.Run("modifier", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"releases", "@sortdesc(meta.date)", "0"}, ParsePath("releases.@sortdesc(meta.date).0"))
+		assert.Equal(t, []string{"a(b", "c)"}, ParsePath("a(b.c)"))
+		assert.Equal(t, []string{"servers", "0", "{host,meta.name}"}, ParsePath("servers.0.{host,meta.name}"))
+		assert.Equal(t, []string{"a{b", "c}"}, ParsePath("a{b.c}"))
+	})
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"server", "timeout", "|30s"}, ParsePath("server.timeout|30s"))
+		assert.Equal(t, []string{"server", "host", "|example.com"}, ParsePath("server.host|example.com"))
+		assert.Equal(t, []string{"items", "[name~=a|b]", "|x|y"}, ParsePath("items.[name~=a|b]|x|y"))
+		assert.Equal(t, []string{"m", `["a|b"]`}, ParsePath(`m.["a|b"]`))
+		assert.Equal(t, []string{"", "|x"}, ParsePath("|x"))
+	})
+
+	t.Run("non-index brackets are kept", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"servers[name=api]", "host"}, ParsePath("servers[name=api].host"))
+		assert.Equal(t, []string{"a[-1]"}, ParsePath("a[-1]"))
+		assert.Equal(t, []string{"a[]"}, ParsePath("a[]"))
+		assert.Equal(t, []string{"a[1]b"}, ParsePath("a[1]b"))
+	})
+}
+
+func TestQuotedKey(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		tok  string
+		want string
+		ok   bool
+	}{
+		{`["a.b"]`, "a.b", true},
+		{`['a.b']`, "a.b", true},
+		{`[""]`, "", true},
+		{`["a=b"]`, "a=b", true},
+		{`["a']`, "", false},
+		{`["a"]x`, "", false},
+		{`[a]`, "", false},
+		{`a`, "", false},
+	}
+	for _, tc := range cases {
+		got, ok := quotedKey(tc.tok)
+		assert.Equal(t, tc.ok, ok, tc.tok)
+		assert.Equal(t, tc.want, got, tc.tok)
+	}
 }
 
 func TestIsFilterToken(t *testing.T) {
@@ -69,14 +151,21 @@ func TestIsFilterToken(t *testing.T) {
 		t.Parallel()
 		assert.False(t, isFilterToken("[kv]"))
 	})
+
+	t.Run("existence", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, isFilterToken("[?k]"))
+		assert.True(t, isFilterToken("[!k]"))
+		assert.False(t, isFilterToken("?k"))
+	})
 }
 
-func TestParseFilterToken(t *testing.T) {
+func TestParseCondition(t *testing.T) {
 	t.Parallel()
 
 	t.Run("simple", func(t *testing.T) {
 		t.Parallel()
-		k, v, err := parseFilterToken("[k=v]")
+		k, v, err := parseCondition("k=v")
 		require.NoError(t, err)
 		assert.Equal(t, "k", k)
 		assert.Equal(t, "v", v)
@@ -84,19 +173,52 @@ func TestParseFilterToken(t *testing.T) {
 
 	t.Run("quoted value", func(t *testing.T) {
 		t.Parallel()
-		k, v, err := parseFilterToken("[k=\"v.with.dots\"]")
+		k, v, err := parseCondition("k=\"v.with.dots\"")
 		require.NoError(t, err)
 		assert.Equal(t, "k", k)
 		assert.Equal(t, "v.with.dots", v)
 	})
 
+	t.Run("only one pair of quotes is stripped", func(t *testing.T) {
+		t.Parallel()
+		_, v, err := parseCondition(`k="'v'"`)
+		require.NoError(t, err)
+		assert.Equal(t, "'v'", v)
+	})
+
+	t.Run("lone quote is literal", func(t *testing.T) {
+		t.Parallel()
+		_, v, err := parseCondition(`k="`)
+		require.NoError(t, err)
+		assert.Equal(t, `"`, v)
+	})
+
 	t.Run("invalid", func(t *testing.T) {
 		t.Parallel()
-		_, _, err := parseFilterToken("[kv]")
+		_, _, err := parseCondition("kv")
 		require.Error(t, err)
 	})
 }
 
+func TestSplitConditions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in   string
+		want []string
+	}{
+		{"k=v", []string{"k=v"}},
+		{"a=1&b=2", []string{"a=1", "b=2"}},
+		{`a="x&y"&b=2`, []string{`a="x&y"`, "b=2"}},
+		{`a= 'x&y' &b=2`, []string{`a= 'x&y' `, "b=2"}},
+		{`a=it's&b=2`, []string{`a=it's`, "b=2"}},
+		{"a=1&", []string{"a=1", ""}},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, splitConditions(tc.in), tc.in)
+	}
+}
+
 func TestCoerce(t *testing.T) {
 	t.Parallel()
 
@@ -176,3 +298,86 @@ func TestEqualCoerced(t *testing.T) {
 		assert.False(t, equalCoerced("x", "y"))
 	})
 }
+
+func TestFilterValue(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		raw    string
+		want   any
+		strict bool
+	}{
+		{"80", 80, false},
+		{"int:80", 80, true},
+		{"float:0.5", 0.5, true},
+		{"bool:true", true, true},
+		{`str:"1"`, "1", true},
+		{"str:1", "1", true},
+		{"http://x", "http://x", false}, // unknown prefix: plain coerced value
+	}
+	for _, tc := range cases {
+		got, strict, err := filterValue(tc.raw)
+		require.NoError(t, err, tc.raw)
+		assert.Equal(t, tc.want, got, tc.raw)
+		assert.Equal(t, tc.strict, strict, tc.raw)
+	}
+
+	for _, raw := range []string{"int:x", "float:x", "bool:maybe"} {
+		_, _, err := filterValue(raw)
+		require.Error(t, err, raw)
+	}
+}
+
+func TestEqualTyped(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, equalTyped(80.0, 80))
+	assert.True(t, equalTyped(int64(80), 80))
+	assert.False(t, equalTyped("80", 80))
+	assert.False(t, equalTyped(80, "80"))
+	assert.False(t, equalTyped("true", true))
+	assert.True(t, equalTyped(2, 2.0))
+}
+
+func FuzzParsePath(f *testing.F) {
+	for _, seed := range []string{
+		"", ".", "..", "a.b", "servers.0.host", "servers.[name=api].port",
+		"servers.[host=example.org].port", "[", "]", "a.[b.c", "a.]b.c", "[[a.b]].c",
+		"a.[k=\"v.w\"].b", "a..b", "ü.ö.[ä=ß]",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		tokens := ParsePath(s)
+		require.NotEmpty(t, tokens)
+		if !strings.Contains(s, "[") {
+			// Splitting only removes separator dots, so joining restores the input.
+			var joined strings.Builder
+			for i, tok := range tokens {
+				if i > 0 && tok != RecursiveDescent && tokens[i-1] != RecursiveDescent &&
+					!strings.HasPrefix(tok, DefaultSeparator) {
+					joined.WriteString(".")
+				}
+				joined.WriteString(tok)
+			}
+			assert.Equal(t, s, joined.String())
+		}
+	})
+}
+
+func FuzzParseCondition(f *testing.F) {
+	for _, seed := range []string{
+		"k=v", "k=\"v\"", "k='v'", "k=", "=v", "k==v", "k=\"", "k='",
+		"k=\"'v'\"", " k = v ", "[", "]", "", "[k=v", "k=v]", "k=\"v'",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, cond string) {
+		k, _, err := parseCondition(cond)
+		if err != nil {
+			return
+		}
+		assert.NotEmpty(t, k)
+		assert.Equal(t, strings.TrimSpace(k), k)
+	})
+}
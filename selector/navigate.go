@@ -1,81 +1,382 @@
 package selector
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 )
 
+// Wildcard is the path segment that matches every value of a map (in key order) or
+// every element of an array.
+const Wildcard = "*"
+
+// DefaultSeparator starts the last token of a path with a default value, as in
+// "server.timeout|30s".
+const DefaultSeparator = "|"
+
+// RecursiveDescent is the path segment that matches a value and everything below it,
+// depth-first with map keys in order, as JSONPath's "..".
+const RecursiveDescent = ".."
+
+// ErrAmbiguousFilter is returned by a filter that matches several elements under
+// FilterUnique.
+var ErrAmbiguousFilter = errors.New("ambiguous filter")
+
+// ErrNotFound is returned when a path leads nowhere: a missing key, an index out of
+// range, a filter no element matches or a segment below a scalar. Malformed paths,
+// filters and typed values are other errors.
+var ErrNotFound = errors.New("not found")
+
+// FilterPolicy is what a filter selects when several array elements match it.
+type FilterPolicy int
+
+const (
+	// FilterFirst selects the first matching element.
+	FilterFirst FilterPolicy = iota
+	// FilterUnique makes several matching elements an error (ErrAmbiguousFilter).
+	FilterUnique
+	// FilterAll selects every matching element, as NavigateAll does.
+	FilterAll
+)
+
+// Options changes how NavigateWith and NavigateAllWith match a path.
+type Options struct {
+	// CaseInsensitiveKeys matches a map key ignoring case when no key matches exactly.
+	// Of several keys that differ only in case, the first in sorted order is taken.
+	CaseInsensitiveKeys bool
+	// Filters is what a filter selects when several elements match. NavigateAllWith
+	// always selects every match.
+	Filters FilterPolicy
+}
+
 // Navigate walks through a nested structure of maps and arrays using path tokens.
 // Each element of `keys` is one segment of the path, typically produced by ParsePath.
 //
 // Supported key forms:
 //   - Map key: "server" → looks up curr["server"]
+//   - Quoted map key: `["app.kubernetes.io/name"]` → looks up the key as written, for
+//     keys containing dots or brackets
 //   - Array index: "0" → takes the 0th element of a slice
 //   - Array filter: "[field=value]" → selects the first element of a slice where elem[field]==value
+//   - Typed filter: "[field=int:80]", "[field=float:0.5]", "[field=bool:true]",
+//     "[field=str:\"1\"]" → like a filter, but matches only values of that type
+//   - Regex filter: "[field~=^api-]" → selects the first element whose field matches
+//     the regular expression
+//   - Nested filter: "[metadata.labels.app=web]" → like a filter, with a path into
+//     each element as the field
+//   - Conjunction: "[name=api&enabled=true]" → selects the first element matching
+//     every condition
+//   - Existence filter: "[?field]", "[!field]" → selects the first element that has
+//     (or lacks) the field
+//   - Array slice: "[1:3]", "[:2]", "[-2:]" → the elements from start up to (not
+//     including) end, as a new slice
+//   - Function: "#length" (of an array, map or string), "#keys" (of a map, sorted),
+//     "#first", "#last" (of an array) → the computed value
+//   - Sort: "@sort(date)", "@sortdesc(meta.date)" → a copy of an array sorted by a
+//     field (or path) of its elements, with elements lacking it last; "@sort()" sorts
+//     by the elements themselves
+//   - Projection: "{host,port}" → a map of only those fields (or paths) of a map,
+//     leaving out missing ones; of an array, the projection of each element
+//   - Wildcard: "*" → every value of a map (in key order) or element of a slice
+//   - Recursive descent: ".." → the current value and every value below it, so
+//     "..password" finds "password" keys at any depth
+//
+// A last token starting with DefaultSeparator ("|30s") holds a default: if the path
+// before it matches nothing (ErrNotFound), the default string (without one pair of
+// surrounding quotes) is returned instead of an error. Malformed filters, patterns
+// and typed values are still errors.
+//
+// After a wildcard or recursive descent, the remaining segments apply to each match,
+// and matches they do not apply to are skipped. Such a path returns its only match as
+// is and several matches as a []any; no match is an error. NavigateAll always returns
+// the matches as a slice.
 //
 // Example paths (split into tokens before calling Navigate):
 //
 //	servers.[name=app].host → ["servers", "[name=app]", "host"]
 //	servers.0.host           → ["servers", "0", "host"]
+//	servers.*.host           → ["servers", "*", "host"]
+//	..password               → ["..", "password"]
+//	server.timeout|30s       → ["server", "timeout", "|30s"]
+//	releases.@sortdesc(date).0.version → ["releases", "@sortdesc(date)", "0", "version"]
 func Navigate(data any, keys []string) (any, error) {
-	current := data
-	for _, k := range keys {
-		switch curr := current.(type) {
+	return NavigateWith(data, keys, Options{})
+}
 
-		case map[string]any:
-			// Map lookup: require string key
-			val, ok := curr[k]
-			if !ok {
-				return nil, fmt.Errorf("key %q not found", k)
-			}
-			current = val
+// NavigateWith is Navigate with options.
+func NavigateWith(data any, keys []string, opts Options) (any, error) {
+	matches, multi, err := navigate(data, keys, false, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !multi || len(matches) == 1 {
+		return matches[0], nil
+	}
+	return matches, nil
+}
 
-		case []any:
-			// Array filter form: [key=value]
-			if isFilterToken(k) {
-				fk, fvRaw, err := parseFilterToken(k)
-				if err != nil {
-					return nil, err
-				}
-				want := coerce(fvRaw) // coerce value to bool/int/float if possible
+// NavigateAll is Navigate returning every match of a path, in document order. Unlike
+// Navigate, a filter selects every matching element rather than the first, so
+// "servers.[env=prod].host" yields the hosts of all production servers. A path without
+// wildcards, descents or filters yields a single match.
+func NavigateAll(data any, keys []string) ([]any, error) {
+	return NavigateAllWith(data, keys, Options{})
+}
 
-				found := false
-				for _, elem := range curr {
-					m, ok := elem.(map[string]any)
-					if !ok {
-						continue // skip if element is not a map
-					}
-					got, ok := m[fk]
-					if !ok {
-						continue // field not present
-					}
-					// Compare with coercion-aware equality
-					if equalCoerced(got, want) {
-						current = elem
-						found = true
-						break
+// NavigateAllWith is NavigateAll with options.
+func NavigateAllWith(data any, keys []string, opts Options) ([]any, error) {
+	matches, _, err := navigate(data, keys, true, opts)
+	return matches, err
+}
+
+// navigate returns the values keys lead to from data and whether the path can match
+// several values. With all set, filters match every element instead of the first.
+func navigate(data any, keys []string, all bool, opts Options) ([]any, bool, error) {
+	if n := len(keys); n > 0 && strings.HasPrefix(keys[n-1], DefaultSeparator) {
+		matches, multi, err := navigate(data, keys[:n-1], all, opts)
+		if errors.Is(err, ErrNotFound) {
+			return []any{unquote(keys[n-1][len(DefaultSeparator):])}, false, nil
+		}
+		return matches, multi, err
+	}
+
+	current := []any{data}
+	multi := false
+	for _, k := range keys {
+		next := make([]any, 0, len(current))
+		switch k {
+		case Wildcard:
+			for _, c := range current {
+				next = append(next, children(c)...)
+			}
+			multi = true
+		case RecursiveDescent:
+			for _, c := range current {
+				next = descendants(c, next)
+			}
+			multi = true
+		default:
+			fanOut := (all || opts.Filters == FilterAll) && isFilterToken(k)
+			for _, c := range current {
+				if arr, ok := c.([]any); ok && fanOut {
+					matches, err := filterElems(arr, k, true, opts)
+					if err != nil && !multi {
+						return nil, false, err
 					}
+					next = append(next, matches...)
+					continue
 				}
-				if !found {
-					return nil, fmt.Errorf("no array element where %s=%v", fk, want)
+				v, err := step(c, k, opts)
+				if err != nil {
+					if multi && !errors.Is(err, ErrAmbiguousFilter) {
+						continue // the segment does not apply to this match
+					}
+					return nil, false, err
 				}
-				continue // move to next key segment
+				next = append(next, v)
 			}
+			multi = multi || fanOut
+		}
+		current = next
+	}
+	if len(current) == 0 {
+		return nil, multi, fmt.Errorf("%w: no match for path %q", ErrNotFound, strings.Join(keys, "."))
+	}
+	return current, multi, nil
+}
+
+// matchesAll reports whether m satisfies every condition of conds.
+func matchesAll(m map[string]any, conds []filterCond, opts Options) bool {
+	for _, c := range conds {
+		got, ok := fieldValue(m, c.field, opts)
+		switch {
+		case c.absent:
+			if ok {
+				return false
+			}
+		case !ok:
+			return false
+		case c.match != nil && !c.match(got):
+			return false
+		}
+	}
+	return true
+}
+
+// fieldValue returns the field of m a filter tests. A field that is not a key of m is
+// taken as a path into m, so "metadata.labels.app" tests a nested value.
+func fieldValue(m map[string]any, field string, opts Options) (any, bool) {
+	if v, ok := lookup(m, field, opts); ok {
+		return v, true
+	}
+	if !strings.ContainsAny(field, ".[") {
+		return nil, false
+	}
+	v, err := NavigateWith(m, ParsePath(field), opts)
+	return v, err == nil
+}
 
-			// Array index form: must be parseable integer
-			idx, err := strconv.Atoi(k)
+// lookup returns the value of key k in m, matching case-insensitively as a fallback if
+// opts asks for it.
+func lookup(m map[string]any, k string, opts Options) (any, bool) {
+	if v, ok := m[k]; ok || !opts.CaseInsensitiveKeys {
+		return v, ok
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		if strings.EqualFold(key, k) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, false
+	}
+	return m[slices.Min(keys)], true
+}
+
+// descendants appends v and every value below it to out, depth-first.
+func descendants(v any, out []any) []any {
+	out = append(out, v)
+	for _, c := range children(v) {
+		out = descendants(c, out)
+	}
+	return out
+}
+
+// children returns the values of a map in key order or the elements of a slice.
+func children(v any) []any {
+	if curr, ok := asMap(v); ok {
+		keys := make([]string, 0, len(curr))
+		for k := range curr {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		out := make([]any, len(keys))
+		for i, k := range keys {
+			out[i] = curr[k]
+		}
+		return out
+	}
+	if curr, ok := v.([]any); ok {
+		return curr
+	}
+	return nil
+}
+
+// asMap returns v as a map[string]any. Maps with other key types, such as the
+// map[any]any decoded by yaml.v2, are copied with their keys formatted as by fmt.Sprint;
+// their values are converted when a path reaches them.
+func asMap(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, true
+	case map[any]any:
+		out := make(map[string]any, len(m))
+		for k, e := range m {
+			out[fmt.Sprint(k)] = e
+		}
+		return out, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return nil, false
+	}
+	out := make(map[string]any, rv.Len())
+	for it := rv.MapRange(); it.Next(); {
+		out[fmt.Sprint(it.Key().Interface())] = it.Value().Interface()
+	}
+	return out, true
+}
+
+// step applies the path segment k to current.
+func step(current any, k string, opts Options) (any, error) {
+	if fn, ok := functions[k]; ok {
+		return fn(current)
+	}
+	if field, desc, ok := sortModifier(k); ok {
+		return sortElems(current, field, desc, opts)
+	}
+	if fields, ok := projectionFields(k); ok {
+		return project(current, fields, opts)
+	}
+
+	if curr, ok := asMap(current); ok {
+		// Map lookup: require string key
+		if name, ok := quotedKey(k); ok {
+			k = name
+		}
+		val, ok := lookup(curr, k, opts)
+		if !ok {
+			return nil, fmt.Errorf("key %q %w", k, ErrNotFound)
+		}
+		return val, nil
+	}
+
+	switch curr := current.(type) {
+	case []any:
+		if _, ok := quotedKey(k); ok {
+			return nil, fmt.Errorf("%w: key %s cannot select from an array", ErrNotFound, k)
+		}
+
+		// Array filter form: [key=value]
+		if isFilterToken(k) {
+			matches, err := filterElems(curr, k, opts.Filters == FilterUnique, opts)
 			if err != nil {
-				return nil, fmt.Errorf("%q is not a valid array index or filter", k)
+				return nil, err
 			}
-			if idx < 0 || idx >= len(curr) {
-				return nil, fmt.Errorf("array index %d out of bounds", idx)
+			if len(matches) > 1 {
+				return nil, fmt.Errorf("%w: %s matches %d elements", ErrAmbiguousFilter, k, len(matches))
 			}
-			current = curr[idx]
+			return matches[0], nil
+		}
 
-		default:
-			// Neither a map nor a slice → cannot descend further
-			return nil, fmt.Errorf("path segment %q not found", k)
+		// Array slice form: [start:end]
+		if lo, hi, ok := sliceBounds(k, len(curr)); ok {
+			return curr[lo:hi:hi], nil
+		}
+
+		// Array index form: must be parseable integer
+		idx, err := strconv.Atoi(k)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a valid array index or filter", ErrNotFound, k)
 		}
+		if idx < 0 || idx >= len(curr) {
+			return nil, fmt.Errorf("%w: array index %d out of bounds", ErrNotFound, idx)
+		}
+		return curr[idx], nil
+
+	default:
+		// Neither a map nor a slice → cannot descend further
+		return nil, fmt.Errorf("path segment %q %w", k, ErrNotFound)
+	}
+}
+
+// filterElems returns the first element of curr matching the filter token k, or with
+// all set, every matching element. No match is an error.
+func filterElems(curr []any, k string, all bool, opts Options) ([]any, error) {
+	conds, err := parseFilter(k)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []any
+	for _, elem := range curr {
+		m, ok := asMap(elem)
+		if !ok {
+			continue // skip if element is not a map
+		}
+		if matchesAll(m, conds, opts) {
+			matches = append(matches, elem)
+			if !all {
+				break
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: no array element where %s", ErrNotFound, k[1:len(k)-1])
 	}
-	return current, nil
+	return matches, nil
 }
@@ -1,6 +1,7 @@
 package selector
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -71,6 +72,195 @@ func TestNavigate(t *testing.T) {
 		assert.Equal(t, "web", val)
 	})
 
+	t.Run("typed filters", func(t *testing.T) {
+		t.Parallel()
+		local := map[string]any{
+			"items": []any{
+				map[string]any{"id": "80", "port": "http", "on": "true", "name": "string-id"},
+				map[string]any{"id": 80, "port": 80.0, "on": true, "name": "int-id"},
+				map[string]any{"id": 1.5, "name": "float-id"},
+			},
+		}
+		cases := []struct{ path, want string }{
+			{`items.[id=80].name`, "string-id"}, // coercion matches the string first
+			{`items.[id=int:80].name`, "int-id"},
+			{`items.[id=str:"80"].name`, "string-id"},
+			{`items.[id=str:80].name`, "string-id"},
+			{`items.[port=int:80].name`, "int-id"},
+			{`items.[on=bool:true].name`, "int-id"},
+			{`items.[on=str:true].name`, "string-id"},
+			{`items.[id=float:1.5].name`, "float-id"},
+		}
+		for _, tc := range cases {
+			val, err := Navigate(local, ParsePath(tc.path))
+			require.NoError(t, err, tc.path)
+			assert.Equal(t, tc.want, val, tc.path)
+		}
+
+		_, err := Navigate(local, ParsePath("items.[name=int:80].id"))
+		require.Error(t, err, "typed filter must not fall back to string comparison")
+
+		_, err = Navigate(local, ParsePath("items.[id=int:eighty].name"))
+		require.ErrorContains(t, err, "invalid int filter value")
+	})
+
+	t.Run("regex filters", func(t *testing.T) {
+		t.Parallel()
+		local := map[string]any{
+			"items": []any{
+				map[string]any{"name": "web-1", "port": 8080},
+				map[string]any{"name": "api-1", "port": 443},
+				map[string]any{"name": "api-2", "port": 8443, "tags": []any{"x"}},
+			},
+		}
+		cases := []struct{ path, want string }{
+			{`items.[name~=^api-].port`, "443"},
+			{`items.[name~=-2$].port`, "8443"},
+			{`items.[port~=^84].name`, "api-2"}, // numbers match by their text
+			{`items.[name~=^(web|api)-[0-9]+$].port`, "8080"},
+			{`items.[ name ~= ^api ].port`, "443"},
+		}
+		for _, tc := range cases {
+			val, err := Navigate(local, ParsePath(tc.path))
+			require.NoError(t, err, tc.path)
+			assert.Equal(t, tc.want, fmt.Sprint(val), tc.path)
+		}
+
+		_, err := Navigate(local, ParsePath("items.[tags~=x].name"))
+		require.Error(t, err, "containers never match a pattern")
+
+		_, err = Navigate(local, ParsePath("items.[name~=(].port"))
+		require.ErrorContains(t, err, "invalid filter pattern")
+
+		_, err = Navigate(local, ParsePath("items.[~=api].port"))
+		require.ErrorContains(t, err, "empty key")
+	})
+
+	t.Run("conjunctions", func(t *testing.T) {
+		t.Parallel()
+		local := map[string]any{
+			"items": []any{
+				map[string]any{"name": "api", "env": "dev", "on": false, "host": "a"},
+				map[string]any{"name": "api", "env": "prod", "on": false, "host": "b"},
+				map[string]any{"name": "api", "env": "prod", "on": true, "host": "c"},
+				map[string]any{"name": "a&b", "env": "x", "host": "d"},
+			},
+		}
+		cases := []struct{ path, want string }{
+			{`items.[name=api&env=prod].host`, "b"},
+			{`items.[name=api&env=prod&on=true].host`, "c"},
+			{`items.[name=api & on=bool:true].host`, "c"},
+			{`items.[name~=^a&env=dev].host`, "a"},
+			{`items.[name="a&b"&env=x].host`, "d"},
+		}
+		for _, tc := range cases {
+			val, err := Navigate(local, ParsePath(tc.path))
+			require.NoError(t, err, tc.path)
+			assert.Equal(t, tc.want, val, tc.path)
+		}
+
+		_, err := Navigate(local, ParsePath("items.[name=api&env=test].host"))
+		require.ErrorContains(t, err, "no array element where name=api&env=test")
+
+		_, err = Navigate(local, ParsePath("items.[name=api&missing=x].host"))
+		require.Error(t, err, "every field must be present")
+
+		_, err = Navigate(local, ParsePath("items.[name=api&].host"))
+		require.ErrorContains(t, err, "invalid filter condition")
+	})
+
+	t.Run("nested filter fields", func(t *testing.T) {
+		t.Parallel()
+		local := map[string]any{
+			"pods": []any{
+				map[string]any{
+					"metadata": map[string]any{"labels": map[string]any{"app": "db"}},
+					"status":   map[string]any{"podIP": "10.0.0.1"},
+				},
+				map[string]any{
+					"metadata": map[string]any{"labels": map[string]any{"app": "web"}, "name": "web-1"},
+					"status":   map[string]any{"podIP": "10.0.0.2"},
+					"ports":    []any{map[string]any{"port": 80}},
+				},
+				map[string]any{"a.b": "literal", "status": map[string]any{"podIP": "10.0.0.3"}},
+			},
+		}
+		cases := []struct{ path, want string }{
+			{`pods.[metadata.labels.app=web].status.podIP`, "10.0.0.2"},
+			{`pods.[metadata.labels.app~=^d&status.podIP=10.0.0.1].status.podIP`, "10.0.0.1"},
+			{`pods.[ports.0.port=80].metadata.name`, "web-1"},
+			{`pods.[a.b=literal].status.podIP`, "10.0.0.3"}, // literal keys win
+		}
+		for _, tc := range cases {
+			val, err := Navigate(local, ParsePath(tc.path))
+			require.NoError(t, err, tc.path)
+			assert.Equal(t, tc.want, val, tc.path)
+		}
+
+		_, err := Navigate(local, ParsePath("pods.[metadata.labels.tier=web].status"))
+		require.Error(t, err)
+	})
+
+	t.Run("existence filters", func(t *testing.T) {
+		t.Parallel()
+		local := map[string]any{
+			"items": []any{
+				map[string]any{"name": "plain"},
+				map[string]any{"name": "tls", "cert": nil, "meta": map[string]any{"owner": "ops"}},
+				map[string]any{"name": "other", "cert": "x"},
+			},
+		}
+		cases := []struct{ path, want string }{
+			{`items.[?cert].name`, "tls"}, // null values exist
+			{`items.[!cert].name`, "plain"},
+			{`items.[?cert&!meta].name`, "other"},
+			{`items.[?meta.owner].name`, "tls"},
+			{`items.[? cert & name=other].name`, "other"},
+		}
+		for _, tc := range cases {
+			val, err := Navigate(local, ParsePath(tc.path))
+			require.NoError(t, err, tc.path)
+			assert.Equal(t, tc.want, val, tc.path)
+		}
+
+		_, err := Navigate(local, ParsePath("items.[?missing].name"))
+		require.ErrorContains(t, err, "no array element where ?missing")
+
+		_, err = Navigate(local, ParsePath("items.[!name].name"))
+		require.Error(t, err)
+
+		_, err = Navigate(local, ParsePath("items.[?].name"))
+		require.ErrorContains(t, err, "empty key")
+	})
+
+	t.Run("quoted keys", func(t *testing.T) {
+		t.Parallel()
+		local := map[string]any{
+			"metadata": map[string]any{
+				"annotations": map[string]any{"app.kubernetes.io/name": "web", "*": "star", "0": "zero"},
+			},
+			"items": []any{
+				map[string]any{"labels": map[string]any{"app.kubernetes.io/name": "db"}, "ip": "10.0.0.1"},
+				map[string]any{"labels": map[string]any{"app.kubernetes.io/name": "web"}, "ip": "10.0.0.2"},
+			},
+		}
+		cases := []struct{ path, want string }{
+			{`metadata.annotations.["app.kubernetes.io/name"]`, "web"},
+			{`metadata.annotations['app.kubernetes.io/name']`, "web"},
+			{`metadata.annotations.["*"]`, "star"},
+			{`metadata.annotations.["0"]`, "zero"},
+			{`items.[labels["app.kubernetes.io/name"]=web].ip`, "10.0.0.2"},
+		}
+		for _, tc := range cases {
+			val, err := Navigate(local, ParsePath(tc.path))
+			require.NoError(t, err, tc.path)
+			assert.Equal(t, tc.want, val, tc.path)
+		}
+
+		_, err := Navigate(local, ParsePath(`items.["0"]`))
+		require.ErrorContains(t, err, "cannot select from an array")
+	})
+
 	t.Run("array filter with dots in value", func(t *testing.T) {
 		t.Parallel()
 		// augment a local copy to avoid data races across parallel tests
@@ -130,3 +320,399 @@ func TestNavigate(t *testing.T) {
 		assert.Equal(t, "example.com", val)
 	})
 }
+
+func TestNavigateWildcard(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"servers": []any{
+			map[string]any{"name": "web", "host": "example.com"},
+			map[string]any{"name": "api", "host": "example.org"},
+			map[string]any{"name": "db"},
+		},
+		"zones": map[string]any{
+			"b": map[string]any{"ip": "10.0.0.2"},
+			"a": map[string]any{"ip": "10.0.0.1"},
+		},
+		"single": []any{map[string]any{"host": "only"}},
+		"leaf":   "done",
+	}
+
+	cases := []struct {
+		name string
+		path string
+		want any
+	}{
+		{"array elements", "servers.*.host", []any{"example.com", "example.org"}},
+		{"map values in key order", "zones.*.ip", []any{"10.0.0.1", "10.0.0.2"}},
+		{"single match unwrapped", "single.*.host", "only"},
+		{"nested wildcards", "*.*.ip", []any{"10.0.0.1", "10.0.0.2"}},
+		{"wildcard then index", "servers.*.name", []any{"web", "api", "db"}},
+		{"trailing wildcard", "zones.a.*", "10.0.0.1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			val, err := Navigate(data, ParsePath(tc.path))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("NavigateAll returns a slice", func(t *testing.T) {
+		t.Parallel()
+		vals, err := NavigateAll(data, ParsePath("single.*.host"))
+		require.NoError(t, err)
+		assert.Equal(t, []any{"only"}, vals)
+
+		vals, err = NavigateAll(data, ParsePath("servers.1.host"))
+		require.NoError(t, err)
+		assert.Equal(t, []any{"example.org"}, vals)
+	})
+
+	t.Run("NavigateAll matches every filtered element", func(t *testing.T) {
+		t.Parallel()
+		vals, err := NavigateAll(data, ParsePath("servers.[?host].host"))
+		require.NoError(t, err)
+		assert.Equal(t, []any{"example.com", "example.org"}, vals)
+
+		vals, err = NavigateAll(data, ParsePath("servers.[name~=^(web|db)$].name"))
+		require.NoError(t, err)
+		assert.Equal(t, []any{"web", "db"}, vals)
+
+		vals, err = NavigateAll(data, ParsePath("servers.[name~=.].host"))
+		require.NoError(t, err, "elements the rest of the path does not apply to are skipped")
+		assert.Equal(t, []any{"example.com", "example.org"}, vals)
+
+		val, err := Navigate(data, ParsePath("servers.[?host].host"))
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", val, "Navigate keeps the first match")
+
+		_, err = NavigateAll(data, ParsePath("servers.[name=cache].host"))
+		require.ErrorContains(t, err, "no array element where name=cache")
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		t.Parallel()
+		_, err := Navigate(data, ParsePath("servers.*.port"))
+		require.ErrorContains(t, err, "no match")
+
+		_, err = Navigate(data, ParsePath("leaf.*"))
+		require.Error(t, err)
+	})
+
+	t.Run("errors before a wildcard are kept", func(t *testing.T) {
+		t.Parallel()
+		_, err := Navigate(data, ParsePath("servers.9.*"))
+		require.ErrorContains(t, err, "out of bounds")
+	})
+}
+
+func TestNavigateRecursiveDescent(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"db": map[string]any{
+			"password": "db-secret",
+			"replica":  map[string]any{"password": "replica-secret"},
+		},
+		"cache":    map[string]any{"password": "cache-secret"},
+		"password": "root-secret",
+		"servers": []any{
+			map[string]any{"name": "web", "port": 80},
+			map[string]any{"name": "api", "port": 8080},
+		},
+	}
+
+	cases := []struct {
+		name string
+		path string
+		want any
+	}{
+		{"any depth, parents first", "..password", []any{"root-secret", "cache-secret", "db-secret", "replica-secret"}},
+		{"below a key", "db..password", []any{"db-secret", "replica-secret"}},
+		{"single match unwrapped", "cache..password", "cache-secret"},
+		{"through arrays", "..port", []any{80, 8080}},
+		{"then filter", "..[name=api].port", 8080},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			val, err := Navigate(data, ParsePath(tc.path))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("no match", func(t *testing.T) {
+		t.Parallel()
+		_, err := Navigate(data, ParsePath("..token"))
+		require.ErrorContains(t, err, "no match")
+	})
+}
+
+func TestNavigateWith(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"Server": map[string]any{"Port": 80, "PORT": 81},
+		"servers": []any{
+			map[string]any{"Name": "api", "Host": "example.org"},
+		},
+	}
+	ci := Options{CaseInsensitiveKeys: true}
+
+	cases := []struct {
+		name string
+		path string
+		want any
+	}{
+		{"different case", "server.port", 81}, // "PORT" sorts before "Port"
+		{"exact match wins", "Server.Port", 80},
+		{"filter fields", "servers.[name=api].host", "example.org"},
+		{"nested filter fields", "servers.[?NAME].HOST", "example.org"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			val, err := NavigateWith(data, ParsePath(tc.path), ci)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("NavigateAllWith", func(t *testing.T) {
+		t.Parallel()
+		vals, err := NavigateAllWith(data, ParsePath("SERVERS.*.host"), ci)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"example.org"}, vals)
+	})
+
+	t.Run("case-sensitive by default", func(t *testing.T) {
+		t.Parallel()
+		_, err := Navigate(data, ParsePath("server.port"))
+		require.Error(t, err)
+		_, err = NavigateWith(data, ParsePath("servers.[name=api].host"), Options{})
+		require.Error(t, err)
+	})
+}
+
+func TestNavigateFilterPolicy(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"servers": []any{
+			map[string]any{"name": "web", "env": "prod", "port": 80},
+			map[string]any{"name": "api", "env": "prod", "port": 443},
+			map[string]any{"name": "dev", "env": "dev", "port": 8080},
+		},
+		"groups": map[string]any{
+			"a": []any{map[string]any{"env": "prod"}, map[string]any{"env": "prod"}},
+		},
+	}
+	unique := Options{Filters: FilterUnique}
+
+	t.Run("FilterFirst by default", func(t *testing.T) {
+		t.Parallel()
+		val, err := NavigateWith(data, ParsePath("servers.[env=prod].name"), Options{})
+		require.NoError(t, err)
+		assert.Equal(t, "web", val)
+	})
+
+	t.Run("FilterUnique single match", func(t *testing.T) {
+		t.Parallel()
+		val, err := NavigateWith(data, ParsePath("servers.[env=dev].name"), unique)
+		require.NoError(t, err)
+		assert.Equal(t, "dev", val)
+	})
+
+	t.Run("FilterUnique several matches", func(t *testing.T) {
+		t.Parallel()
+		_, err := NavigateWith(data, ParsePath("servers.[env=prod].name"), unique)
+		require.ErrorIs(t, err, ErrAmbiguousFilter)
+		assert.EqualError(t, err, "ambiguous filter: [env=prod] matches 2 elements")
+	})
+
+	t.Run("FilterUnique is not skipped or defaulted", func(t *testing.T) {
+		t.Parallel()
+		_, err := NavigateWith(data, ParsePath("groups.*.[env=prod]"), unique)
+		require.ErrorIs(t, err, ErrAmbiguousFilter)
+
+		_, err = NavigateWith(data, ParsePath("servers.[env=prod].name|none"), unique)
+		require.ErrorIs(t, err, ErrAmbiguousFilter)
+
+		val, err := NavigateWith(data, ParsePath("servers.[env=test].name|none"), unique)
+		require.NoError(t, err)
+		assert.Equal(t, "none", val)
+	})
+
+	t.Run("FilterAll", func(t *testing.T) {
+		t.Parallel()
+		all := Options{Filters: FilterAll}
+		val, err := NavigateWith(data, ParsePath("servers.[env=prod].name"), all)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"web", "api"}, val)
+
+		val, err = NavigateWith(data, ParsePath("servers.[env=dev].name"), all)
+		require.NoError(t, err)
+		assert.Equal(t, "dev", val)
+	})
+
+	t.Run("NavigateAllWith ignores the policy", func(t *testing.T) {
+		t.Parallel()
+		vals, err := NavigateAllWith(data, ParsePath("servers.[env=prod].port"), unique)
+		require.NoError(t, err)
+		assert.Equal(t, []any{80, 443}, vals)
+	})
+}
+
+func TestNavigateDefault(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"server":  map[string]any{"timeout": "10s", "port": 8080},
+		"servers": []any{map[string]any{"port": 80}, map[string]any{}},
+	}
+
+	cases := []struct {
+		name string
+		path string
+		want any
+	}{
+		{"present value wins", "server.timeout|30s", "10s"},
+		{"missing key", "server.retries|3", "3"},
+		{"missing parent", "client.timeout|30s", "30s"},
+		{"dots in default", "server.host|example.com", "example.com"},
+		{"quoted default", `server.host|"a|b"`, "a|b"},
+		{"empty default", "server.user|", ""},
+		{"no wildcard match", "servers.*.host|none", "none"},
+		{"filter", "servers.[port=443].port|443", "443"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			val, err := Navigate(data, ParsePath(tc.path))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("NavigateAll", func(t *testing.T) {
+		t.Parallel()
+		vals, err := NavigateAll(data, ParsePath("servers.*.port|0"))
+		require.NoError(t, err)
+		assert.Equal(t, []any{80}, vals)
+
+		vals, err = NavigateAll(data, ParsePath("servers.*.host|none"))
+		require.NoError(t, err)
+		assert.Equal(t, []any{"none"}, vals)
+	})
+
+	t.Run("malformed paths are not defaulted", func(t *testing.T) {
+		t.Parallel()
+		for _, path := range []string{
+			"servers.[name~=(].port|99",    // invalid regex
+			"servers.[port=int:x].port|99", // invalid typed value
+			"servers.[=443].port|99",       // empty filter key
+		} {
+			_, err := Navigate(data, ParsePath(path))
+			require.Error(t, err, path)
+			assert.NotErrorIs(t, err, ErrNotFound, path)
+		}
+	})
+
+	t.Run("missing values are ErrNotFound", func(t *testing.T) {
+		t.Parallel()
+		for _, path := range []string{"server.retries", "client.timeout", "servers.5", "servers.[port=1]", "server.timeout.x"} {
+			_, err := Navigate(data, ParsePath(path))
+			assert.ErrorIs(t, err, ErrNotFound, path)
+		}
+	})
+}
+
+func TestNavigateSlice(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"nums": []any{0, 1, 2, 3, 4},
+		"servers": []any{
+			map[string]any{"host": "a"},
+			map[string]any{"host": "b"},
+			map[string]any{"host": "c"},
+		},
+	}
+
+	cases := []struct {
+		name string
+		path string
+		want any
+	}{
+		{"range", "nums.[1:3]", []any{1, 2}},
+		{"open start", "nums.[:2]", []any{0, 1}},
+		{"open end", "nums[3:]", []any{3, 4}},
+		{"whole", "nums.[:]", []any{0, 1, 2, 3, 4}},
+		{"negative", "nums.[-2:]", []any{3, 4}},
+		{"clamped", "nums.[3:99]", []any{3, 4}},
+		{"empty", "nums.[4:1]", []any{}},
+		{"then index", "servers[1:][0].host", "b"},
+		{"then wildcard", "servers.[:2].*.host", []any{"a", "b"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			val, err := Navigate(data, ParsePath(tc.path))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("invalid bound", func(t *testing.T) {
+		t.Parallel()
+		_, err := Navigate(data, ParsePath("nums.[a:2]"))
+		require.Error(t, err)
+	})
+}
+
+func TestNavigateOtherMapTypes(t *testing.T) {
+	t.Parallel()
+
+	// As decoded by yaml.v2: map[any]any at every level, with non-string keys.
+	data := map[any]any{
+		"servers": []any{
+			map[any]any{"name": "web", "port": 80},
+			map[any]any{"name": "api", "port": 443},
+		},
+		"codes":  map[any]any{404: "not found", true: "yes"},
+		"labels": map[string]string{"app": "web"},
+	}
+
+	cases := []struct {
+		name string
+		path string
+		want any
+	}{
+		{"nested key", "servers.1.name", "api"},
+		{"filter", "servers.[name=api].port", 443},
+		{"int key", "codes.404", "not found"},
+		{"bool key", "codes.true", "yes"},
+		{"typed map", "labels.app", "web"},
+		{"wildcard", "servers.*.port", []any{80, 443}},
+		{"recursive descent", "..name", []any{"web", "api"}},
+		{"length", "codes.#length", 2},
+		{"keys", "codes.#keys", []any{"404", "true"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			val, err := Navigate(data, ParsePath(tc.path))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := Navigate(data, ParsePath("codes.500"))
+		assert.EqualError(t, err, `key "500" not found`)
+	})
+}
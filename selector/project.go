@@ -0,0 +1,51 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// projectionFields returns the fields of a projection segment, "{host,port}". Fields
+// are trimmed and may be quoted; an empty field makes the segment no projection.
+func projectionFields(k string) ([]string, bool) {
+	if len(k) < 2 || k[0] != '{' || k[len(k)-1] != '}' {
+		return nil, false
+	}
+	parts := strings.Split(k[1:len(k)-1], ",")
+	for i, p := range parts {
+		parts[i] = unquote(strings.TrimSpace(p))
+		if parts[i] == "" {
+			return nil, false
+		}
+	}
+	return parts, true
+}
+
+// project returns a map of the fields of v that it has, each under the field as
+// written (see fieldValue), so "{host,meta.name}" keeps "host" and "meta.name". Missing
+// fields are left out. Applied to an array, it projects each element.
+func project(v any, fields []string, opts Options) (any, error) {
+	if arr, ok := v.([]any); ok {
+		out := make([]any, len(arr))
+		for i, e := range arr {
+			p, err := project(e, fields, opts)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = p
+		}
+		return out, nil
+	}
+
+	m, ok := asMap(v)
+	if !ok {
+		return nil, fmt.Errorf("cannot project fields of a %T", v)
+	}
+	out := make(map[string]any, len(fields))
+	for _, f := range fields {
+		if val, ok := fieldValue(m, f, opts); ok {
+			out[f] = val
+		}
+	}
+	return out, nil
+}
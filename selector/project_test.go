@@ -0,0 +1,62 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNavigateProjection(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"db": map[string]any{
+			"host":     "db.local",
+			"port":     5432,
+			"password": "s3cret",
+			"meta":     map[string]any{"name": "main"},
+		},
+		"servers": []any{
+			map[string]any{"host": "a", "port": 80, "token": "x"},
+			map[string]any{"host": "b", "token": "y"},
+		},
+	}
+
+	cases := []struct {
+		name string
+		path string
+		want any
+	}{
+		{"fields", "db.{host,port}", map[string]any{"host": "db.local", "port": 5432}},
+		{"spaces and quotes", `db.{ host , "port" }`, map[string]any{"host": "db.local", "port": 5432}},
+		{"nested field", "db.{host,meta.name}", map[string]any{"host": "db.local", "meta.name": "main"}},
+		{"missing field left out", "db.{host,user}", map[string]any{"host": "db.local"}},
+		{"array", "servers.{host,port}", []any{
+			map[string]any{"host": "a", "port": 80},
+			map[string]any{"host": "b"},
+		}},
+		{"after filter", "servers.[host=b].{host}", map[string]any{"host": "b"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			val, err := Navigate(data, ParsePath(tc.path))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("case-insensitive", func(t *testing.T) {
+		t.Parallel()
+		val, err := NavigateWith(data, ParsePath("db.{HOST}"), Options{CaseInsensitiveKeys: true})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"HOST": "db.local"}, val)
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		t.Parallel()
+		_, err := Navigate(data, ParsePath("db.host.{a}"))
+		assert.EqualError(t, err, "cannot project fields of a string")
+	})
+}
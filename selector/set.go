@@ -0,0 +1,141 @@
+package selector
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Set stores value at the path keys in data, the companion of Navigate for writing.
+// Missing map keys along the path are created as map[string]any; existing values are
+// replaced. Keys may be map keys (quoted or not), array indices and filters, which
+// select the first matching element to write into. An index one past the end of an
+// array appends to it, except for data itself, which must be a map or array that Set
+// can change in place.
+//
+// Paths that may select several values or compute one (wildcards, recursive descent,
+// slices, functions, sorts, projections and defaults) cannot be set and are an error.
+//
+// Example:
+//
+//	Set(cfg, ParsePath("server.tls.enabled"), true)
+//	Set(cfg, ParsePath("servers.[name=api].port"), 8443)
+func Set(data any, keys []string, value any) error {
+	if len(keys) == 0 {
+		return errors.New("cannot set an empty path")
+	}
+	if arr, ok := data.([]any); ok {
+		out, err := set(arr, keys, value)
+		if err == nil && len(out.([]any)) != len(arr) {
+			return fmt.Errorf("cannot append to the root array at %q", keys[0])
+		}
+		return err
+	}
+	_, err := set(data, keys, value)
+	return err
+}
+
+// set stores value at keys below current and returns current, or the new slice if an
+// array was appended to.
+func set(current any, keys []string, value any) (any, error) {
+	k := keys[0]
+	if err := checkSettable(k); err != nil {
+		return nil, err
+	}
+
+	// child stores value or the result of setting the rest of the path in child.
+	child := func(c any) (any, error) {
+		if len(keys) == 1 {
+			return value, nil
+		}
+		if c == nil {
+			c = map[string]any{}
+		}
+		return set(c, keys[1:], value)
+	}
+
+	switch curr := current.(type) {
+	case map[string]any:
+		if name, ok := quotedKey(k); ok {
+			k = name
+		}
+		v, err := child(curr[k])
+		if err != nil {
+			return nil, err
+		}
+		curr[k] = v
+		return curr, nil
+
+	case map[any]any:
+		if name, ok := quotedKey(k); ok {
+			k = name
+		}
+		var key any = k
+		for mk := range curr {
+			if fmt.Sprint(mk) == k {
+				key = mk
+				break
+			}
+		}
+		v, err := child(curr[key])
+		if err != nil {
+			return nil, err
+		}
+		curr[key] = v
+		return curr, nil
+
+	case []any:
+		if _, ok := quotedKey(k); ok {
+			return nil, fmt.Errorf("key %s cannot select from an array", k)
+		}
+
+		if isFilterToken(k) {
+			matches, err := filterElems(curr, k, false, Options{})
+			if err != nil {
+				return nil, err
+			}
+			if len(keys) == 1 {
+				return nil, fmt.Errorf("cannot replace the element selected by filter %s", k)
+			}
+			_, err = set(matches[0], keys[1:], value)
+			return curr, err
+		}
+
+		idx, err := strconv.Atoi(k)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid array index or filter", k)
+		}
+		switch {
+		case idx == len(curr):
+			v, err := child(nil)
+			if err != nil {
+				return nil, err
+			}
+			return append(curr, v), nil
+		case idx < 0 || idx > len(curr):
+			return nil, fmt.Errorf("array index %d out of bounds", idx)
+		}
+		v, err := child(curr[idx])
+		if err != nil {
+			return nil, err
+		}
+		curr[idx] = v
+		return curr, nil
+
+	default:
+		return nil, fmt.Errorf("cannot set %q in a %T", k, current)
+	}
+}
+
+// checkSettable reports an error for path segments that do not name a single place.
+func checkSettable(k string) error {
+	_, fn := functions[k]
+	_, _, sorted := sortModifier(k)
+	_, projected := projectionFields(k)
+	switch {
+	case k == Wildcard, k == RecursiveDescent, fn, sorted, projected, isSliceToken(k), strings.HasPrefix(k, DefaultSeparator):
+		return fmt.Errorf("cannot set through %q", k)
+	}
+	return nil
+}
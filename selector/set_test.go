@@ -0,0 +1,102 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSetData() map[string]any {
+	return map[string]any{
+		"server": map[string]any{"host": "localhost", "port": 8080},
+		"servers": []any{
+			map[string]any{"name": "web", "port": 80},
+			map[string]any{"name": "api", "port": 443},
+		},
+		"legacy": map[any]any{404: "not found"},
+	}
+}
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		path  string
+		value any
+	}{
+		{"replace", "server.port", 9090},
+		{"add key", "server.tls", true},
+		{"create parents", "client.retry.max", 3},
+		{"quoted key", `server.["app.kubernetes.io/name"]`, "web"},
+		{"index", "servers.1.port", 8443},
+		{"bracketed index", "servers[0].name", "www"},
+		{"filter", "servers.[name=api].port", 8443},
+		{"append", "servers.2", "new"},
+		{"append nested", "servers.2.name", "db"},
+		{"replace element", "servers.0", "gone"},
+		{"other map key type", "legacy.404", "missing"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data := newSetData()
+			require.NoError(t, Set(data, ParsePath(tc.path), tc.value))
+
+			got, err := Navigate(data, ParsePath(tc.path))
+			require.NoError(t, err)
+			assert.Equal(t, tc.value, got)
+		})
+	}
+
+	t.Run("other values kept", func(t *testing.T) {
+		t.Parallel()
+		data := newSetData()
+		require.NoError(t, Set(data, ParsePath("servers.[name=api].port"), 8443))
+		assert.Equal(t, map[string]any{"name": "web", "port": 80}, data["servers"].([]any)[0])
+		assert.Equal(t, map[any]any{404: "not found"}, data["legacy"])
+	})
+
+	t.Run("root array", func(t *testing.T) {
+		t.Parallel()
+		data := []any{"a", "b"}
+		require.NoError(t, Set(data, ParsePath("1"), "c"))
+		assert.Equal(t, []any{"a", "c"}, data)
+		assert.EqualError(t, Set(data, ParsePath("2"), "d"), `cannot append to the root array at "2"`)
+	})
+}
+
+func TestSet_Errors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"empty path", "", "cannot set an empty path"},
+		{"wildcard", "servers.*.port", `cannot set through "*"`},
+		{"recursive descent", "..port", `cannot set through ".."`},
+		{"slice", "servers.[0:1]", `cannot set through "[0:1]"`},
+		{"function", "servers.#last.port", `cannot set through "#last"`},
+		{"sort", "servers.@sort(port).0.port", `cannot set through "@sort(port)"`},
+		{"projection", "server.{host}.host", `cannot set through "{host}"`},
+		{"default", "server.port|80", `cannot set through "|80"`},
+		{"index out of bounds", "servers.5.port", "array index 5 out of bounds"},
+		{"no filter match", "servers.[name=db].port", "not found: no array element where name=db"},
+		{"filter as last segment", "servers.[name=api]", "cannot replace the element selected by filter [name=api]"},
+		{"through a scalar", "server.host.name", `cannot set "name" in a string`},
+		{"key on an array", "servers.name", `"name" is not a valid array index or filter`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var keys []string
+			if tc.path != "" {
+				keys = ParsePath(tc.path)
+			}
+			assert.EqualError(t, Set(newSetData(), keys, 1), tc.want)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package selector
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// sortModifier returns the field and direction of a sort segment, "@sort(field)" or
+// "@sortdesc(field)". An empty field ("@sort()") sorts by the elements themselves.
+func sortModifier(k string) (string, bool, bool) {
+	for _, m := range []struct {
+		prefix string
+		desc   bool
+	}{{"@sort(", false}, {"@sortdesc(", true}} {
+		if field, ok := strings.CutPrefix(k, m.prefix); ok && strings.HasSuffix(field, ")") {
+			return strings.TrimSpace(field[:len(field)-1]), m.desc, true
+		}
+	}
+	return "", false, false
+}
+
+// sortElems returns a copy of the array v stably sorted by field of its elements
+// (see fieldValue), descending with desc set. Elements without the field come last
+// in either direction. Numbers, strings and booleans sort by value; a field with
+// values of different kinds is an error.
+func sortElems(v any, field string, desc bool, opts Options) (any, error) {
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot sort a %T", v)
+	}
+
+	type keyed struct {
+		elem any
+		key  any
+		ok   bool
+	}
+	elems := make([]keyed, len(arr))
+	for i, e := range arr {
+		elems[i] = keyed{elem: e, key: e, ok: true}
+		if field != "" {
+			m, isMap := asMap(e)
+			if !isMap {
+				elems[i].ok = false
+				continue
+			}
+			elems[i].key, elems[i].ok = fieldValue(m, field, opts)
+		}
+	}
+
+	var err error
+	slices.SortStableFunc(elems, func(a, b keyed) int {
+		if !a.ok || !b.ok {
+			return cmpBool(!a.ok, !b.ok) // missing fields last
+		}
+		c, cerr := compareValues(a.key, b.key)
+		if cerr != nil && err == nil {
+			err = cerr
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot sort by %q: %w", field, err)
+	}
+
+	out := make([]any, len(elems))
+	for i, e := range elems {
+		out[i] = e.elem
+	}
+	return out, nil
+}
+
+// compareValues orders two numbers, strings or booleans of the same kind.
+func compareValues(a, b any) (int, error) {
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			return cmp.Compare(af, bf), nil
+		}
+	}
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), nil
+		}
+	case bool:
+		if bv, ok := b.(bool); ok {
+			return cmpBool(av, bv), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %T with %T", a, b)
+}
+
+// cmpBool orders false before true.
+func cmpBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// toFloat returns v as a float64 if it is a number.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,65 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNavigateSort(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"releases": []any{
+			map[string]any{"version": "1.1.0", "date": "2024-03-01", "downloads": 30, "meta": map[string]any{"rank": 2}},
+			map[string]any{"version": "1.2.0", "date": "2024-06-15", "downloads": 5.5, "meta": map[string]any{"rank": 1}},
+			map[string]any{"version": "1.0.0", "date": "2023-11-20", "downloads": 30},
+			map[string]any{"version": "0.9.0"},
+		},
+		"numbers": []any{3, 1.5, 2},
+		"mixed":   []any{map[string]any{"v": 1}, map[string]any{"v": "a"}},
+	}
+
+	cases := []struct {
+		name string
+		path string
+		want any
+	}{
+		{"newest first", "releases.@sortdesc(date).0.version", "1.2.0"},
+		{"oldest first", "releases.@sort(date).0.version", "1.0.0"},
+		{"missing field last ascending", "releases.@sort(date).#last.version", "0.9.0"},
+		{"missing field last descending", "releases.@sortdesc(date).#last.version", "0.9.0"},
+		{"stable for equal keys", "releases.@sortdesc(downloads).*.version", []any{"1.1.0", "1.0.0", "1.2.0", "0.9.0"}},
+		{"nested field", "releases.@sort(meta.rank).*.version", []any{"1.2.0", "1.1.0", "1.0.0", "0.9.0"}},
+		{"elements themselves", "numbers.@sort()", []any{1.5, 2, 3}},
+		{"elements descending", "numbers.@sortdesc( )", []any{3, 2, 1.5}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			val, err := Navigate(data, ParsePath(tc.path))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("source order kept", func(t *testing.T) {
+		t.Parallel()
+		_, err := Navigate(data, ParsePath("numbers.@sort()"))
+		require.NoError(t, err)
+		assert.Equal(t, []any{3, 1.5, 2}, data["numbers"])
+	})
+
+	t.Run("mixed kinds", func(t *testing.T) {
+		t.Parallel()
+		_, err := Navigate(data, ParsePath("mixed.@sort(v)"))
+		assert.ErrorContains(t, err, `cannot sort by "v": cannot compare`)
+	})
+
+	t.Run("not an array", func(t *testing.T) {
+		t.Parallel()
+		_, err := Navigate(data, ParsePath("releases.0.@sort(date)"))
+		assert.EqualError(t, err, "cannot sort a map[string]interface {}")
+	})
+}
@@ -0,0 +1,125 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PathError describes why ParsePathStrict rejected a path.
+type PathError struct {
+	Path   string // the path as given
+	Offset int    // byte offset of the problem in Path
+	Msg    string
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("invalid path %q at offset %d: %s", e.Path, e.Offset, e.Msg)
+}
+
+// ParsePathStrict is ParsePath for paths that should be checked before use, such as
+// those read from configuration. It returns a *PathError for an empty path or
+// segment ("a..", "a.", ".a"), unbalanced brackets, an invalid filter, a bracket
+// expression after a key that is not an index, slice or quoted key ("a[b]"), and
+// characters after a closing bracket ("a[0]b"), where ParsePath would return tokens
+// that only fail later in Navigate. A default ("|30s") is taken as is.
+func ParsePathStrict(s string) ([]string, error) {
+	if s == "" {
+		return nil, &PathError{Path: s, Msg: "empty path"}
+	}
+	for _, seg := range splitPath(s) {
+		if seg.text == RecursiveDescent || strings.HasPrefix(seg.text, DefaultSeparator) {
+			continue
+		}
+		if off, msg := checkSegment(seg.text); msg != "" {
+			return nil, &PathError{Path: s, Offset: seg.off + off, Msg: msg}
+		}
+	}
+	return ParsePath(s), nil
+}
+
+// checkSegment returns the offset in seg and a description of its first problem, or
+// an empty description if there is none.
+func checkSegment(seg string) (int, string) {
+	if seg == "" {
+		return 0, "empty segment"
+	}
+	if seg[0] == '{' {
+		if _, ok := projectionFields(seg); !ok {
+			return 0, fmt.Sprintf("invalid projection %s", seg)
+		}
+		return 0, ""
+	}
+	if seg[0] == '@' && strings.Contains(seg, "(") {
+		if _, _, ok := sortModifier(seg); !ok {
+			return 0, fmt.Sprintf("invalid modifier %s", seg)
+		}
+		return 0, ""
+	}
+	if isFilterToken(seg) {
+		if off, msg := checkBrackets(seg); msg != "" {
+			return off, msg
+		}
+		if _, err := parseFilter(seg); err != nil {
+			return 0, err.Error()
+		}
+		return 0, ""
+	}
+
+	i := strings.IndexByte(seg, '[')
+	if i < 0 {
+		i = len(seg)
+	}
+	if j := strings.IndexByte(seg[:i], ']'); j >= 0 {
+		return j, "unexpected ']'"
+	}
+	for i < len(seg) {
+		if seg[i] != '[' {
+			return i, fmt.Sprintf("unexpected %q after ']'", seg[i])
+		}
+		end, ok := quotedKeyEnd(seg, i)
+		if !ok {
+			if i+1 < len(seg) && (seg[i+1] == '"' || seg[i+1] == '\'') {
+				return i, "unterminated quoted key"
+			}
+			close := strings.IndexByte(seg[i:], ']')
+			if close < 0 {
+				return i, "unclosed '['"
+			}
+			end = i + close + 1
+			group := seg[i:end]
+			if _, ok := parseBracketIndices(group); !ok && !(i == 0 && isFilterToken(group)) {
+				if isFilterToken(group) {
+					return i, fmt.Sprintf("filter %s must be a segment of its own", group)
+				}
+				return i, fmt.Sprintf("%s is not an index, slice or quoted key", group)
+			}
+		}
+		i = end
+	}
+	return 0, ""
+}
+
+// checkBrackets checks that the brackets of a filter token are balanced, ignoring
+// quoted text.
+func checkBrackets(tok string) (int, string) {
+	depth := 0
+	for i := 0; i < len(tok); i++ {
+		switch tok[i] {
+		case '"', '\'':
+			if end := strings.IndexByte(tok[i+1:], tok[i]); end >= 0 {
+				i += end + 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 && i != len(tok)-1 {
+				return i + 1, fmt.Sprintf("unexpected %q after ']'", tok[i+1])
+			}
+		}
+	}
+	if depth > 0 {
+		return 0, "unclosed '['"
+	}
+	return 0, ""
+}
@@ -0,0 +1,90 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathStrict(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid paths", func(t *testing.T) {
+		t.Parallel()
+		for _, path := range []string{
+			"a.b", "servers[1].host", "matrix[0][2]", "[0].x", "servers[1:3]",
+			"servers.[name=api].port", `servers.[name="a]b"].x`, "items.[meta.labels[0]=x]",
+			`labels.["a.b"]`, "releases.@sortdesc(meta.date).0", "servers.0.{host, meta.name}", "..password", "a..b", "x|30s", "x|a..b[",
+		} {
+			tokens, err := ParsePathStrict(path)
+			require.NoError(t, err, path)
+			assert.Equal(t, ParsePath(path), tokens, path)
+		}
+	})
+
+	cases := []struct {
+		path   string
+		offset int
+		msg    string
+	}{
+		{"", 0, "empty path"},
+		{"a.", 2, "empty segment"},
+		{".a", 0, "empty segment"},
+		{"a...b", 3, "empty segment"},
+		{"a..", 3, "empty segment"},
+		{"|30s", 0, "empty segment"},
+		{"a]b", 1, "unexpected ']'"},
+		{"a[0", 1, "unclosed '['"},
+		{"servers.[name=api", 8, "unclosed '['"},
+		{"servers.[name=a]]", 16, `unexpected ']' after ']'`},
+		{"a[0]b", 4, `unexpected 'b' after ']'`},
+		{"x.[name=api]y", 12, `unexpected 'y' after ']'`},
+		{`a["x`, 1, "unterminated quoted key"},
+		{"a[b]", 1, "[b] is not an index, slice or quoted key"},
+		{"x.[]", 2, "[] is not an index, slice or quoted key"},
+		{"servers[name=api]", 7, "filter [name=api] must be a segment of its own"},
+		{"servers.[=api]", 8, `empty key in filter "=api"`},
+		{"releases.@sort(date.x", 9, "invalid modifier @sort(date.x"},
+		{"releases.@top(3)", 9, "invalid modifier @top(3)"},
+		{"servers.{host,}", 8, "invalid projection {host,}"},
+		{"servers.{host.port", 8, "invalid projection {host.port"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParsePathStrict(tc.path)
+			var perr *PathError
+			require.True(t, errors.As(err, &perr), "got %v", err)
+			assert.Equal(t, tc.path, perr.Path)
+			assert.Equal(t, tc.offset, perr.Offset)
+			assert.Equal(t, tc.msg, perr.Msg)
+		})
+	}
+
+	t.Run("error text", func(t *testing.T) {
+		t.Parallel()
+		_, err := ParsePathStrict("a[0]b")
+		assert.EqualError(t, err, `invalid path "a[0]b" at offset 4: unexpected 'b' after ']'`)
+	})
+}
+
+func FuzzParsePathStrict(f *testing.F) {
+	for _, seed := range []string{
+		"", "a.b", "a.", "a[0]b", "a[b]", "servers.[name=api].port", `a.["b.c"]`, "a..b", "x|y", "[[a]]",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		tokens, err := ParsePathStrict(s)
+		if err != nil {
+			var perr *PathError
+			require.True(t, errors.As(err, &perr))
+			assert.GreaterOrEqual(t, perr.Offset, 0)
+			assert.LessOrEqual(t, perr.Offset, len(s))
+			return
+		}
+		assert.Equal(t, ParsePath(s), tokens)
+	})
+}
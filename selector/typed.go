@@ -0,0 +1,112 @@
+package selector
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NavigateString is Navigate for a value used as a string. Strings are returned as is
+// and numbers and booleans in their usual text form; other values are an error.
+func NavigateString(data any, keys []string) (string, error) {
+	return navigateAs(data, keys, "a string", asString)
+}
+
+// NavigateInt is Navigate for a value used as an int. Integers, floats without a
+// fractional part and strings holding a base-10 integer are accepted.
+func NavigateInt(data any, keys []string) (int, error) {
+	return navigateAs(data, keys, "an int", asInt)
+}
+
+// NavigateBool is Navigate for a value used as a bool. Booleans and the strings "true"
+// and "false" (in any case) are accepted; as in filters, "1" and "0" are not.
+func NavigateBool(data any, keys []string) (bool, error) {
+	return navigateAs(data, keys, "a bool", asBool)
+}
+
+// NavigateStringSlice is Navigate for a list of strings. The value must be an array
+// whose elements NavigateString would accept; a path with several matches, such as
+// "servers.*.host", yields the matches.
+func NavigateStringSlice(data any, keys []string) ([]string, error) {
+	return navigateAs(data, keys, "a list of strings", asStringSlice)
+}
+
+// navigateAs navigates to a value and converts it with conv, describing the wanted
+// type as want in the error if it cannot.
+func navigateAs[T any](data any, keys []string, want string, conv func(any) (T, bool)) (T, error) {
+	v, err := Navigate(data, keys)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	out, ok := conv(v)
+	if !ok {
+		return out, fmt.Errorf("value at %q is %T, not %s", strings.Join(keys, "."), v, want)
+	}
+	return out, nil
+}
+
+func asString(v any) (string, bool) {
+	switch vv := v.(type) {
+	case string:
+		return vv, true
+	case bool:
+		return strconv.FormatBool(vv), true
+	case int:
+		return strconv.Itoa(vv), true
+	case int64:
+		return strconv.FormatInt(vv, 10), true
+	case uint64:
+		return strconv.FormatUint(vv, 10), true
+	case float64:
+		return strconv.FormatFloat(vv, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+func asInt(v any) (int, bool) {
+	switch vv := v.(type) {
+	case int:
+		return vv, true
+	case int64:
+		return int(vv), int64(int(vv)) == vv
+	case uint64:
+		return int(vv), vv <= math.MaxInt
+	case float64:
+		return int(vv), vv == math.Trunc(vv) && math.Abs(vv) <= 1<<53
+	case string:
+		i, err := strconv.Atoi(vv)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+func asBool(v any) (bool, bool) {
+	switch vv := v.(type) {
+	case bool:
+		return vv, true
+	case string:
+		switch strings.ToLower(vv) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+func asStringSlice(v any) ([]string, bool) {
+	a, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+	out := make([]string, len(a))
+	for i, e := range a {
+		if out[i], ok = asString(e); !ok {
+			return nil, false
+		}
+	}
+	return out, true
+}
@@ -0,0 +1,102 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNavigateTyped(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"server": map[string]any{
+			"host":    "localhost",
+			"port":    8080,
+			"weight":  float64(3),
+			"ratio":   0.5,
+			"retries": "5",
+			"debug":   true,
+			"tls":     "False",
+			"flag":    "1",
+		},
+		"tags":    []any{"a", "b"},
+		"ports":   []any{80, 443},
+		"servers": []any{map[string]any{"host": "x"}, map[string]any{"host": "y"}},
+		"mixed":   []any{"a", map[string]any{}},
+	}
+
+	t.Run("NavigateString", func(t *testing.T) {
+		t.Parallel()
+		s, err := NavigateString(data, ParsePath("server.host"))
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", s)
+
+		s, err = NavigateString(data, ParsePath("server.port"))
+		require.NoError(t, err)
+		assert.Equal(t, "8080", s)
+
+		s, err = NavigateString(data, ParsePath("server.ratio"))
+		require.NoError(t, err)
+		assert.Equal(t, "0.5", s)
+
+		_, err = NavigateString(data, ParsePath("server"))
+		assert.EqualError(t, err, `value at "server" is map[string]interface {}, not a string`)
+	})
+
+	t.Run("NavigateInt", func(t *testing.T) {
+		t.Parallel()
+		for path, want := range map[string]int{"server.port": 8080, "server.weight": 3, "server.retries": 5} {
+			i, err := NavigateInt(data, ParsePath(path))
+			require.NoError(t, err, path)
+			assert.Equal(t, want, i, path)
+		}
+
+		for _, path := range []string{"server.ratio", "server.host", "tags"} {
+			_, err := NavigateInt(data, ParsePath(path))
+			assert.ErrorContains(t, err, "not an int", path)
+		}
+	})
+
+	t.Run("NavigateBool", func(t *testing.T) {
+		t.Parallel()
+		b, err := NavigateBool(data, ParsePath("server.debug"))
+		require.NoError(t, err)
+		assert.True(t, b)
+
+		b, err = NavigateBool(data, ParsePath("server.tls"))
+		require.NoError(t, err)
+		assert.False(t, b)
+
+		_, err = NavigateBool(data, ParsePath("server.flag"))
+		assert.ErrorContains(t, err, "not a bool")
+	})
+
+	t.Run("NavigateStringSlice", func(t *testing.T) {
+		t.Parallel()
+		s, err := NavigateStringSlice(data, ParsePath("tags"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, s)
+
+		s, err = NavigateStringSlice(data, ParsePath("ports"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"80", "443"}, s)
+
+		s, err = NavigateStringSlice(data, ParsePath("servers.*.host"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"x", "y"}, s)
+
+		_, err = NavigateStringSlice(data, ParsePath("mixed"))
+		assert.ErrorContains(t, err, "not a list of strings")
+
+		_, err = NavigateStringSlice(data, ParsePath("server.host"))
+		assert.ErrorContains(t, err, "not a list of strings")
+	})
+
+	t.Run("Missing path", func(t *testing.T) {
+		t.Parallel()
+		_, err := NavigateInt(data, ParsePath("server.missing"))
+		assert.EqualError(t, err, `key "missing" not found`)
+	})
+}
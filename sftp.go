@@ -0,0 +1,353 @@
+package resolver
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SFTPResolver downloads a file over SFTP and applies the key selection of its
+// extension (.json, .yaml, .toml, .ini, .xml, otherwise key=value lines).
+// Format: "sftp:[user@]host[:port]/path//key", e.g.
+// "sftp:deploy@config.example.com/etc/app/config.yaml//db.host". The path is absolute;
+// start it with "~/" for a path relative to the remote home directory.
+//
+// The user defaults to $SFTP_USER, then $USER. Authentication tries the SSH agent
+// ($SSH_AUTH_SOCK), then KeyFiles (default $SFTP_IDENTITY_FILE or ~/.ssh/id_ed25519,
+// id_ecdsa and id_rsa), then $SFTP_PASSWORD. Host keys are checked against
+// HostKeyCallback, or the known_hosts file KnownHosts ($SFTP_KNOWN_HOSTS or
+// ~/.ssh/known_hosts); unknown hosts are rejected.
+type SFTPResolver struct {
+	KeyFiles        []string
+	KnownHosts      string
+	HostKeyCallback ssh.HostKeyCallback
+	Timeout         time.Duration // connection timeout; defaults to 30s
+}
+
+func (r *SFTPResolver) Resolve(value string) (string, error) {
+	return r.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context; cancelling ctx closes the connection.
+func (r *SFTPResolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	location, keyPath := splitFileAndKey(strings.TrimSpace(value))
+	addr, remote, ok := strings.Cut(location, "/")
+	if !ok || addr == "" || strings.Trim(remote, "/") == "" {
+		return "", fmt.Errorf("%w: sftp reference %q must be [user@]host[:port]/path", ErrBadPath, location)
+	}
+	user, host, found := strings.Cut(addr, "@")
+	if !found {
+		user, host = firstNonEmpty(os.Getenv("SFTP_USER"), os.Getenv("USER")), addr
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+	if rest, ok := strings.CutPrefix(remote, "~/"); ok {
+		remote = rest
+	} else {
+		remote = "/" + remote
+	}
+
+	data, err := r.download(ctx, user, host, remote)
+	if err != nil {
+		return "", err
+	}
+	return selectByExtension(remote, data, keyPath)
+}
+
+// Describe reports the resolver metadata.
+func (r *SFTPResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile, CapabilityRemote}}
+}
+
+// download fetches remote from host.
+func (r *SFTPResolver) download(ctx context.Context, user, host, remote string) ([]byte, error) {
+	cfg, closeAgent, err := r.clientConfig(user)
+	if err != nil {
+		return nil, err
+	}
+	defer closeAgent()
+
+	conn, err := (&net.Dialer{Timeout: cfg.Timeout}).DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("sftp connect to %s: %w", host, err)
+	}
+	defer conn.Close()                                      // nolint:errcheck
+	stop := context.AfterFunc(ctx, func() { conn.Close() }) // nolint:errcheck
+	defer stop()
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, host, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("sftp connect to %s: %w", host, sftpErr(ctx, err))
+	}
+	client := ssh.NewClient(c, chans, reqs)
+	defer client.Close() // nolint:errcheck
+
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("sftp session on %s: %w", host, err)
+	}
+	defer session.Close() // nolint:errcheck
+	w, err := session.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	rd, err := session.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		return nil, fmt.Errorf("sftp subsystem on %s: %w", host, err)
+	}
+
+	data, err := (&sftpConn{w: w, r: rd}).readFile(remote)
+	if err != nil {
+		return nil, fmt.Errorf("sftp %s:%s: %w", host, remote, sftpErr(ctx, err))
+	}
+	return data, nil
+}
+
+// sftpErr reports ctx's error instead of err once ctx is done, since err is then
+// only the result of closing the connection.
+func sftpErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// clientConfig builds the SSH configuration for user. The returned func releases the
+// agent connection.
+func (r *SFTPResolver) clientConfig(user string) (*ssh.ClientConfig, func(), error) {
+	hostKeys := r.HostKeyCallback
+	if hostKeys == nil {
+		path := firstNonEmpty(r.KnownHosts, os.Getenv("SFTP_KNOWN_HOSTS"), expandHome("~/.ssh/known_hosts"))
+		cb, err := knownhosts.New(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("sftp known hosts %q: %w", path, err)
+		}
+		hostKeys = cb
+	}
+
+	release := func() {}
+	var methods []ssh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+			release = func() { conn.Close() } // nolint:errcheck
+		}
+	}
+
+	keyFiles := r.KeyFiles
+	if len(keyFiles) == 0 {
+		if f := os.Getenv("SFTP_IDENTITY_FILE"); f != "" {
+			keyFiles = []string{f}
+		} else {
+			keyFiles = []string{"~/.ssh/id_ed25519", "~/.ssh/id_ecdsa", "~/.ssh/id_rsa"}
+		}
+	}
+	var signers []ssh.Signer
+	for _, f := range keyFiles {
+		pem, err := os.ReadFile(expandHome(f))
+		if err != nil {
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(pem)
+		if err != nil {
+			release()
+			return nil, nil, fmt.Errorf("sftp identity %q: %w", f, err)
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+	if pw := os.Getenv("SFTP_PASSWORD"); pw != "" {
+		methods = append(methods, ssh.Password(pw))
+	}
+
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = defaultHTTPClient.Timeout
+	}
+	return &ssh.ClientConfig{User: user, Auth: methods, HostKeyCallback: hostKeys, Timeout: timeout}, release, nil
+}
+
+// expandHome replaces a leading "~/" with the user's home directory.
+func expandHome(p string) string {
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return p
+}
+
+// SFTP protocol version 3 packets (draft-ietf-secsh-filexfer-02) needed to read a file.
+const (
+	sftpVersion     = 3
+	sftpReadFlag    = 1
+	sftpChunk       = 32 << 10
+	sftpStatusEOF   = 1
+	sftpNoSuchFile  = 2
+	sftpPermDenied  = 3
+	sftpMaxPacket   = 256 << 10
+	sftpTypeVersion = 2
+	sftpTypeStatus  = 101
+)
+
+type sftpInitMsg struct {
+	Version uint32 `sshtype:"1"`
+}
+
+type sftpOpenMsg struct {
+	ID     uint32 `sshtype:"3"`
+	Path   string
+	PFlags uint32
+	Attrs  uint32 // attribute flags; no attributes
+}
+
+type sftpCloseMsg struct {
+	ID     uint32 `sshtype:"4"`
+	Handle string
+}
+
+type sftpReadMsg struct {
+	ID     uint32 `sshtype:"5"`
+	Handle string
+	Offset uint64
+	Len    uint32
+}
+
+type sftpStatusMsg struct {
+	ID      uint32 `sshtype:"101"`
+	Code    uint32
+	Message string
+	Rest    []byte `ssh:"rest"`
+}
+
+type sftpHandleMsg struct {
+	ID     uint32 `sshtype:"102"`
+	Handle string
+}
+
+type sftpDataMsg struct {
+	ID   uint32 `sshtype:"103"`
+	Data []byte
+}
+
+// sftpConn is a minimal SFTP client that reads whole files.
+type sftpConn struct {
+	w  io.Writer
+	r  io.Reader
+	id uint32
+}
+
+func (c *sftpConn) send(msg any) error {
+	p := ssh.Marshal(msg)
+	buf := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(p)), uint32(len(p)))
+	_, err := c.w.Write(append(buf, p...))
+	return err
+}
+
+func (c *sftpConn) recv() ([]byte, error) {
+	var n uint32
+	if err := binary.Read(c.r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	if n == 0 || n > sftpMaxPacket {
+		return nil, fmt.Errorf("invalid SFTP packet length %d", n)
+	}
+	p := make([]byte, n)
+	if _, err := io.ReadFull(c.r, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// call sends msg and returns the reply, turning an error status into an error.
+func (c *sftpConn) call(msg any) ([]byte, error) {
+	if err := c.send(msg); err != nil {
+		return nil, err
+	}
+	p, err := c.recv()
+	if err != nil {
+		return nil, err
+	}
+	if p[0] == sftpTypeStatus {
+		var st sftpStatusMsg
+		if err := ssh.Unmarshal(p, &st); err != nil {
+			return nil, err
+		}
+		switch st.Code {
+		case sftpStatusEOF:
+			return nil, io.EOF
+		case sftpNoSuchFile:
+			return nil, ErrNotFound
+		case sftpPermDenied:
+			return nil, ErrForbidden
+		}
+		return nil, fmt.Errorf("SFTP error %d: %s", st.Code, st.Message)
+	}
+	return p, nil
+}
+
+func (c *sftpConn) nextID() uint32 {
+	c.id++
+	return c.id
+}
+
+// readFile reads the whole file at path, up to DefaultMaxOutputSize bytes.
+func (c *sftpConn) readFile(path string) ([]byte, error) {
+	if err := c.send(sftpInitMsg{Version: sftpVersion}); err != nil {
+		return nil, err
+	}
+	if p, err := c.recv(); err != nil {
+		return nil, err
+	} else if p[0] != sftpTypeVersion {
+		return nil, fmt.Errorf("unexpected SFTP packet type %d during init", p[0])
+	}
+
+	p, err := c.call(sftpOpenMsg{ID: c.nextID(), Path: path, PFlags: sftpReadFlag})
+	if err != nil {
+		return nil, err
+	}
+	var h sftpHandleMsg
+	if err := ssh.Unmarshal(p, &h); err != nil {
+		return nil, err
+	}
+	defer c.call(sftpCloseMsg{ID: c.nextID(), Handle: h.Handle}) // nolint:errcheck
+
+	var data []byte
+	for {
+		p, err := c.call(sftpReadMsg{ID: c.nextID(), Handle: h.Handle, Offset: uint64(len(data)), Len: sftpChunk})
+		if errors.Is(err, io.EOF) {
+			return data, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var d sftpDataMsg
+		if err := ssh.Unmarshal(p, &d); err != nil {
+			return nil, err
+		}
+		if len(d.Data) == 0 {
+			return data, nil
+		}
+		data = append(data, d.Data...)
+		if len(data) > DefaultMaxOutputSize {
+			return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrTooLarge, path, DefaultMaxOutputSize)
+		}
+	}
+}
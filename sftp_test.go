@@ -0,0 +1,163 @@
+package resolver
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// startSFTPServer serves files read-only over SFTP to user "deploy" with password
+// "pw" and returns its address and host key.
+func startSFTPServer(t *testing.T, files map[string]string) (string, ssh.PublicKey) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+
+	cfg := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == "deploy" && string(pass) == "pw" {
+				return nil, nil
+			}
+			return nil, errors.New("denied")
+		},
+	}
+	cfg.AddHostKey(signer)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() }) // nolint:errcheck
+
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(nc, cfg)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for nch := range chans {
+					ch, reqs, err := nch.Accept()
+					if err != nil {
+						continue
+					}
+					go func() {
+						for req := range reqs {
+							ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+							_ = req.Reply(ok, nil)
+							if ok {
+								go func() {
+									serveSFTP(ch, files)
+									ch.Close() // nolint:errcheck
+								}()
+							}
+						}
+					}()
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), signer.PublicKey()
+}
+
+// serveSFTP answers the requests sent by sftpConn.
+func serveSFTP(rw io.ReadWriter, files map[string]string) {
+	c := &sftpConn{w: rw, r: rw}
+	reply := func(msg any) { _ = c.send(msg) }
+	status := func(id, code uint32) { reply(sftpStatusMsg{ID: id, Code: code, Message: "status"}) }
+	for {
+		p, err := c.recv()
+		if err != nil {
+			return
+		}
+		switch p[0] {
+		case 1:
+			reply(struct {
+				Version uint32 `sshtype:"2"`
+			}{sftpVersion})
+		case 3:
+			var m sftpOpenMsg
+			_ = ssh.Unmarshal(p, &m)
+			if _, ok := files[m.Path]; !ok {
+				status(m.ID, sftpNoSuchFile)
+				continue
+			}
+			reply(sftpHandleMsg{ID: m.ID, Handle: m.Path})
+		case 4:
+			status(binary.BigEndian.Uint32(p[1:]), 0)
+		case 5:
+			var m sftpReadMsg
+			_ = ssh.Unmarshal(p, &m)
+			content := files[m.Handle]
+			if m.Offset >= uint64(len(content)) {
+				status(m.ID, sftpStatusEOF)
+				continue
+			}
+			end := min(int(m.Offset)+5, len(content)) // short reads exercise the loop
+			reply(sftpDataMsg{ID: m.ID, Data: []byte(content[m.Offset:end])})
+		}
+	}
+}
+
+func TestSFTPResolver_Resolve(t *testing.T) {
+	addr, hostKey := startSFTPServer(t, map[string]string{
+		"/etc/app/config.yaml": "db:\n  host: db.internal\n  port: 5432\n",
+		"app.env":              "TOKEN=abc\n",
+	})
+	t.Setenv("SSH_AUTH_SOCK", "")
+	t.Setenv("SFTP_PASSWORD", "pw")
+	r := &SFTPResolver{KeyFiles: []string{"/nonexistent"}, HostKeyCallback: ssh.FixedHostKey(hostKey)}
+
+	t.Run("Key from YAML", func(t *testing.T) {
+		val, err := r.Resolve("deploy@" + addr + "/etc/app/config.yaml//db.host")
+		require.NoError(t, err)
+		assert.Equal(t, "db.internal", val)
+	})
+
+	t.Run("Home-relative path", func(t *testing.T) {
+		val, err := r.Resolve("deploy@" + addr + "/~/app.env//TOKEN")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", val)
+	})
+
+	t.Run("Whole file", func(t *testing.T) {
+		val, err := r.Resolve("deploy@" + addr + "/~/app.env")
+		require.NoError(t, err)
+		assert.Equal(t, "TOKEN=abc", val)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve("deploy@" + addr + "/etc/nope.yaml//x")
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		t.Setenv("SFTP_PASSWORD", "nope")
+		_, err := r.Resolve("deploy@" + addr + "/etc/app/config.yaml//db.host")
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown host key", func(t *testing.T) {
+		other := &SFTPResolver{KeyFiles: []string{"/nonexistent"}, KnownHosts: "/dev/null"}
+		_, err := other.Resolve("deploy@" + addr + "/etc/app/config.yaml//db.host")
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid reference", func(t *testing.T) {
+		_, err := r.Resolve("deploy@" + addr)
+		assert.True(t, errors.Is(err, ErrBadPath))
+	})
+}
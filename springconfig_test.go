@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpringConfigResolver_Resolve(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if user, pass, _ := req.BasicAuth(); user != "cfg" || pass != "pw" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		label := "master"
+		switch req.URL.Path {
+		case "/billing/prod":
+		case "/billing/prod/v2":
+			label = "v2"
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+  "name": "billing",
+  "profiles": ["prod"],
+  "label": "` + label + `",
+  "propertySources": [
+    {"name": "billing-prod.yml", "source": {"server.port": 8443, "db.host": "db.prod"}},
+    {"name": "billing.yml", "source": {"server.port": 8080, "db.host": "localhost", "db.name": "billing-` + label + `"}}
+  ]
+}`))
+	}))
+	defer srv.Close()
+
+	r := &SpringConfigResolver{BaseURL: srv.URL, Username: "cfg", Password: "pw"}
+
+	t.Run("Profile overrides default", func(t *testing.T) {
+		val, err := r.Resolve("billing/prod//db.host")
+		require.NoError(t, err)
+		assert.Equal(t, "db.prod", val)
+
+		val, err = r.Resolve("billing/prod//server.port")
+		require.NoError(t, err)
+		assert.Equal(t, "8443", val)
+	})
+
+	t.Run("Default source", func(t *testing.T) {
+		val, err := r.Resolve("billing/prod//db.name")
+		require.NoError(t, err)
+		assert.Equal(t, "billing-master", val)
+	})
+
+	t.Run("Label", func(t *testing.T) {
+		val, err := r.Resolve("billing/prod/v2//db.name")
+		require.NoError(t, err)
+		assert.Equal(t, "billing-v2", val)
+	})
+
+	t.Run("Merged properties", func(t *testing.T) {
+		val, err := r.Resolve("billing/prod")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"server.port":8443,"db.host":"db.prod","db.name":"billing-master"}`, val)
+	})
+
+	t.Run("Credentials from environment", func(t *testing.T) {
+		t.Setenv("SPRING_CLOUD_CONFIG_URI", srv.URL)
+		t.Setenv("SPRING_CLOUD_CONFIG_USERNAME", "cfg")
+		t.Setenv("SPRING_CLOUD_CONFIG_PASSWORD", "pw")
+		reg := NewDefaultRegistry()
+		RegisterBackends(reg)
+		val, err := reg.ResolveVariable("spring-config:billing/prod//db.host")
+		require.NoError(t, err)
+		assert.Equal(t, "db.prod", val)
+	})
+
+	t.Run("Missing property", func(t *testing.T) {
+		_, err := r.Resolve("billing/prod//nope")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Unknown application", func(t *testing.T) {
+		_, err := r.Resolve("shipping/prod//db.host")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Wrong credentials", func(t *testing.T) {
+		bad := &SpringConfigResolver{BaseURL: srv.URL, Username: "cfg", Password: "nope"}
+		_, err := bad.Resolve("billing/prod//db.host")
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Bad reference", func(t *testing.T) {
+		_, err := r.Resolve("billing//db.host")
+		require.ErrorIs(t, err, ErrBadPath)
+	})
+}
@@ -0,0 +1,202 @@
+package resolver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SSHConfigResolver resolves the effective setting of an OpenSSH client config for a
+// host. Format: "sshconfig:<host>//<Keyword>", e.g. "sshconfig:myhost//HostName" or
+// "sshconfig:myhost//IdentityFile".
+//
+// As with ssh, the first value obtained for a keyword wins, from the Host blocks whose
+// patterns (with '*', '?' and '!' negation) match the host, in file order; "Match all"
+// applies to every host and other Match blocks are skipped. Include directives are
+// followed, relative to the config's directory. Keywords are case-insensitive.
+// HostName defaults to the host, with "%h" replaced by it. Without a keyword, all
+// settings are returned as JSON with lowercase keywords. Path defaults to
+// ~/.ssh/config.
+type SSHConfigResolver struct {
+	Path string
+}
+
+func (r *SSHConfigResolver) Resolve(value string) (string, error) {
+	host, keyword := splitFileAndKey(value)
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return "", fmt.Errorf("%w: empty host", ErrBadPath)
+	}
+
+	cfgPath := expandHome(os.ExpandEnv(firstNonEmpty(r.Path, "~/.ssh/config")))
+	l := &sshConfigLookup{host: strings.ToLower(host), dir: filepath.Dir(cfgPath), values: make(map[string]string)}
+	if err := l.parseFile(cfgPath, 0); err != nil {
+		return "", err
+	}
+
+	hostName := strings.ReplaceAll(firstNonEmpty(l.values["hostname"], host), "%h", host)
+	l.values["hostname"] = strings.ReplaceAll(hostName, "%%", "%")
+
+	if keyword == "" {
+		out, _ := json.Marshal(l.values)
+		return string(out), nil
+	}
+	v, ok := l.values[strings.ToLower(keyword)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s not set for host %q in %s", ErrNotFound, keyword, host, cfgPath)
+	}
+	return v, nil
+}
+
+// Describe reports the resolver metadata.
+func (r *SSHConfigResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// maxSSHConfigIncludeDepth bounds nested Include directives.
+const maxSSHConfigIncludeDepth = 16
+
+// sshConfigLookup collects the settings that apply to host.
+type sshConfigLookup struct {
+	host   string
+	dir    string            // directory of the config, for relative Include paths
+	values map[string]string // lowercase keyword -> first value
+}
+
+// parseFile applies the config file at p. Lines before its first Host or Match line
+// apply to every host.
+func (l *sshConfigLookup) parseFile(p string, depth int) error {
+	if depth > maxSSHConfigIncludeDepth {
+		return fmt.Errorf("%w: ssh config includes nested too deeply at %s", ErrBadPath, p)
+	}
+	data, err := readTextFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotFound, p)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return fmt.Errorf("%w: %s", ErrForbidden, p)
+		}
+		return fmt.Errorf("failed to read ssh config %q: %w", p, err)
+	}
+
+	active := true
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		keyword, args := splitSSHConfigLine(sc.Text())
+		if keyword == "" {
+			continue
+		}
+		switch keyword {
+		case "host":
+			active = l.matches(args)
+		case "match":
+			active = len(args) == 1 && strings.EqualFold(args[0], "all")
+		case "include":
+			if active {
+				if err := l.include(args, depth); err != nil {
+					return err
+				}
+			}
+		default:
+			if _, seen := l.values[keyword]; active && !seen && len(args) > 0 {
+				l.values[keyword] = strings.Join(args, " ")
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("failed to read ssh config %q: %w", p, err)
+	}
+	return nil
+}
+
+// include applies the files named by an Include directive; relative names are
+// relative to the directory of the config (~/.ssh by default), and glob patterns may
+// match no files.
+func (l *sshConfigLookup) include(patterns []string, depth int) error {
+	for _, pattern := range patterns {
+		pattern = expandHome(pattern)
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(l.dir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("%w: invalid Include pattern %q", ErrBadPath, pattern)
+		}
+		for _, m := range matches {
+			// Host blocks inside an included file end with it.
+			if err := l.parseFile(m, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// matches reports whether the host matches a Host line: at least one pattern
+// matches and no negated pattern does.
+func (l *sshConfigLookup) matches(patterns []string) bool {
+	matched := false
+	for _, p := range patterns {
+		negated := strings.HasPrefix(p, "!")
+		ok, _ := path.Match(strings.ToLower(strings.TrimPrefix(p, "!")), l.host)
+		switch {
+		case ok && negated:
+			return false
+		case ok:
+			matched = true
+		}
+	}
+	return matched
+}
+
+// splitSSHConfigLine returns the lowercase keyword and arguments of a config line
+// ("Keyword arg ..." or "Keyword=arg ..."); double quotes group an argument.
+// Blank and comment lines return "".
+func splitSSHConfigLine(line string) (string, []string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil
+	}
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return strings.ToLower(line), nil
+	}
+	keyword := strings.ToLower(line[:end])
+	rest := strings.TrimSpace(line[end:])
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, "="))
+
+	var (
+		args   []string
+		cur    strings.Builder
+		quoted bool
+		inArg  bool
+	)
+	for _, c := range rest {
+		switch {
+		case c == '"':
+			quoted = !quoted
+			inArg = true
+		case !quoted && (c == ' ' || c == '\t'):
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return keyword, args
+}
@@ -0,0 +1,86 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSSHConfig = `# user config
+Include conf.d/*.conf
+
+Host myhost
+  HostName 10.0.0.5
+  User deploy
+  IdentityFile ~/.ssh/deploy_ed25519
+
+Host *.internal !bastion.internal
+  ProxyJump bastion.internal
+  User ops
+
+Host alias
+  HostName %h.example.com
+
+Host=quoted
+  IdentityFile "/keys/with space"
+
+Match exec "true"
+  User never
+
+Host *
+  User fallback
+  IdentityFile ~/.ssh/id_ed25519
+  ServerAliveInterval 30
+`
+
+func TestSSHConfigResolver_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(cfg, []byte(testSSHConfig), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "conf.d"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "conf.d", "work.conf"), []byte("Host work\n  HostName work.example.com\n  Port 2222\n"), 0o600))
+	r := &SSHConfigResolver{Path: cfg}
+
+	cases := []struct {
+		name, ref, want string
+	}{
+		{"HostName", "myhost//HostName", "10.0.0.5"},
+		{"First value wins", "myhost//User", "deploy"},
+		{"Specific identity first", "myhost//IdentityFile", "~/.ssh/deploy_ed25519"},
+		{"Case-insensitive keyword", "myhost//hostname", "10.0.0.5"},
+		{"Wildcard block", "db.internal//ProxyJump", "bastion.internal"},
+		{"Negated pattern", "bastion.internal//User", "fallback"},
+		{"HostName defaults to host", "unknown//HostName", "unknown"},
+		{"Token in HostName", "alias//HostName", "alias.example.com"},
+		{"Equals and quotes", "quoted//IdentityFile", "/keys/with space"},
+		{"Match block skipped", "other//User", "fallback"},
+		{"Included file", "work//Port", "2222"},
+		{"Defaults", "work//ServerAliveInterval", "30"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(tc.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("All settings", func(t *testing.T) {
+		val, err := r.Resolve("work")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"hostname":"work.example.com","port":"2222","user":"fallback","identityfile":"~/.ssh/id_ed25519","serveraliveinterval":"30"}`, val)
+	})
+
+	t.Run("Unset keyword", func(t *testing.T) {
+		_, err := r.Resolve("myhost//ProxyJump")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing config", func(t *testing.T) {
+		_, err := (&SSHConfigResolver{Path: filepath.Join(dir, "nope")}).Resolve("myhost//HostName")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,55 @@
+package resolver
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// StdinResolver resolves values from a document piped to standard input.
+// Formats: "stdin:" returns the whole input; "stdin://<key>" selects key from it when
+// the input is JSON, YAML or dotenv (key=value lines), guessed from the content.
+//
+// Input is read once, on first use, and kept for the lifetime of the resolver (the
+// default registry's resolver lives as long as the process), so any number of
+// tokens can share one piped bundle. Reader defaults to os.Stdin.
+type StdinResolver struct {
+	Reader io.Reader
+
+	once sync.Once
+	data []byte
+	err  error
+}
+
+func (r *StdinResolver) Resolve(value string) (string, error) {
+	key := strings.TrimPrefix(strings.TrimSpace(value), "//")
+	r.once.Do(r.read)
+	if r.err != nil {
+		return "", r.err
+	}
+	return selectSniffed(r.data, key, "stdin")
+}
+
+// Describe reports the resolver metadata.
+func (r *StdinResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// read loads the input, up to DefaultMaxOutputSize bytes.
+func (r *StdinResolver) read() {
+	rd := r.Reader
+	if rd == nil {
+		rd = os.Stdin
+	}
+	data, err := io.ReadAll(io.LimitReader(rd, DefaultMaxOutputSize+1))
+	switch {
+	case err != nil:
+		r.err = fmt.Errorf("failed to read stdin: %w", err)
+	case len(data) > DefaultMaxOutputSize:
+		r.err = fmt.Errorf("%w: stdin is larger than %d bytes", ErrTooLarge, DefaultMaxOutputSize)
+	default:
+		r.data = data
+	}
+}
@@ -0,0 +1,57 @@
+package resolver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingReader counts Read calls.
+type countingReader struct {
+	r     *strings.Reader
+	reads int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.reads++
+	return c.r.Read(p)
+}
+
+func TestStdinResolver_Resolve(t *testing.T) {
+	t.Run("Keys from a JSON bundle", func(t *testing.T) {
+		in := &countingReader{r: strings.NewReader(`{"db":{"user":"app","password":"s3cr3t"}}`)}
+		r := &StdinResolver{Reader: in}
+
+		val, err := r.Resolve("//db.user")
+		require.NoError(t, err)
+		assert.Equal(t, "app", val)
+		reads := in.reads
+
+		val, err = r.Resolve("//db.password")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", val)
+		assert.Equal(t, reads, in.reads, "input must be read only once")
+	})
+
+	t.Run("Dotenv", func(t *testing.T) {
+		r := &StdinResolver{Reader: strings.NewReader("TOKEN=abc\nexport REGION=eu\n")}
+		val, err := r.Resolve("//REGION")
+		require.NoError(t, err)
+		assert.Equal(t, "eu", val)
+	})
+
+	t.Run("Whole input", func(t *testing.T) {
+		r := &StdinResolver{Reader: strings.NewReader("s3cr3t\n")}
+		val, err := r.Resolve("")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", val)
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		r := &StdinResolver{Reader: strings.NewReader("db:\n  user: app\n")}
+		_, err := r.Resolve("//db.nope")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
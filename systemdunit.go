@@ -0,0 +1,160 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SystemdUnitResolver resolves a setting from a systemd unit file.
+// Format: "systemd-unit:/path/unit//Section.Key", e.g.
+// "systemd-unit:/etc/systemd/system/app.service//Service.ExecStart".
+//
+// Drop-ins in "<unit>.d/*.conf" are applied after the unit, in name order. A key set
+// once is returned as a string; a key set several times (Environment=, ExecStartPre=,
+// ...) is returned as a JSON list, where an empty assignment clears the earlier
+// values as in systemd. Lines ending in a backslash continue on the next line.
+// "Section" alone returns the section as JSON. If no key is provided, returns the
+// whole unit file as a string.
+type SystemdUnitResolver struct{}
+
+func (r *SystemdUnitResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := readUnitFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	if keyPath == "" {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	sections := make(map[string]map[string][]string)
+	if err := parseUnitFile(data, filePath, sections); err != nil {
+		return "", err
+	}
+	dropIns, _ := filepath.Glob(filepath.Join(filePath+".d", "*.conf"))
+	for _, p := range dropIns { // Glob returns names in lexical order
+		data, err := readUnitFile(p)
+		if err != nil {
+			return "", err
+		}
+		if err := parseUnitFile(data, p, sections); err != nil {
+			return "", err
+		}
+	}
+
+	sectionName, key, hasKey := strings.Cut(keyPath, ".")
+	section, ok := sections[sectionName]
+	if !ok {
+		return "", fmt.Errorf("%w: section [%s] in %q", ErrNotFound, sectionName, filePath)
+	}
+	if !hasKey {
+		out := make(map[string]any, len(section))
+		for k, vals := range section {
+			if len(vals) > 0 {
+				out[k] = unitValue(vals)
+			}
+		}
+		jData, _ := json.Marshal(out)
+		return string(jData), nil
+	}
+
+	vals := section[key]
+	if len(vals) == 0 {
+		return "", fmt.Errorf("%w: %s in section [%s] of %q", ErrNotFound, key, sectionName, filePath)
+	}
+	if v, ok := unitValue(vals).(string); ok {
+		return v, nil
+	}
+	jData, _ := json.Marshal(vals)
+	return string(jData), nil
+}
+
+// Describe reports the resolver metadata.
+func (r *SystemdUnitResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// unitValue returns a single value as a string and several as a list.
+func unitValue(vals []string) any {
+	if len(vals) == 1 {
+		return vals[0]
+	}
+	return vals
+}
+
+// readUnitFile reads a unit or drop-in file.
+func readUnitFile(p string) ([]byte, error) {
+	data, err := readTextFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, p)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, fmt.Errorf("%w: %s", ErrForbidden, p)
+		}
+		return nil, fmt.Errorf("failed to read unit file %q: %w", p, err)
+	}
+	return data, nil
+}
+
+// parseUnitFile adds the assignments of a unit file to sections. source names the
+// file in error messages.
+func parseUnitFile(data []byte, source string, sections map[string]map[string][]string) error {
+	var section map[string][]string
+	lines := strings.Split(stripBOM(string(data)), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") || len(line) < 3 {
+				return fmt.Errorf("invalid unit file %q, line %d: bad section header", source, lineNo)
+			}
+			name := line[1 : len(line)-1]
+			if section = sections[name]; section == nil {
+				section = make(map[string][]string)
+				sections[name] = section
+			}
+			continue
+		}
+
+		for strings.HasSuffix(line, `\`) && i+1 < len(lines) {
+			i++
+			next := strings.TrimSpace(lines[i])
+			if next != "" && (next[0] == '#' || next[0] == ';') {
+				continue // comments inside a continuation are skipped
+			}
+			line = strings.TrimSpace(strings.TrimSuffix(line, `\`)) + " " + next
+		}
+		line = strings.TrimSuffix(line, `\`)
+
+		key, val, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("invalid unit file %q, line %d: expected Key=value", source, lineNo)
+		}
+		if section == nil {
+			return fmt.Errorf("invalid unit file %q, line %d: assignment outside a section", source, lineNo)
+		}
+		val = strings.TrimSpace(val)
+		if val == "" {
+			section[key] = nil
+			continue
+		}
+		section[key] = append(section[key], val)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testUnit = `[Unit]
+Description=Billing API
+After=network.target
+
+[Service]
+# main process
+ExecStart=/usr/bin/billing \
+    --port 8080 \
+    --verbose
+Environment=A=1
+Environment="B=two words"
+ExecStartPre=/bin/true
+ExecStartPre=
+ExecStartPre=/usr/bin/migrate
+User=billing
+
+[Install]
+WantedBy=multi-user.target
+`
+
+func TestSystemdUnitResolver_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "billing.service")
+	require.NoError(t, os.WriteFile(p, []byte(testUnit), 0o644))
+	require.NoError(t, os.MkdirAll(p+".d", 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(p+".d", "10-user.conf"), []byte("[Service]\nUser=svc\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(p+".d", "20-env.conf"), []byte("[Service]\nEnvironment=C=3\n"), 0o644))
+	r := &SystemdUnitResolver{}
+
+	cases := []struct {
+		name, key, want string
+	}{
+		{"Single value", "Unit.Description", "Billing API"},
+		{"Continuation", "Service.ExecStart", "/usr/bin/billing --port 8080 --verbose"},
+		{"Repeated key", "Service.Environment", `["A=1","\"B=two words\"","C=3"]`},
+		{"Empty assignment resets", "Service.ExecStartPre", "/usr/bin/migrate"},
+		{"Drop-in adds a value", "Service.User", `["billing","svc"]`},
+		{"Section", "Install", `{"WantedBy":"multi-user.target"}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("Whole file", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(testUnit), val)
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		_, err := r.Resolve(p + "//Service.Nope")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing section", func(t *testing.T) {
+		_, err := r.Resolve(p + "//Timer.OnCalendar")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		bad := filepath.Join(dir, "bad.service")
+		require.NoError(t, os.WriteFile(bad, []byte("[Service]\nnot an assignment\n"), 0o644))
+		_, err := r.Resolve(bad + "//Service.User")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(dir, "nope.service") + "//Unit.Description")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
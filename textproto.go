@@ -0,0 +1,148 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// TextprotoResolver resolves a value by parsing a text-format protobuf message and
+// extracting a field path. Format: "textproto:/path/file.txtpb//field1.field2.fieldN",
+// e.g. "textproto:/etc/app/config.txtpb//server.listen_port".
+//
+// The message type is looked up in a descriptor set (protoc --descriptor_set_out
+// --include_imports) from DescriptorSet or TEXTPROTO_DESCRIPTOR_SET. The type name
+// comes from Message, TEXTPROTO_MESSAGE, or a "# proto-message: pkg.Type" header
+// comment in the file. Fields use their proto names; repeated fields are lists and
+// maps are objects. The message is converted with the proto3 JSON mapping, so 64-bit
+// integers and enums are strings. Strings are returned as-is, other values as JSON.
+// If no path is provided, returns the whole file as a string.
+type TextprotoResolver struct {
+	DescriptorSet string // path of a serialized FileDescriptorSet
+	Message       string // fully-qualified message name, e.g. "acme.config.v1.Config"
+}
+
+func (r *TextprotoResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := readTextFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read textproto file %q: %w", filePath, err)
+	}
+	if keyPath == "" {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	desc, err := r.messageDescriptor(string(data))
+	if err != nil {
+		return "", err
+	}
+	msg := dynamicpb.NewMessage(desc)
+	if err := prototext.Unmarshal(data, msg); err != nil {
+		return "", fmt.Errorf("failed to parse textproto in %q: %w", filePath, err)
+	}
+	jData, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert textproto %q: %w", filePath, err)
+	}
+	var content map[string]any
+	if err := json.Unmarshal(jData, &content); err != nil {
+		return "", fmt.Errorf("failed to convert textproto %q: %w", filePath, err)
+	}
+
+	val, err := selectPath(content, keyPath)
+	if err != nil {
+		return "", fmt.Errorf("%w: field path %q in textproto %q: %v", ErrNotFound, keyPath, filePath, err)
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	out, _ := json.Marshal(val)
+	return string(out), nil
+}
+
+// Describe reports the resolver metadata.
+func (r *TextprotoResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// messageDescriptor loads the descriptor set and finds the message type of src.
+func (r *TextprotoResolver) messageDescriptor(src string) (protoreflect.MessageDescriptor, error) {
+	setPath := os.ExpandEnv(firstNonEmpty(r.DescriptorSet, os.Getenv("TEXTPROTO_DESCRIPTOR_SET")))
+	if setPath == "" {
+		return nil, fmt.Errorf("%w: no descriptor set (set TEXTPROTO_DESCRIPTOR_SET)", ErrBadPath)
+	}
+	name := firstNonEmpty(r.Message, os.Getenv("TEXTPROTO_MESSAGE"), textprotoHeader(src, "proto-message"))
+	if name == "" {
+		return nil, fmt.Errorf("%w: no message type (set TEXTPROTO_MESSAGE or a # proto-message: header)", ErrBadPath)
+	}
+
+	raw, err := os.ReadFile(setPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: descriptor set %s", ErrNotFound, setPath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, fmt.Errorf("%w: descriptor set %s", ErrForbidden, setPath)
+		}
+		return nil, fmt.Errorf("failed to read descriptor set %q: %w", setPath, err)
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse descriptor set %q: %w", setPath, err)
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set %q: %w", setPath, err)
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(strings.TrimPrefix(name, ".")))
+	if err != nil {
+		return nil, fmt.Errorf("%w: message %q in descriptor set %q", ErrNotFound, name, setPath)
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not a message", ErrBadPath, name)
+	}
+	return md, nil
+}
+
+// textprotoHeader returns the value of a "# name: value" comment in the leading
+// comment block of src, the convention of txtpbfmt and other proto tooling.
+func textprotoHeader(src, name string) string {
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		comment, ok := strings.CutPrefix(line, "#")
+		if !ok {
+			break
+		}
+		if k, v, ok := strings.Cut(comment, ":"); ok && strings.TrimSpace(k) == name {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
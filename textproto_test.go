@@ -0,0 +1,163 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// writeTestDescriptorSet writes a descriptor set for:
+//
+//	package acme.v1;
+//	message Server { string host = 1; int32 listen_port = 2; }
+//	message Config {
+//	  Server server = 1;
+//	  repeated string tags = 2;
+//	  map<string, string> labels = 3;
+//	  bool debug = 4;
+//	}
+func writeTestDescriptorSet(t *testing.T) string {
+	t.Helper()
+	field := func(name string, num int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(num),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	opt := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	rep := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	msg := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("acme/v1/config.proto"),
+		Package: proto.String("acme.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Server"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("host", 1, str, opt, ""),
+					field("listen_port", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, opt, ""),
+				},
+			},
+			{
+				Name: proto.String("Config"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("server", 1, msg, opt, ".acme.v1.Server"),
+					field("tags", 2, str, rep, ""),
+					field("labels", 3, msg, rep, ".acme.v1.Config.LabelsEntry"),
+					field("debug", 4, descriptorpb.FieldDescriptorProto_TYPE_BOOL, opt, ""),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("LabelsEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, str, opt, ""),
+						field("value", 2, str, opt, ""),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+			},
+		},
+	}
+	raw, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	require.NoError(t, err)
+	p := filepath.Join(t.TempDir(), "config.pb")
+	require.NoError(t, os.WriteFile(p, raw, 0o666))
+	return p
+}
+
+const testTextproto = `# proto-file: acme/v1/config.proto
+# proto-message: acme.v1.Config
+
+server {
+  host: "api.internal"
+  listen_port: 8443
+}
+tags: "a"
+tags: "b"
+labels { key: "team" value: "platform" }
+debug: true
+`
+
+func TestTextprotoResolver_Resolve(t *testing.T) {
+	set := writeTestDescriptorSet(t)
+	p := filepath.Join(t.TempDir(), "config.txtpb")
+	require.NoError(t, os.WriteFile(p, []byte(testTextproto), 0o666))
+	r := &TextprotoResolver{DescriptorSet: set}
+
+	cases := []struct {
+		name, key, want string
+	}{
+		{"Nested string", "server.host", "api.internal"},
+		{"Int32", "server.listen_port", "8443"},
+		{"Repeated index", "tags.1", "b"},
+		{"Map", "labels.team", "platform"},
+		{"Bool", "debug", "true"},
+		{"Message as JSON", "server", `{"host":"api.internal","listen_port":8443}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("Whole file", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.Contains(t, val, `host: "api.internal"`)
+	})
+
+	t.Run("Settings from environment", func(t *testing.T) {
+		t.Setenv("TEXTPROTO_DESCRIPTOR_SET", set)
+		t.Setenv("TEXTPROTO_MESSAGE", "acme.v1.Server")
+		srv := filepath.Join(t.TempDir(), "server.txtpb")
+		require.NoError(t, os.WriteFile(srv, []byte(`host: "db"`), 0o666))
+		val, err := (&TextprotoResolver{}).Resolve(srv + "//host")
+		require.NoError(t, err)
+		assert.Equal(t, "db", val)
+	})
+
+	t.Run("Missing field", func(t *testing.T) {
+		_, err := r.Resolve(p + "//server.nope")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Unknown message", func(t *testing.T) {
+		_, err := (&TextprotoResolver{DescriptorSet: set, Message: "acme.v1.Nope"}).Resolve(p + "//debug")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("No descriptor set", func(t *testing.T) {
+		t.Setenv("TEXTPROTO_DESCRIPTOR_SET", "")
+		_, err := (&TextprotoResolver{}).Resolve(p + "//debug")
+		assert.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Invalid textproto", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.txtpb")
+		require.NoError(t, os.WriteFile(bad, []byte("# proto-message: acme.v1.Config\nnope: 1\n"), 0o666))
+		_, err := r.Resolve(bad + "//debug")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "nope.txtpb") + "//debug")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,222 @@
+package resolver
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// TFStateResolver reads values from a Terraform state file.
+// Formats:
+//   - "tfstate:<path>//<selector>": a local state file
+//   - "tfstate:https://host/path//<selector>": a state file served over HTTP(S), e.g.
+//     by the Terraform http backend
+//   - "tfstate:s3:<bucket>/<key>//<selector>": a state file in S3
+//
+// The selector runs on the state document with every output replaced by its value, so
+// "outputs.db_endpoint" returns the db_endpoint output; other top-level fields
+// (resources, version, ...) are left as they are. Strings are returned as is, other
+// values as JSON. Without a selector, the outputs are returned as a JSON object.
+//
+// HTTP requests use basic auth when $TF_HTTP_USERNAME is set (with $TF_HTTP_PASSWORD),
+// matching the Terraform http backend. S3 requests are signed with AWS Signature V4
+// from $AWS_ACCESS_KEY_ID, $AWS_SECRET_ACCESS_KEY and $AWS_SESSION_TOKEN (unsigned
+// without credentials). Region defaults to $AWS_REGION, $AWS_DEFAULT_REGION or
+// us-east-1, and S3Endpoint to $AWS_ENDPOINT_URL_S3 or $AWS_ENDPOINT_URL; with an
+// endpoint, path-style addressing is used.
+type TFStateResolver struct {
+	Region     string
+	S3Endpoint string
+	Client     *http.Client
+	// Clock dates S3 request signatures; defaults to SystemClock.
+	Clock Clock
+}
+
+func (r *TFStateResolver) Resolve(value string) (string, error) {
+	return r.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context bounding HTTP and S3 requests.
+func (r *TFStateResolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	source, key := splitTFStateRef(strings.TrimSpace(value))
+	if source == "" {
+		return "", fmt.Errorf("%w: empty tfstate source", ErrBadPath)
+	}
+
+	data, err := r.read(ctx, source)
+	if err != nil {
+		return "", err
+	}
+	state, err := tfstateView(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse Terraform state %q: %w", source, err)
+	}
+
+	if key == "" {
+		out, _ := json.Marshal(state["outputs"])
+		return string(out), nil
+	}
+	val, err := selectPath(state, key)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q in Terraform state %q: %v", ErrNotFound, key, source, err)
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	out, _ := json.Marshal(val)
+	return string(out), nil
+}
+
+// Describe reports the resolver metadata. Outputs marked sensitive in Terraform are
+// stored in clear text in the state, so all values are treated as sensitive.
+func (r *TFStateResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilitySelector, CapabilityRemote}}
+}
+
+// splitTFStateRef splits value into source and selector. The "//" of a URL scheme is
+// not taken as the separator.
+func splitTFStateRef(value string) (source, key string) {
+	for _, scheme := range []string{"https://", "http://"} {
+		if rest, ok := strings.CutPrefix(value, scheme); ok {
+			src, key := splitFileAndKey(rest)
+			return scheme + src, key
+		}
+	}
+	return splitFileAndKey(value)
+}
+
+// read loads the raw state document from source.
+func (r *TFStateResolver) read(ctx context.Context, source string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, fmt.Errorf("build Terraform state request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+		if user := os.Getenv("TF_HTTP_USERNAME"); user != "" {
+			req.SetBasicAuth(user, os.Getenv("TF_HTTP_PASSWORD"))
+		}
+		return doHTTP(r.Client, req)
+	case strings.HasPrefix(source, "s3:"):
+		return r.readS3(ctx, strings.TrimPrefix(source, "s3:"))
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, source)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, fmt.Errorf("%w: %s", ErrForbidden, source)
+		}
+		return nil, fmt.Errorf("failed to read %q: %w", source, err)
+	}
+	return data, nil
+}
+
+// readS3 fetches bucket/key from S3.
+func (r *TFStateResolver) readS3(ctx context.Context, ref string) ([]byte, error) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(ref, "/"), "/")
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("%w: S3 state reference %q must be bucket/key", ErrBadPath, ref)
+	}
+	region := firstNonEmpty(r.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1")
+
+	var endpoint string
+	if base := firstNonEmpty(r.S3Endpoint, os.Getenv("AWS_ENDPOINT_URL_S3"), os.Getenv("AWS_ENDPOINT_URL")); base != "" {
+		endpoint = strings.TrimRight(base, "/") + "/" + escapeBlobPath(bucket) + "/" + escapeBlobPath(key)
+	} else {
+		endpoint = "https://" + bucket + ".s3." + region + ".amazonaws.com/" + escapeBlobPath(key)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build S3 request: %w", err)
+	}
+	if id := os.Getenv("AWS_ACCESS_KEY_ID"); id != "" {
+		clock := r.Clock
+		if clock == nil {
+			clock = SystemClock
+		}
+		signS3Request(req, id, os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN"), region, clock.Now())
+	}
+	return doHTTP(r.Client, req)
+}
+
+// signS3Request adds an AWS Signature V4 Authorization header to a body-less S3
+// request.
+func signS3Request(req *http.Request, accessKey, secretKey, sessionToken, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	const payloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" // sha256("")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{"host": req.URL.Host, "x-amz-content-sha256": payloadHash, "x-amz-date": amzDate}
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+		headers = append(headers, "x-amz-security-token")
+		values["x-amz-security-token"] = sessionToken
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		canonicalHeaders.WriteString(h + ":" + values[h] + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data)) // nolint:errcheck
+	return h.Sum(nil)
+}
+
+// tfstateView parses a state document and replaces each output with its value.
+func tfstateView(data []byte) (map[string]any, error) {
+	var state map[string]any
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	outputs, _ := state["outputs"].(map[string]any)
+	values := make(map[string]any, len(outputs))
+	for name, o := range outputs {
+		if m, ok := o.(map[string]any); ok {
+			values[name] = m["value"]
+		}
+	}
+	state["outputs"] = values
+	return state, nil
+}
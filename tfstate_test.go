@@ -0,0 +1,140 @@
+package resolver
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTFState = `{
+  "version": 4,
+  "terraform_version": "1.7.0",
+  "outputs": {
+    "db_endpoint": {"value": "db.internal:5432", "type": "string"},
+    "subnet_ids": {"value": ["subnet-a", "subnet-b"], "type": ["list", "string"]},
+    "db_password": {"value": "s3cr3t", "type": "string", "sensitive": true}
+  },
+  "resources": []
+}`
+
+func TestTFStateResolver_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "terraform.tfstate")
+	require.NoError(t, os.WriteFile(path, []byte(testTFState), 0o600))
+
+	r := &TFStateResolver{}
+
+	t.Run("Local output", func(t *testing.T) {
+		val, err := r.Resolve(path + "//outputs.db_endpoint")
+		require.NoError(t, err)
+		assert.Equal(t, "db.internal:5432", val)
+	})
+
+	t.Run("Non-string output", func(t *testing.T) {
+		val, err := r.Resolve(path + "//outputs.subnet_ids")
+		require.NoError(t, err)
+		assert.Equal(t, `["subnet-a","subnet-b"]`, val)
+
+		val, err = r.Resolve(path + "//outputs.subnet_ids.1")
+		require.NoError(t, err)
+		assert.Equal(t, "subnet-b", val)
+	})
+
+	t.Run("Other fields", func(t *testing.T) {
+		val, err := r.Resolve(path + "//terraform_version")
+		require.NoError(t, err)
+		assert.Equal(t, "1.7.0", val)
+	})
+
+	t.Run("All outputs", func(t *testing.T) {
+		val, err := r.Resolve(path)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"db_endpoint":"db.internal:5432","subnet_ids":["subnet-a","subnet-b"],"db_password":"s3cr3t"}`, val)
+	})
+
+	t.Run("Missing output", func(t *testing.T) {
+		_, err := r.Resolve(path + "//outputs.nope")
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(dir, "nope.tfstate") + "//outputs.db_endpoint")
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("Invalid state", func(t *testing.T) {
+		bad := filepath.Join(dir, "bad.tfstate")
+		require.NoError(t, os.WriteFile(bad, []byte("not json"), 0o600))
+		_, err := r.Resolve(bad + "//outputs.db_endpoint")
+		assert.Error(t, err)
+	})
+}
+
+func TestTFStateResolver_HTTP(t *testing.T) {
+	t.Setenv("TF_HTTP_USERNAME", "tf")
+	t.Setenv("TF_HTTP_PASSWORD", "pw")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if user, pass, _ := req.BasicAuth(); user != "tf" || pass != "pw" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.URL.Path != "/state/prod" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(testTFState))
+	}))
+	defer srv.Close()
+
+	r := &TFStateResolver{}
+
+	val, err := r.Resolve(srv.URL + "/state/prod//outputs.db_endpoint")
+	require.NoError(t, err)
+	assert.Equal(t, "db.internal:5432", val)
+
+	_, err = r.Resolve(srv.URL + "/state/dev//outputs.db_endpoint")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestTFStateResolver_S3(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+	t.Setenv("AWS_REGION", "eu-central-1")
+
+	var auth, token string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		auth = req.Header.Get("Authorization")
+		token = req.Header.Get("X-Amz-Security-Token")
+		if req.URL.Path != "/tf-state/prod/network.tfstate" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(testTFState))
+	}))
+	defer srv.Close()
+
+	clock := NewManualClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	r := &TFStateResolver{S3Endpoint: srv.URL, Clock: clock}
+
+	val, err := r.Resolve("s3:tf-state/prod/network.tfstate//outputs.subnet_ids.0")
+	require.NoError(t, err)
+	assert.Equal(t, "subnet-a", val)
+	assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240501/eu-central-1/s3/aws4_request, "), auth)
+	assert.Contains(t, auth, "SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token, Signature=")
+	assert.Equal(t, "session", token)
+
+	_, err = r.Resolve("s3:tf-state/prod/missing.tfstate//outputs.db_endpoint")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = r.Resolve("s3:tf-state//outputs.db_endpoint")
+	assert.True(t, errors.Is(err, ErrBadPath))
+}
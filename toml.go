@@ -1,20 +1,41 @@
 package resolver
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/containeroo/resolver/selector"
 	"github.com/pelletier/go-toml/v2"
 )
 
 // TOMLResolver resolves a value by loading a TOML file and extracting a nested key.
 // Format: "toml:/path/file.toml//key1.key2.keyN"
 // If no key is provided, returns the entire TOML file as a string.
-type TOMLResolver struct{}
+//
+// Non-string values are re-encoded as TOML by default; the fields below change how
+// they render.
+type TOMLResolver struct {
+	// RFC3339Datetimes renders a selected date-time as RFC 3339 with an offset; local
+	// date-times (without an offset) are taken as UTC.
+	RFC3339Datetimes bool
+	// RawNumbers renders a selected integer or float as Go formats it ("3" instead of
+	// "3.0", "1e+20" instead of "100000000000000000000.0").
+	RawNumbers bool
+	// InlineTables renders a selected table or array on one line in inline syntax
+	// ("{a = 1, b = 'x'}") instead of as multi-line tables.
+	InlineTables bool
+	// CaseInsensitiveKeys matches keys of dotted paths ignoring case when no key
+	// matches exactly (see selector.Options).
+	CaseInsensitiveKeys bool
+	// UniqueFilters makes a filter of a dotted path that matches several elements an
+	// ErrBadPath error instead of selecting the first (see selector.FilterUnique).
+	UniqueFilters bool
+}
 
 func (r *TOMLResolver) Resolve(value string) (string, error) {
 	filePath, keyPath := splitFileAndKey(value)
@@ -24,7 +45,7 @@ func (r *TOMLResolver) Resolve(value string) (string, error) {
 		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := readTextFile(filePath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
@@ -35,30 +56,81 @@ func (r *TOMLResolver) Resolve(value string) (string, error) {
 		return "", fmt.Errorf("failed to read TOML file %q: %w", filePath, err)
 	}
 
+	return r.selectValue(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *TOMLResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectTOML returns the value at keyPath in TOML data, or the whole document if keyPath is empty.
+// source names the document in error messages.
+func selectTOML(data []byte, keyPath, source string) (string, error) {
+	return (&TOMLResolver{}).selectValue(data, keyPath, source)
+}
+
+// selectValue is selectTOML with the options of r.
+func (r *TOMLResolver) selectValue(data []byte, keyPath, source string) (string, error) {
 	// Validate TOML syntax by decoding
 	var validationTarget struct{}
 	if err := toml.Unmarshal(data, &validationTarget); err != nil {
-		return "", fmt.Errorf("failed to parse TOML in %q: %w", filePath, err)
+		return "", fmt.Errorf("failed to parse TOML in %q: %w", source, err)
 	}
 
 	// Decode into navigable structure
 	var content map[string]any
 	if err := toml.Unmarshal(data, &content); err != nil {
-		return "", fmt.Errorf("failed to parse TOML in %q: %w", filePath, err)
+		return "", fmt.Errorf("failed to parse TOML in %q: %w", source, err)
 	}
 
 	if keyPath == "" {
 		return strings.TrimSpace(string(data)), nil
 	}
 
-	val, err := selector.Navigate(content, selector.ParsePath(keyPath))
+	val, err := selectPathWith(content, keyPath, selectorOptions(r.CaseInsensitiveKeys, r.UniqueFilters))
 	if err != nil {
-		return "", fmt.Errorf("%w: key path %q in TOML %q: %v", ErrNotFound, keyPath, filePath, err)
+		return "", fmt.Errorf("%w: key path %q in TOML %q: %v", selectErr(err), keyPath, source, err)
 	}
 
 	if strVal, ok := val.(string); ok {
 		return strVal, nil
 	}
+	return r.render(val)
+}
+
+// render encodes a selected non-string value.
+func (r *TOMLResolver) render(val any) (string, error) {
+	switch v := val.(type) {
+	case time.Time:
+		if r.RFC3339Datetimes {
+			return v.Format(time.RFC3339Nano), nil
+		}
+	case toml.LocalDateTime:
+		if r.RFC3339Datetimes {
+			return v.AsTime(time.UTC).Format(time.RFC3339Nano), nil
+		}
+	case int64:
+		if r.RawNumbers {
+			return strconv.FormatInt(v, 10), nil
+		}
+	case float64:
+		if r.RawNumbers {
+			return strconv.FormatFloat(v, 'g', -1, 64), nil
+		}
+	case map[string]any, []any:
+		if r.InlineTables {
+			// The encoder only inlines values below the top level, so encode the
+			// value under a placeholder key and strip it again.
+			var buf bytes.Buffer
+			enc := toml.NewEncoder(&buf)
+			enc.SetTablesInline(true)
+			if err := enc.Encode(map[string]any{"v": v}); err != nil {
+				return "", fmt.Errorf("failed to encode TOML value: %w", err)
+			}
+			return strings.TrimPrefix(strings.TrimSpace(buf.String()), "v = "), nil
+		}
+	}
 
 	tomlVal, err := toml.Marshal(val)
 	if err != nil {
@@ -144,6 +144,17 @@ host = "localhost"
 		require.Error(t, err)
 	})
 
+	t.Run("Case-insensitive keys", func(t *testing.T) {
+		p := createTOMLTestFile(t, "[Server]\nTimeout = \"30s\"\n")
+
+		_, err := r.Resolve(p + "//server.timeout")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		val, err := (&TOMLResolver{CaseInsensitiveKeys: true}).Resolve(p + "//server.timeout")
+		require.NoError(t, err)
+		assert.Equal(t, "30s", val)
+	})
+
 	t.Run("Non-existing file", func(t *testing.T) {
 		_, err := r.Resolve(filepath.Join(t.TempDir(), "nonexistent.toml"))
 		require.Error(t, err)
@@ -159,3 +170,49 @@ host = "localhost"
 		require.Error(t, err)
 	})
 }
+
+func TestTOMLResolver_Options(t *testing.T) {
+	content := `
+offset = 1979-05-27T07:32:00.5-08:00
+local = 1979-05-27T07:32:00
+day = 1979-05-27
+count = 443
+ratio = 3.0
+big = 1e20
+
+[server]
+host = "localhost"
+ports = [80, 443]
+
+[[servers]]
+host = "example.com"
+`
+	p := createTOMLTestFile(t, content)
+
+	cases := []struct {
+		name     string
+		r        *TOMLResolver
+		key      string
+		expected string
+	}{
+		{"Default datetime", &TOMLResolver{}, "local", "1979-05-27T07:32:00"},
+		{"RFC3339 offset datetime", &TOMLResolver{RFC3339Datetimes: true}, "offset", "1979-05-27T07:32:00.5-08:00"},
+		{"RFC3339 local datetime", &TOMLResolver{RFC3339Datetimes: true}, "local", "1979-05-27T07:32:00Z"},
+		{"RFC3339 local date unchanged", &TOMLResolver{RFC3339Datetimes: true}, "day", "1979-05-27"},
+		{"Default float", &TOMLResolver{}, "ratio", "3.0"},
+		{"Raw float", &TOMLResolver{RawNumbers: true}, "ratio", "3"},
+		{"Raw big float", &TOMLResolver{RawNumbers: true}, "big", "1e+20"},
+		{"Raw integer", &TOMLResolver{RawNumbers: true}, "count", "443"},
+		{"Inline table", &TOMLResolver{InlineTables: true}, "server", "{host = 'localhost', ports = [80, 443]}"},
+		{"Inline array of tables", &TOMLResolver{InlineTables: true}, "servers", "[{host = 'example.com'}]"},
+		{"Multi-line table", &TOMLResolver{}, "server", "host = 'localhost'\nports = [80, 443]"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := tc.r.Resolve(p + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, val)
+		})
+	}
+}
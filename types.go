@@ -1,7 +1,10 @@
 package resolver
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 )
@@ -29,20 +32,80 @@ const (
 
 // Scheme prefixes (include trailing colon so CutPrefix is unambiguous).
 const (
-	envPrefix  string = "env:"
-	filePrefix string = "file:"
-	iniPrefix  string = "ini:"
-	jsonPrefix string = "json:"
-	tomlPrefix string = "toml:"
-	yamlPrefix string = "yaml:"
+	archivePrefix      string = "archive:"
+	avroPrefix         string = "avro:"
+	azblobPrefix       string = "azblob:"
+	binPrefix          string = "bin:"
+	binBase64Prefix    string = "bin+base64:"
+	binBase64URLPrefix string = "bin+base64url:"
+	binHexPrefix       string = "bin+hex:"
+	bsonPrefix         string = "bson:"
+	cborPrefix         string = "cbor:"
+	dockerSecPrefix    string = "docker-secret:"
+	dotenvPrefix       string = "dotenv:"
+	envPrefix          string = "env:"
+	fdPrefix           string = "fd:"
+	filePrefix         string = "file:"
+	gitConfigPrefix    string = "gitconfig:"
+	gitPrefix          string = "git:"
+	hclPrefix          string = "hcl:"
+	htpasswdPrefix     string = "htpasswd:"
+	infisicalPrefix    string = "infisical:"
+	iniPrefix          string = "ini:"
+	jsonPrefix         string = "json:"
+	jsoncPrefix        string = "jsonc:"
+	k8sSAPrefix        string = "k8s-sa:"
+	keePassPrefix      string = "keepass:"
+	keyringPrefix      string = "keyring:"
+	keystorePrefix     string = "keystore:"
+	msgpackPrefix      string = "msgpack:"
+	natsKVPrefix       string = "natskv:"
+	natsKVAltPrefix    string = "nats-kv:"
+	ndjsonPrefix       string = "ndjson:"
+	netrcPrefix        string = "netrc:"
+	oauth2Prefix       string = "oauth2:"
+	passPrefix         string = "pass:"
+	pemPrefix          string = "pem:"
+	plistPrefix        string = "plist:"
+	secretSvcPrefix    string = "secretservice:"
+	sftpPrefix         string = "sftp:"
+	springConfigPrefix string = "spring-config:"
+	sshConfigPrefix    string = "sshconfig:"
+	stdinPrefix        string = "stdin:"
+	systemdUnitPrefix  string = "systemd-unit:"
+	textprotoPrefix    string = "textproto:"
+	tfstatePrefix      string = "tfstate:"
+	tomlPrefix         string = "toml:"
+	vaultTransitPrefix string = "vault-transit:"
+	x509Prefix         string = "x509:"
+	xlsxPrefix         string = "xlsx:"
+	xmlPrefix          string = "xml:"
+	yamlPrefix         string = "yaml:"
+	zkPrefix           string = "zk:"
 )
 
 // Registry holds an ordered set of (scheme -> Resolver) mappings; it is concurrency-safe.
 type Registry struct {
-	mu      sync.RWMutex        // guards all fields below
-	order   []string            // stable resolution order (schemes incl. trailing ':')
-	backing map[string]Resolver // scheme -> resolver
-	unknown UnknownSchemePolicy // policy for unknown schemes
+	mu         sync.RWMutex        // guards all fields below
+	order      []string            // stable resolution order (schemes incl. trailing ':')
+	backing    map[string]Resolver // scheme -> resolver
+	unknown    UnknownSchemePolicy // policy for unknown schemes
+	unknownSet bool                // unknown was set explicitly (children stop inheriting)
+	maxOutput  int                 // interpolation output cap in bytes; 0 inherits/defaults, <0 unlimited
+	parent     *Registry           // consulted for schemes not registered here; nil for roots
+	hooks      *Hooks              // nil inherits the parent's hooks
+	clock      Clock               // nil inherits the parent's clock
+	random     io.Reader           // nil inherits the parent's randomness source
+
+	placeholders    bool // sensitive/remote schemes return placeholders (see SetPlaceholderMode)
+	placeholdersSet bool // placeholders was set explicitly (children stop inheriting)
+
+	masking    bool                // remember sensitive values for Mask (see SetMasking)
+	maskingSet bool                // masking was set explicitly (children stop inheriting)
+	secrets    map[string]struct{} // sensitive values resolved through this registry
+
+	defaults    string // defaults document for file-based lookups (see SetDefaultsFile)
+	defaultsSet bool   // defaults was set explicitly (children stop inheriting)
 }
 
 // NewRegistry creates an empty Registry.
@@ -52,7 +115,11 @@ func NewRegistry() *Registry {
 	}
 }
 
-// NewDefaultRegistry returns a Registry with built-in resolvers pre-registered.
+// NewDefaultRegistry returns a Registry with the built-in env:, json:, yaml:, ini:, file:
+// and toml: resolvers pre-registered. Other schemes are opt-in, so that values with
+// their prefixes pass through unchanged unless a program asks for them:
+// NewExtendedRegistry adds the resolvers for other local file formats, and
+// RegisterBackends the ones that contact a service, run a program or read stdin.
 func NewDefaultRegistry() *Registry {
 	r := NewRegistry()
 	r.Register(envPrefix, &EnvResolver{})
@@ -64,6 +131,76 @@ func NewDefaultRegistry() *Registry {
 	return r
 }
 
+// NewExtendedRegistry returns NewDefaultRegistry with the resolvers for the other
+// file formats added (xml:, hcl:, dotenv:, pem:, keystore:, ...). Like the defaults,
+// they only read the local files they are given.
+func NewExtendedRegistry() *Registry {
+	r := NewDefaultRegistry()
+	r.Register(dockerSecPrefix, &DockerSecretResolver{})
+	r.Register(keePassPrefix, &KeePassResolver{})
+	r.Register(x509Prefix, &X509Resolver{})
+	r.Register(xmlPrefix, &XMLResolver{})
+	r.Register(hclPrefix, &HCLResolver{})
+	r.Register(dotenvPrefix, &DotenvResolver{})
+	r.Register(plistPrefix, &PlistResolver{})
+	r.Register(msgpackPrefix, &MsgpackResolver{})
+	r.Register(cborPrefix, &CBORResolver{})
+	r.Register(bsonPrefix, &BSONResolver{})
+	r.Register(textprotoPrefix, &TextprotoResolver{})
+	r.Register(jsoncPrefix, &JSONCResolver{})
+	r.Register(ndjsonPrefix, &NDJSONResolver{})
+	r.Register(archivePrefix, &ArchiveResolver{})
+	r.Register(sshConfigPrefix, &SSHConfigResolver{})
+	r.Register(gitConfigPrefix, &GitConfigResolver{})
+	r.Register(netrcPrefix, &NetrcResolver{})
+	r.Register(htpasswdPrefix, &HtpasswdResolver{})
+	r.Register(systemdUnitPrefix, &SystemdUnitResolver{})
+	r.Register(pemPrefix, &PEMResolver{})
+	r.Register(keystorePrefix, &KeystoreResolver{})
+	r.Register(binPrefix, &BinaryResolver{})
+	r.Register(binBase64Prefix, &BinaryResolver{Encoding: "base64"})
+	r.Register(binBase64URLPrefix, &BinaryResolver{Encoding: "base64url"})
+	r.Register(binHexPrefix, &BinaryResolver{Encoding: "hex"})
+	r.Register(xlsxPrefix, &XLSXResolver{})
+	r.Register(avroPrefix, &AvroResolver{})
+	return r
+}
+
+// RegisterBackends registers the built-in resolvers with side effects beyond reading a
+// local file: network services (infisical:, vault-transit:, git:, sftp:, ...), the
+// pass: and keyring: stores, which run gpg or talk to the session bus, and stdin:,
+// which blocks until standard input is closed. Register them only in programs that
+// resolve values from trusted sources; exec:, fd:, mem: and plugin: still need an
+// instance of their own.
+func RegisterBackends(r *Registry) {
+	r.Register(infisicalPrefix, &InfisicalResolver{})
+	r.Register(natsKVPrefix, &NATSKVResolver{})
+	r.Register(natsKVAltPrefix, &NATSKVResolver{})
+	r.Register(passPrefix, &PassResolver{})
+	r.Register(secretSvcPrefix, &SecretServiceResolver{})
+	r.Register(azblobPrefix, &AzureBlobResolver{})
+	r.Register(gitPrefix, &GitResolver{})
+	r.Register(zkPrefix, &ZooKeeperResolver{})
+	r.Register(keyringPrefix, &KeyringResolver{})
+	r.Register(vaultTransitPrefix, &VaultTransitResolver{})
+	r.Register(springConfigPrefix, &SpringConfigResolver{})
+	r.Register(k8sSAPrefix, &K8sServiceAccountResolver{})
+	r.Register(oauth2Prefix, &OAuth2Resolver{})
+	r.Register(tfstatePrefix, &TFStateResolver{})
+	r.Register(sftpPrefix, &SFTPResolver{})
+	r.Register(stdinPrefix, &StdinResolver{})
+}
+
+// Child returns a registry that inherits schemes and the unknown-scheme policy from r.
+// Inheritance is dynamic: later changes to r are visible in the child. Schemes registered
+// on the child shadow the parent's, and SetUnknownSchemePolicy on the child overrides the
+// inherited policy. The parent is never modified through the child.
+func (r *Registry) Child() *Registry {
+	c := NewRegistry()
+	c.parent = r
+	return c
+}
+
 // Register adds or replaces a resolver for a scheme (e.g., "json:") and preserves order.
 // Panics if scheme is empty or missing the trailing ":".
 func (r *Registry) Register(scheme string, res Resolver) {
@@ -83,44 +220,142 @@ func (r *Registry) Register(scheme string, res Resolver) {
 func (r *Registry) SetUnknownSchemePolicy(p UnknownSchemePolicy) {
 	r.mu.Lock()
 	r.unknown = p
+	r.unknownSet = true
 	r.mu.Unlock()
 }
 
-// Schemes returns the registered schemes in resolution order.
+// Schemes returns the registered schemes in resolution order (see SchemeInfos for the
+// ordering guarantees and per-scheme metadata).
 func (r *Registry) Schemes() []string {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
 	out := make([]string, len(r.order))
 	copy(out, r.order)
+	parent := r.parent
+	r.mu.RUnlock()
+
+	if parent == nil {
+		return out
+	}
+	seen := make(map[string]struct{}, len(out))
+	for _, s := range out {
+		seen[s] = struct{}{}
+	}
+	for _, s := range parent.Schemes() {
+		if _, shadowed := seen[s]; !shadowed {
+			out = append(out, s)
+		}
+	}
 	return out
 }
 
-// ResolveVariable resolves value using the first matching scheme; unknown handling is policy-driven.
-func (r *Registry) ResolveVariable(value string) (string, error) {
+// lookup returns the resolver for the first scheme matching value and the value with
+// the scheme stripped. Own schemes are tried before the parent's.
+func (r *Registry) lookup(value string) (Resolver, string, bool) {
+	res, _, rest, ok := r.lookupScheme(value)
+	return res, rest, ok
+}
+
+// lookupScheme is lookup that also returns the matched scheme.
+func (r *Registry) lookupScheme(value string) (Resolver, string, string, bool) {
 	r.mu.RLock()
 	for _, scheme := range r.order {
 		if rest, ok := strings.CutPrefix(value, scheme); ok {
 			res := r.backing[scheme]
 			r.mu.RUnlock()
-			return res.Resolve(rest)
+			return res, scheme, rest, true
 		}
 	}
-	p := r.unknown
+	parent := r.parent
+	r.mu.RUnlock()
+
+	if parent == nil {
+		return nil, "", "", false
+	}
+	return parent.lookupScheme(value)
+}
+
+// policy returns the effective unknown-scheme policy, inheriting from the parent unless set.
+func (r *Registry) policy() UnknownSchemePolicy {
+	r.mu.RLock()
+	p, set, parent := r.unknown, r.unknownSet, r.parent
 	r.mu.RUnlock()
 
+	if set || parent == nil {
+		return p
+	}
+	return parent.policy()
+}
+
+// ResolveVariable resolves value using the first matching scheme; unknown handling is policy-driven.
+func (r *Registry) ResolveVariable(value string) (string, error) {
+	return r.ResolveVariableContext(context.Background(), value)
+}
+
+// ResolveVariableContext is ResolveVariable with a context. ctx is passed to resolvers
+// implementing ContextResolver and to hooks; it receives a new resolution ID unless it
+// already carries one (see WithResolutionID).
+func (r *Registry) ResolveVariableContext(ctx context.Context, value string) (string, error) {
+	resp, err := r.ResolveRequest(ctx, value)
+	return resp.Value, err
+}
+
+// ResolveRequest is ResolveVariableContext returning a structured Response. Values
+// passed through by the unknown-scheme policy come back unchanged and not sensitive.
+func (r *Registry) ResolveRequest(ctx context.Context, value string) (Response, error) {
+	if res, scheme, rest, ok := r.lookupScheme(value); ok {
+		if err := ctx.Err(); err != nil {
+			return Response{}, err
+		}
+		ctx = r.ensureResolutionID(ctx)
+		clock := r.effectiveClock()
+		start := clock.Now()
+		var resp Response
+		var err error
+		if r.placeholderMode() && needsPlaceholder(res) {
+			resp = Response{Value: Placeholder(value)}
+		} else {
+			resp, err = AdaptResolver(res).ResolveRequest(ctx, Request{Scheme: scheme, Value: rest})
+			if err == nil {
+				r.rememberSecret(resp)
+				resp = r.maskWholeFile(res, rest, resp)
+			} else if errors.Is(err, ErrNotFound) {
+				if v, ok := r.fromDefaults(res, rest); ok {
+					resp, err = Response{Value: v}, nil
+				}
+			}
+		}
+		err = r.maskErr(err)
+		if h := r.effectiveHooks(); h.OnResolve != nil {
+			h.OnResolve(ctx, ResolveEvent{
+				ResolutionID: ResolutionID(ctx),
+				Scheme:       scheme,
+				Token:        r.maskText(value),
+				Duration:     clock.Now().Sub(start),
+				Err:          err,
+			})
+		}
+		if err != nil {
+			return Response{}, err
+		}
+		return resp, nil
+	}
+	p := r.policy()
+
 	// If configured to be strict and the string looks like "scheme:...", treat as unknown.
 	if p == ErrorOnUnknown && strings.Contains(value, ":") {
-		return "", fmt.Errorf("%w: %q", ErrNotFound, value)
+		return Response{}, r.maskErr(fmt.Errorf("%w: %q", ErrNotFound, value))
 	}
 	// Pass-through (back-compat behavior).
-	return value, nil
+	return Response{Value: value}, nil
 }
 
 // ResolveSlice resolves each value with the same rules as ResolveVariable (strict, fail-fast).
-func (r *Registry) ResolveSlice(values []string) ([]string, error) {
+// With the Templates option, values containing ${...} are interpolated instead.
+func (r *Registry) ResolveSlice(values []string, opts ...BatchOption) ([]string, error) {
+	o := newBatchOptions(opts)
 	out := make([]string, len(values))
 	for i, v := range values {
-		s, e := r.ResolveVariable(v)
+		s, e := r.resolveElement(v, o)
 		if e != nil {
 			return nil, fmt.Errorf("resolve slice index %d (%q): %w", i, v, e)
 		}
@@ -130,11 +365,13 @@ func (r *Registry) ResolveSlice(values []string) ([]string, error) {
 }
 
 // ResolveSliceBestEffort resolves all values and returns outputs plus one error per failed index.
-func (r *Registry) ResolveSliceBestEffort(values []string) (out []string, errs []error) {
+// It accepts the same options as ResolveSlice.
+func (r *Registry) ResolveSliceBestEffort(values []string, opts ...BatchOption) (out []string, errs []error) {
+	o := newBatchOptions(opts)
 	out = make([]string, len(values))
 	errs = make([]error, 0, len(values)) // len 0, cap N
 	for i, v := range values {
-		s, err := r.ResolveVariable(v)
+		s, err := r.resolveElement(v, o)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("index %d (%q): %w", i, v, err))
 		}
@@ -1,13 +1,146 @@
 package resolver
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/containeroo/resolver/selector"
+)
+
+// jqPrefix and jmesPrefix mark jq and JMESPath key paths (see the jq and jmespath
+// packages). They are matched here rather than taken from those packages so that
+// builds without those engines still split tokens the same way.
+const (
+	jqPrefix   = "#jq:"
+	jmesPrefix = "#jmes:"
+)
+
+// allSuffix ends a dotted key path whose every match is returned (see selectPath).
+const allSuffix = "[]"
 
 // splitFileAndKey splits a value by "//" to separate file path and key path.
+// A "//#jq:" or "//#jmes:" key path starts at its first occurrence, since jq programs
+// and JMESPath string literals may contain "//". Otherwise the key path starts after the last "//" outside brackets, so filters and
+// quoted keys may contain "//" ("servers.[url~=^https://api].host").
 func splitFileAndKey(value string) (string, string) {
 	const keyDelim = "//"
-	idx := strings.LastIndex(value, keyDelim)
+	for _, prefix := range []string{jqPrefix, jmesPrefix} {
+		if idx := strings.Index(value, keyDelim+prefix); idx != -1 {
+			return value[:idx], value[idx+len(keyDelim):]
+		}
+	}
+	idx := lastKeyDelim(value)
 	if idx == -1 {
 		return value, ""
 	}
 	return value[:idx], value[idx+len(keyDelim):]
 }
+
+// lastKeyDelim returns the index of the last "//" in value that is not inside
+// brackets, scanning from the end, or of the last "//" at all if brackets do not
+// balance.
+func lastKeyDelim(value string) int {
+	depth := 0
+	for i := len(value) - 1; i > 0; i-- {
+		switch value[i] {
+		case ']':
+			depth++
+		case '[':
+			depth--
+		case '/':
+			if depth == 0 && value[i-1] == '/' {
+				return i - 1
+			}
+		}
+		if depth < 0 {
+			break
+		}
+	}
+	return strings.LastIndex(value, "//")
+}
+
+// firstNonEmpty returns the first argument that is not the empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// selectPath walks content along keyPath. Key paths accepted by a registered selector
+// engine (JSONPath for '$...', JMESPath for "#jmes:...", jq for "#jq:...", all opt-in)
+// are evaluated by it; the rest are dotted paths for selector.Navigate, except those
+// of engines not linked in (see missingEngine). An engine producing several values
+// yields them as a []any. A dotted path ending in "[]" returns every match as a []any
+// (see selector.NavigateAll), so "servers.[env=prod].host[]" lists all matching hosts.
+func selectPath(content any, keyPath string) (any, error) {
+	return selectPathWith(content, keyPath, selector.Options{})
+}
+
+// selectPathWith is selectPath with options for dotted paths.
+func selectPathWith(content any, keyPath string, opts selector.Options) (any, error) {
+	if _, e, ok := selector.LookupEngine(keyPath); ok {
+		results, err := e.Select(content, keyPath)
+		if err != nil {
+			return nil, err
+		}
+		return singleOrAll(results, strings.TrimSpace(keyPath))
+	}
+	if err := missingEngine(keyPath); err != nil {
+		return nil, err
+	}
+	if p, ok := strings.CutSuffix(keyPath, allSuffix); ok {
+		return selector.NavigateAllWith(content, selector.ParsePath(p), opts)
+	}
+	return selector.NavigateWith(content, selector.ParsePath(keyPath), opts)
+}
+
+// missingEngine returns ErrBadPath for a key path meant for an opt-in engine that is
+// not linked in, rather than navigating it as a dotted path.
+func missingEngine(keyPath string) error {
+	var lang, pkg string
+	switch rest, dollar := strings.CutPrefix(strings.TrimSpace(keyPath), "$"); {
+	case strings.HasPrefix(keyPath, jqPrefix):
+		lang, pkg = "jq", "jq"
+	case strings.HasPrefix(keyPath, jmesPrefix):
+		lang, pkg = "JMESPath", "jmespath"
+	case dollar && (rest == "" || rest[0] == '.' || rest[0] == '['):
+		lang, pkg = "JSONPath", "jsonpath"
+	default:
+		return nil
+	}
+	return fmt.Errorf("%w: %s key paths need the %s engine (import github.com/containeroo/resolver/%s or build with -tags resolver_%s)", ErrBadPath, lang, pkg, pkg, pkg)
+}
+
+// selectorOptions returns the selector options for a resolver's settings.
+func selectorOptions(caseInsensitiveKeys, uniqueFilters bool) selector.Options {
+	opts := selector.Options{CaseInsensitiveKeys: caseInsensitiveKeys}
+	if uniqueFilters {
+		opts.Filters = selector.FilterUnique
+	}
+	return opts
+}
+
+// selectErr returns the sentinel for a failed key path: ErrBadPath for an ambiguous
+// filter or a missing engine, which should not fall through to another source,
+// otherwise ErrNotFound.
+func selectErr(err error) error {
+	if errors.Is(err, selector.ErrAmbiguousFilter) || errors.Is(err, ErrBadPath) {
+		return ErrBadPath
+	}
+	return ErrNotFound
+}
+
+// singleOrAll unwraps a single result and returns several as a []any.
+func singleOrAll(results []any, expr string) (any, error) {
+	switch len(results) {
+	case 0:
+		return nil, fmt.Errorf("no match for %s", expr)
+	case 1:
+		return results[0], nil
+	}
+	return results, nil
+}
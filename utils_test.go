@@ -43,4 +43,44 @@ func TestUtils(t *testing.T) {
 		assert.Equal(t, "path//to//file", file)
 		assert.Equal(t, "key", key)
 	})
+
+	t.Run("DelimiterInsideBrackets", func(t *testing.T) {
+		t.Parallel()
+		file, key := splitFileAndKey("/f.json//servers.[url~=^https://api].host")
+		assert.Equal(t, "/f.json", file)
+		assert.Equal(t, "servers.[url~=^https://api].host", key)
+
+		file, key = splitFileAndKey(`/a//b.json//links.["http://x"]`)
+		assert.Equal(t, "/a//b.json", file)
+		assert.Equal(t, `links.["http://x"]`, key)
+
+		file, key = splitFileAndKey("/f.json//a]//b")
+		assert.Equal(t, "/f.json//a]", file)
+		assert.Equal(t, "b", key)
+	})
+
+	t.Run("JQProgramWithAlternative", func(t *testing.T) {
+		t.Parallel()
+		file, key := splitFileAndKey(`path/to/file.json//#jq:.a // "fallback"`)
+		assert.Equal(t, "path/to/file.json", file)
+		assert.Equal(t, `#jq:.a // "fallback"`, key)
+	})
+
+	t.Run("MissingEngine", func(t *testing.T) {
+		t.Parallel()
+		for keyPath, tag := range map[string]string{
+			"#jq:.a":     "resolver_jq",
+			"#jmes:a[0]": "resolver_jmespath",
+			"$.a":        "resolver_jsonpath",
+			"$['a']":     "resolver_jsonpath",
+			"$":          "resolver_jsonpath",
+		} {
+			err := missingEngine(keyPath)
+			assert.ErrorIs(t, err, ErrBadPath, keyPath)
+			assert.ErrorContains(t, err, "-tags "+tag, keyPath)
+		}
+		for _, keyPath := range []string{"a.b", "$schema", "$id.x", "servers.[name=$x]"} {
+			assert.NoError(t, missingEngine(keyPath), keyPath)
+		}
+	})
 }
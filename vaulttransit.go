@@ -0,0 +1,89 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// VaultTransitResolver decrypts ciphertext with HashiCorp Vault's transit secrets engine,
+// so encrypted values can be stored inline in config files.
+// Format: "vault-transit:[<mount>/]<key>:<ciphertext>", e.g.
+// "vault-transit:app-key:vault:v1:AbCd..." (mount defaults to "transit").
+//
+// Address defaults to $VAULT_ADDR, Token to $VAULT_TOKEN and Namespace to $VAULT_NAMESPACE.
+type VaultTransitResolver struct {
+	Address   string
+	Token     string
+	Namespace string
+	Client    *http.Client
+}
+
+func (r *VaultTransitResolver) Resolve(value string) (string, error) {
+	return r.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context bounding the Vault request.
+func (r *VaultTransitResolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	keyRef, ciphertext, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok || keyRef == "" || !strings.HasPrefix(ciphertext, "vault:v") {
+		return "", fmt.Errorf("%w: expected vault-transit:[mount/]key:vault:v<N>:..., got %q", ErrBadPath, value)
+	}
+	mount, key := "transit", keyRef
+	if i := strings.LastIndexByte(keyRef, '/'); i >= 0 {
+		mount, key = strings.Trim(keyRef[:i], "/"), keyRef[i+1:]
+	}
+	if mount == "" || key == "" {
+		return "", fmt.Errorf("%w: empty transit mount or key in %q", ErrBadPath, keyRef)
+	}
+
+	token := firstNonEmpty(r.Token, os.Getenv("VAULT_TOKEN"))
+	if token == "" {
+		return "", fmt.Errorf("%w: no Vault token configured (set VAULT_TOKEN)", ErrForbidden)
+	}
+	addr := firstNonEmpty(r.Address, os.Getenv("VAULT_ADDR"))
+	if addr == "" {
+		return "", fmt.Errorf("%w: no Vault address configured (set VAULT_ADDR)", ErrBadPath)
+	}
+
+	payload, _ := json.Marshal(map[string]string{"ciphertext": ciphertext})
+	endpoint := strings.TrimRight(addr, "/") + "/v1/" + mount + "/decrypt/" + key
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("build Vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	req.Header.Set("Content-Type", "application/json")
+	if ns := firstNonEmpty(r.Namespace, os.Getenv("VAULT_NAMESPACE")); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	body, err := doHTTP(r.Client, req)
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse Vault transit response for key %q: %w", key, err)
+	}
+	plain, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode Vault transit plaintext for key %q: %w", key, err)
+	}
+	return string(plain), nil
+}
+
+// Describe reports the resolver metadata.
+func (r *VaultTransitResolver) Describe() ResolverMeta {
+	return ResolverMeta{Sensitive: true, Capabilities: []Capability{CapabilityRemote}}
+}
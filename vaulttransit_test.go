@@ -0,0 +1,92 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultTransitResolver_Resolve(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Vault-Token") != "tok" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if req.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var body struct {
+			Ciphertext string `json:"ciphertext"`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+
+		switch req.URL.Path {
+		case "/v1/transit/decrypt/app-key", "/v1/secrets/transit/decrypt/other":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if body.Ciphertext != "vault:v1:abc" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		plain := "s3cr3t@" + req.URL.Path + "@" + req.Header.Get("X-Vault-Namespace")
+		_, _ = w.Write([]byte(`{"data":{"plaintext":"` + base64.StdEncoding.EncodeToString([]byte(plain)) + `"}}`))
+	}))
+	defer srv.Close()
+
+	t.Run("Default mount", func(t *testing.T) {
+		r := &VaultTransitResolver{Address: srv.URL, Token: "tok"}
+		val, err := r.Resolve("app-key:vault:v1:abc")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t@/v1/transit/decrypt/app-key@", val)
+	})
+
+	t.Run("Custom mount and namespace", func(t *testing.T) {
+		r := &VaultTransitResolver{Address: srv.URL, Token: "tok", Namespace: "team-a"}
+		val, err := r.Resolve("secrets/transit/other:vault:v1:abc")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t@/v1/secrets/transit/decrypt/other@team-a", val)
+	})
+
+	t.Run("Env configuration", func(t *testing.T) {
+		t.Setenv("VAULT_ADDR", srv.URL)
+		t.Setenv("VAULT_TOKEN", "tok")
+		r := &VaultTransitResolver{}
+		_, err := r.Resolve("app-key:vault:v1:abc")
+		require.NoError(t, err)
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		r := &VaultTransitResolver{Address: srv.URL, Token: "tok"}
+		_, err := r.Resolve("nope:vault:v1:abc")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Wrong token", func(t *testing.T) {
+		r := &VaultTransitResolver{Address: srv.URL, Token: "bad"}
+		_, err := r.Resolve("app-key:vault:v1:abc")
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Missing token", func(t *testing.T) {
+		t.Setenv("VAULT_TOKEN", "")
+		r := &VaultTransitResolver{Address: srv.URL}
+		_, err := r.Resolve("app-key:vault:v1:abc")
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Malformed references", func(t *testing.T) {
+		r := &VaultTransitResolver{Address: srv.URL, Token: "tok"}
+		for _, v := range []string{"app-key", "app-key:notcipher", ":vault:v1:abc", "mount/:vault:v1:abc"} {
+			_, err := r.Resolve(v)
+			require.ErrorIs(t, err, ErrBadPath, v)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest lists tokens and the expectations a configuration bundle must meet.
+//
+// Example (YAML):
+//
+//	checks:
+//	  - token: env:DATABASE_URL
+//	    nonEmpty: true
+//	    type: url
+//	  - name: worker count
+//	    token: json:/etc/app/config.json//workers
+//	    type: int
+//	  - token: env:DEBUG
+//	    optional: true
+//	    match: ^(true|false)$
+type Manifest struct {
+	Checks []Check `yaml:"checks" json:"checks"`
+}
+
+// Check is a single expectation about a token.
+// Every token must use a registered scheme and resolve unless Optional is set; an
+// optional token whose value is not found (ErrNotFound) passes, other errors fail.
+type Check struct {
+	Name     string `yaml:"name,omitempty" json:"name,omitempty"`         // label for reports; defaults to Token
+	Token    string `yaml:"token" json:"token"`                           // resolved with ResolveVariable
+	Optional bool   `yaml:"optional,omitempty" json:"optional,omitempty"` // a missing value is not a failure
+	NonEmpty bool   `yaml:"nonEmpty,omitempty" json:"nonEmpty,omitempty"` // value must not be blank
+	Match    string `yaml:"match,omitempty" json:"match,omitempty"`       // value must match this regexp
+	Type     string `yaml:"type,omitempty" json:"type,omitempty"`         // "url", "int", "float", "bool" or "" (any)
+}
+
+// CheckResult is the outcome of one Check. Resolved values are never included,
+// so reports are safe to print.
+type CheckResult struct {
+	Check    Check
+	Passed   bool
+	Skipped  bool     // optional token whose value was not found
+	Problems []string // reasons for failure; empty when Passed
+}
+
+// Report is the result of Verify.
+type Report struct {
+	Results []CheckResult
+}
+
+// Passed reports whether every check passed.
+func (r *Report) Passed() bool {
+	for _, res := range r.Results {
+		if !res.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the results that did not pass.
+func (r *Report) Failed() []CheckResult {
+	var out []CheckResult
+	for _, res := range r.Results {
+		if !res.Passed {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// LoadManifest reads a YAML (or JSON) verification manifest from path.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %q: %w", path, err)
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %q: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Verify resolves every token in m and checks it against its expectations.
+// The returned error is non-nil only for invalid manifests or a cancelled ctx;
+// failed checks are reported in the Report.
+func (r *Registry) Verify(ctx context.Context, m *Manifest) (*Report, error) {
+	rep := &Report{Results: make([]CheckResult, 0, len(m.Checks))}
+	ctx = r.ensureResolutionID(ctx)
+	for i, c := range m.Checks {
+		if err := ctx.Err(); err != nil {
+			return rep, err
+		}
+		if strings.TrimSpace(c.Token) == "" {
+			return nil, fmt.Errorf("%w: check %d has no token", ErrBadPath, i)
+		}
+		if c.Name == "" {
+			c.Name = c.Token
+		}
+		var re *regexp.Regexp
+		if c.Match != "" {
+			var err error
+			if re, err = regexp.Compile(c.Match); err != nil {
+				return nil, fmt.Errorf("check %q: invalid match pattern: %w", c.Name, err)
+			}
+		}
+		switch c.Type {
+		case "", "url", "int", "float", "bool":
+		default:
+			return nil, fmt.Errorf("check %q: unknown type %q", c.Name, c.Type)
+		}
+		rep.Results = append(rep.Results, r.verifyCheck(ctx, c, re))
+	}
+	return rep, nil
+}
+
+// verifyCheck resolves c.Token and evaluates the expectations.
+func (r *Registry) verifyCheck(ctx context.Context, c Check, re *regexp.Regexp) CheckResult {
+	res := CheckResult{Check: c}
+	// A misspelled scheme would pass through as a literal under PassThrough.
+	if _, _, _, ok := r.lookupScheme(c.Token); !ok {
+		res.Problems = append(res.Problems, "has no registered scheme")
+		return res
+	}
+	val, err := r.ResolveVariableContext(ctx, c.Token)
+	if err != nil {
+		if c.Optional && errors.Is(err, ErrNotFound) {
+			res.Passed, res.Skipped = true, true
+			return res
+		}
+		res.Problems = append(res.Problems, fmt.Sprintf("does not resolve: %v", err))
+		return res
+	}
+
+	if c.NonEmpty && strings.TrimSpace(val) == "" {
+		res.Problems = append(res.Problems, "is empty")
+	}
+	if re != nil && !re.MatchString(val) {
+		res.Problems = append(res.Problems, fmt.Sprintf("does not match %q", c.Match))
+	}
+	if p := checkValueType(val, c.Type); p != "" {
+		res.Problems = append(res.Problems, p)
+	}
+	res.Passed = len(res.Problems) == 0
+	return res
+}
+
+// checkValueType returns a problem description if val does not parse as typ.
+func checkValueType(val, typ string) string {
+	v := strings.TrimSpace(val)
+	switch typ {
+	case "url":
+		u, err := url.Parse(v)
+		if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+			return "is not an absolute URL"
+		}
+	case "int":
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return "is not an integer"
+		}
+	case "float":
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return "is not a number"
+		}
+	case "bool":
+		if _, err := strconv.ParseBool(v); err != nil {
+			return "is not a boolean"
+		}
+	}
+	return ""
+}
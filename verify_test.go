@@ -0,0 +1,111 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Verify(t *testing.T) {
+	reg := NewRegistry()
+	mem := NewMemResolver()
+	reg.Register("mem:", mem)
+	mem.Set("url", "postgres://db:5432/app")
+	mem.Set("port", "5432")
+	mem.Set("blank", "  ")
+	mem.Set("flag", "yes")
+
+	t.Run("Passing checks", func(t *testing.T) {
+		rep, err := reg.Verify(context.Background(), &Manifest{Checks: []Check{
+			{Token: "mem:url", Type: "url", Match: "^postgres://"},
+			{Token: "mem:port", Type: "int", NonEmpty: true},
+			{Token: "mem:missing", Optional: true},
+		}})
+		require.NoError(t, err)
+		assert.True(t, rep.Passed())
+		require.Len(t, rep.Results, 3)
+		assert.Equal(t, "mem:url", rep.Results[0].Check.Name)
+		assert.True(t, rep.Results[2].Skipped)
+	})
+
+	t.Run("Failing checks", func(t *testing.T) {
+		rep, err := reg.Verify(context.Background(), &Manifest{Checks: []Check{
+			{Name: "missing", Token: "mem:missing"},
+			{Name: "blank", Token: "mem:blank", NonEmpty: true},
+			{Name: "flag", Token: "mem:flag", Type: "bool", Match: "^(true|false)$"},
+			{Name: "port", Token: "mem:port", Type: "url"},
+			{Name: "ok", Token: "mem:port", Type: "float"},
+		}})
+		require.NoError(t, err)
+		assert.False(t, rep.Passed())
+
+		failed := rep.Failed()
+		require.Len(t, failed, 4)
+		assert.Contains(t, failed[0].Problems[0], "does not resolve")
+		assert.Equal(t, []string{"is empty"}, failed[1].Problems)
+		assert.Equal(t, []string{`does not match "^(true|false)$"`, "is not a boolean"}, failed[2].Problems)
+		assert.Equal(t, []string{"is not an absolute URL"}, failed[3].Problems)
+	})
+
+	t.Run("Unknown scheme", func(t *testing.T) {
+		rep, err := reg.Verify(context.Background(), &Manifest{Checks: []Check{
+			{Token: "vualt:secret/db"},
+			{Token: "vualt:secret/debug", Optional: true},
+		}})
+		require.NoError(t, err)
+		failed := rep.Failed()
+		require.Len(t, failed, 2, "misspelled schemes do not pass through")
+		assert.Equal(t, []string{"has no registered scheme"}, failed[0].Problems)
+		assert.False(t, failed[1].Skipped)
+	})
+
+	t.Run("Optional only skips missing values", func(t *testing.T) {
+		r := reg.Child()
+		r.Register("denied:", ResolverFunc(func(string) (string, error) { return "", ErrForbidden }))
+		rep, err := r.Verify(context.Background(), &Manifest{Checks: []Check{
+			{Token: "denied:x", Optional: true},
+			{Token: "mem:missing", Optional: true},
+		}})
+		require.NoError(t, err)
+		require.Len(t, rep.Results, 2)
+		assert.False(t, rep.Results[0].Passed)
+		assert.False(t, rep.Results[0].Skipped)
+		assert.Contains(t, rep.Results[0].Problems[0], "does not resolve")
+		assert.True(t, rep.Results[1].Skipped)
+	})
+
+	t.Run("Invalid manifest", func(t *testing.T) {
+		_, err := reg.Verify(context.Background(), &Manifest{Checks: []Check{{Token: " "}}})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrBadPath)
+
+		_, err = reg.Verify(context.Background(), &Manifest{Checks: []Check{{Token: "mem:url", Match: "("}}})
+		require.Error(t, err)
+
+		_, err = reg.Verify(context.Background(), &Manifest{Checks: []Check{{Token: "mem:url", Type: "ipv9"}}})
+		require.Error(t, err)
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := reg.Verify(ctx, &Manifest{Checks: []Check{{Token: "mem:url"}}})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Load manifest", func(t *testing.T) {
+		p := createYAMLTestFile(t, `
+checks:
+  - name: db
+    token: mem:url
+    nonEmpty: true
+    match: ^postgres
+    type: url
+`)
+		m, err := LoadManifest(p)
+		require.NoError(t, err)
+		assert.Equal(t, []Check{{Name: "db", Token: "mem:url", NonEmpty: true, Match: "^postgres", Type: "url"}}, m.Checks)
+	})
+}
@@ -0,0 +1,106 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+)
+
+// WaitOptions configures WaitFor.
+type WaitOptions struct {
+	InitialBackoff time.Duration // delay after the first failed attempt; defaults to 500ms
+	MaxBackoff     time.Duration // upper bound for the delay; defaults to 30s
+	Multiplier     float64       // backoff growth per attempt; defaults to 2
+
+	// OnAttempt is called after every attempt that left tokens unresolved.
+	OnAttempt func(WaitProgress)
+}
+
+// WaitProgress describes a failed WaitFor attempt.
+type WaitProgress struct {
+	Attempt int              // 1-based attempt number
+	Pending []string         // tokens that did not resolve yet
+	Errors  map[string]error // last error per pending token
+	Next    time.Duration    // delay before the next attempt
+}
+
+// WaitFor resolves tokens (with ResolveVariable) until all of them succeed or ctx ends,
+// retrying the failed ones with exponential backoff. Tokens that resolved once are not
+// retried. It returns the values in the order of tokens. If ctx ends first, the error
+// wraps ctx.Err() and the last error of each pending token.
+func (r *Registry) WaitFor(ctx context.Context, tokens []string, opts WaitOptions) ([]string, error) {
+	opts.applyDefaults()
+
+	values := make([]string, len(tokens))
+	pending := make([]int, len(tokens))
+	for i := range tokens {
+		pending[i] = i
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ctx = r.ensureResolutionID(ctx)
+	clock := r.effectiveClock()
+
+	errs := make(map[string]error) // last error per pending token
+	backoff := opts.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		var still []int
+		for _, i := range pending {
+			v, err := r.ResolveVariableContext(ctx, tokens[i])
+			if err != nil {
+				// Once ctx has ended, keep the token's previous, more telling error.
+				if ctx.Err() == nil || errs[tokens[i]] == nil {
+					errs[tokens[i]] = err
+				}
+				still = append(still, i)
+				continue
+			}
+			values[i] = v
+			delete(errs, tokens[i])
+		}
+		pending = still
+		if len(pending) == 0 {
+			return values, nil
+		}
+
+		names := make([]string, len(pending))
+		for j, i := range pending {
+			names[j] = tokens[i]
+		}
+		if opts.OnAttempt != nil {
+			opts.OnAttempt(WaitProgress{Attempt: attempt, Pending: names, Errors: maps.Clone(errs), Next: backoff})
+		}
+
+		select {
+		case <-ctx.Done():
+			causes := []error{ctx.Err()}
+			for _, name := range names {
+				causes = append(causes, fmt.Errorf("${%s}: %w", name, errs[name]))
+			}
+			return nil, fmt.Errorf("wait for %d token(s) after %d attempt(s): %w", len(names), attempt, errors.Join(causes...))
+		case <-clock.After(backoff):
+		}
+
+		backoff = min(time.Duration(float64(backoff)*opts.Multiplier), opts.MaxBackoff)
+	}
+}
+
+// applyDefaults fills unset options.
+func (o *WaitOptions) applyDefaults() {
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = 500 * time.Millisecond
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = 30 * time.Second
+	}
+	if o.MaxBackoff < o.InitialBackoff {
+		o.MaxBackoff = o.InitialBackoff
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = 2
+	}
+}
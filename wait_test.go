@@ -0,0 +1,128 @@
+package resolver
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WaitFor(t *testing.T) {
+	fast := WaitOptions{InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond}
+
+	t.Run("Succeeds once backend is ready", func(t *testing.T) {
+		var calls atomic.Int32
+		r := NewRegistry()
+		r.Register("slow:", ResolverFunc(func(v string) (string, error) {
+			if calls.Add(1) < 3 {
+				return "", ErrNotFound
+			}
+			return "ready-" + v, nil
+		}))
+		r.Register("ok:", ResolverFunc(func(v string) (string, error) { return v, nil }))
+
+		var progress []WaitProgress
+		opts := fast
+		opts.OnAttempt = func(p WaitProgress) { progress = append(progress, p) }
+
+		vals, err := r.WaitFor(context.Background(), []string{"ok:a", "slow:b"}, opts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "ready-b"}, vals)
+
+		require.Len(t, progress, 2)
+		assert.Equal(t, 1, progress[0].Attempt)
+		assert.Equal(t, []string{"slow:b"}, progress[0].Pending)
+		assert.ErrorIs(t, progress[0].Errors["slow:b"], ErrNotFound)
+		assert.Equal(t, time.Millisecond, progress[0].Next)
+		assert.Equal(t, 2*time.Millisecond, progress[1].Next)
+	})
+
+	t.Run("Resolved tokens are not retried", func(t *testing.T) {
+		var okCalls, failCalls atomic.Int32
+		r := NewRegistry()
+		r.Register("ok:", ResolverFunc(func(v string) (string, error) { okCalls.Add(1); return v, nil }))
+		r.Register("flaky:", ResolverFunc(func(v string) (string, error) {
+			if failCalls.Add(1) < 4 {
+				return "", ErrNotFound
+			}
+			return v, nil
+		}))
+
+		_, err := r.WaitFor(context.Background(), []string{"ok:a", "flaky:b"}, fast)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), okCalls.Load())
+	})
+
+	t.Run("Deadline reports pending tokens", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register("never:", ResolverFunc(func(string) (string, error) { return "", ErrForbidden }))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := r.WaitFor(ctx, []string{"never:x"}, fast)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, ErrForbidden)
+		assert.Contains(t, err.Error(), "${never:x}")
+	})
+
+	t.Run("Context ending mid-attempt keeps the previous error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stub := &cancelStub{cancelOn: 2, cancel: cancel}
+		r := NewRegistry()
+		r.Register("never:", stub)
+
+		_, err := r.WaitFor(ctx, []string{"never:x"}, fast)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, err, ErrForbidden, "the error of the last full attempt is reported")
+		assert.Equal(t, int32(2), stub.calls.Load())
+	})
+
+	t.Run("Backoff is capped", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register("never:", ResolverFunc(func(string) (string, error) { return "", ErrNotFound }))
+
+		var delays []time.Duration
+		opts := fast
+		opts.OnAttempt = func(p WaitProgress) { delays = append(delays, p.Next) }
+
+		ctx, cancel := context.WithTimeout(context.Background(), 40*time.Millisecond)
+		defer cancel()
+		_, _ = r.WaitFor(ctx, []string{"never:x"}, opts)
+
+		require.GreaterOrEqual(t, len(delays), 4)
+		assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond}, delays[:4])
+	})
+
+	t.Run("No tokens", func(t *testing.T) {
+		vals, err := NewRegistry().WaitFor(context.Background(), nil, WaitOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, vals)
+	})
+}
+
+// cancelStub fails with ErrForbidden, and on call cancelOn cancels the caller's
+// context and fails with its error, as a backend interrupted mid-request does.
+type cancelStub struct {
+	calls    atomic.Int32
+	cancelOn int32
+	cancel   context.CancelFunc
+}
+
+func (s *cancelStub) Resolve(value string) (string, error) {
+	return s.ResolveContext(context.Background(), value)
+}
+
+func (s *cancelStub) ResolveContext(ctx context.Context, _ string) (string, error) {
+	if s.calls.Add(1) == s.cancelOn {
+		s.cancel()
+		return "", ctx.Err()
+	}
+	return "", ErrForbidden
+}
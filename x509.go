@@ -0,0 +1,158 @@
+package resolver
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+)
+
+// X509Resolver resolves fields of an X.509 certificate in a PEM (or DER) file.
+// Format: "x509:/path/tls.crt//field", e.g. "x509:/etc/tls/tls.crt//notAfter".
+// For bundles, the first certificate (the leaf) is used.
+//
+// Fields: subject.cn, subject.o, subject.dn (likewise issuer.*), sans, dnsNames,
+// ipAddresses, emailAddresses, uris, notBefore, notAfter (RFC 3339, UTC), serial,
+// fingerprint (SHA-256, hex), fingerprintSHA1, isCA, signatureAlgorithm and
+// publicKeyAlgorithm. Without a field, all of them are returned as JSON.
+type X509Resolver struct{}
+
+func (r *X509Resolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read certificate %q: %w", filePath, err)
+	}
+
+	cert, err := parseCertificate(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse certificate %q: %w", filePath, err)
+	}
+	fields := certificateFields(cert)
+
+	if keyPath == "" {
+		out, _ := json.Marshal(fields)
+		return string(out), nil
+	}
+
+	val, err := selectPath(fields, keyPath)
+	if err != nil {
+		return "", fmt.Errorf("%w: field %q in certificate %q: %v", ErrNotFound, keyPath, filePath, err)
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	out, _ := json.Marshal(val)
+	return string(out), nil
+}
+
+// Describe reports the resolver metadata.
+func (r *X509Resolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector}}
+}
+
+// parseCertificate returns the first certificate in PEM data, or parses data as DER.
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, errors.New("no certificate found")
+	}
+	return cert, nil
+}
+
+// certificateFields maps cert onto the navigable fields documented on X509Resolver.
+func certificateFields(cert *x509.Certificate) map[string]any {
+	var ips, uris, sans []any
+	dnsNames := stringsToAny(cert.DNSNames)
+	emails := stringsToAny(cert.EmailAddresses)
+	for _, ip := range cert.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+	for _, u := range cert.URIs {
+		uris = append(uris, u.String())
+	}
+	sans = append(sans, dnsNames...)
+	sans = append(sans, ips...)
+	sans = append(sans, emails...)
+	sans = append(sans, uris...)
+
+	sum256 := sha256.Sum256(cert.Raw)
+	sum1 := sha1.Sum(cert.Raw)
+
+	return map[string]any{
+		"subject":            nameFields(cert.Subject),
+		"issuer":             nameFields(cert.Issuer),
+		"sans":               nonNil(sans),
+		"dnsNames":           nonNil(dnsNames),
+		"ipAddresses":        nonNil(ips),
+		"emailAddresses":     nonNil(emails),
+		"uris":               nonNil(uris),
+		"notBefore":          cert.NotBefore.UTC().Format(time.RFC3339),
+		"notAfter":           cert.NotAfter.UTC().Format(time.RFC3339),
+		"serial":             cert.SerialNumber.Text(16),
+		"fingerprint":        hex.EncodeToString(sum256[:]),
+		"fingerprintSHA1":    hex.EncodeToString(sum1[:]),
+		"isCA":               cert.IsCA,
+		"signatureAlgorithm": cert.SignatureAlgorithm.String(),
+		"publicKeyAlgorithm": cert.PublicKeyAlgorithm.String(),
+	}
+}
+
+// nameFields returns the common attributes of a distinguished name.
+func nameFields(n pkix.Name) map[string]any {
+	return map[string]any{
+		"cn": n.CommonName,
+		"o":  strings.Join(n.Organization, ", "),
+		"ou": strings.Join(n.OrganizationalUnit, ", "),
+		"c":  strings.Join(n.Country, ", "),
+		"dn": n.String(),
+	}
+}
+
+func stringsToAny(ss []string) []any {
+	out := make([]any, 0, len(ss))
+	for _, s := range ss {
+		out = append(out, s)
+	}
+	return out
+}
+
+// nonNil keeps empty lists encoding as [] instead of null.
+func nonNil(v []any) []any {
+	if v == nil {
+		return []any{}
+	}
+	return v
+}
@@ -0,0 +1,103 @@
+package resolver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createCertFile writes a self-signed certificate and returns its path and DER bytes.
+func createCertFile(t *testing.T) (string, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(0x1f),
+		Subject:      pkix.Name{CommonName: "api.example.com", Organization: []string{"Example"}},
+		DNSNames:     []string{"api.example.com", "www.example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("10.0.0.1")},
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	p := filepath.Join(t.TempDir(), "tls.crt")
+	require.NoError(t, os.WriteFile(p, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return p, der
+}
+
+func TestX509Resolver_Resolve(t *testing.T) {
+	r := &X509Resolver{}
+	p, der := createCertFile(t)
+
+	t.Run("Fields", func(t *testing.T) {
+		sum := sha256.Sum256(der)
+		for field, want := range map[string]string{
+			"subject.cn":  "api.example.com",
+			"subject.o":   "Example",
+			"issuer.cn":   "api.example.com",
+			"notBefore":   "2024-01-01T00:00:00Z",
+			"notAfter":    "2025-01-01T00:00:00Z",
+			"serial":      "1f",
+			"fingerprint": hex.EncodeToString(sum[:]),
+			"sans.1":      "www.example.com",
+			"sans":        `["api.example.com","www.example.com","10.0.0.1"]`,
+			"isCA":        "false",
+		} {
+			val, err := r.Resolve(p + "//" + field)
+			require.NoError(t, err, field)
+			assert.Equal(t, want, val, field)
+		}
+	})
+
+	t.Run("All fields as JSON", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		var fields map[string]any
+		require.NoError(t, json.Unmarshal([]byte(val), &fields))
+		assert.Equal(t, "2025-01-01T00:00:00Z", fields["notAfter"])
+		assert.Equal(t, []any{}, fields["emailAddresses"])
+	})
+
+	t.Run("DER file", func(t *testing.T) {
+		dp := filepath.Join(t.TempDir(), "tls.der")
+		require.NoError(t, os.WriteFile(dp, der, 0o600))
+		val, err := r.Resolve(dp + "//subject.cn")
+		require.NoError(t, err)
+		assert.Equal(t, "api.example.com", val)
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		_, err := r.Resolve(p + "//nope")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Not a certificate", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.crt")
+		require.NoError(t, os.WriteFile(bad, []byte("hello"), 0o600))
+		_, err := r.Resolve(bad + "//notAfter")
+		require.Error(t, err)
+	})
+
+	t.Run("File not found", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "missing.crt"))
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
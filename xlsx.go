@@ -0,0 +1,350 @@
+package resolver
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// XLSXResolver resolves cells of an Excel (.xlsx) workbook.
+// Format: "xlsx:/path/book.xlsx//<sheet>.<cell>", e.g. "xlsx:/data/limits.xlsx//Sheet1.B2".
+//
+// Instead of a cell reference, the rest of the selector may navigate the sheet as a list
+// of records keyed by the header row: "Quotas.[service=api].limit" returns the "limit"
+// column of the first row whose "service" column is "api", and "Quotas.0.limit" that of
+// the first row below the header. Headers that are empty use the column letter. "Sheet"
+// alone returns the records as JSON, and without a selector the sheet names are returned
+// as a JSON list. The sheet name ends at the first dot.
+//
+// Cells are returned as stored: numbers (including dates, which are day serials) in
+// their shortest form, booleans as "true"/"false" and formula cells as their cached
+// result.
+type XLSXResolver struct{}
+
+func (r *XLSXResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to open workbook %q: %w", filePath, err)
+	}
+	defer zr.Close() // nolint:errcheck
+
+	book := &xlsxBook{zr: &zr.Reader}
+	sheets, err := book.sheets()
+	if err != nil {
+		return "", fmt.Errorf("workbook %q: %w", filePath, err)
+	}
+
+	if keyPath == "" {
+		names := make([]string, len(sheets))
+		for i, s := range sheets {
+			names[i] = s.name
+		}
+		out, _ := json.Marshal(names)
+		return string(out), nil
+	}
+
+	sheetName, rest, _ := strings.Cut(keyPath, ".")
+	var target string
+	for _, s := range sheets {
+		if s.name == sheetName {
+			target = s.target
+			break
+		}
+	}
+	if target == "" {
+		return "", fmt.Errorf("%w: sheet %q in workbook %q", ErrNotFound, sheetName, filePath)
+	}
+	cells, err := book.cells(target)
+	if err != nil {
+		return "", fmt.Errorf("workbook %q, sheet %q: %w", filePath, sheetName, err)
+	}
+
+	if col, row, ok := parseCellRef(rest); ok {
+		v, found := cells[xlsxCell{row, col}]
+		if !found {
+			return "", fmt.Errorf("%w: cell %s of sheet %q in %q", ErrNotFound, rest, sheetName, filePath)
+		}
+		return xlsxString(v), nil
+	}
+
+	records := xlsxRecords(cells)
+	if rest == "" {
+		out, _ := json.Marshal(records)
+		return string(out), nil
+	}
+	val, err := selectPath(records, rest)
+	if err != nil {
+		return "", fmt.Errorf("%w: key path %q in sheet %q of %q: %v", ErrNotFound, rest, sheetName, filePath, err)
+	}
+	return xlsxString(val), nil
+}
+
+// Describe reports the resolver metadata.
+func (r *XLSXResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector}}
+}
+
+// xlsxCell is a 1-based row and column.
+type xlsxCell struct{ row, col int }
+
+// xlsxSheet is a sheet of the workbook and its part name.
+type xlsxSheet struct{ name, target string }
+
+// xlsxBook reads the parts of a workbook.
+type xlsxBook struct {
+	zr      *zip.Reader
+	strings []string // shared strings, loaded on first use
+	loaded  bool
+}
+
+// part decodes the XML part name into v.
+func (b *xlsxBook) part(name string, v any) error {
+	for _, f := range b.zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %q: %w", name, err)
+		}
+		defer rc.Close() // nolint:errcheck
+		data, err := readMember(rc, name)
+		if err != nil {
+			return err
+		}
+		if err := xml.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("failed to parse %q: %w", name, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: part %q", ErrNotFound, name)
+}
+
+// sheets returns the sheets of the workbook in order.
+func (b *xlsxBook) sheets() ([]xlsxSheet, error) {
+	var wb struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := b.part("xl/workbook.xml", &wb); err != nil {
+		return nil, err
+	}
+	var rels struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := b.part("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+
+	sheets := make([]xlsxSheet, 0, len(wb.Sheets))
+	for _, s := range wb.Sheets {
+		for _, rel := range rels.Rels {
+			if rel.ID != s.ID {
+				continue
+			}
+			target := path.Join("xl", rel.Target)
+			if strings.HasPrefix(rel.Target, "/") {
+				target = rel.Target[1:]
+			}
+			sheets = append(sheets, xlsxSheet{name: s.Name, target: target})
+		}
+	}
+	return sheets, nil
+}
+
+// xlsxText is rich or plain text of a shared or inline string.
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	s := t.T
+	for _, r := range t.Runs {
+		s += r.T
+	}
+	return s
+}
+
+// sharedString returns the shared string at index i.
+func (b *xlsxBook) sharedString(i int) (string, error) {
+	if !b.loaded {
+		var sst struct {
+			Items []xlsxText `xml:"si"`
+		}
+		if err := b.part("xl/sharedStrings.xml", &sst); err != nil && !errors.Is(err, ErrNotFound) {
+			return "", err
+		}
+		for _, si := range sst.Items {
+			b.strings = append(b.strings, si.String())
+		}
+		b.loaded = true
+	}
+	if i < 0 || i >= len(b.strings) {
+		return "", fmt.Errorf("invalid shared string index %d", i)
+	}
+	return b.strings[i], nil
+}
+
+// cells returns the non-empty cells of the sheet part name as strings, float64s and
+// bools.
+func (b *xlsxBook) cells(name string) (map[xlsxCell]any, error) {
+	var ws struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				R  string   `xml:"r,attr"`
+				T  string   `xml:"t,attr"`
+				V  *string  `xml:"v"`
+				Is xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := b.part(name, &ws); err != nil {
+		return nil, err
+	}
+
+	cells := make(map[xlsxCell]any)
+	row := 0
+	for _, rw := range ws.Rows {
+		row = max(rw.R, row+1)
+		col := 0
+		for _, c := range rw.Cells {
+			col++
+			if cc, _, ok := parseCellRef(c.R); ok {
+				col = cc
+			}
+			pos := xlsxCell{row, col}
+			if c.T == "inlineStr" {
+				cells[pos] = c.Is.String()
+				continue
+			}
+			if c.V == nil {
+				continue
+			}
+			switch v := *c.V; c.T {
+			case "s":
+				i, err := strconv.Atoi(v)
+				if err != nil {
+					return nil, fmt.Errorf("invalid shared string index %q in cell %s", v, c.R)
+				}
+				if cells[pos], err = b.sharedString(i); err != nil {
+					return nil, err
+				}
+			case "b":
+				cells[pos] = v == "1"
+			case "", "n":
+				f, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid number %q in cell %s", v, c.R)
+				}
+				cells[pos] = f
+			default: // str (formula string), e (error), d (ISO 8601 date)
+				cells[pos] = v
+			}
+		}
+	}
+	return cells, nil
+}
+
+// xlsxRecords returns the rows below the first row as maps keyed by the first row's
+// cells (or the column letters where they are empty). Empty rows are skipped.
+func xlsxRecords(cells map[xlsxCell]any) []any {
+	first, lastRow, lastCol := 0, 0, 0
+	for pos := range cells {
+		if first == 0 || pos.row < first {
+			first = pos.row
+		}
+		lastRow, lastCol = max(lastRow, pos.row), max(lastCol, pos.col)
+	}
+
+	headers := make([]string, lastCol+1)
+	for col := 1; col <= lastCol; col++ {
+		headers[col] = columnName(col)
+		if h, ok := cells[xlsxCell{first, col}]; ok && xlsxString(h) != "" {
+			headers[col] = xlsxString(h)
+		}
+	}
+
+	records := []any{}
+	for row := first + 1; first > 0 && row <= lastRow; row++ {
+		rec := make(map[string]any)
+		for col := 1; col <= lastCol; col++ {
+			if v, ok := cells[xlsxCell{row, col}]; ok {
+				rec[headers[col]] = v
+			}
+		}
+		if len(rec) > 0 {
+			records = append(records, rec)
+		}
+	}
+	return records
+}
+
+// xlsxString formats a cell value or a selected value.
+func xlsxString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	out, _ := json.Marshal(v)
+	return string(out)
+}
+
+// parseCellRef parses an A1-style reference ("B2", "$B$2") into a 1-based column and
+// row.
+func parseCellRef(ref string) (int, int, bool) {
+	ref = strings.ReplaceAll(ref, "$", "")
+	i := 0
+	col := 0
+	for i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z' {
+		col = col*26 + int(ref[i]-'A'+1)
+		i++
+	}
+	if i == 0 || i > 3 || i == len(ref) {
+		return 0, 0, false
+	}
+	row, err := strconv.Atoi(ref[i:])
+	if err != nil || row < 1 || ref[i] == '+' {
+		return 0, 0, false
+	}
+	return col, row, true
+}
+
+// columnName returns the letters of a 1-based column ("A", ..., "Z", "AA", ...).
+func columnName(col int) string {
+	var b []byte
+	for ; col > 0; col = (col - 1) / 26 {
+		b = append([]byte{byte('A' + (col-1)%26)}, b...)
+	}
+	return string(b)
+}
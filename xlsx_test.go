@@ -0,0 +1,124 @@
+package resolver
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createXLSXTestFile writes a minimal workbook with the given parts.
+func createXLSXTestFile(t *testing.T, parts map[string]string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "book.xlsx")
+	f, err := os.Create(p)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+	return p
+}
+
+func TestXLSXResolver_Resolve(t *testing.T) {
+	r := &XLSXResolver{}
+
+	p := createXLSXTestFile(t, map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+  <sheets>
+    <sheet name="Quotas" sheetId="1" r:id="rId1"/>
+    <sheet name="Notes" sheetId="2" r:id="rId2"/>
+  </sheets>
+</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+  <Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <si><t>service</t></si>
+  <si><t>limit</t></si>
+  <si><t>api</t></si>
+  <si><r><t>work</t></r><r><t>er</t></r></si>
+</sst>`,
+		"xl/worksheets/sheet1.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <sheetData>
+    <row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1"/></row>
+    <row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2"><v>100</v></c><c r="C2" t="b"><v>1</v></c></row>
+    <row r="4"><c r="A4" t="s"><v>3</v></c><c r="B4"><v>2.5</v></c><c r="C4" t="str"><f>B4*2</f><v>five</v></c></row>
+  </sheetData>
+</worksheet>`,
+		"xl/worksheets/sheet2.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <sheetData>
+    <row><c t="inlineStr"><is><t>inline text</t></is></c><c><v>7</v></c></row>
+  </sheetData>
+</worksheet>`,
+	})
+
+	cases := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{"Shared string cell", "Quotas.A2", "api"},
+		{"Number cell", "Quotas.B2", "100"},
+		{"Absolute reference", "Quotas.$B$4", "2.5"},
+		{"Boolean cell", "Quotas.C2", "true"},
+		{"Formula string cell", "Quotas.C4", "five"},
+		{"Rich shared string", "Quotas.A4", "worker"},
+		{"Inline string without references", "Notes.A1", "inline text"},
+		{"Column after inline string", "Notes.B1", "7"},
+		{"Header filter", "Quotas.[service=worker].limit", "2.5"},
+		{"Row index", "Quotas.0.limit", "100"},
+		{"Empty header uses column letter", "Quotas.0.C", "true"},
+		{"Sheet names", "", `["Quotas","Notes"]`},
+		{"Records", "Quotas", `[{"C":true,"limit":100,"service":"api"},{"C":"five","limit":2.5,"service":"worker"}]`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, val)
+		})
+	}
+
+	t.Run("Empty cell", func(t *testing.T) {
+		_, err := r.Resolve(p + "//Quotas.D2")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing sheet", func(t *testing.T) {
+		_, err := r.Resolve(p + "//Limits.A1")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing header", func(t *testing.T) {
+		_, err := r.Resolve(p + "//Quotas.[service=db].limit")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Not a workbook", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.xlsx")
+		require.NoError(t, os.WriteFile(bad, []byte("not a zip"), 0o600))
+		_, err := r.Resolve(bad + "//Quotas.A1")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "missing.xlsx") + "//Quotas.A1")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,199 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// XMLResolver resolves a value by loading an XML file and selecting a node with an
+// XPath-like path. Format: "xml:/path/file.xml//root/child/leaf", e.g.
+// "xml:/cfg/app.xml//server/host/@port".
+//
+// The path starts at the document element and uses '/' between steps. A step is an
+// element name (namespace prefixes are ignored), "*" for any element, optionally
+// followed by a 1-based index ("server[2]"); the last step may be "@attr" for an
+// attribute or "text()". Elements with child elements return their inner XML, other
+// elements their text. The first matching node wins. If no path is provided, returns
+// the whole XML file as a string.
+type XMLResolver struct{}
+
+func (r *XMLResolver) Resolve(value string) (string, error) {
+	filePath, keyPath := splitFileAndKey(value)
+	filePath = os.ExpandEnv(filePath)
+
+	if strings.TrimSpace(filePath) == "" {
+		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
+	}
+
+	data, err := readTextFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: %s", ErrForbidden, filePath)
+		}
+		return "", fmt.Errorf("failed to read XML file %q: %w", filePath, err)
+	}
+
+	return selectXML(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *XMLResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// xmlNode is a parsed XML element.
+type xmlNode struct {
+	name     string
+	attrs    map[string]string
+	children []*xmlNode
+	text     strings.Builder // character data directly inside the element
+	inner    []byte          // raw content between the start and end tags
+}
+
+// selectXML returns the node at keyPath in XML data, or the whole document if keyPath
+// is empty. source names the document in error messages.
+func selectXML(data []byte, keyPath, source string) (string, error) {
+	if keyPath == "" {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	root, err := parseXML(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse XML in %q: %w", source, err)
+	}
+	val, err := xmlPath(root, keyPath)
+	if err != nil {
+		return "", fmt.Errorf("%w: path %q in XML %q: %v", ErrNotFound, keyPath, source, err)
+	}
+	return val, nil
+}
+
+// parseXML parses data into a tree and returns the document element.
+func parseXML(data []byte) (*xmlNode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = true
+	var (
+		root  *xmlNode
+		stack []*xmlNode
+		start []int64 // content offsets of the open elements
+	)
+	for {
+		before := dec.InputOffset()
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: t.Name.Local, attrs: make(map[string]string, len(t.Attr))}
+			for _, a := range t.Attr {
+				n.attrs[a.Name.Local] = a.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+			start = append(start, dec.InputOffset())
+		case xml.EndElement:
+			n := stack[len(stack)-1]
+			if from := start[len(start)-1]; before > from {
+				n.inner = data[from:before]
+			}
+			stack, start = stack[:len(stack)-1], start[:len(start)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("no document element")
+	}
+	return root, nil
+}
+
+// xmlPath evaluates path against the document element root.
+func xmlPath(root *xmlNode, path string) (string, error) {
+	steps := strings.Split(strings.Trim(strings.TrimSpace(path), "/"), "/")
+	nodes := []*xmlNode{{children: []*xmlNode{root}}} // virtual document node
+	for i, step := range steps {
+		last := i == len(steps)-1
+		switch {
+		case last && strings.HasPrefix(step, "@"):
+			for _, n := range nodes {
+				if v, ok := n.attrs[step[1:]]; ok {
+					return v, nil
+				}
+			}
+			return "", fmt.Errorf("attribute %q not found", step[1:])
+		case last && step == "text()":
+			return strings.TrimSpace(nodes[0].text.String()), nil
+		}
+
+		name, index, err := parseXMLStep(step)
+		if err != nil {
+			return "", err
+		}
+		var next []*xmlNode
+		for _, n := range nodes {
+			var matched []*xmlNode
+			for _, c := range n.children {
+				if name == "*" || c.name == name {
+					matched = append(matched, c)
+				}
+			}
+			if index > 0 {
+				if index > len(matched) {
+					continue
+				}
+				matched = matched[index-1 : index]
+			}
+			next = append(next, matched...)
+		}
+		if len(next) == 0 {
+			return "", fmt.Errorf("element %q not found", step)
+		}
+		nodes = next
+	}
+
+	n := nodes[0]
+	if len(n.children) > 0 {
+		return strings.TrimSpace(string(n.inner)), nil
+	}
+	return strings.TrimSpace(n.text.String()), nil
+}
+
+// parseXMLStep splits "name[n]" into name and the 1-based index n (0 if absent).
+func parseXMLStep(step string) (string, int, error) {
+	name, rest, ok := strings.Cut(step, "[")
+	if name == "" {
+		return "", 0, fmt.Errorf("empty step in path")
+	}
+	if _, local, found := strings.Cut(name, ":"); found {
+		name = local
+	}
+	if !ok {
+		return name, 0, nil
+	}
+	idx, err := strconv.Atoi(strings.TrimSuffix(rest, "]"))
+	if err != nil || !strings.HasSuffix(rest, "]") || idx < 1 {
+		return "", 0, fmt.Errorf("invalid index in step %q", step)
+	}
+	return name, idx, nil
+}
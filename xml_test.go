@@ -0,0 +1,91 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testXML = `<?xml version="1.0" encoding="UTF-8"?>
+<config xmlns:app="urn:app">
+  <server>
+    <host port="8443">app.internal</host>
+  </server>
+  <servers>
+    <server name="a"><host>a.example.com</host></server>
+    <server name="b"><host>b.example.com</host></server>
+  </servers>
+  <app:feature enabled="true">beta &amp; more</app:feature>
+</config>
+`
+
+func TestXMLResolver_Resolve(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "app.xml")
+	require.NoError(t, os.WriteFile(p, []byte(testXML), 0o666))
+	r := &XMLResolver{}
+
+	cases := []struct {
+		name, path, want string
+	}{
+		{"Element text", "config/server/host", "app.internal"},
+		{"Attribute", "config/server/host/@port", "8443"},
+		{"Index", "config/servers/server[2]/host", "b.example.com"},
+		{"Index attribute", "config/servers/server[1]/@name", "a"},
+		{"First match wins", "config/servers/server/host", "a.example.com"},
+		{"Wildcard", "config/*/host/@port", "8443"},
+		{"Namespace prefix ignored", "config/app:feature", "beta & more"},
+		{"Local name", "config/feature/@enabled", "true"},
+		{"text()", "config/server/host/text()", "app.internal"},
+		{"Inner XML", "config/server", `<host port="8443">app.internal</host>`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := r.Resolve(p + "//" + tc.path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, val)
+		})
+	}
+
+	t.Run("Whole file", func(t *testing.T) {
+		val, err := r.Resolve(p)
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(testXML), val)
+	})
+
+	t.Run("Missing element", func(t *testing.T) {
+		_, err := r.Resolve(p + "//config/nope")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing attribute", func(t *testing.T) {
+		_, err := r.Resolve(p + "//config/server/host/@nope")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Index out of range", func(t *testing.T) {
+		_, err := r.Resolve(p + "//config/servers/server[3]")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid index", func(t *testing.T) {
+		_, err := r.Resolve(p + "//config/servers/server[0]")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid XML", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.xml")
+		require.NoError(t, os.WriteFile(bad, []byte("<config><open></config>"), 0o666))
+		_, err := r.Resolve(bad + "//config")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := r.Resolve(filepath.Join(t.TempDir(), "nope.xml") + "//config")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -7,14 +7,20 @@ import (
 	"os"
 	"strings"
 
-	"github.com/containeroo/resolver/selector"
 	"gopkg.in/yaml.v3"
 )
 
 // YAMLResolver resolves a value by loading a YAML file and extracting a nested key.
 // Format: "yaml:/path/file.yaml//key1.key2.keyN".
 // If no key is provided, returns the whole YAML file as a string.
-type YAMLResolver struct{}
+type YAMLResolver struct {
+	// CaseInsensitiveKeys matches keys of dotted paths ignoring case when no key
+	// matches exactly (see selector.Options).
+	CaseInsensitiveKeys bool
+	// UniqueFilters makes a filter of a dotted path that matches several elements an
+	// ErrBadPath error instead of selecting the first (see selector.FilterUnique).
+	UniqueFilters bool
+}
 
 func (r *YAMLResolver) Resolve(value string) (string, error) {
 	filePath, keyPath := splitFileAndKey(value)
@@ -24,7 +30,7 @@ func (r *YAMLResolver) Resolve(value string) (string, error) {
 		return "", fmt.Errorf("%w: empty file path", ErrBadPath)
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := readTextFile(filePath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return "", fmt.Errorf("%w: %s", ErrNotFound, filePath)
@@ -35,16 +41,32 @@ func (r *YAMLResolver) Resolve(value string) (string, error) {
 		return "", fmt.Errorf("failed to read YAML file %q: %w", filePath, err)
 	}
 
+	return r.selectValue(data, keyPath, filePath)
+}
+
+// Describe reports the resolver metadata.
+func (r *YAMLResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile}}
+}
+
+// selectYAML returns the value at keyPath in YAML data, or the whole document if keyPath is empty.
+// source names the document in error messages.
+func selectYAML(data []byte, keyPath, source string) (string, error) {
+	return (&YAMLResolver{}).selectValue(data, keyPath, source)
+}
+
+// selectValue is selectYAML with the key matching options of r.
+func (r *YAMLResolver) selectValue(data []byte, keyPath, source string) (string, error) {
 	// Parse YAML into a generic structure (map[string]any / []any / scalars).
 	var content any
 	if err := yaml.Unmarshal(data, &content); err != nil {
-		return "", fmt.Errorf("failed to parse YAML in %q: %w", filePath, err)
+		return "", fmt.Errorf("failed to parse YAML in %q: %w", source, err)
 	}
 
 	// Normalize to map[string]any at the root so selector can navigate uniformly.
 	contentMap, err := convertToMapStringInterface(content)
 	if err != nil {
-		return "", fmt.Errorf("failed to process YAML %q: %w", filePath, err)
+		return "", fmt.Errorf("failed to process YAML %q: %w", source, err)
 	}
 
 	// No key → return the entire file (trimmed).
@@ -52,15 +74,13 @@ func (r *YAMLResolver) Resolve(value string) (string, error) {
 		return strings.TrimSpace(string(data)), nil
 	}
 
-	// Bracket-aware path splitting (supports servers.[host=example.org].port).
-	tokens := selector.ParsePath(keyPath)
-	// Walk the structure using selector.
-	val, err := selector.Navigate(contentMap, tokens)
+	// Walk the structure using selector (or JSONPath for "$..." expressions).
+	val, err := selectPathWith(contentMap, keyPath, selectorOptions(r.CaseInsensitiveKeys, r.UniqueFilters))
 	if err != nil {
-		return "", fmt.Errorf("%w: key path %q in YAML %q: %v", ErrNotFound, keyPath, filePath, err)
+		return "", fmt.Errorf("%w: key path %q in YAML %q: %v", selectErr(err), keyPath, source, err)
 	}
 
-	// Strings are returned as-is; non-strings are re-encoded as YAML (trimmed).
+	// Strings are returned as-is; non-strings are re-encoded as YAML (trimmed, keys sorted).
 	if s, ok := val.(string); ok {
 		return s, nil
 	}
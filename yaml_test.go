@@ -131,6 +131,41 @@ nonString:
 		require.Error(t, err)
 	})
 
+	t.Run("Projection", func(t *testing.T) {
+		p := createYAMLTestFile(t, "db:\n  host: db.local\n  port: 5432\n  password: s3cret\n")
+
+		val, err := r.Resolve(p + "//db.{host,port}")
+		require.NoError(t, err)
+		assert.Equal(t, "host: db.local\nport: 5432", val)
+	})
+
+	t.Run("Default for missing key", func(t *testing.T) {
+		p := createYAMLTestFile(t, "server:\n  port: 8080\n")
+
+		val, err := r.Resolve(p + "//server.timeout|30s")
+		require.NoError(t, err)
+		assert.Equal(t, "30s", val)
+
+		val, err = r.Resolve(p + "//server.port|80")
+		require.NoError(t, err)
+		assert.Equal(t, "8080", val)
+	})
+
+	t.Run("Case-insensitive keys", func(t *testing.T) {
+		p := createYAMLTestFile(t, "Server:\n  Host: example.com\n  host: exact\n")
+
+		_, err := r.Resolve(p + "//server.host")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		val, err := (&YAMLResolver{CaseInsensitiveKeys: true}).Resolve(p + "//server.host")
+		require.NoError(t, err)
+		assert.Equal(t, "exact", val, "an exact match wins")
+
+		val, err = (&YAMLResolver{CaseInsensitiveKeys: true}).Resolve(p + "//SERVER.HOST")
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", val)
+	})
+
 	t.Run("Non-existing file", func(t *testing.T) {
 		_, err := r.Resolve(filepath.Join(t.TempDir(), "nonexistent.yaml"))
 		require.Error(t, err)
@@ -0,0 +1,119 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+)
+
+// ZooKeeperResolver resolves a value from ZooKeeper znode data.
+// Format: "zk:/path/to/znode" returns the raw data; "zk:/path/to/znode//key.path"
+// parses the data as JSON and selects key.path.
+// Servers default to $ZK_SERVERS (comma-separated), falling back to 127.0.0.1:2181.
+type ZooKeeperResolver struct {
+	Servers []string
+	Auth    string        // optional "user:password" for the digest scheme; defaults to $ZK_AUTH
+	Timeout time.Duration // per lookup; defaults to 10s
+
+	// lookup replaces the ZooKeeper round trip in tests.
+	lookup func(ctx context.Context, path string) ([]byte, error)
+}
+
+func (r *ZooKeeperResolver) Resolve(value string) (string, error) {
+	return r.ResolveContext(context.Background(), value)
+}
+
+// ResolveContext is Resolve with a context bounding the ZooKeeper round trip.
+func (r *ZooKeeperResolver) ResolveContext(ctx context.Context, value string) (string, error) {
+	path, keyPath := splitFileAndKey(value)
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", fmt.Errorf("%w: empty znode path", ErrBadPath)
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("%w: znode path %q must be absolute", ErrBadPath, path)
+	}
+
+	lookup := r.lookup
+	if lookup == nil {
+		lookup = r.fetch
+	}
+	data, err := lookup(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	if keyPath == "" {
+		return string(data), nil
+	}
+	return selectJSON(data, keyPath, "zk:"+path)
+}
+
+// Describe reports the resolver metadata.
+func (r *ZooKeeperResolver) Describe() ResolverMeta {
+	return ResolverMeta{Capabilities: []Capability{CapabilitySelector, CapabilityWholeFile, CapabilityRemote}}
+}
+
+// fetch connects to the ensemble and reads the data of path.
+func (r *ZooKeeperResolver) fetch(ctx context.Context, path string) ([]byte, error) {
+	servers := r.Servers
+	if len(servers) == 0 {
+		servers = strings.Split(firstNonEmpty(os.Getenv("ZK_SERVERS"), "127.0.0.1:2181"), ",")
+	}
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	conn, _, err := zk.Connect(servers, timeout, zk.WithLogger(discardZKLogger{}))
+	if err != nil {
+		return nil, fmt.Errorf("connect to ZooKeeper %v: %w", servers, err)
+	}
+	defer conn.Close()
+
+	if auth := firstNonEmpty(r.Auth, os.Getenv("ZK_AUTH")); auth != "" {
+		if err := conn.AddAuth("digest", []byte(auth)); err != nil {
+			return nil, fmt.Errorf("authenticate to ZooKeeper: %w", err)
+		}
+	}
+
+	// Get blocks until a server is reachable; bound it by the timeout and ctx.
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, _, err := conn.Get(path)
+		done <- result{data, err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("read znode %q: %w", path, ctx.Err())
+	}
+
+	switch {
+	case errors.Is(res.err, zk.ErrNoNode):
+		return nil, fmt.Errorf("%w: znode %q", ErrNotFound, path)
+	case errors.Is(res.err, zk.ErrNoAuth), errors.Is(res.err, zk.ErrAuthFailed):
+		return nil, fmt.Errorf("%w: znode %q", ErrForbidden, path)
+	case res.err != nil:
+		return nil, fmt.Errorf("read znode %q: %w", path, res.err)
+	}
+	return res.data, nil
+}
+
+// discardZKLogger silences the zk client's connection chatter.
+type discardZKLogger struct{}
+
+func (discardZKLogger) Printf(string, ...any) {}
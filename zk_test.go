@@ -0,0 +1,61 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZooKeeperResolver_Resolve(t *testing.T) {
+	nodes := map[string]string{
+		"/config/app":   `{"db": {"host": "db.local", "port": 5432}}`,
+		"/config/plain": "just text\n",
+	}
+	r := &ZooKeeperResolver{lookup: func(_ context.Context, path string) ([]byte, error) {
+		v, ok := nodes[path]
+		if !ok {
+			return nil, fmt.Errorf("%w: znode %q", ErrNotFound, path)
+		}
+		return []byte(v), nil
+	}}
+
+	t.Run("Raw data", func(t *testing.T) {
+		val, err := r.Resolve("/config/plain")
+		require.NoError(t, err)
+		assert.Equal(t, "just text\n", val)
+	})
+
+	t.Run("JSON key", func(t *testing.T) {
+		val, err := r.Resolve("/config/app//db.host")
+		require.NoError(t, err)
+		assert.Equal(t, "db.local", val)
+	})
+
+	t.Run("Missing JSON key", func(t *testing.T) {
+		_, err := r.Resolve("/config/app//db.user")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Selecting from non-JSON data", func(t *testing.T) {
+		_, err := r.Resolve("/config/plain//x")
+		require.Error(t, err)
+	})
+
+	t.Run("Missing znode", func(t *testing.T) {
+		_, err := r.Resolve("/config/nope")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Relative path", func(t *testing.T) {
+		_, err := r.Resolve("config/app")
+		require.ErrorIs(t, err, ErrBadPath)
+	})
+
+	t.Run("Empty path", func(t *testing.T) {
+		_, err := r.Resolve("//db.host")
+		require.ErrorIs(t, err, ErrBadPath)
+	})
+}